@@ -51,7 +51,7 @@ func ApplicationAuthenticationList(c echo.Context) error {
 		out[i] = *a.ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(appAuths)...))
 }
 
 func ApplicationAuthenticationGet(c echo.Context) error {
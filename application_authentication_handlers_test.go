@@ -100,7 +100,7 @@ func TestApplicationAuthenticationList(t *testing.T) {
 		t.Error(err)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestApplicationAuthenticationListTenantNotExist tests that empty list is returned
@@ -776,7 +776,7 @@ func TestApplicationAuthenticationListAuthentications(t *testing.T) {
 		}
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestApplicationAuthenticationListAuthenticationsTenantNotExist tests that not found
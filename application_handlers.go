@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
@@ -41,6 +42,11 @@ func ApplicationList(c echo.Context) error {
 		return err
 	}
 
+	includes, err := parseIncludes(c, applicationIncludes)
+	if err != nil {
+		return err
+	}
+
 	var (
 		applications []m.Application
 		count        int64
@@ -54,11 +60,18 @@ func ApplicationList(c echo.Context) error {
 	c.Logger().Infof("tenant: %v", *applicationDB.Tenant())
 
 	out := make([]interface{}, len(applications))
-	for i := 0; i < len(applications); i++ {
-		out[i] = applications[i].ToResponse()
+	if len(includes) == 0 {
+		for i := 0; i < len(applications); i++ {
+			out[i] = applications[i].ToResponse()
+		}
+	} else {
+		out, err = applicationsWithIncluded(c, applicationDB, applications, includes)
+		if err != nil {
+			return err
+		}
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(applications)...))
 }
 
 func ApplicationGet(c echo.Context) error {
@@ -72,6 +85,11 @@ func ApplicationGet(c echo.Context) error {
 		return util.NewErrBadRequest(err)
 	}
 
+	includes, err := parseIncludes(c, applicationIncludes)
+	if err != nil {
+		return err
+	}
+
 	c.Logger().Infof("Getting Application ID %v", id)
 
 	app, err := applicationDB.GetById(&id)
@@ -79,7 +97,61 @@ func ApplicationGet(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, app.ToResponse())
+	if len(includes) == 0 {
+		return sendResource(c, app, app.ToResponse())
+	}
+
+	// The entity tag only tracks the application itself, so it is not sent along with the related records.
+	out, err := applicationsWithIncluded(c, applicationDB, []m.Application{*app}, includes)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, out[0])
+}
+
+// applicationsWithIncluded returns the applications' responses along with the requested related records. The related
+// records of all the applications are loaded at once, instead of one application at a time.
+func applicationsWithIncluded(c echo.Context, applicationDB dao.ApplicationDao, applications []m.Application, includes []string) ([]interface{}, error) {
+	ids := make([]int64, len(applications))
+	for i := range applications {
+		ids[i] = applications[i].ID
+	}
+
+	preloaded, err := applicationDB.ListByIdsWithPreload(ids, preloadsFor(includes, applicationPreloads)...)
+	if err != nil {
+		return nil, err
+	}
+
+	preloadedByID := make(map[int64]*m.Application, len(preloaded))
+	for i := range preloaded {
+		preloadedByID[preloaded[i].ID] = &preloaded[i]
+	}
+
+	var auths map[int64][]interface{}
+
+	if slices.Contains(includes, "authentications") {
+		auths, err = includedAuthentications(c, ids, "application")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]interface{}, len(applications))
+
+	for i := range applications {
+		app, ok := preloadedByID[applications[i].ID]
+		if !ok {
+			return nil, util.NewErrNotFound("application")
+		}
+
+		out[i], err = withIncluded(c, applications[i].ToResponse(), applicationIncluded(app, auths[app.ID], includes), applicationIncludes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
 }
 
 func ApplicationCreate(superKeySvc service.SuperKeyProducer) echo.HandlerFunc {
@@ -171,6 +243,16 @@ func ApplicationEdit(c echo.Context) error {
 		return util.NewErrNotFound("application")
 	}
 
+	err = checkIfMatch(c, app)
+	if err != nil {
+		return err
+	}
+
+	// Keep the fetched version, so that the update does not go through if the application gets modified in the meantime.
+	updatedAt := app.UpdatedAt
+
+	setAuditPrevious(c, app)
+
 	// Store the previous status before updating the application.
 	previousStatus := app.AvailabilityStatus
 
@@ -179,7 +261,7 @@ func ApplicationEdit(c echo.Context) error {
 	if app.PausedAt != nil {
 		input := &m.ResourceEditPausedRequest{}
 
-		err := c.Bind(input)
+		err := bindEditRequest(c, app.ToResponse(), input)
 		if err != nil {
 			return util.NewErrBadRequest(err)
 		}
@@ -193,7 +275,7 @@ func ApplicationEdit(c echo.Context) error {
 	} else {
 		input := &m.ApplicationEditRequest{}
 
-		err := c.Bind(input)
+		err := bindEditRequest(c, app.ToResponse(), input)
 		if err != nil {
 			return util.NewErrBadRequest(err)
 		}
@@ -207,7 +289,12 @@ func ApplicationEdit(c echo.Context) error {
 		app.UpdateFromRequest(input)
 	}
 
-	err = applicationDB.Update(app)
+	if hasIfMatch(c) {
+		err = applicationDB.UpdateIfUnmodified(app, updatedAt)
+	} else {
+		err = applicationDB.Update(app)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -236,6 +323,8 @@ func ApplicationEdit(c echo.Context) error {
 		}
 	}
 
+	setETag(c, app)
+
 	return c.JSON(http.StatusOK, app.ToResponse())
 }
 
@@ -260,11 +349,30 @@ func ApplicationDelete(c echo.Context) error {
 		return util.NewErrNotFound("application")
 	}
 
+	// The application's last state goes to the audit trail, and the client might want to make sure it is deleting
+	// the representation it last saw.
+	app, err := applicationDB.GetById(&id)
+	if err != nil {
+		return err
+	}
+
+	err = checkIfMatch(c, app)
+	if err != nil {
+		return err
+	}
+
 	// Superkey applications are deleted asynchronously: we enqueue a job
 	// that sends the destroy request to the superkey worker, which cleans
-	// up the cloud resources before the actual DB cascade delete runs.
+	// up the cloud resources before the actual DB cascade delete runs. The
+	// "If-Match" header can only be checked against the fetched
+	// application for these.
 	if applicationDB.IsSuperkey(id) {
-		return enqueueSuperKeyDelete(c, "application", id)
+		err = enqueueSuperKeyDelete(c, "application", id)
+		if err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusAccepted)
 	}
 
 	// Non-superkey: cascade delete immediately.
@@ -273,7 +381,12 @@ func ApplicationDelete(c echo.Context) error {
 		return err
 	}
 
-	err = service.DeleteCascade(applicationDB.Tenant(), applicationDB.User(), "Application", id, forwardableHeaders)
+	if hasIfMatch(c) {
+		err = service.DeleteCascadeIfUnmodified(applicationDB.Tenant(), applicationDB.User(), "Application", id, app.UpdatedAt, forwardableHeaders)
+	} else {
+		err = service.DeleteCascade(applicationDB.Tenant(), applicationDB.User(), "Application", id, forwardableHeaders)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -283,6 +396,8 @@ func ApplicationDelete(c echo.Context) error {
 		"application_id": id,
 	}).Infof("deleted application")
 
+	setAuditDestroyedResource(c, app)
+
 	return c.NoContent(http.StatusNoContent)
 }
 
@@ -348,7 +463,7 @@ func SourceListApplications(c echo.Context) error {
 		out[i] = applications[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(applications)...))
 }
 
 // ApplicationPause pauses a given application by setting its "paused_at" column to "now()".
@@ -117,7 +117,7 @@ func TestSourceApplicationSubcollectionList(t *testing.T) {
 		t.Error(err)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 func TestSourceApplicationSubcollectionListEmptyList(t *testing.T) {
@@ -373,7 +373,7 @@ func TestApplicationList(t *testing.T) {
 		t.Error(err)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 func TestApplicationListBadRequestInvalidFilter(t *testing.T) {
@@ -614,6 +614,57 @@ func TestApplicationGetBadRequest(t *testing.T) {
 	templates.BadRequestTest(t, rec)
 }
 
+// TestApplicationGetInclude tests that the requested related records are returned along with the application, trimmed
+// down to their sparse fieldsets when requested.
+func TestApplicationGetInclude(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/applications/1?include=source,application_type,authentications",
+		nil,
+		map[string]interface{}{
+			"tenantID":  int64(1),
+			"fieldsets": map[string][]string{"sources": {"name"}},
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := ApplicationGet(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want status code %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var out struct {
+		ID              string                   `json:"id"`
+		Source          map[string]interface{}   `json:"source"`
+		ApplicationType map[string]interface{}   `json:"application_type"`
+		Authentications []map[string]interface{} `json:"authentications"`
+	}
+
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("failed unmarshaling output: %s", err)
+	}
+
+	wantSource := map[string]interface{}{"id": "1", "name": fixtures.TestSourceData[0].Name}
+	if !reflect.DeepEqual(out.Source, wantSource) {
+		t.Errorf(`want the included source "%v", got "%v"`, wantSource, out.Source)
+	}
+
+	if out.ApplicationType["id"] != strconv.FormatInt(fixtures.TestApplicationData[0].ApplicationTypeID, 10) {
+		t.Errorf(`want the application's type, got "%v"`, out.ApplicationType)
+	}
+
+	if out.Authentications == nil {
+		t.Errorf(`want the application's authentications to be included, got "%s"`, rec.Body.String())
+	}
+}
+
 func TestApplicationCreateGood(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 
@@ -1319,7 +1370,7 @@ func TestApplicationListAuthentications(t *testing.T) {
 		}
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestApplicationListAuthenticationsEmptyList tests that an empty list is returned
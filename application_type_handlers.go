@@ -65,7 +65,7 @@ func SourceListApplicationTypes(c echo.Context) error {
 		out[i] = appTypes[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(appTypes)...))
 }
 
 func ApplicationTypeList(c echo.Context) error {
@@ -101,7 +101,7 @@ func ApplicationTypeList(c echo.Context) error {
 		out[i] = appTypes[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(appTypes)...))
 }
 
 func ApplicationTypeGet(c echo.Context) error {
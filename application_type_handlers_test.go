@@ -86,7 +86,7 @@ func TestSourceApplicationTypeSubcollectionList(t *testing.T) {
 		}
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestSourceApplicationTypeSubcollectionListDisabledAppTypes tests that the
@@ -190,7 +190,7 @@ func TestSourceApplicationTypesSubcollectionListDisabledAppTypes(t *testing.T) {
 		}
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestSourceApplicationTypeSubcollectionListEmptyList tests that empty list is
@@ -421,7 +421,7 @@ func TestApplicationTypeList(t *testing.T) {
 		}
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestApplicationTypesListDisabledAppTypes tests that the handler under test
@@ -518,7 +518,7 @@ func TestApplicationTypesListDisabledAppTypes(t *testing.T) {
 		}
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestApplicationTypeListWithTenant tests that list of application types is returned
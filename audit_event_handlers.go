@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+var getAuditEventDao func(c echo.Context) (dao.AuditEventDao, error)
+
+func getAuditEventDaoWithTenant(c echo.Context) (dao.AuditEventDao, error) {
+	requestParams, err := dao.NewRequestParamsFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return dao.GetAuditEventDao(requestParams), nil
+}
+
+// AuditEventList lists the audit trail of every resource of the tenant.
+func AuditEventList(c echo.Context) error {
+	auditEventDao, err := getAuditEventDao(c)
+	if err != nil {
+		return err
+	}
+
+	filters, err := getFilters(c)
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := getLimitAndOffset(c)
+	if err != nil {
+		return err
+	}
+
+	auditEvents, count, err := auditEventDao.List(limit, offset, filters)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, auditEventsCollection(c, auditEvents, count, limit, offset))
+}
+
+// SourceHistory lists the audit trail of the given source. The trail outlives the source, so that it is still
+// possible to tell who deleted it.
+func SourceHistory(c echo.Context) error {
+	auditEventDao, err := getAuditEventDao(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	filters, err := getFilters(c)
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := getLimitAndOffset(c)
+	if err != nil {
+		return err
+	}
+
+	auditEvents, count, err := auditEventDao.ListForResource("Source", strconv.FormatInt(id, 10), limit, offset, filters)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, auditEventsCollection(c, auditEvents, count, limit, offset))
+}
+
+func auditEventsCollection(c echo.Context, auditEvents []m.AuditEvent, count int64, limit, offset int) *util.Collection {
+	out := make([]interface{}, len(auditEvents))
+	for i := 0; i < len(auditEvents); i++ {
+		out[i] = auditEvents[i].ToResponse()
+	}
+
+	return util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(auditEvents)...)
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// auditEventsRequest lists the audit events of the given handler.
+func auditEventsRequest(t *testing.T, handler echo.HandlerFunc, path string, id string) *httptest.ResponseRecorder {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		path,
+		nil,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"filters":  []util.Filter{},
+			h.TenantID: int64(1),
+		},
+	)
+
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+
+	err := ErrorHandlingContext(handler)(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	return rec
+}
+
+// unmarshalAuditEvents unmarshals the listed audit events.
+func unmarshalAuditEvents(t *testing.T, rec *httptest.ResponseRecorder) []m.AuditEventResponse {
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var out struct {
+		Data []m.AuditEventResponse `json:"data"`
+	}
+
+	err := json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the audit events: %s", err)
+	}
+
+	return out.Data
+}
+
+// TestAuditEventList tests that the tenant's audit events get listed.
+func TestAuditEventList(t *testing.T) {
+	auditEvents := unmarshalAuditEvents(t, auditEventsRequest(t, AuditEventList, "/api/sources/v3.1/audit_events", ""))
+
+	if len(auditEvents) < 3 {
+		t.Fatalf("want at least 3 audit events, got %d", len(auditEvents))
+	}
+
+	for _, auditEvent := range auditEvents {
+		if auditEvent.ID == "" || auditEvent.ResourceType == "" || auditEvent.Action == "" || len(auditEvent.Changes) == 0 {
+			t.Errorf(`unexpected audit event "%+v"`, auditEvent)
+		}
+	}
+}
+
+// TestSourceHistory tests that only the given source's audit events get listed.
+func TestSourceHistory(t *testing.T) {
+	auditEvents := unmarshalAuditEvents(t, auditEventsRequest(t, SourceHistory, "/api/sources/v3.1/sources/1/history", "1"))
+
+	if len(auditEvents) != 2 {
+		t.Fatalf("want 2 audit events for the source, got %d", len(auditEvents))
+	}
+
+	for _, auditEvent := range auditEvents {
+		if auditEvent.ResourceType != "Source" || auditEvent.ResourceID != "1" {
+			t.Errorf(`want the source's audit events only, got "%+v"`, auditEvent)
+		}
+	}
+
+	if auditEvents[0].Action != m.AuditActionCreate || auditEvents[1].ActorType != m.AuditActorPSK {
+		t.Errorf(`unexpected source history "%+v"`, auditEvents)
+	}
+}
+
+// TestSourceHistoryBadRequest tests that an invalid source id gets rejected.
+func TestSourceHistoryBadRequest(t *testing.T) {
+	templates.BadRequestTest(t, auditEventsRequest(t, SourceHistory, "/api/sources/v3.1/sources/xxx/history", "xxx"))
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
@@ -48,7 +49,7 @@ func AuthenticationList(c echo.Context) error {
 		out = append(out, *auth.ToResponse())
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(authentications)...))
 }
 
 func AuthenticationGet(c echo.Context) error {
@@ -62,7 +63,7 @@ func AuthenticationGet(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, auth.ToResponse())
+	return sendResource(c, auth, auth.ToResponse())
 }
 
 func AuthenticationCreate(c echo.Context) error {
@@ -133,19 +134,42 @@ func AuthenticationEdit(c echo.Context) error {
 
 	updateRequest := &m.AuthenticationEditRequest{}
 
-	err = c.Bind(updateRequest)
+	// The regular payloads can be bound straight away, but the patch documents need to be applied on top of the
+	// current authentication.
+	patchDocument := isPatchDocument(c)
+	if !patchDocument {
+		err = c.Bind(updateRequest)
+		if err != nil {
+			return err
+		}
+	}
+
+	auth, err := authDao.GetById(c.Param("uid"))
 	if err != nil {
 		return err
 	}
 
-	err = service.ValidateAuthenticationEditRequest(updateRequest)
+	err = checkIfMatch(c, auth)
 	if err != nil {
-		return util.NewErrBadRequest(err)
+		return err
 	}
 
-	auth, err := authDao.GetById(c.Param("uid"))
+	// Keep the fetched entity tag, so that the update does not go through if the authentication gets modified in the
+	// meantime.
+	etag := auth.ETag()
+
+	setAuditPrevious(c, auth)
+
+	if patchDocument {
+		err = bindEditRequest(c, auth.ToResponse(), updateRequest)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = service.ValidateAuthenticationEditRequest(updateRequest)
 	if err != nil {
-		return err
+		return util.NewErrBadRequest(err)
 	}
 
 	previousStatus := ""
@@ -158,7 +182,16 @@ func AuthenticationEdit(c echo.Context) error {
 		return util.NewErrBadRequest(`invalid JSON given in "extra" field`)
 	}
 
-	err = authDao.Update(auth)
+	if hasIfMatch(c) {
+		err = authDao.UpdateIfMatch(auth, etag)
+	} else {
+		err = authDao.Update(auth)
+	}
+
+	if errors.As(err, &util.ErrPreconditionFailed{}) {
+		return err
+	}
+
 	if err != nil {
 		return util.NewErrBadRequest(err)
 	}
@@ -183,6 +216,7 @@ func AuthenticationEdit(c echo.Context) error {
 
 	setNotificationForAvailabilityStatus(c, previousStatus, auth)
 	setEventStreamResource(c, auth)
+	setETag(c, auth)
 
 	return c.JSON(http.StatusOK, auth.ToResponse())
 }
@@ -193,7 +227,25 @@ func AuthenticationDelete(c echo.Context) error {
 		return err
 	}
 
-	auth, err := authDao.Delete(c.Param("uid"))
+	// The authentication only needs to be fetched when the client wants to make sure it is deleting the
+	// representation it last saw. In that case, the deletion only goes through if it has not been modified in between.
+	var auth *m.Authentication
+	if hasIfMatch(c) {
+		auth, err = authDao.GetById(c.Param("uid"))
+		if err != nil {
+			return err
+		}
+
+		err = checkIfMatch(c, auth)
+		if err != nil {
+			return err
+		}
+
+		auth, err = authDao.DeleteIfMatch(c.Param("uid"), auth.ETag())
+	} else {
+		auth, err = authDao.Delete(c.Param("uid"))
+	}
+
 	if err != nil {
 		return err
 	}
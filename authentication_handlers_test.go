@@ -113,7 +113,7 @@ func TestAuthenticationList(t *testing.T) {
 		t.Error(err)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestAuthenticationTenantNotExist tests that empty list is returned
@@ -3,27 +3,89 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"slices"
+	"strconv"
 
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/middleware"
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
 	"github.com/sirupsen/logrus"
 )
 
+// bulkDestroyMaxIds is the maximum number of resources a single bulk destroy request can delete.
+const bulkDestroyMaxIds = 100
+
+// boolQueryParam parses the given boolean query parameter, which defaults to false when it is not present.
+func boolQueryParam(c echo.Context, name string) (bool, error) {
+	value := c.QueryParam(name)
+	if value == "" {
+		return false, nil
+	}
+
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, util.NewErrBadRequest(fmt.Sprintf(`invalid "%s" value "%s"`, name, value))
+	}
+
+	return parsed, nil
+}
+
+// bulkCreateTenantAndUser returns the tenant and the user the bulk created resources belong to.
+func bulkCreateTenantAndUser(c echo.Context) (*m.Tenant, *m.User, error) {
+	tenantID, ok := c.Get(h.TenantID).(int64)
+	if !ok {
+		return nil, nil, fmt.Errorf("failed to pull tenant from request")
+	}
+
+	id, ok := c.Get(h.ParsedIdentity).(*identity.XRHID)
+	if !ok {
+		c.Logger().Warnf("failed to pull identity from request")
+		return nil, nil, fmt.Errorf("failed to pull identity from request")
+	}
+
+	user := &m.User{TenantID: tenantID}
+	userID, ok := c.Get(h.UserID).(int64)
+
+	if ok {
+		if id.Identity.User != nil {
+			user.UserID = id.Identity.User.UserID
+		}
+
+		user.Id = userID
+	}
+
+	// TODO: Pull the identity from the context after the org_id changes are merged.
+	return &m.Tenant{Id: tenantID, ExternalTenant: id.Identity.AccountNumber}, user, nil
+}
+
 func BulkCreate(superKeySvc service.SuperKeyProducer) func(echo.Context) error {
 	return func(c echo.Context) error {
+		dryRun, err := boolQueryParam(c, "dry_run")
+		if err != nil {
+			return err
+		}
+
+		partial, err := boolQueryParam(c, "partial")
+		if err != nil {
+			return err
+		}
+
 		req := m.BulkCreateRequest{}
 
-		err := c.Bind(&req)
+		err = c.Bind(&req)
 		if err != nil {
 			return err
 		}
 
-		tenantID, ok := c.Get(h.TenantID).(int64)
-		if !ok {
-			return fmt.Errorf("failed to pull tenant from request")
+		err = service.DecryptBulkCreatePasswords(&req, c.Request().Header.Get(h.EncryptionKey))
+		if err != nil {
+			return err
 		}
 
 		xrhid, ok := c.Get(h.XRHID).(string)
@@ -31,30 +93,52 @@ func BulkCreate(superKeySvc service.SuperKeyProducer) func(echo.Context) error {
 			c.Logger().Warnf("bad xrhid %v", c.Get(h.XRHID))
 		}
 
-		id, ok := c.Get(h.ParsedIdentity).(*identity.XRHID)
-		if !ok {
-			c.Logger().Warnf("failed to pull identity from request")
-			return fmt.Errorf("failed to pull identity from request")
+		tenant, user, err := bulkCreateTenantAndUser(c)
+		if err != nil {
+			return err
+		}
+
+		tenantID := tenant.Id
+
+		forwardableHeaders, err := service.ForwadableHeaders(c)
+		if err != nil {
+			return err
 		}
 
-		user := &m.User{TenantID: tenantID}
-		userID, ok := c.Get(h.UserID).(int64)
+		if partial {
+			partialOutput := service.BulkAssemblyPartial(req, tenant, user, dryRun, nil)
 
-		if ok {
-			if id.Identity.User != nil {
-				user.UserID = id.Identity.User.UserID
+			// The dry runs roll everything back, so there is nothing to raise events or superkey requests for.
+			if !dryRun {
+				for i := range partialOutput.Created {
+					service.SendBulkMessages(&partialOutput.Created[i], forwardableHeaders, xrhid, superKeySvc)
+				}
 			}
 
-			user.Id = userID
+			handlerLogEntry(c).WithFields(logrus.Fields{
+				"tenant_id":     tenantID,
+				"dry_run":       dryRun,
+				"units_created": len(partialOutput.Created),
+			}).Infof("partial bulk create completed")
+
+			return c.JSON(http.StatusOK, partialOutput.Results)
 		}
 
-		// TODO: Pull the identity from the context after the org_id changes are merged.
-		output, err := service.BulkAssembly(req, &m.Tenant{Id: tenantID, ExternalTenant: id.Identity.AccountNumber}, user)
-		if err != nil {
-			return err
+		// The dry runs roll everything back, so there is nothing to raise events or superkey requests for.
+		if dryRun {
+			output, err := service.BulkAssemblyDryRun(req, tenant, user)
+			if err != nil {
+				return err
+			}
+
+			return c.JSON(http.StatusOK, m.BulkCreateDryRunResponse{
+				BulkCreateResponse: *output.ToResponse(),
+				DryRun:             true,
+				SuperKeyRequests:   service.BulkCreateSuperKeyRequests(output),
+			})
 		}
 
-		forwardableHeaders, err := service.ForwadableHeaders(c)
+		output, err := service.BulkAssembly(req, tenant, user)
 		if err != nil {
 			return err
 		}
@@ -68,8 +152,280 @@ func BulkCreate(superKeySvc service.SuperKeyProducer) func(echo.Context) error {
 			"endpoints_count":                   len(output.Endpoints),
 			"authentications_count":             len(output.Authentications),
 			"application_authentications_count": len(output.ApplicationAuthentications),
+			"rhc_connections_count":             len(output.RhcConnections),
 		}).Infof("bulk create completed")
 
 		return c.JSON(http.StatusCreated, output.ToResponse())
 	}
 }
+
+// BulkDestroy deletes the given sources, applications, endpoints and authentications, along with their dependants.
+// Every ID is checked against the tenant before anything gets deleted, with one query per resource type, so a single
+// unknown ID rejects the whole request. The dependants get deleted before their parents, so that the parents' cascades do not remove them first,
+// and the superkey managed sources and applications get deleted asynchronously. The sources get soft deleted instead
+// when there is a retention window. The response reports the outcome of every deletion.
+func BulkDestroy(c echo.Context) error {
+	req := m.BulkDestroyRequest{}
+
+	err := c.Bind(&req)
+	if err != nil {
+		return err
+	}
+
+	if len(req.Sources)+len(req.Applications)+len(req.Endpoints)+len(req.Authentications) > bulkDestroyMaxIds {
+		return util.NewErrBadRequest(fmt.Sprintf("the request cannot contain more than %d resources to delete", bulkDestroyMaxIds))
+	}
+
+	sourceIds, err := bulkDestroyIds("source", req.Sources)
+	if err != nil {
+		return err
+	}
+
+	applicationIds, err := bulkDestroyIds("application", req.Applications)
+	if err != nil {
+		return err
+	}
+
+	endpointIds, err := bulkDestroyIds("endpoint", req.Endpoints)
+	if err != nil {
+		return err
+	}
+
+	authenticationIds := make([]string, 0, len(req.Authentications))
+
+	for _, rawId := range req.Authentications {
+		id, err := util.InterfaceToString(rawId)
+		if err != nil || id == "" {
+			return util.NewErrBadRequest(fmt.Sprintf(`invalid authentication id "%v"`, rawId))
+		}
+
+		if !slices.Contains(authenticationIds, id) {
+			authenticationIds = append(authenticationIds, id)
+		}
+	}
+
+	if len(sourceIds)+len(applicationIds)+len(endpointIds)+len(authenticationIds) == 0 {
+		return util.NewErrBadRequest("the request does not contain any resources to delete")
+	}
+
+	// The certificate authenticated callers may only delete satellite sources, the same way "SourceDelete" restricts
+	// them, so they cannot delete the other resources on their own.
+	if c.Get("cert-auth") != nil && len(applicationIds)+len(endpointIds)+len(authenticationIds) > 0 {
+		return util.NewErrBadRequest("Unauthorized.")
+	}
+
+	sourcesDB, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	applicationDB, err := getApplicationDao(c)
+	if err != nil {
+		return err
+	}
+
+	endpointDao, err := getEndpointDao(c)
+	if err != nil {
+		return err
+	}
+
+	authDao, err := getAuthenticationDao(c)
+	if err != nil {
+		return err
+	}
+
+	// Validate that every resource belongs to the tenant before deleting anything. The fetched resources are kept so
+	// that their destruction can be recorded in the audit trail.
+	sources := make(map[int64]*m.Source, len(sourceIds))
+
+	if len(sourceIds) > 0 {
+		fetchedSources, err := sourcesDB.ListByIdsWithPreload(sourceIds)
+		if err != nil {
+			return err
+		}
+
+		for i := range fetchedSources {
+			sources[fetchedSources[i].ID] = &fetchedSources[i]
+		}
+	}
+
+	for _, id := range sourceIds {
+		src, ok := sources[id]
+		if !ok {
+			return util.NewErrNotFound(fmt.Sprintf("source %d", id))
+		}
+
+		if c.Get("cert-auth") != nil && src.SourceTypeID != dao.Static.GetSourceTypeId("satellite") {
+			// We only allow delete with cert auth if source type is Satellite
+			return util.NewErrBadRequest("Unauthorized.")
+		}
+	}
+
+	applications := make(map[int64]*m.Application, len(applicationIds))
+
+	if len(applicationIds) > 0 {
+		fetchedApplications, err := applicationDB.ListByIdsWithPreload(applicationIds)
+		if err != nil {
+			return err
+		}
+
+		for i := range fetchedApplications {
+			applications[fetchedApplications[i].ID] = &fetchedApplications[i]
+		}
+	}
+
+	for _, id := range applicationIds {
+		if _, ok := applications[id]; !ok {
+			return util.NewErrNotFound(fmt.Sprintf("application %d", id))
+		}
+	}
+
+	endpoints := make(map[int64]*m.Endpoint, len(endpointIds))
+
+	if len(endpointIds) > 0 {
+		fetchedEndpoints, err := endpointDao.ListByIds(endpointIds)
+		if err != nil {
+			return err
+		}
+
+		for i := range fetchedEndpoints {
+			endpoints[fetchedEndpoints[i].ID] = &fetchedEndpoints[i]
+		}
+	}
+
+	for _, id := range endpointIds {
+		if _, ok := endpoints[id]; !ok {
+			return util.NewErrNotFound(fmt.Sprintf("endpoint %d", id))
+		}
+	}
+
+	if len(authenticationIds) > 0 {
+		fetchedAuthentications, err := authDao.ListByIds(authenticationIds)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range authenticationIds {
+			found := slices.ContainsFunc(fetchedAuthentications, func(auth m.Authentication) bool { return auth.GetID() == id })
+			if !found {
+				return util.NewErrNotFound(fmt.Sprintf("authentication %s", id))
+			}
+		}
+	}
+
+	forwardableHeaders, err := service.ForwadableHeaders(c)
+	if err != nil {
+		return err
+	}
+
+	response := m.BulkDestroyResponse{
+		Sources:         make([]m.BulkDestroyResult, 0, len(sourceIds)),
+		Applications:    make([]m.BulkDestroyResult, 0, len(applicationIds)),
+		Endpoints:       make([]m.BulkDestroyResult, 0, len(endpointIds)),
+		Authentications: make([]m.BulkDestroyResult, 0, len(authenticationIds)),
+	}
+
+	for _, id := range authenticationIds {
+		auth, err := authDao.Delete(id)
+		if err == nil {
+			eventErr := service.RaiseEvent("Authentication.destroy", auth, forwardableHeaders)
+			if eventErr != nil {
+				c.Logger().Errorf(`Event "Authentication.destroy" could not be raised for authentication %v: %s`, auth.ToEvent(), eventErr)
+			}
+
+			recordAuditDestroyedResource(c, auth)
+		}
+
+		response.Authentications = append(response.Authentications, bulkDestroyResult(c, id, m.BulkDestroyDeleted, err))
+	}
+
+	for _, id := range endpointIds {
+		err := service.DeleteCascade(endpointDao.Tenant(), nil, "Endpoint", id, forwardableHeaders)
+		if err == nil {
+			recordAuditDestroyedResource(c, endpoints[id])
+		}
+
+		response.Endpoints = append(response.Endpoints, bulkDestroyResult(c, strconv.FormatInt(id, 10), m.BulkDestroyDeleted, err))
+	}
+
+	for _, id := range applicationIds {
+		var err error
+
+		status := m.BulkDestroyDeleted
+
+		if applicationDB.IsSuperkey(id) {
+			status = m.BulkDestroyAccepted
+			err = enqueueSuperKeyDelete(c, "application", id)
+		} else {
+			err = service.DeleteCascade(applicationDB.Tenant(), applicationDB.User(), "Application", id, forwardableHeaders)
+			if err == nil {
+				recordAuditDestroyedResource(c, applications[id])
+			}
+		}
+
+		response.Applications = append(response.Applications, bulkDestroyResult(c, strconv.FormatInt(id, 10), status, err))
+	}
+
+	for _, id := range sourceIds {
+		var err error
+
+		status := m.BulkDestroyDeleted
+
+		// The sources are kept around for the retention window, the same way "SourceDelete" does, and the
+		// "PurgeDeletedSourcesJob" deletes them for good once it expires.
+		if config.Get().SourceRetention > 0 {
+			_, err = sourcesDB.SoftDelete(id)
+			if err == nil {
+				recordAuditDestroyedResource(c, sources[id])
+			}
+		} else if sourcesDB.IsSuperkey(id) {
+			status = m.BulkDestroyAccepted
+			err = enqueueSuperKeyDelete(c, "source", id)
+		} else {
+			err = service.DeleteCascade(sourcesDB.Tenant(), sourcesDB.User(), "Source", id, forwardableHeaders)
+			if err == nil {
+				recordAuditDestroyedResource(c, sources[id])
+			}
+		}
+
+		response.Sources = append(response.Sources, bulkDestroyResult(c, strconv.FormatInt(id, 10), status, err))
+	}
+
+	handlerLogEntry(c).WithFields(logrus.Fields{
+		"tenant_id":             *sourcesDB.Tenant(),
+		"sources_count":         len(sourceIds),
+		"applications_count":    len(applicationIds),
+		"endpoints_count":       len(endpointIds),
+		"authentications_count": len(authenticationIds),
+	}).Infof("bulk destroy completed")
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// bulkDestroyIds parses the given numeric IDs, dropping the duplicated ones.
+func bulkDestroyIds(resourceType string, rawIds []interface{}) ([]int64, error) {
+	ids := make([]int64, 0, len(rawIds))
+
+	for _, rawId := range rawIds {
+		id, err := util.InterfaceToInt64(rawId)
+		if err != nil || id < 1 {
+			return nil, util.NewErrBadRequest(fmt.Sprintf(`invalid %s id "%v"`, resourceType, rawId))
+		}
+
+		if !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+
+	return ids, nil
+}
+
+// bulkDestroyResult returns the result of a single deletion, which failed if the given error is not nil. The error is
+// reported the same way it would be if the resource had been deleted on its own.
+func bulkDestroyResult(c echo.Context, id string, status string, err error) m.BulkDestroyResult {
+	if err != nil {
+		return m.BulkDestroyResult{ID: id, Status: m.BulkDestroyFailed, Error: middleware.ClientErrorMessage(c, err)}
+	}
+
+	return m.BulkDestroyResult{ID: id, Status: status}
+}
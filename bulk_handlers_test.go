@@ -3,7 +3,10 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -15,6 +18,7 @@ import (
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	"github.com/RedHatInsights/sources-api-go/jobs"
 	"github.com/RedHatInsights/sources-api-go/kafka"
 	"github.com/RedHatInsights/sources-api-go/middleware"
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
@@ -254,6 +258,158 @@ func TestBulkCreate(t *testing.T) {
 	}
 }
 
+// TestBulkCreateDryRun tests that a dry run returns the linked up resources, and that nothing gets created or sent.
+func TestBulkCreateDryRun(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	nameSource := "dry run source"
+	requestBody := testutils.SingleResourceBulkCreateRequest(nameSource, "bitbucket", "app-studio", "application")
+
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Error("Could not marshal JSON")
+	}
+
+	c, res := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/bulk_create?dry_run=true",
+		bytes.NewReader(body),
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+	c.Set(h.ParsedIdentity, testutils.IdentityHeaderForUser("testUser"))
+
+	superKeyProducer := &mocks.MockSuperKeyProducer{}
+
+	err = BulkCreate(superKeyProducer)(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if res.Code != http.StatusOK {
+		t.Errorf("want status code %d, got %d", http.StatusOK, res.Code)
+	}
+
+	var response m.BulkCreateDryRunResponse
+
+	err = json.Unmarshal(res.Body.Bytes(), &response)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if !response.DryRun {
+		t.Errorf(`want the response to be flagged as a dry run`)
+	}
+
+	source := response.Sources[0]
+	if response.Applications[0].SourceID != source.ID || response.Endpoints[0].SourceID != source.ID {
+		t.Errorf("want the application and the endpoint linked to the source %s, got %v", source.ID, response)
+	}
+
+	if response.Authentications[0].ResourceID != response.Applications[0].ID {
+		t.Errorf("want the authentication linked to the application %s, got %s", response.Applications[0].ID, response.Authentications[0].ResourceID)
+	}
+
+	if superKeyProducer.CreateRequestCallCount != 0 {
+		t.Errorf("want no superkey requests sent, got %d", superKeyProducer.CreateRequestCallCount)
+	}
+
+	var count int64
+
+	err = dao.DB.Model(&m.Source{}).Where("name = ?", nameSource).Count(&count).Error
+	if err != nil {
+		t.Error(err)
+	}
+
+	if count != 0 {
+		t.Errorf("want the dry run to be rolled back, got %d sources created", count)
+	}
+}
+
+// TestBulkCreateDryRunBadRequest tests that an invalid "dry_run" value is rejected.
+func TestBulkCreateDryRunBadRequest(t *testing.T) {
+	c, res := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/bulk_create?dry_run=maybe",
+		bytes.NewReader([]byte(`{}`)),
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+	err := ErrorHandlingContext(BulkCreate(&mocks.MockSuperKeyProducer{}))(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, res)
+}
+
+// TestBulkCreatePartial tests that in the partial success mode the valid sources get created along with their
+// dependants, and that the invalid ones get reported as failed.
+func TestBulkCreatePartial(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	nameSource := "partial source"
+	requestBody := testutils.SingleResourceBulkCreateRequest(nameSource, "bitbucket", "app-studio", "application")
+
+	// A second source with an invalid source type, which must not prevent the first one from being created.
+	invalidName := "partial invalid source"
+	requestBody.Sources = append(requestBody.Sources, m.BulkCreateSource{SourceCreateRequest: m.SourceCreateRequest{Name: &invalidName}, SourceTypeName: "invalid"})
+
+	body, err := json.Marshal(requestBody)
+	if err != nil {
+		t.Error("Could not marshal JSON")
+	}
+
+	c, res := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/bulk_create?partial=true",
+		bytes.NewReader(body),
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+	c.Set(h.ParsedIdentity, testutils.IdentityHeaderForUser("testUser"))
+
+	err = BulkCreate(&mocks.MockSuperKeyProducer{})(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if res.Code != http.StatusOK {
+		t.Errorf("want status code %d, got %d", http.StatusOK, res.Code)
+	}
+
+	var response m.BulkCreatePartialResponse
+
+	err = json.Unmarshal(res.Body.Bytes(), &response)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if response.Sources[0].Status != m.BulkCreateCreated || response.Applications[0].Status != m.BulkCreateCreated ||
+		response.Endpoints[0].Status != m.BulkCreateCreated || response.Authentications[0].Status != m.BulkCreateCreated {
+		t.Errorf(`want the first source and its dependants created, got "%+v"`, response)
+	}
+
+	if response.Sources[1].Status != m.BulkCreateFailed || response.Sources[1].Error == "" {
+		t.Errorf(`want the second source to fail, got "%+v"`, response.Sources[1])
+	}
+
+	err = cleanSourceForTenant(nameSource, &fixtures.TestTenantData[0].Id)
+	if err != nil {
+		t.Errorf(`unexpected error received when deleting the source: %s`, err)
+	}
+}
+
 func TestBulkCreateSourceValidationBadRequest(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 
@@ -473,3 +629,216 @@ func TestBulkCreateAvailableByDefault(t *testing.T) {
 		}
 	}
 }
+
+// bulkDestroyRequest sends the given body to the bulk destroy handler.
+func bulkDestroyRequest(t *testing.T, body string) *httptest.ResponseRecorder {
+	return bulkDestroyRequestWithContext(t, body, map[string]any{})
+}
+
+// bulkDestroyRequestWithContext sends the given body to the bulk destroy handler, with the given values added to the
+// request's context.
+func bulkDestroyRequestWithContext(t *testing.T, body string, context map[string]any) *httptest.ResponseRecorder {
+	context[h.TenantID] = int64(1)
+	context[h.XRHID] = buildXRHIdentity()
+	context["x-rh-sources-account-number"] = "12345"
+	context["x-rh-sources-org-id"] = "23456"
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/bulk_destroy",
+		strings.NewReader(body),
+		context,
+	)
+
+	c.Request().Header.Set("Content-Type", "application/json")
+
+	err := ErrorHandlingContext(BulkDestroy)(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	return rec
+}
+
+// TestBulkDestroy tests that the resources get cascade deleted, and that every deletion gets reported.
+func TestBulkDestroy(t *testing.T) {
+	cleanup := setupSuperKeyTest(false)
+	defer cleanup()
+
+	rec := bulkDestroyRequest(t, `{"sources": [1], "applications": ["2", 2]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var response m.BulkDestroyResponse
+
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the response: %s", err)
+	}
+
+	wantSources := []m.BulkDestroyResult{{ID: "1", Status: m.BulkDestroyDeleted}}
+	if !reflect.DeepEqual(response.Sources, wantSources) {
+		t.Errorf(`want sources "%v", got "%v"`, wantSources, response.Sources)
+	}
+
+	// The duplicated IDs are only deleted once.
+	wantApplications := []m.BulkDestroyResult{{ID: "2", Status: m.BulkDestroyDeleted}}
+	if !reflect.DeepEqual(response.Applications, wantApplications) {
+		t.Errorf(`want applications "%v", got "%v"`, wantApplications, response.Applications)
+	}
+
+	if len(mockEnqueuedJobs) != 0 {
+		t.Errorf("want no enqueued jobs, got %d", len(mockEnqueuedJobs))
+	}
+}
+
+// TestBulkDestroyAuditTrail tests that the destruction of every deleted resource gets recorded in the audit trail.
+func TestBulkDestroyAuditTrail(t *testing.T) {
+	cleanup := setupSuperKeyTest(false)
+	defer cleanup()
+
+	mockAuditDao := &mocks.MockAuditEventDao{}
+	dao.GetAuditEventDao = func(_ *dao.RequestParams) dao.AuditEventDao { return mockAuditDao }
+
+	rec := bulkDestroyRequest(t, `{"sources": [1], "applications": [2]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	want := []string{"Application 2 destroy", "Source 1 destroy"}
+
+	got := make([]string, 0, len(mockAuditDao.AuditEvents))
+	for _, auditEvent := range mockAuditDao.AuditEvents {
+		got = append(got, fmt.Sprintf("%s %s %s", auditEvent.ResourceType, auditEvent.ResourceID, auditEvent.Action))
+	}
+
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf(`want audit events "%v", got "%v"`, want, got)
+	}
+}
+
+// TestBulkDestroySuperKey tests that the superkey managed resources get deleted asynchronously.
+func TestBulkDestroySuperKey(t *testing.T) {
+	cleanup := setupSuperKeyTest(true)
+	defer cleanup()
+
+	rec := bulkDestroyRequest(t, `{"sources": [1], "applications": [2]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var response m.BulkDestroyResponse
+
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the response: %s", err)
+	}
+
+	if len(response.Sources) != 1 || response.Sources[0].Status != m.BulkDestroyAccepted {
+		t.Errorf(`want the source to be accepted, got "%v"`, response.Sources)
+	}
+
+	if len(response.Applications) != 1 || response.Applications[0].Status != m.BulkDestroyAccepted {
+		t.Errorf(`want the application to be accepted, got "%v"`, response.Applications)
+	}
+
+	if len(mockEnqueuedJobs) != 2 {
+		t.Fatalf("want 2 enqueued jobs, got %d", len(mockEnqueuedJobs))
+	}
+
+	// The dependants are handled before their parents.
+	for i, wantModel := range []string{"application", "source"} {
+		job, ok := mockEnqueuedJobs[i].(*jobs.SuperkeyDestroyJob)
+		if !ok {
+			t.Fatalf("want a superkey destroy job, got %T", mockEnqueuedJobs[i])
+		}
+
+		if job.Model != wantModel {
+			t.Errorf(`want job %d for model "%s", got "%s"`, i, wantModel, job.Model)
+		}
+	}
+}
+
+// TestBulkDestroySoftDelete tests that the sources get soft deleted when there is a retention window, even the
+// superkey managed ones, which get cleaned up when they are purged.
+func TestBulkDestroySoftDelete(t *testing.T) {
+	cleanup := setupSuperKeyTest(true)
+	defer cleanup()
+
+	conf.SourceRetention = 3600
+
+	mockAuditDao := &mocks.MockAuditEventDao{}
+	dao.GetAuditEventDao = func(_ *dao.RequestParams) dao.AuditEventDao { return mockAuditDao }
+
+	rec := bulkDestroyRequest(t, `{"sources": [1]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var response m.BulkDestroyResponse
+
+	err := json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the response: %s", err)
+	}
+
+	if len(response.Sources) != 1 || response.Sources[0].Status != m.BulkDestroyDeleted {
+		t.Errorf(`want the source to be deleted, got "%v"`, response.Sources)
+	}
+
+	if len(mockEnqueuedJobs) != 0 {
+		t.Errorf("want no superkey destroy jobs for the soft deleted source, got %d", len(mockEnqueuedJobs))
+	}
+
+	if len(mockAuditDao.AuditEvents) != 1 || mockAuditDao.AuditEvents[0].Action != "destroy" {
+		t.Errorf(`want a destroy audit event for the soft deleted source, got "%v"`, mockAuditDao.AuditEvents)
+	}
+}
+
+// TestBulkDestroyNotFound tests that nothing gets deleted when any of the resources cannot be found.
+func TestBulkDestroyNotFound(t *testing.T) {
+	cleanup := setupSuperKeyTest(true)
+	defer cleanup()
+
+	rec := bulkDestroyRequest(t, `{"sources": [1, 12345], "applications": [2]}`)
+
+	templates.NotFoundTest(t, rec)
+
+	if len(mockEnqueuedJobs) != 0 {
+		t.Errorf("want no enqueued jobs, got %d", len(mockEnqueuedJobs))
+	}
+}
+
+// TestBulkDestroyBadRequest tests that the empty requests, the invalid IDs and the requests with too many IDs get
+// rejected.
+func TestBulkDestroyBadRequest(t *testing.T) {
+	cleanup := setupSuperKeyTest(false)
+	defer cleanup()
+
+	// One more ID than a request can contain.
+	tooManyIds := strings.Repeat("1, ", bulkDestroyMaxIds) + "1"
+
+	for _, body := range []string{`{}`, `{"sources": ["abc"]}`, `{"applications": [-1]}`, `{"authentications": [""]}`, `{"sources": [` + tooManyIds + `]}`} {
+		rec := bulkDestroyRequest(t, body)
+
+		templates.BadRequestTest(t, rec)
+	}
+}
+
+// TestBulkDestroyCertAuth tests that the certificate authenticated callers cannot delete anything but satellite
+// sources.
+func TestBulkDestroyCertAuth(t *testing.T) {
+	cleanup := setupSuperKeyTest(true)
+	defer cleanup()
+
+	for _, body := range []string{`{"sources": [1]}`, `{"applications": [2]}`, `{"endpoints": [1]}`, `{"authentications": ["1"]}`} {
+		rec := bulkDestroyRequestWithContext(t, body, map[string]any{"cert-auth": true})
+
+		templates.BadRequestTest(t, rec)
+	}
+
+	if len(mockEnqueuedJobs) != 0 {
+		t.Errorf("want no enqueued jobs, got %d", len(mockEnqueuedJobs))
+	}
+}
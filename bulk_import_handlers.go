@@ -0,0 +1,185 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/jobs"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// mimeApplicationNDJSON is the media type of the newline delimited JSON documents.
+const mimeApplicationNDJSON = "application/x-ndjson"
+
+// The limits of a bulk import, which keep a single request from tying the service up.
+const (
+	bulkImportMaxBodySize  = 32 << 20
+	bulkImportMaxDocuments = 1000
+)
+
+// BulkImportCreate accepts a bulk create document and imports it in the background, in the partial success mode of
+// the bulk create. The document can either be a single JSON bulk create request, or a newline delimited JSON stream
+// of them, in which case their resources get concatenated. The returned bulk import can be polled for the progress.
+func BulkImportCreate(c echo.Context) error {
+	req, err := parseBulkImportDocument(c)
+	if err != nil {
+		return err
+	}
+
+	if len(req.Sources)+len(req.Applications)+len(req.Endpoints)+len(req.Authentications)+len(req.RhcConnections) == 0 {
+		return util.NewErrBadRequest("the bulk import document does not contain any resources")
+	}
+
+	// The request waits in the jobs queue, so the passwords must not be stored in plaintext there.
+	err = service.SealBulkCreatePasswords(req)
+	if err != nil {
+		return err
+	}
+
+	xrhid, ok := c.Get(h.XRHID).(string)
+	if !ok {
+		return fmt.Errorf("failed to pull x-rh-identity from request")
+	}
+
+	tenant, user, err := bulkCreateTenantAndUser(c)
+	if err != nil {
+		return err
+	}
+
+	forwardableHeaders, err := service.ForwadableHeaders(c)
+	if err != nil {
+		return err
+	}
+
+	bulkImport := &m.BulkImportResponse{
+		ID:        uuid.New().String(),
+		Status:    m.BulkImportQueued,
+		CreatedAt: time.Now(),
+	}
+
+	err = jobs.SaveBulkImport(c.Request().Context(), tenant.Id, bulkImport)
+	if err != nil {
+		return err
+	}
+
+	jobs.Enqueue(&jobs.BulkImportJob{
+		ID:       bulkImport.ID,
+		Headers:  forwardableHeaders,
+		Identity: xrhid,
+		Tenant:   *tenant,
+		User:     *user,
+		Request:  *req,
+	})
+
+	handlerLogEntry(c).WithFields(logrus.Fields{
+		"tenant_id":             tenant.Id,
+		"bulk_import_id":        bulkImport.ID,
+		"sources_count":         len(req.Sources),
+		"applications_count":    len(req.Applications),
+		"endpoints_count":       len(req.Endpoints),
+		"authentications_count": len(req.Authentications),
+		"rhc_connections_count": len(req.RhcConnections),
+	}).Infof("bulk import enqueued")
+
+	return c.JSON(http.StatusAccepted, bulkImport)
+}
+
+// BulkImportGet reports the progress of a bulk import, along with the outcome of the resources processed so far.
+func BulkImportGet(c echo.Context) error {
+	tenantID, ok := c.Get(h.TenantID).(int64)
+	if !ok {
+		return fmt.Errorf("failed to pull tenant from request")
+	}
+
+	id, err := uuid.Parse(c.Param("uid"))
+	if err != nil {
+		return util.NewErrBadRequest(fmt.Sprintf("invalid bulk import id: %s", err))
+	}
+
+	bulkImport, err := jobs.GetBulkImport(c.Request().Context(), tenantID, id.String())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, bulkImport)
+}
+
+// parseBulkImportDocument parses the request's body, which is either a JSON bulk create request or a newline
+// delimited JSON stream of them. The encrypted passwords get decrypted with the caller's key as the documents get
+// parsed, since every document carries the key derivation parameters. The documents of a stream must share them, so
+// that the key only gets derived once.
+func parseBulkImportDocument(c echo.Context) (*m.BulkCreateRequest, error) {
+	decrypter := service.NewBulkCreatePasswordDecrypter(c.Request().Header.Get(h.EncryptionKey))
+
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, bulkImportMaxBodySize)
+
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+	if mediaType != mimeApplicationNDJSON {
+		req := m.BulkCreateRequest{}
+
+		// the binder already turns the oversized bodies into bad requests.
+		err := c.Bind(&req)
+		if err != nil {
+			return nil, err
+		}
+
+		err = decrypter.Decrypt(&req)
+		if err != nil {
+			return nil, err
+		}
+
+		return &req, nil
+	}
+
+	req := m.BulkCreateRequest{}
+	decoder := json.NewDecoder(c.Request().Body)
+
+	for number := 1; ; number++ {
+		var document m.BulkCreateRequest
+
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if tooLarge := (&http.MaxBytesError{}); errors.As(err, &tooLarge) {
+			return nil, util.NewErrBadRequest(fmt.Sprintf("the bulk import document must not exceed %d bytes", bulkImportMaxBodySize))
+		}
+
+		if err != nil {
+			return nil, util.NewErrBadRequest(fmt.Sprintf("invalid bulk import document %d: %s", number, err))
+		}
+
+		if number > bulkImportMaxDocuments {
+			return nil, util.NewErrBadRequest(fmt.Sprintf("the bulk import must not contain more than %d documents", bulkImportMaxDocuments))
+		}
+
+		err = decrypter.Decrypt(&document)
+		if badRequest := (util.ErrBadRequest{}); errors.As(err, &badRequest) {
+			return nil, util.NewErrBadRequest(fmt.Sprintf("invalid bulk import document %d: %s", number, badRequest.Message))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		req.Sources = append(req.Sources, document.Sources...)
+		req.Applications = append(req.Applications, document.Applications...)
+		req.Endpoints = append(req.Endpoints, document.Endpoints...)
+		req.Authentications = append(req.Authentications, document.Authentications...)
+		req.RhcConnections = append(req.RhcConnections, document.RhcConnections...)
+	}
+
+	return &req, nil
+}
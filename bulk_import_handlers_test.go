@@ -0,0 +1,282 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	"github.com/RedHatInsights/sources-api-go/jobs"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+// inMemoryBulkImportKeyStore is a bulk import store which does not need a running Valkey instance.
+type inMemoryBulkImportKeyStore struct {
+	values map[string][]byte
+}
+
+func (s *inMemoryBulkImportKeyStore) Get(_ context.Context, key string) ([]byte, error) {
+	return s.values[key], nil
+}
+
+func (s *inMemoryBulkImportKeyStore) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	s.values[key] = value
+
+	return nil
+}
+
+// setupBulkImportTest replaces the bulk import store and the job queue for the duration of the test.
+func setupBulkImportTest(t *testing.T) {
+	backupStore := jobs.BulkImportStore
+	backupEnqueue := jobs.Enqueue
+
+	jobs.BulkImportStore = &inMemoryBulkImportKeyStore{values: map[string][]byte{}}
+	mockEnqueuedJobs = nil
+	jobs.Enqueue = mockEnqueue
+
+	t.Cleanup(func() {
+		jobs.BulkImportStore = backupStore
+		jobs.Enqueue = backupEnqueue
+	})
+}
+
+// bulkImportRequest sends the given bulk import document with the given content type.
+func bulkImportRequest(t *testing.T, contentType string, body string) *httptest.ResponseRecorder {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/bulk_imports",
+		strings.NewReader(body),
+		map[string]interface{}{
+			h.TenantID:       int64(1),
+			h.XRHID:          buildXRHIdentity(),
+			h.ParsedIdentity: &identity.XRHID{Identity: identity.Identity{AccountNumber: "12345"}},
+		},
+	)
+
+	c.Request().Header.Set(echo.HeaderContentType, contentType)
+
+	err := ErrorHandlingContext(BulkImportCreate)(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	return rec
+}
+
+// TestBulkImportCreate tests that the bulk import gets stored as queued and that the job gets enqueued with the
+// document.
+func TestBulkImportCreate(t *testing.T) {
+	setupBulkImportTest(t)
+
+	rec := bulkImportRequest(t, echo.MIMEApplicationJSON, `{"sources": [{"name": "a", "source_type_name": "amazon"}], "applications": [{"source_name": "a", "application_type_name": "cost"}]}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+
+	var bulkImport m.BulkImportResponse
+
+	err := json.Unmarshal(rec.Body.Bytes(), &bulkImport)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the response: %s", err)
+	}
+
+	if bulkImport.ID == "" || bulkImport.Status != m.BulkImportQueued {
+		t.Errorf(`want a queued bulk import, got "%+v"`, bulkImport)
+	}
+
+	stored, err := jobs.GetBulkImport(context.Background(), 1, bulkImport.ID)
+	if err != nil {
+		t.Fatalf("want the bulk import to be stored, got %s", err)
+	}
+
+	if stored.Status != m.BulkImportQueued {
+		t.Errorf(`want the stored bulk import to be queued, got "%s"`, stored.Status)
+	}
+
+	if len(mockEnqueuedJobs) != 1 {
+		t.Fatalf("want 1 enqueued job, got %d", len(mockEnqueuedJobs))
+	}
+
+	job, ok := mockEnqueuedJobs[0].(*jobs.BulkImportJob)
+	if !ok {
+		t.Fatalf("want a bulk import job, got %T", mockEnqueuedJobs[0])
+	}
+
+	if job.ID != bulkImport.ID || job.Tenant.Id != 1 || len(job.Request.Sources) != 1 || len(job.Request.Applications) != 1 {
+		t.Errorf(`unexpected job "%+v"`, job)
+	}
+}
+
+// TestBulkImportCreateSealsPasswords tests that the passwords do not get enqueued in plaintext.
+func TestBulkImportCreateSealsPasswords(t *testing.T) {
+	setupBulkImportTest(t)
+	util.InitializeEncryption()
+
+	rec := bulkImportRequest(t, echo.MIMEApplicationJSON, `{"sources": [{"name": "a", "source_type_name": "amazon"}], "authentications": [{"resource_type": "source", "resource_name": "a", "authtype": "arn", "password": "secret"}]}`)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+
+	if len(mockEnqueuedJobs) != 1 {
+		t.Fatalf("want 1 enqueued job, got %d", len(mockEnqueuedJobs))
+	}
+
+	job, ok := mockEnqueuedJobs[0].(*jobs.BulkImportJob)
+	if !ok {
+		t.Fatalf("want a bulk import job, got %T", mockEnqueuedJobs[0])
+	}
+
+	if strings.Contains(string(job.ToJSON()), "secret") {
+		t.Errorf(`want the password to be sealed in the enqueued job, got "%s"`, job.ToJSON())
+	}
+
+	err := service.UnsealBulkCreatePasswords(&job.Request)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if *job.Request.Authentications[0].Password != "secret" {
+		t.Errorf(`want the password to be unsealed back, got "%s"`, *job.Request.Authentications[0].Password)
+	}
+}
+
+// TestBulkImportCreateNDJSON tests that the resources of the newline delimited documents get concatenated.
+func TestBulkImportCreateNDJSON(t *testing.T) {
+	setupBulkImportTest(t)
+
+	body := `{"sources": [{"name": "a", "source_type_name": "amazon"}]}
+{"sources": [{"name": "b", "source_type_name": "amazon"}], "endpoints": [{"source_name": "b", "host": "example.com"}]}
+`
+
+	rec := bulkImportRequest(t, "application/x-ndjson", body)
+	if rec.Code != http.StatusAccepted {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusAccepted, rec.Code, rec.Body.String())
+	}
+
+	job, ok := mockEnqueuedJobs[0].(*jobs.BulkImportJob)
+	if !ok {
+		t.Fatalf("want a bulk import job, got %T", mockEnqueuedJobs[0])
+	}
+
+	if len(job.Request.Sources) != 2 || len(job.Request.Endpoints) != 1 {
+		t.Errorf(`want 2 sources and 1 endpoint, got "%+v"`, job.Request)
+	}
+
+	if *job.Request.Sources[1].Name != "b" {
+		t.Errorf(`want the documents' order to be kept, got "%s" as the second source`, *job.Request.Sources[1].Name)
+	}
+}
+
+// TestBulkImportCreateBadRequest tests that the empty, the malformed and the oversized documents get rejected, along
+// with the streams whose documents do not share their encryption parameters.
+func TestBulkImportCreateBadRequest(t *testing.T) {
+	setupBulkImportTest(t)
+
+	testCases := []struct {
+		contentType string
+		body        string
+	}{
+		{contentType: echo.MIMEApplicationJSON, body: `{}`},
+		{contentType: "application/x-ndjson", body: "{\"sources\": []}\n{\"sources\": [}\n"},
+		{contentType: "application/x-ndjson", body: ""},
+		{contentType: "application/x-ndjson", body: strings.Repeat("{\"sources\": [{\"name\": \"a\", \"source_type_name\": \"amazon\"}]}\n", bulkImportMaxDocuments+1)},
+		{contentType: echo.MIMEApplicationJSON, body: `{"sources": [{"name": "` + strings.Repeat("a", bulkImportMaxBodySize) + `"}]}`},
+		{contentType: "application/x-ndjson", body: `{"sources": [{"name": "` + strings.Repeat("a", bulkImportMaxBodySize) + `"}]}`},
+		{
+			contentType: "application/x-ndjson",
+			body: `{"sources": [{"name": "a", "source_type_name": "amazon"}], "encryption": {"algorithm": "argon2id", "salt": "AAAAAAAAAAAAAAAAAAAAAA==", "time": 1, "memory": 8, "threads": 1}}
+{"sources": [{"name": "b", "source_type_name": "amazon"}], "encryption": {"algorithm": "argon2id", "salt": "AQEBAQEBAQEBAQEBAQEBAQ==", "time": 1, "memory": 8, "threads": 1}}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		rec := bulkImportRequest(t, tc.contentType, tc.body)
+
+		templates.BadRequestTest(t, rec)
+	}
+
+	if len(mockEnqueuedJobs) != 0 {
+		t.Errorf("want no enqueued jobs, got %d", len(mockEnqueuedJobs))
+	}
+}
+
+// bulkImportGetRequest requests the given bulk import for the given tenant.
+func bulkImportGetRequest(t *testing.T, tenantID int64, id string) *httptest.ResponseRecorder {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/bulk_imports/"+id,
+		nil,
+		map[string]interface{}{
+			h.TenantID: tenantID,
+		},
+	)
+
+	c.SetParamNames("uid")
+	c.SetParamValues(id)
+
+	err := ErrorHandlingContext(BulkImportGet)(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	return rec
+}
+
+// TestBulkImportGet tests that the progress and the results of a bulk import are reported, and that the imports of
+// other tenants are not visible.
+func TestBulkImportGet(t *testing.T) {
+	setupBulkImportTest(t)
+
+	bulkImport := &m.BulkImportResponse{
+		ID:        "6f7ea3f8-0f6d-4a9b-8ea4-6cbdf5c51f3b",
+		Status:    m.BulkImportRunning,
+		Processed: 1,
+		Total:     2,
+		Results: &m.BulkCreatePartialResponse{
+			Sources: []m.BulkCreateResult{
+				{Index: 0, Status: m.BulkCreateCreated, ID: "10"},
+				{Index: 1, Status: m.BulkCreateFailed, Error: "invalid source type"},
+			},
+		},
+	}
+
+	err := jobs.SaveBulkImport(context.Background(), 1, bulkImport)
+	if err != nil {
+		t.Fatalf("unable to store the bulk import: %s", err)
+	}
+
+	rec := bulkImportGetRequest(t, 1, bulkImport.ID)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var got m.BulkImportResponse
+
+	err = json.Unmarshal(rec.Body.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the response: %s", err)
+	}
+
+	if got.Status != m.BulkImportRunning || got.Processed != 1 || got.Total != 2 {
+		t.Errorf(`unexpected progress "%+v"`, got)
+	}
+
+	if got.Results == nil || got.Results.Sources[0].ID != "10" || got.Results.Sources[1].Error == "" {
+		t.Errorf(`unexpected results "%+v"`, got.Results)
+	}
+
+	templates.NotFoundTest(t, bulkImportGetRequest(t, 2, bulkImport.ID))
+	templates.NotFoundTest(t, bulkImportGetRequest(t, 1, "0b0ee5d2-51a8-4d0e-9c5e-9a0f1e2d3c4b"))
+	templates.BadRequestTest(t, bulkImportGetRequest(t, 1, "not-a-uuid"))
+}
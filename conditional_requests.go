@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// etagger is implemented by the resources which support conditional requests.
+type etagger interface {
+	ETag() string
+}
+
+// setETag sets the entity tag of the given resource in the response.
+func setETag(c echo.Context, resource etagger) {
+	c.Response().Header().Set(h.ETag, resource.ETag())
+}
+
+// notModified sets the entity tag of the given resource in the response, and returns true when the client's
+// "If-None-Match" header matches it, meaning that a "304 Not Modified" response must be sent instead of the resource.
+func notModified(c echo.Context, resource etagger) bool {
+	etag := resource.ETag()
+	c.Response().Header().Set(h.ETag, etag)
+
+	ifNoneMatch := c.Request().Header.Get(h.IfNoneMatch)
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	return util.ETagMatches(ifNoneMatch, etag, true)
+}
+
+// sendResource sends the given resource's response along with its entity tag, or a "304 Not Modified" response when
+// the client already has the current representation.
+func sendResource(c echo.Context, resource etagger, response interface{}) error {
+	if notModified(c, resource) {
+		return c.NoContent(http.StatusNotModified)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// hasIfMatch returns true when the client sent an "If-Match" header.
+func hasIfMatch(c echo.Context) bool {
+	return c.Request().Header.Get(h.IfMatch) != ""
+}
+
+// checkIfMatch verifies that the client's "If-Match" header, if any, matches the resource's current entity tag, so
+// that updates and deletions made from stale representations are rejected.
+func checkIfMatch(c echo.Context, resource etagger) error {
+	ifMatch := c.Request().Header.Get(h.IfMatch)
+	if ifMatch == "" {
+		return nil
+	}
+
+	if !util.ETagMatches(ifMatch, resource.ETag(), false) {
+		return util.NewErrPreconditionFailed("the resource has been modified since it was last fetched")
+	}
+
+	return nil
+}
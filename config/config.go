@@ -52,6 +52,13 @@ type SourcesApiConfig struct {
 	CacheHost                string
 	CachePort                int
 	CachePassword            string
+	IdempotencyKeyTTL        int
+	BulkImportTTL            int
+	SourceRetention          int
+	GraphQLMaxComplexity     int
+	GraphQLMaxDepth          int
+	GraphQLAPQTTL            int
+	GraphQLAPQAllowlist      bool
 	SlowSQLThreshold         int
 	AuthorizedPsks           []string
 	BypassRbac               bool
@@ -94,6 +101,13 @@ func (s SourcesApiConfig) String() string {
 	fmt.Fprintf(&b, "%s=%v ", "FeatureFlagsService", s.FeatureFlagsService)
 	fmt.Fprintf(&b, "%s=%v ", "CacheHost", s.CacheHost)
 	fmt.Fprintf(&b, "%s=%v ", "CachePort", s.CachePort)
+	fmt.Fprintf(&b, "%s=%v ", "IdempotencyKeyTTL", s.IdempotencyKeyTTL)
+	fmt.Fprintf(&b, "%s=%v ", "BulkImportTTL", s.BulkImportTTL)
+	fmt.Fprintf(&b, "%s=%v ", "SourceRetention", s.SourceRetention)
+	fmt.Fprintf(&b, "%s=%v ", "GraphQLMaxComplexity", s.GraphQLMaxComplexity)
+	fmt.Fprintf(&b, "%s=%v ", "GraphQLMaxDepth", s.GraphQLMaxDepth)
+	fmt.Fprintf(&b, "%s=%v ", "GraphQLAPQTTL", s.GraphQLAPQTTL)
+	fmt.Fprintf(&b, "%s=%v ", "GraphQLAPQAllowlist", s.GraphQLAPQAllowlist)
 	fmt.Fprintf(&b, "%s=%v ", "SlowSQLThreshold", s.SlowSQLThreshold)
 	fmt.Fprintf(&b, "%s=%v ", "BypassRbac", s.BypassRbac)
 	fmt.Fprintf(&b, "%s=%v ", "SecretStore", s.SecretStore)
@@ -255,6 +269,55 @@ func Get() *SourcesApiConfig {
 
 	options.SetDefault("LogLevel", os.Getenv("LOG_LEVEL"))
 	options.SetDefault("SlowSQLThreshold", 2) //seconds
+
+	idempotencyKeyTTL, err := strconv.Atoi(os.Getenv("IDEMPOTENCY_KEY_TTL"))
+	if err != nil || idempotencyKeyTTL <= 0 {
+		idempotencyKeyTTL = 86400
+	}
+
+	options.SetDefault("IdempotencyKeyTTL", idempotencyKeyTTL) //seconds
+
+	bulkImportTTL, err := strconv.Atoi(os.Getenv("BULK_IMPORT_TTL"))
+	if err != nil || bulkImportTTL <= 0 {
+		bulkImportTTL = 604800
+	}
+
+	options.SetDefault("BulkImportTTL", bulkImportTTL) //seconds
+
+	// The deleted sources are kept for the retention window so that they can be restored. A zero retention deletes
+	// them right away.
+	sourceRetention, err := strconv.Atoi(os.Getenv("SOURCE_RETENTION"))
+	if err != nil || sourceRetention < 0 {
+		sourceRetention = 604800
+	}
+
+	options.SetDefault("SourceRetention", sourceRetention) //seconds
+
+	// The GraphQL operations which cost more than the complexity limit, or which are nested deeper than the depth
+	// limit, are rejected before they run.
+	graphQLMaxComplexity, err := strconv.Atoi(os.Getenv("GRAPHQL_MAX_COMPLEXITY"))
+	if err != nil || graphQLMaxComplexity <= 0 {
+		graphQLMaxComplexity = 100000
+	}
+
+	options.SetDefault("GraphQLMaxComplexity", graphQLMaxComplexity)
+
+	graphQLMaxDepth, err := strconv.Atoi(os.Getenv("GRAPHQL_MAX_DEPTH"))
+	if err != nil || graphQLMaxDepth <= 0 {
+		graphQLMaxDepth = 12
+	}
+
+	options.SetDefault("GraphQLMaxDepth", graphQLMaxDepth)
+
+	// The queries the clients persist with the automatic persisted queries are remembered for the TTL. In the allowlist
+	// mode only the registered queries are allowed, and the clients cannot persist any others.
+	graphQLAPQTTL, err := strconv.Atoi(os.Getenv("GRAPHQL_APQ_TTL"))
+	if err != nil || graphQLAPQTTL <= 0 {
+		graphQLAPQTTL = 86400
+	}
+
+	options.SetDefault("GraphQLAPQTTL", graphQLAPQTTL) //seconds
+	options.SetDefault("GraphQLAPQAllowlist", os.Getenv("GRAPHQL_APQ_ALLOWLIST") == "true")
 	options.SetDefault("BypassRbac", os.Getenv("BYPASS_RBAC") == "true")
 
 	switch os.Getenv("SECRET_STORE") {
@@ -291,7 +354,7 @@ func Get() *SourcesApiConfig {
 	setUpDatabase := fs.Bool("setup", false, "create the database and exit")
 	resetDatabase := fs.Bool("reset", false, "drop the database, recreate it and exit")
 
-	err := fs.Parse(os.Args[1:])
+	err = fs.Parse(os.Args[1:])
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error parsing flags: %v\n", err)
 	}
@@ -363,6 +426,13 @@ func Get() *SourcesApiConfig {
 		CacheHost:                options.GetString("CacheHost"),
 		CachePort:                options.GetInt("CachePort"),
 		CachePassword:            options.GetString("CachePassword"),
+		IdempotencyKeyTTL:        options.GetInt("IdempotencyKeyTTL"),
+		BulkImportTTL:            options.GetInt("BulkImportTTL"),
+		SourceRetention:          options.GetInt("SourceRetention"),
+		GraphQLMaxComplexity:     options.GetInt("GraphQLMaxComplexity"),
+		GraphQLMaxDepth:          options.GetInt("GraphQLMaxDepth"),
+		GraphQLAPQTTL:            options.GetInt("GraphQLAPQTTL"),
+		GraphQLAPQAllowlist:      options.GetBool("GraphQLAPQAllowlist"),
 		AuthorizedPsks:           options.GetStringSlice("AuthorizedPsks"),
 		BypassRbac:               options.GetBool("BypassRbac"),
 		StatusListener:           options.GetBool("StatusListener"),
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/logger"
@@ -131,11 +132,20 @@ func (a *applicationAuthenticationDaoImpl) List(limit int, offset int, filters [
 	count := int64(0)
 	query.Count(&count)
 
-	result := query.Limit(limit).Offset(offset).Find(&appAuths)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&appAuths)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(appAuths)
+	}
+
 	return appAuths, count, nil
 }
 
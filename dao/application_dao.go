@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -70,7 +71,11 @@ func (a *applicationDaoImpl) getDb() *gorm.DB {
 }
 
 func (a *applicationDaoImpl) getDbWithModel() *gorm.DB {
-	return a.getDb().Model(&m.Application{})
+	if a.IncludeSoftDeleted {
+		return a.getDb().Model(&m.Application{})
+	}
+
+	return withoutSoftDeletedSources(a.getDb(), "applications").Model(&m.Application{})
 }
 
 func (a *applicationDaoImpl) SubCollectionList(primaryCollection interface{}, limit int, offset int, filters []util.Filter) ([]m.Application, int64, error) {
@@ -92,11 +97,20 @@ func (a *applicationDaoImpl) SubCollectionList(primaryCollection interface{}, li
 	count := int64(0)
 	query.Model(&m.Application{}).Count(&count)
 
-	result := query.Limit(limit).Offset(offset).Find(&applications)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&applications)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(applications)
+	}
+
 	return applications, count, nil
 }
 
@@ -112,17 +126,36 @@ func (a *applicationDaoImpl) List(limit int, offset int, filters []util.Filter)
 	count := int64(0)
 	query.Count(&count)
 
-	result := query.
-		Limit(limit).
-		Offset(offset).
-		Find(&applications)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&applications)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(applications)
+	}
+
 	return applications, count, nil
 }
 
+func (a *applicationDaoImpl) Stream(filters []util.Filter, fn func(*m.Application) error) error {
+	query, err := applyFilters(a.getDbWithModel(), filters)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	return streamRows(query, fn)
+}
+
+func (a *applicationDaoImpl) Stats(groupBy []string, filters []util.Filter) ([]m.StatsGroup, error) {
+	return countBy(a.getDbWithModel(), applicationStatsDimensions, groupBy, filters)
+}
+
 func (a *applicationDaoImpl) GetById(id *int64) (*m.Application, error) {
 	var app m.Application
 
@@ -158,6 +191,29 @@ func (a *applicationDaoImpl) GetByIdWithPreload(id *int64, preloads ...string) (
 	return &app, nil
 }
 
+// ListByIdsWithPreload fetches the given applications and preloads any specified relations, which gets every relation
+// loaded with one query instead of one per application.
+func (a *applicationDaoImpl) ListByIdsWithPreload(ids []int64, preloads ...string) ([]m.Application, error) {
+	q := a.getDbWithModel().
+		Where("id IN ?", ids)
+
+	for _, preload := range preloads {
+		q = q.Preload(preload)
+	}
+
+	applications := make([]m.Application, 0, len(ids))
+
+	err := q.
+		Order("id").
+		Find(&applications).
+		Error
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	return applications, nil
+}
+
 func (a *applicationDaoImpl) Create(app *m.Application) error {
 	app.TenantID = *a.TenantID
 	err := DB.Debug().Create(app).Error
@@ -196,6 +252,19 @@ func (a *applicationDaoImpl) Update(app *m.Application) error {
 	}
 }
 
+func (a *applicationDaoImpl) UpdateIfUnmodified(app *m.Application, updatedAt time.Time) error {
+	err := updateIfUnmodified(a.getDb().Omit(clause.Associations), app, updatedAt)
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": *a.TenantID, "source_id": app.SourceID, "application_id": app.ID}).Errorf("Unable to update application: %s", err)
+
+		return err
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": *a.TenantID, "source_id": app.SourceID, "application_id": app.ID}).Info("Application updated")
+
+	return nil
+}
+
 func (a *applicationDaoImpl) Delete(id *int64) (*m.Application, error) {
 	var application m.Application
 
@@ -333,6 +402,16 @@ func (a *applicationDaoImpl) Unpause(id int64) error {
 }
 
 func (a *applicationDaoImpl) DeleteCascade(applicationId int64) ([]m.ApplicationAuthentication, *m.Application, error) {
+	return a.deleteCascade(applicationId, nil)
+}
+
+func (a *applicationDaoImpl) DeleteCascadeIfUnmodified(applicationId int64, updatedAt time.Time) ([]m.ApplicationAuthentication, *m.Application, error) {
+	return a.deleteCascade(applicationId, &updatedAt)
+}
+
+// deleteCascade deletes the application along with its application authentications. When the given time is not nil,
+// the application only gets deleted if it has not been modified since then.
+func (a *applicationDaoImpl) deleteCascade(applicationId int64, updatedAt *time.Time) ([]m.ApplicationAuthentication, *m.Application, error) {
 	var (
 		applicationAuthentications []m.ApplicationAuthentication
 		application                *m.Application
@@ -348,6 +427,14 @@ func (a *applicationDaoImpl) DeleteCascade(applicationId int64) ([]m.Application
 	err := DB.
 		Debug().
 		Transaction(func(tx *gorm.DB) error {
+			// The application stays locked until it gets deleted, so that it cannot be modified in the meantime.
+			if updatedAt != nil {
+				err := lockIfUnmodified(tx, "applications", applicationId, a.TenantID, *updatedAt)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Fetch and delete the application authentications.
 			err := tx.
 				Model(m.ApplicationAuthentication{}).
@@ -394,6 +481,9 @@ func (a *applicationDaoImpl) DeleteCascade(applicationId int64) ([]m.Application
 				return nil
 			}
 		})
+	if err != nil {
+		return nil, nil, err
+	}
 
 	// Log all the changes for observability, traceability and debugging purposes.
 	for _, appAuth := range applicationAuthentications {
@@ -402,11 +492,7 @@ func (a *applicationDaoImpl) DeleteCascade(applicationId int64) ([]m.Application
 
 	logger.Log.WithFields(logrus.Fields{"tenant_id": *a.TenantID, "source_id": application.SourceID, "application_id": applicationId}).Info("Application deleted")
 
-	if err != nil {
-		return nil, nil, err
-	}
-
-	return applicationAuthentications, application, err
+	return applicationAuthentications, application, nil
 }
 
 func (a *applicationDaoImpl) Exists(applicationId int64) (bool, error) {
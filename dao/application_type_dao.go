@@ -2,6 +2,7 @@ package dao
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/RedHatInsights/sources-api-go/config"
 	m "github.com/RedHatInsights/sources-api-go/model"
@@ -56,11 +57,20 @@ func (at *applicationTypeDaoImpl) SubCollectionList(primaryCollection interface{
 	query.Model(&m.ApplicationType{}).Count(&count)
 
 	// limiting + running the actual query.
-	result := query.Limit(limit).Offset(offset).Find(&applicationTypes)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&applicationTypes)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(applicationTypes)
+	}
+
 	return applicationTypes, count, nil
 }
 
@@ -83,11 +93,20 @@ func (at *applicationTypeDaoImpl) List(limit, offset int, filters []util.Filter)
 	query.Count(&count)
 
 	// limiting + running the actual query.
-	result := query.Limit(limit).Offset(offset).Find(&appTypes)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&appTypes)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(appTypes)
+	}
+
 	return appTypes, count, nil
 }
 
@@ -0,0 +1,92 @@
+package dao
+
+import (
+	"context"
+	"slices"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+)
+
+// GetAuditEventDao is a function definition that can be replaced in runtime in case some other DAO provider is
+// needed.
+var GetAuditEventDao func(*RequestParams) AuditEventDao
+
+// getDefaultAuditEventDao gets the default DAO implementation which will have the given tenant ID.
+func getDefaultAuditEventDao(params *RequestParams) AuditEventDao {
+	return &auditEventDaoImpl{
+		TenantID: params.TenantID,
+		UserID:   params.UserID,
+		ctx:      params.ctx,
+	}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetAuditEventDao = getDefaultAuditEventDao
+}
+
+type auditEventDaoImpl struct {
+	TenantID *int64
+	UserID   *int64
+	ctx      context.Context
+}
+
+func (a *auditEventDaoImpl) getDb() *gorm.DB {
+	return DB.Debug().WithContext(a.ctx)
+}
+
+func (a *auditEventDaoImpl) Create(auditEvent *m.AuditEvent) error {
+	auditEvent.TenantID = *a.TenantID
+	auditEvent.UserID = a.UserID
+
+	return a.getDb().Create(auditEvent).Error
+}
+
+func (a *auditEventDaoImpl) List(limit, offset int, filters []util.Filter) ([]m.AuditEvent, int64, error) {
+	query := a.getDb().
+		Model(&m.AuditEvent{}).
+		Where("audit_events.tenant_id = ?", a.TenantID)
+
+	return a.list(query, limit, offset, filters)
+}
+
+func (a *auditEventDaoImpl) ListForResource(resourceType, resourceID string, limit, offset int, filters []util.Filter) ([]m.AuditEvent, int64, error) {
+	query := a.getDb().
+		Model(&m.AuditEvent{}).
+		Where("audit_events.tenant_id = ?", a.TenantID).
+		Where("audit_events.resource_type = ?", resourceType).
+		Where("audit_events.resource_id = ?", resourceID)
+
+	return a.list(query, limit, offset, filters)
+}
+
+// list runs the given query with the filters and the pagination applied.
+func (a *auditEventDaoImpl) list(query *gorm.DB, limit, offset int, filters []util.Filter) ([]m.AuditEvent, int64, error) {
+	auditEvents := make([]m.AuditEvent, 0, limit)
+
+	query, err := applyFilters(query, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	count := int64(0)
+	query.Count(&count)
+
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&auditEvents)
+	if result.Error != nil {
+		return nil, 0, util.NewErrBadRequest(result.Error)
+	}
+
+	if isPreviousPage(filters) {
+		slices.Reverse(auditEvents)
+	}
+
+	return auditEvents, count, nil
+}
@@ -0,0 +1,69 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestAuditEventCreateAndList tests that the recorded audit events are listed for their tenant and their resource,
+// and that they can be filtered.
+func TestAuditEventCreateAndList(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("audit_events")
+
+	tenantID := fixtures.TestTenantData[0].Id
+	otherTenantID := fixtures.TestTenantData[1].Id
+
+	auditEventDao := GetAuditEventDao(&RequestParams{TenantID: &tenantID})
+	otherAuditEventDao := GetAuditEventDao(&RequestParams{TenantID: &otherTenantID})
+
+	auditEvents := []struct {
+		dao   AuditEventDao
+		event m.AuditEvent
+	}{
+		{dao: auditEventDao, event: m.AuditEvent{ActorType: m.AuditActorUser, ActorID: "a", ResourceType: "Source", ResourceID: "1", Action: m.AuditActionCreate, Changes: []byte(`{}`)}},
+		{dao: auditEventDao, event: m.AuditEvent{ActorType: m.AuditActorPSK, ActorID: "b", ResourceType: "Source", ResourceID: "1", Action: m.AuditActionUpdate, Changes: []byte(`{}`)}},
+		{dao: auditEventDao, event: m.AuditEvent{ActorType: m.AuditActorUser, ActorID: "a", ResourceType: "Application", ResourceID: "1", Action: m.AuditActionCreate, Changes: []byte(`{}`)}},
+		{dao: otherAuditEventDao, event: m.AuditEvent{ActorType: m.AuditActorUser, ActorID: "c", ResourceType: "Source", ResourceID: "1", Action: m.AuditActionDestroy, Changes: []byte(`{}`)}},
+	}
+
+	for _, ae := range auditEvents {
+		err := ae.dao.Create(&ae.event)
+		if err != nil {
+			t.Fatalf("unable to create the audit event: %s", err)
+		}
+	}
+
+	_, count, err := auditEventDao.List(100, 0, []util.Filter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if count != 3 {
+		t.Errorf("want 3 audit events for the tenant, got %d", count)
+	}
+
+	history, count, err := auditEventDao.ListForResource("Source", "1", 100, 0, []util.Filter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if count != 2 || history[0].Action != m.AuditActionCreate || history[1].Action != m.AuditActionUpdate {
+		t.Errorf(`want the source's create and update audit events, got "%+v"`, history)
+	}
+
+	filtered, _, err := auditEventDao.List(100, 0, []util.Filter{{Name: "actor_type", Value: []string{m.AuditActorPSK}}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(filtered) != 1 || filtered[0].ActorID != "b" {
+		t.Errorf(`want the PSK's audit event, got "%+v"`, filtered)
+	}
+
+	DropSchema("audit_events")
+}
@@ -3,6 +3,8 @@ package dao
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/RedHatInsights/sources-api-go/logger"
@@ -35,7 +37,11 @@ func (add *authenticationDaoDbImpl) getDb() *gorm.DB {
 }
 
 func (add *authenticationDaoDbImpl) getDbWithModel() *gorm.DB {
-	return add.getDb().Model(&m.Authentication{})
+	if add.IncludeSoftDeleted {
+		return add.getDb().Model(&m.Authentication{})
+	}
+
+	return withoutSoftDeletedSources(add.getDb(), "authentications").Model(&m.Authentication{})
 }
 
 func (add *authenticationDaoDbImpl) List(limit, offset int, filters []util.Filter) ([]m.Authentication, int64, error) {
@@ -53,15 +59,22 @@ func (add *authenticationDaoDbImpl) List(limit, offset int, filters []util.Filte
 	// limiting + running the actual query.
 	authentications := make([]m.Authentication, 0, limit)
 
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
 	err = query.
-		Limit(limit).
-		Offset(offset).
 		Find(&authentications).
 		Error
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(authentications)
+	}
+
 	return authentications, count, nil
 }
 
@@ -83,11 +96,17 @@ func (add *authenticationDaoDbImpl) ListForSource(sourceID int64, limit, offset
 	// Check that the source exists before continuing.
 	var sourceExists bool
 
-	err := DB.Debug().
+	sourceQuery := DB.Debug().
 		Model(&m.Source{}).
 		Select(`1`).
 		Where(`id = ?`, sourceID).
-		Where(`tenant_id = ?`, add.TenantID).
+		Where(`tenant_id = ?`, add.TenantID)
+
+	if !add.IncludeSoftDeleted {
+		sourceQuery = withoutSoftDeleted(sourceQuery, "")
+	}
+
+	err := sourceQuery.
 		Scan(&sourceExists).
 		Error
 	if err != nil {
@@ -115,15 +134,22 @@ func (add *authenticationDaoDbImpl) ListForSource(sourceID int64, limit, offset
 	// limiting + running the actual query.
 	authentications := make([]m.Authentication, 0, limit)
 
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
 	err = query.
-		Limit(limit).
-		Offset(offset).
 		Find(&authentications).
 		Error
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(authentications)
+	}
+
 	return authentications, count, nil
 }
 
@@ -154,27 +180,33 @@ func (add *authenticationDaoDbImpl) ListForApplication(applicationID int64, limi
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
+	query = query.
+		Where("resource_id = ?", applicationID).
+		Where("resource_type = 'Application'")
+
 	// getting the total count (filters included) for pagination
 	count := int64(0)
-	query.
-		Where("resource_id = ?", applicationID).
-		Where("resource_type = 'Application'").
-		Count(&count)
+	query.Count(&count)
 
 	// limiting + running the actual query.
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
 	authentications := make([]m.Authentication, 0, limit)
 
 	err = query.
-		Where("resource_id = ?", applicationID).
-		Where("resource_type = 'Application'").
-		Limit(limit).
-		Offset(offset).
 		Find(&authentications).
 		Error
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(authentications)
+	}
+
 	return authentications, count, nil
 }
 
@@ -228,30 +260,96 @@ func (add *authenticationDaoDbImpl) ListForEndpoint(endpointID int64, limit, off
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
+	query = query.
+		Where("resource_id = ?", endpointID).
+		Where("resource_type = 'Endpoint'")
+
 	// getting the total count (filters included) for pagination
 	count := int64(0)
-	query.
-		Where("resource_id = ?", endpointID).
-		Where("resource_type = 'Endpoint'").
-		Count(&count)
+	query.Count(&count)
 
 	// limiting + running the actual query.
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
 	authentications := make([]m.Authentication, 0, limit)
 
 	err = query.
-		Where("resource_id = ?", endpointID).
-		Where("resource_type = 'Endpoint'").
-		Limit(limit).
-		Offset(offset).
 		Find(&authentications).
 		Error
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(authentications)
+	}
+
 	return authentications, count, nil
 }
 
+func (add *authenticationDaoDbImpl) ListForSources(sourceIDs []int64) ([]m.Authentication, error) {
+	authentications := make([]m.Authentication, 0)
+
+	err := add.getDbWithModel().
+		Where("source_id IN ?", sourceIDs).
+		Order("id").
+		Find(&authentications).
+		Error
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	return authentications, nil
+}
+
+func (add *authenticationDaoDbImpl) ListSecretsForSources(sourceIDs []int64) ([]m.Authentication, error) {
+	// the passwords are stored along with the rest of the authentication.
+	return add.ListForSources(sourceIDs)
+}
+
+func (add *authenticationDaoDbImpl) ListByIds(ids []string) ([]m.Authentication, error) {
+	// the IDs are the numeric primary keys, so the ones that are not numbers cannot be found.
+	dbIds := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		dbId, err := strconv.ParseInt(id, 10, 64)
+		if err == nil {
+			dbIds = append(dbIds, dbId)
+		}
+	}
+
+	authentications := make([]m.Authentication, 0, len(dbIds))
+
+	err := add.getDbWithModel().
+		Where("id IN ?", dbIds).
+		Order("id").
+		Find(&authentications).
+		Error
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	return authentications, nil
+}
+
+func (add *authenticationDaoDbImpl) ListForApplications(applicationIDs []int64) ([]m.Authentication, error) {
+	authentications := make([]m.Authentication, 0)
+
+	err := add.getDbWithModel().
+		Where("resource_type = 'Application'").
+		Where("resource_id IN ?", applicationIDs).
+		Order("id").
+		Find(&authentications).
+		Error
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	return authentications, nil
+}
+
 func (add *authenticationDaoDbImpl) Create(authentication *m.Authentication) error {
 	query := DB.Debug().
 		Where("tenant_id = ?", *add.TenantID)
@@ -340,6 +438,51 @@ func (add *authenticationDaoDbImpl) Update(authentication *m.Authentication) err
 	}
 }
 
+// UpdateIfMatch locks the authentication's row while its entity tag gets compared, since the "authentications" table
+// does not have an "updated_at" column to make the update conditional on.
+func (add *authenticationDaoDbImpl) UpdateIfMatch(authentication *m.Authentication, etag string) error {
+	err := DB.
+		Debug().
+		WithContext(add.ctx).
+		Transaction(func(tx *gorm.DB) error {
+			query := tx.
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", authentication.DbID).
+				Where("tenant_id = ?", add.TenantID)
+
+			if add.UserID != nil {
+				query = query.Where("user_id IS NULL OR user_id = ?", add.UserID)
+			} else {
+				query = query.Where("user_id IS NULL")
+			}
+
+			var current m.Authentication
+
+			err := query.First(&current).Error
+			if err != nil {
+				return util.NewErrNotFound("authentication")
+			}
+
+			if current.ETag() != etag {
+				return errModifiedSinceFetched
+			}
+
+			return tx.
+				Omit(clause.Associations).
+				Updates(authentication).
+				Error
+		})
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": *add.TenantID, "resource_type": authentication.ResourceType, "resource_id": authentication.ResourceID}).Errorf("Unable to update authentication: %s", err)
+
+		return err
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": *add.TenantID, "resource_type": authentication.ResourceType, "resource_id": authentication.ResourceID, "authentication_id": authentication.ID}).Info("Authentication updated")
+
+	return nil
+}
+
 func (add *authenticationDaoDbImpl) Delete(id string) (*m.Authentication, error) {
 	var authentication m.Authentication
 
@@ -365,6 +508,50 @@ func (add *authenticationDaoDbImpl) Delete(id string) (*m.Authentication, error)
 	}
 }
 
+// DeleteIfMatch locks the authentication's row while its entity tag gets compared, for the same reason UpdateIfMatch
+// does.
+func (add *authenticationDaoDbImpl) DeleteIfMatch(id string, etag string) (*m.Authentication, error) {
+	var authentication m.Authentication
+
+	err := DB.
+		Debug().
+		WithContext(add.ctx).
+		Transaction(func(tx *gorm.DB) error {
+			query := tx.
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("id = ?", id).
+				Where("tenant_id = ?", add.TenantID)
+
+			if add.UserID != nil {
+				query = query.Where("user_id IS NULL OR user_id = ?", add.UserID)
+			} else {
+				query = query.Where("user_id IS NULL")
+			}
+
+			err := query.First(&authentication).Error
+			if err != nil {
+				return util.NewErrNotFound("authentication")
+			}
+
+			if authentication.ETag() != etag {
+				return errModifiedSinceFetched
+			}
+
+			return tx.
+				Delete(&authentication).
+				Error
+		})
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": *add.TenantID, "authentication_id": id}).Errorf("Unable to delete authentication: %s", err)
+
+		return nil, err
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": *add.TenantID, "resource_type": authentication.ResourceType, "resource_id": authentication.ResourceID, "authentication_id": authentication.ID}).Info("Authentication deleted")
+
+	return &authentication, nil
+}
+
 func (add *authenticationDaoDbImpl) Tenant() *int64 {
 	return add.TenantID
 }
@@ -196,6 +196,95 @@ func TestAuthenticationDbUpdate(t *testing.T) {
 	DropSchema("authentications_db")
 }
 
+// TestAuthenticationDbUpdateIfMatch tests that the conditional update only goes through when the stored
+// authentication still has the given entity tag.
+func TestAuthenticationDbUpdateIfMatch(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	testutils.SkipIfNotSecretStoreDatabase(t)
+	SwitchSchema("authentications_db")
+
+	authFixture := setUpValidAuthentication()
+
+	dao := GetAuthenticationDao(&RequestParams{TenantID: &fixtures.TestTenantData[0].Id})
+
+	err := dao.BulkCreate(authFixture)
+	if err != nil {
+		t.Errorf(`error creating the authentication: %s`, err)
+	}
+
+	fetched, err := dao.GetById(strconv.FormatInt(authFixture.DbID, 10))
+	if err != nil {
+		t.Errorf(`error fetching the authentication: %s`, err)
+	}
+
+	etag := fetched.ETag()
+
+	fetched.AuthType = "new-fresh-authtype"
+
+	err = dao.UpdateIfMatch(fetched, etag)
+	if err != nil {
+		t.Errorf(`want the unmodified authentication to be updated, got "%s"`, err)
+	}
+
+	fetched.AuthType = "stale-authtype"
+
+	err = dao.UpdateIfMatch(fetched, etag)
+	if !errors.As(err, &util.ErrPreconditionFailed{}) {
+		t.Errorf(`want a precondition failed error when updating from a stale version, got "%v"`, err)
+	}
+
+	DropSchema("authentications_db")
+}
+
+// TestAuthenticationDbDeleteIfMatch tests that the conditional delete only goes through when the stored
+// authentication still has the given entity tag.
+func TestAuthenticationDbDeleteIfMatch(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	testutils.SkipIfNotSecretStoreDatabase(t)
+	SwitchSchema("authentications_db")
+
+	authFixture := setUpValidAuthentication()
+
+	dao := GetAuthenticationDao(&RequestParams{TenantID: &fixtures.TestTenantData[0].Id})
+
+	err := dao.BulkCreate(authFixture)
+	if err != nil {
+		t.Errorf(`error creating the authentication: %s`, err)
+	}
+
+	id := strconv.FormatInt(authFixture.DbID, 10)
+
+	fetched, err := dao.GetById(id)
+	if err != nil {
+		t.Errorf(`error fetching the authentication: %s`, err)
+	}
+
+	etag := fetched.ETag()
+
+	fetched.AuthType = "new-fresh-authtype"
+
+	err = dao.Update(fetched)
+	if err != nil {
+		t.Errorf(`error updating the authentication: %s`, err)
+	}
+
+	_, err = dao.DeleteIfMatch(id, etag)
+	if !errors.As(err, &util.ErrPreconditionFailed{}) {
+		t.Errorf(`want a precondition failed error when deleting from a stale version, got "%v"`, err)
+	}
+
+	deleted, err := dao.DeleteIfMatch(id, fetched.ETag())
+	if err != nil {
+		t.Errorf(`want the unmodified authentication to be deleted, got "%s"`, err)
+	}
+
+	if deleted.DbID != authFixture.DbID {
+		t.Errorf(`incorrect authentication deleted. Want id "%d", got "%d"`, authFixture.DbID, deleted.DbID)
+	}
+
+	DropSchema("authentications_db")
+}
+
 // TestAuthenticationDbGet tests that the "delete" operation is able to delete the expected authentication.
 func TestAuthenticationDbDelete(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
@@ -32,6 +32,22 @@ func (a *noSecretStoreAuthenticationDao) ListForEndpoint(endpointID int64, limit
 	return nil, 0, m.ErrBadSecretStore
 }
 
+func (a *noSecretStoreAuthenticationDao) ListForSources(sourceIDs []int64) ([]m.Authentication, error) {
+	return nil, m.ErrBadSecretStore
+}
+
+func (a *noSecretStoreAuthenticationDao) ListForApplications(applicationIDs []int64) ([]m.Authentication, error) {
+	return nil, m.ErrBadSecretStore
+}
+
+func (a *noSecretStoreAuthenticationDao) ListSecretsForSources(sourceIDs []int64) ([]m.Authentication, error) {
+	return nil, m.ErrBadSecretStore
+}
+
+func (a *noSecretStoreAuthenticationDao) ListByIds(ids []string) ([]m.Authentication, error) {
+	return nil, m.ErrBadSecretStore
+}
+
 func (a *noSecretStoreAuthenticationDao) Create(src *m.Authentication) error {
 	return m.ErrBadSecretStore
 }
@@ -44,10 +60,18 @@ func (a *noSecretStoreAuthenticationDao) Update(src *m.Authentication) error {
 	return m.ErrBadSecretStore
 }
 
+func (a *noSecretStoreAuthenticationDao) UpdateIfMatch(src *m.Authentication, etag string) error {
+	return m.ErrBadSecretStore
+}
+
 func (a *noSecretStoreAuthenticationDao) Delete(id string) (*m.Authentication, error) {
 	return nil, m.ErrBadSecretStore
 }
 
+func (a *noSecretStoreAuthenticationDao) DeleteIfMatch(id string, etag string) (*m.Authentication, error) {
+	return nil, m.ErrBadSecretStore
+}
+
 func (a *noSecretStoreAuthenticationDao) Tenant() *int64 {
 	return new(int64)
 }
@@ -152,12 +152,71 @@ func (a *authenticationSecretsManagerDaoImpl) Update(auth *m.Authentication) err
 	return a.authenticationDaoDbImpl.Update(auth)
 }
 
+// UpdateIfMatch performs the conditional update in the database first, so that the secret in amazon is only overwritten
+// when the authentication has not been modified since it was fetched.
+func (a *authenticationSecretsManagerDaoImpl) UpdateIfMatch(auth *m.Authentication, etag string) error {
+	var password *string
+
+	if auth.Password != nil && !strings.HasPrefix(*auth.Password, config.Get().SecretsManagerPrefix) {
+		// fetch the ARN of the current password (since we overwrote it in memory)
+		arns := make([]*string, 1)
+
+		err := a.getDbWithModel().
+			Where("id = ?", auth.GetID()).
+			Limit(1).
+			Pluck("password_hash", &arns).Error
+		if err != nil {
+			return err
+		}
+
+		if *arns[0] == "" {
+			return fmt.Errorf("failed to fetch ARN for authentication %v", auth.GetID())
+		}
+
+		// set the password back to the ARN so that we don't overwrite it.
+		password = auth.Password
+		auth.Password = arns[0]
+	}
+
+	err := a.authenticationDaoDbImpl.UpdateIfMatch(auth, etag)
+	if err != nil {
+		return err
+	}
+
+	if password == nil {
+		return nil
+	}
+
+	sm, err := amazon.NewSecretsManagerClient(conf.LocalStackURL, conf.SecretsManagerAccessKey, conf.SecretsManagerSecretKey)
+	if err != nil {
+		return err
+	}
+
+	return sm.UpdateSecret(*auth.Password, *password)
+}
+
 func (a *authenticationSecretsManagerDaoImpl) Delete(id string) (*m.Authentication, error) {
 	auth, err := a.authenticationDaoDbImpl.Delete(id)
 	if err != nil {
 		return nil, err
 	}
 
+	return a.deleteSecret(auth)
+}
+
+// DeleteIfMatch performs the conditional delete in the database first, so that the secret in amazon is only deleted
+// when the authentication has not been modified since it was fetched.
+func (a *authenticationSecretsManagerDaoImpl) DeleteIfMatch(id string, etag string) (*m.Authentication, error) {
+	auth, err := a.authenticationDaoDbImpl.DeleteIfMatch(id, etag)
+	if err != nil {
+		return nil, err
+	}
+
+	return a.deleteSecret(auth)
+}
+
+// deleteSecret deletes the secret of the given, already deleted authentication from amazon.
+func (a *authenticationSecretsManagerDaoImpl) deleteSecret(auth *m.Authentication) (*m.Authentication, error) {
 	// only reach out to amazon to nuke the secret if the password exists
 	if auth.Password != nil {
 		sm, err := amazon.NewSecretsManagerClient(conf.LocalStackURL, conf.SecretsManagerAccessKey, conf.SecretsManagerSecretKey)
@@ -224,3 +283,34 @@ func (a *authenticationSecretsManagerDaoImpl) GetById(id string) (*m.Authenticat
 
 	return auth, nil
 }
+
+// ListSecretsForSources fetches the authentications of the given sources from the database, and swaps the stored ARNs
+// for the passwords from secrets manager, reusing the same client for all of them.
+func (a *authenticationSecretsManagerDaoImpl) ListSecretsForSources(sourceIDs []int64) ([]m.Authentication, error) {
+	auths, err := a.authenticationDaoDbImpl.ListForSources(sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var sm amazon.SecretsManagerClient
+
+	for i := range auths {
+		if auths[i].Password == nil {
+			continue
+		}
+
+		if sm == nil {
+			sm, err = amazon.NewSecretsManagerClient(conf.LocalStackURL, conf.SecretsManagerAccessKey, conf.SecretsManagerSecretKey)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		auths[i].Password, err = sm.GetSecret(*auths[i].Password)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return auths, nil
+}
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"fmt"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -36,6 +37,12 @@ TODO: Maybe parallelize fetching multiple records with goroutines +
 waitgroup
 */
 func (a *authenticationDaoVaultImpl) List(limit int, offset int, filters []util.Filter) ([]m.Authentication, int64, error) {
+	// Vault's keys have no ordering we could anchor a keyset on.
+	cursor, err := util.CursorFromFilters(filters)
+	if err != nil || cursor != nil {
+		return nil, 0, util.NewErrBadRequest("cursor pagination is not supported by the Vault secret store")
+	}
+
 	keys, err := a.listKeys()
 	if err != nil {
 		return nil, 0, err
@@ -53,6 +60,11 @@ func (a *authenticationDaoVaultImpl) List(limit int, offset int, filters []util.
 		return nil, int64(len(keys)), nil
 	}
 
+	hidden, err := a.hiddenSourceIds()
+	if err != nil {
+		return nil, 0, err
+	}
+
 	out := make([]m.Authentication, 0, len(keys))
 	for _, val := range keys[start:end] {
 		secret, err := a.getKey(val)
@@ -60,6 +72,11 @@ func (a *authenticationDaoVaultImpl) List(limit int, offset int, filters []util.
 			return nil, 0, err
 		}
 
+		// the keys do not tell the secret's source, so the page comes up short instead of skipping to the next one.
+		if hidden[secret.SourceID] {
+			continue
+		}
+
 		out = append(out, *secret)
 	}
 
@@ -70,7 +87,7 @@ func (a *authenticationDaoVaultImpl) List(limit int, offset int, filters []util.
 
 func (a *authenticationDaoVaultImpl) ListForSource(sourceID int64, _, _ int, _ []util.Filter) ([]m.Authentication, int64, error) {
 	// Check if sourceID exists
-	_, err := GetSourceDao(&RequestParams{TenantID: a.TenantID}).GetById(&sourceID)
+	_, err := GetSourceDao(&RequestParams{TenantID: a.TenantID, IncludeSoftDeleted: a.IncludeSoftDeleted}).GetById(&sourceID)
 	if err != nil {
 		return nil, 0, util.NewErrNotFound("source")
 	}
@@ -98,7 +115,7 @@ func (a *authenticationDaoVaultImpl) ListForSource(sourceID int64, _, _ int, _ [
 
 func (a *authenticationDaoVaultImpl) ListForApplication(applicationID int64, _, _ int, _ []util.Filter) ([]m.Authentication, int64, error) {
 	// checking if application exists first
-	_, err := GetApplicationDao(&RequestParams{TenantID: a.TenantID, UserID: a.UserID}).GetById(&applicationID)
+	_, err := GetApplicationDao(&RequestParams{TenantID: a.TenantID, UserID: a.UserID, IncludeSoftDeleted: a.IncludeSoftDeleted}).GetById(&applicationID)
 	if err != nil {
 		return nil, 0, util.NewErrNotFound("application")
 	}
@@ -169,6 +186,100 @@ func (a *authenticationDaoVaultImpl) ListForEndpoint(endpointID int64, limit, of
 	return auths, int64(len(auths)), nil
 }
 
+func (a *authenticationDaoVaultImpl) ListForSources(sourceIDs []int64) ([]m.Authentication, error) {
+	keys, err := a.listKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]m.Authentication, 0)
+
+	for _, key := range keys {
+		auth, err := a.getKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.Contains(sourceIDs, auth.SourceID) {
+			out = append(out, *auth)
+		}
+	}
+
+	return out, nil
+}
+
+func (a *authenticationDaoVaultImpl) ListSecretsForSources(sourceIDs []int64) ([]m.Authentication, error) {
+	// the secrets fetched from Vault already carry the passwords.
+	return a.ListForSources(sourceIDs)
+}
+
+func (a *authenticationDaoVaultImpl) ListForApplications(applicationIDs []int64) ([]m.Authentication, error) {
+	keys, err := a.listKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	auths := make([]m.Authentication, 0)
+
+	for _, key := range keys {
+		// The keys look like "Application_<id>_<uuid>", so the ones of other applications can be skipped without
+		// fetching them.
+		parts := strings.SplitN(key, "_", 3)
+		if len(parts) != 3 || parts[0] != "Application" {
+			continue
+		}
+
+		applicationID, err := strconv.ParseInt(parts[1], 10, 64)
+		if err != nil || !slices.Contains(applicationIDs, applicationID) {
+			continue
+		}
+
+		auth, err := a.getKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		auths = append(auths, *auth)
+	}
+
+	return auths, nil
+}
+
+func (a *authenticationDaoVaultImpl) ListByIds(ids []string) ([]m.Authentication, error) {
+	keys, err := a.listKeys()
+	if err != nil {
+		return nil, err
+	}
+
+	hidden, err := a.hiddenSourceIds()
+	if err != nil {
+		return nil, err
+	}
+
+	auths := make([]m.Authentication, 0, len(ids))
+
+	for _, key := range keys {
+		// The keys end with the authentication's ID, so only the requested ones get fetched.
+		parts := strings.SplitN(key, "_", 3)
+		if len(parts) != 3 || !slices.Contains(ids, parts[2]) {
+			continue
+		}
+
+		auth, err := a.getKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		if hidden[auth.SourceID] {
+			continue
+		}
+
+		auths = append(auths, *auth)
+	}
+
+	return auths, nil
+}
+
 func (a *authenticationDaoVaultImpl) getAuthsForAppAuth(appAuths []m.ApplicationAuthentication) ([]m.Authentication, error) {
 	out := make([]m.Authentication, len(appAuths))
 	for i, appAuth := range appAuths {
@@ -208,7 +319,31 @@ func (a *authenticationDaoVaultImpl) GetById(uid string) (*m.Authentication, err
 		return nil, util.NewErrNotFound("authentication")
 	}
 
-	return a.getKey(fullKey)
+	auth, err := a.getKey(fullKey)
+	if err != nil {
+		return nil, err
+	}
+
+	hidden, err := a.hiddenSourceIds()
+	if err != nil {
+		return nil, err
+	}
+
+	if hidden[auth.SourceID] {
+		return nil, util.NewErrNotFound("authentication")
+	}
+
+	return auth, nil
+}
+
+// hiddenSourceIds returns the IDs of the sources whose secrets are hidden, which are the soft deleted ones unless they
+// were asked for.
+func (a *authenticationDaoVaultImpl) hiddenSourceIds() (map[int64]bool, error) {
+	if a.IncludeSoftDeleted {
+		return map[int64]bool{}, nil
+	}
+
+	return softDeletedSourceIds(*a.TenantID)
 }
 
 func (a *authenticationDaoVaultImpl) Create(auth *m.Authentication) error {
@@ -314,6 +449,41 @@ func (a *authenticationDaoVaultImpl) Update(auth *m.Authentication) error {
 	return nil
 }
 
+// UpdateIfMatch relies on Vault's check-and-set, since the authentication's entity tag is built from the version it was
+// fetched at.
+func (a *authenticationDaoVaultImpl) UpdateIfMatch(auth *m.Authentication, etag string) error {
+	if auth.ETag() != etag {
+		return errModifiedSinceFetched
+	}
+
+	path := fmt.Sprintf("secret/data/%d/%s_%v_%s", *a.TenantID, auth.ResourceType, auth.ResourceID, auth.ID)
+
+	data, err := auth.ToVaultMap()
+	if err != nil {
+		return err
+	}
+
+	data["options"] = map[string]interface{}{"cas": auth.Version}
+
+	out, err := Vault.Write(path, data)
+	if err != nil {
+		if strings.Contains(err.Error(), "check-and-set parameter did not match the current version") {
+			return errModifiedSinceFetched
+		}
+
+		return err
+	}
+
+	number, ok := out.Data["version"].(json.Number)
+	if !ok {
+		return errors.New("failed to cast vault version number to string")
+	}
+
+	auth.Version = number.String()
+
+	return nil
+}
+
 func (a *authenticationDaoVaultImpl) Delete(uid string) (*m.Authentication, error) {
 	keys, err := a.listKeys()
 	if err != nil {
@@ -332,6 +502,21 @@ func (a *authenticationDaoVaultImpl) Delete(uid string) (*m.Authentication, erro
 	return nil, util.NewErrNotFound("authentication")
 }
 
+// DeleteIfMatch compares the entity tag of the stored authentication before deleting it. Vault does not support a
+// check-and-set on the metadata deletes, so a write which lands between both calls is not detected.
+func (a *authenticationDaoVaultImpl) DeleteIfMatch(uid string, etag string) (*m.Authentication, error) {
+	auth, err := a.GetById(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	if auth.ETag() != etag {
+		return nil, errModifiedSinceFetched
+	}
+
+	return a.Delete(uid)
+}
+
 func (a *authenticationDaoVaultImpl) Tenant() *int64 {
 	return a.TenantID
 }
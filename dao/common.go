@@ -3,9 +3,12 @@ package dao
 import (
 	"fmt"
 	"strings"
+	"time"
 
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 const (
@@ -149,3 +152,50 @@ func BulkMessageFromSource(source *m.Source, authentication *m.Authentication) (
 
 	return bulkMessage, nil
 }
+
+// errModifiedSinceFetched is returned by the conditional updates when the resource has been modified after the client
+// fetched it.
+var errModifiedSinceFetched = util.NewErrPreconditionFailed("the resource has been modified since it was last fetched")
+
+// updateIfUnmodified updates the given resource only when its "updated_at" column still holds the given time. The
+// condition is part of the "UPDATE" statement itself, so that a concurrent modification cannot slip in between the
+// check and the update.
+func updateIfUnmodified(query *gorm.DB, resource interface{}, updatedAt time.Time) error {
+	result := query.
+		Where("updated_at = ?", updatedAt).
+		Updates(resource)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return errModifiedSinceFetched
+	}
+
+	return nil
+}
+
+// lockIfUnmodified locks the given table's row until the transaction ends, as long as its "updated_at" column still
+// holds the given time. The deletions which run in the same transaction afterwards cannot then remove a resource that
+// got modified after the client fetched it.
+func lockIfUnmodified(tx *gorm.DB, table string, id int64, tenantId *int64, updatedAt time.Time) error {
+	var ids []int64
+
+	err := tx.
+		Table(table).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		Where("tenant_id = ?", tenantId).
+		Where("updated_at = ?", updatedAt).
+		Pluck("id", &ids).
+		Error
+	if err != nil {
+		return err
+	}
+
+	if len(ids) == 0 {
+		return errModifiedSinceFetched
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package dao
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/logger"
 	m "github.com/RedHatInsights/sources-api-go/model"
@@ -50,11 +52,20 @@ func (a *endpointDaoImpl) SubCollectionList(primaryCollection interface{}, limit
 	count := int64(0)
 	query.Model(&m.Endpoint{}).Count(&count)
 
-	result := query.Limit(limit).Offset(offset).Find(&endpoints)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&endpoints)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(endpoints)
+	}
+
 	return endpoints, count, nil
 }
 
@@ -63,6 +74,8 @@ func (a *endpointDaoImpl) List(limit int, offset int, filters []util.Filter) ([]
 	query := DB.Debug().Model(&m.Endpoint{}).
 		Where("tenant_id = ?", a.TenantID)
 
+	query = withoutSoftDeletedSources(query, "endpoints")
+
 	query, err := applyFilters(query, filters)
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
@@ -71,11 +84,20 @@ func (a *endpointDaoImpl) List(limit int, offset int, filters []util.Filter) ([]
 	count := int64(0)
 	query.Count(&count)
 
-	result := query.Limit(limit).Offset(offset).Find(&endpoints)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&endpoints)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(endpoints)
+	}
+
 	return endpoints, count, nil
 }
 
@@ -102,10 +124,12 @@ func (a *endpointDaoImpl) GetByIdWithPreload(id *int64, preloads ...string) (*m.
 func (a *endpointDaoImpl) GetById(id *int64) (*m.Endpoint, error) {
 	var endpoint m.Endpoint
 
-	err := DB.Debug().
+	query := DB.Debug().
 		Model(&m.Endpoint{}).
 		Where("id = ?", *id).
-		Where("tenant_id = ?", a.TenantID).
+		Where("tenant_id = ?", a.TenantID)
+
+	err := withoutSoftDeletedSources(query, "endpoints").
 		First(&endpoint).
 		Error
 	if err != nil {
@@ -115,6 +139,25 @@ func (a *endpointDaoImpl) GetById(id *int64) (*m.Endpoint, error) {
 	return &endpoint, nil
 }
 
+func (a *endpointDaoImpl) ListByIds(ids []int64) ([]m.Endpoint, error) {
+	endpoints := make([]m.Endpoint, 0, len(ids))
+
+	query := DB.Debug().
+		Model(&m.Endpoint{}).
+		Where("id IN ?", ids).
+		Where("tenant_id = ?", a.TenantID)
+
+	err := withoutSoftDeletedSources(query, "endpoints").
+		Order("id").
+		Find(&endpoints).
+		Error
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	return endpoints, nil
+}
+
 func (a *endpointDaoImpl) Create(endpoint *m.Endpoint) error {
 	endpoint.TenantID = *a.TenantID
 
@@ -143,15 +186,43 @@ func (a *endpointDaoImpl) Update(endpoint *m.Endpoint) error {
 	}
 }
 
+func (a *endpointDaoImpl) UpdateIfUnmodified(endpoint *m.Endpoint, updatedAt time.Time) error {
+	err := updateIfUnmodified(DB.Omit(clause.Associations), endpoint, updatedAt)
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": *a.TenantID, "source_id": endpoint.SourceID, "endpoint_id": endpoint.ID}).Errorf("Unable to update endpoint: %s", err)
+
+		return err
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": *a.TenantID, "source_id": endpoint.SourceID, "endpoint_id": endpoint.ID}).Info("Endpoint updated")
+
+	return nil
+}
+
 func (a *endpointDaoImpl) Delete(id *int64) (*m.Endpoint, error) {
+	return a.delete(id, nil)
+}
+
+func (a *endpointDaoImpl) DeleteIfUnmodified(id *int64, updatedAt time.Time) (*m.Endpoint, error) {
+	return a.delete(id, &updatedAt)
+}
+
+// delete deletes the endpoint. When the given time is not nil, the endpoint only gets deleted if it has not been
+// modified since then.
+func (a *endpointDaoImpl) delete(id *int64, updatedAt *time.Time) (*m.Endpoint, error) {
 	var endpoint m.Endpoint
 
-	result := DB.
+	query := DB.
 		Debug().
 		Clauses(clause.Returning{}).
 		Where("id = ?", id).
-		Where("tenant_id = ?", a.TenantID).
-		Delete(&endpoint)
+		Where("tenant_id = ?", a.TenantID)
+
+	if updatedAt != nil {
+		query = query.Where("updated_at = ?", *updatedAt)
+	}
+
+	result := query.Delete(&endpoint)
 
 	if result.Error != nil {
 		logger.Log.WithFields(logrus.Fields{"tenant_id": *a.TenantID, "source_id": endpoint.SourceID, "endpoint_id": *id}).Errorf("Unable to delete endpoint: %s", result.Error)
@@ -159,6 +230,10 @@ func (a *endpointDaoImpl) Delete(id *int64) (*m.Endpoint, error) {
 		return nil, fmt.Errorf(`failed to delete endpoint with id "%d": %s`, id, result.Error)
 	}
 
+	if result.RowsAffected == 0 && updatedAt != nil {
+		return nil, errModifiedSinceFetched
+	}
+
 	if result.RowsAffected == 0 {
 		return nil, util.NewErrNotFound("endpoint")
 	}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
@@ -75,6 +76,36 @@ func TestDeleteEndpointNotExists(t *testing.T) {
 	DropSchema("delete")
 }
 
+// TestDeleteEndpointIfUnmodified tests that the conditional delete only goes through when the endpoint has not been
+// modified since the given time.
+func TestDeleteEndpointIfUnmodified(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("delete_if_unmodified")
+
+	endpointDao := GetEndpointDao(&fixtures.TestSourceData[0].TenantID)
+
+	endpoint, err := endpointDao.GetById(&fixtures.TestEndpointData[0].ID)
+	if err != nil {
+		t.Errorf(`unexpected error when fetching the endpoint: %s`, err)
+	}
+
+	_, err = endpointDao.DeleteIfUnmodified(&endpoint.ID, endpoint.UpdatedAt.Add(-time.Hour))
+	if !errors.As(err, &util.ErrPreconditionFailed{}) {
+		t.Errorf(`want a precondition failed error when deleting from a stale version, got "%v"`, err)
+	}
+
+	deletedEndpoint, err := endpointDao.DeleteIfUnmodified(&endpoint.ID, endpoint.UpdatedAt)
+	if err != nil {
+		t.Errorf(`want the unmodified endpoint to be deleted, got "%s"`, err)
+	}
+
+	if deletedEndpoint.ID != endpoint.ID {
+		t.Errorf(`incorrect endpoint deleted. Want id "%d", got "%d"`, endpoint.ID, deletedEndpoint.ID)
+	}
+
+	DropSchema("delete_if_unmodified")
+}
+
 // TestEndpointExists tests whether the function exists returns true when the given endpoint exists.
 func TestEndpointExists(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"gorm.io/gorm"
 )
@@ -45,6 +46,17 @@ var allowedFilterColumns = map[string]map[string]bool{
 		"availability_status": true, "last_checked_at": true, "last_available_at": true,
 		"availability_status_error": true, "source_id": true,
 	},
+	"audit_events": {
+		"id": true, "created_at": true, "actor_type": true, "actor_id": true,
+		"resource_type": true, "resource_id": true, "action": true, "user_id": true,
+	},
+	"webhooks": {
+		"id": true, "created_at": true, "updated_at": true, "url": true, "enabled": true,
+	},
+	"webhook_deliveries": {
+		"id": true, "created_at": true, "updated_at": true, "event_type": true, "status": true,
+		"attempts": true, "next_attempt_at": true, "last_attempt_at": true, "response_status": true,
+	},
 	"meta_data": {
 		"id": true, "created_at": true, "updated_at": true,
 		"step": true, "name": true, "payload": true,
@@ -69,12 +81,89 @@ var subresourceToTable = map[string]string{
 	"source_type":      "source_types",
 	"application_type": "application_types",
 	"application":      "applications",
+	"endpoint":         "endpoints",
+	"authentication":   "authentications",
+	"rhc_connection":   "rhc_connections",
 }
 
 var subresourceToAlias = map[string]string{
 	"source_type":      `"SourceType"`,
 	"application_type": `"ApplicationType"`,
 	"application":      `"Applications"`,
+	"endpoint":         `"Endpoints"`,
+	"authentication":   `"Authentications"`,
+	"rhc_connection":   `"RhcConnections"`,
+}
+
+// subresourceJoin holds the table a subresource can be joined to, and the association which brings it in under its
+// alias.
+type subresourceJoin struct {
+	table       string
+	association string
+}
+
+var subresourceJoins = map[string]subresourceJoin{
+	"source_type":      {table: "sources", association: "SourceType"},
+	"application_type": {table: "applications", association: "ApplicationType"},
+	"application":      {table: "sources", association: "Applications"},
+}
+
+// sourceSubquery holds the tables a subresource gets looked up from, under its alias, and the condition which ties it
+// to the source.
+type sourceSubquery struct {
+	from  string
+	where string
+}
+
+// sourceSubqueries are used instead of joins for the subresources a source can have many of, so that the sources do
+// not show up once per each of them, and so that the counts stay right.
+var sourceSubqueries = map[string]sourceSubquery{
+	"endpoint": {
+		from:  `endpoints "Endpoints"`,
+		where: `"Endpoints".source_id = sources.id`,
+	},
+	"authentication": {
+		from:  `authentications "Authentications"`,
+		where: `"Authentications".source_id = sources.id`,
+	},
+	"rhc_connection": {
+		from:  `source_rhc_connections INNER JOIN rhc_connections "RhcConnections" ON "RhcConnections".id = source_rhc_connections.rhc_connection_id`,
+		where: `source_rhc_connections.source_id = sources.id`,
+	},
+}
+
+// joinSubresource joins the given subresource to the query unless it has already been joined. The subresources which
+// get looked up with subqueries are only checked. The action is what the subresource is needed for, to be able to
+// tell the caller what went wrong.
+func joinSubresource(query *gorm.DB, subresource string, alreadyJoined map[string]bool, action string) (*gorm.DB, error) {
+	if _, ok := sourceSubqueries[subresource]; ok {
+		if query.Statement.Table != "sources" {
+			return nil, fmt.Errorf("cannot %s %s subresource for table %q", action, subresource, query.Statement.Table)
+		}
+
+		// the authentications only live in the database when it is the secret store, Vault keeps them on its own.
+		if subresource == "authentication" && config.Get().SecretStore == config.VaultStore {
+			return nil, fmt.Errorf("cannot %s authentication subresource when the authentications are stored in Vault", action)
+		}
+
+		return query, nil
+	}
+
+	join, ok := subresourceJoins[subresource]
+	if !ok {
+		return nil, fmt.Errorf("invalid subresource type [%v]", subresource)
+	}
+
+	if query.Statement.Table != join.table {
+		return nil, fmt.Errorf("cannot %s %s subresource for table %q", action, subresource, query.Statement.Table)
+	}
+
+	if !alreadyJoined[subresource] {
+		query = query.Joins(join.association)
+		alreadyJoined[subresource] = true
+	}
+
+	return query, nil
 }
 
 func isColumnAllowed(table, subresource, column string) bool {
@@ -95,6 +184,12 @@ func isColumnAllowed(table, subresource, column string) bool {
 }
 
 func applyFilters(query *gorm.DB, filters []util.Filter) (*gorm.DB, error) {
+	return applyFiltersWithJoins(query, filters, make(map[string]bool))
+}
+
+// applyFiltersWithJoins applies the filters to a query which might have some subresources joined already, so that
+// they do not get joined twice.
+func applyFiltersWithJoins(query *gorm.DB, filters []util.Filter, alreadyJoined map[string]bool) (*gorm.DB, error) {
 	if query.Statement.Table == "" {
 		err := query.Statement.Parse(query.Statement.Model)
 		if err != nil {
@@ -102,48 +197,22 @@ func applyFilters(query *gorm.DB, filters []util.Filter) (*gorm.DB, error) {
 		}
 	}
 
-	var (
-		filterName    string
-		alreadyJoined = make(map[string]bool)
-	)
-
 	for _, filter := range filters {
+		// the cursor, the keyset sorting and the sparse fieldset are applied when paginating, once the count has been
+		// taken.
+		if filter.Operation == util.CursorFilterOperation || filter.Operation == util.KeysetFilterOperation || filter.Operation == util.FieldsFilterOperation {
+			continue
+		}
+
 		if filter.Operation == "sort_by" {
+			var err error
 			if filter.Subresource != "" {
-				switch filter.Subresource {
-				case "source_type":
-					if query.Statement.Table != "sources" {
-						return nil, fmt.Errorf("cannot sort by source_type subresource for table %q", query.Statement.Table)
-					}
-
-					if !alreadyJoined[filter.Subresource] {
-						query = query.Joins("SourceType")
-						alreadyJoined[filter.Subresource] = true
-					}
-				case "application_type":
-					if query.Statement.Table != "applications" {
-						return nil, fmt.Errorf("cannot sort by application_type subresource for table %q", query.Statement.Table)
-					}
-
-					if !alreadyJoined[filter.Subresource] {
-						query = query.Joins("ApplicationType")
-						alreadyJoined[filter.Subresource] = true
-					}
-				case "application":
-					if query.Statement.Table != "sources" {
-						return nil, fmt.Errorf("cannot sort by applications subresource for table %q", query.Statement.Table)
-					}
-
-					if !alreadyJoined[filter.Subresource] {
-						query = query.Joins("Applications")
-						alreadyJoined[filter.Subresource] = true
-					}
-				default:
-					return nil, fmt.Errorf("invalid subresource type [%v]", filter.Subresource)
+				query, err = joinSubresource(query, filter.Subresource, alreadyJoined, "sort by")
+				if err != nil {
+					return nil, err
 				}
 			}
 
-			var err error
 			query, err = applySortBy(query, filter)
 			if err != nil {
 				return nil, err
@@ -152,109 +221,162 @@ func applyFilters(query *gorm.DB, filters []util.Filter) (*gorm.DB, error) {
 			continue
 		}
 
-		if filter.Name != "" && !util.IsValidColumnName(filter.Name) {
-			return nil, fmt.Errorf("invalid filter parameter")
-		}
+		conditions := filterConditions{alreadyJoined: alreadyJoined}
 
-		if filter.Name != "" && !isColumnAllowed(query.Statement.Table, filter.Subresource, filter.Name) {
-			return nil, fmt.Errorf("invalid filter parameter")
+		var (
+			condition string
+			args      []interface{}
+			err       error
+		)
+
+		query, condition, args, err = conditions.build(query, filter)
+		if err != nil {
+			return nil, err
 		}
 
-		// subresource filtering!
-		if filter.Subresource != "" {
-			switch filter.Subresource {
-			case "source_type":
-				if query.Statement.Table != "sources" {
-					return nil, fmt.Errorf("cannot filter based on source_type subresource for table %q", query.Statement.Table)
-				}
+		query = query.Where(condition, args...)
 
-				if !alreadyJoined[filter.Subresource] {
-					query = query.Joins("SourceType")
-					alreadyJoined[filter.Subresource] = true
-				}
+		// distinct since IN apparently can return multiple copies.
+		if conditions.distinct {
+			query = query.Distinct()
+		}
+	}
 
-				filterName = fmt.Sprintf("%v.%v", `"SourceType"`, filter.Name)
-			case "application_type":
-				if query.Statement.Table != "applications" {
-					return nil, fmt.Errorf("cannot filter based on application_type subresource for table %q", query.Statement.Table)
-				}
+	return query, nil
+}
 
-				if !alreadyJoined[filter.Subresource] {
-					query = query.Joins("ApplicationType")
-					alreadyJoined[filter.Subresource] = true
-				}
+// filterConditions builds the SQL conditions of the filters, joining the subresources they filter on along the way.
+type filterConditions struct {
+	alreadyJoined map[string]bool
+	distinct      bool
+}
 
-				filterName = fmt.Sprintf("%v.%v", `"ApplicationType"`, filter.Name)
-			case "application":
-				if query.Statement.Table != "sources" {
-					return nil, fmt.Errorf("cannot filter based on applications subresource for table %q", query.Statement.Table)
-				}
+// build returns the SQL condition of the given filter, along with its arguments. The filter groups get their filters'
+// conditions combined and parenthesized, so that the "or" groups do not mix with the rest of the query's conditions.
+func (fc *filterConditions) build(query *gorm.DB, filter util.Filter) (*gorm.DB, string, []interface{}, error) {
+	if filter.IsGroup() {
+		if len(filter.Filters) == 0 {
+			return nil, "", nil, fmt.Errorf("bad filter, empty %s group", filter.Operation)
+		}
 
-				if !alreadyJoined[filter.Subresource] {
-					query = query.Joins(`Applications`)
-					alreadyJoined[filter.Subresource] = true
-				}
+		var (
+			conditions = make([]string, 0, len(filter.Filters))
+			args       = make([]interface{}, 0)
+		)
+
+		for _, grouped := range filter.Filters {
+			var (
+				condition   string
+				groupedArgs []interface{}
+				err         error
+			)
 
-				filterName = fmt.Sprintf("%v.%v", `"Applications"`, filter.Name)
-			default:
-				return nil, fmt.Errorf("invalid subresource type [%v]", filter.Subresource)
+			query, condition, groupedArgs, err = fc.build(query, grouped)
+			if err != nil {
+				return nil, "", nil, err
 			}
-		} else if query.Statement.Table != "" {
-			filterName = fmt.Sprintf("%v.%v", query.Statement.Table, filter.Name)
-		} else {
-			filterName = filter.Name
-		}
 
-		// this can happen sometimes via graphql.
-		if len(filter.Value) == 0 {
-			return nil, fmt.Errorf("bad filter, no value")
+			conditions = append(conditions, condition)
+			args = append(args, groupedArgs...)
 		}
 
 		switch filter.Operation {
-		case "", "eq":
-			if len(filter.Value) > 1 {
-				query = query.Where(fmt.Sprintf("%v IN ?", filterName), filter.Value)
-				// distinct since IN apparently can return multiple copies.
-				query = query.Distinct()
-			} else {
-				query = query.Where(fmt.Sprintf("%v = ?", filterName), filter.Value[0])
-			}
-		case "not_eq":
-			query = query.Where(fmt.Sprintf("%v != ?", filterName), filter.Value[0])
-		case "gt":
-			query = query.Where(fmt.Sprintf("%v > ?", filterName), filter.Value[0])
-		case "gte":
-			query = query.Where(fmt.Sprintf("%v >= ?", filterName), filter.Value[0])
-		case "lt":
-			query = query.Where(fmt.Sprintf("%v < ?", filterName), filter.Value[0])
-		case "lte":
-			query = query.Where(fmt.Sprintf("%v <= ?", filterName), filter.Value[0])
-		case "nil":
-			query = query.Where(fmt.Sprintf("%v IS NULL", filterName))
-		case "not_nil":
-			query = query.Where(fmt.Sprintf("%v IS NOT NULL", filterName))
-		case "contains":
-			query = query.Where(fmt.Sprintf("%v LIKE ?", filterName), fmt.Sprintf("%%%s%%", filter.Value[0]))
-		case "starts_with":
-			query = query.Where(fmt.Sprintf("%v LIKE ?", filterName), fmt.Sprintf("%s%%", filter.Value[0]))
-		case "ends_with":
-			query = query.Where(fmt.Sprintf("%v LIKE ?", filterName), fmt.Sprintf("%%%s", filter.Value[0]))
-		case "eq_i":
-			query = query.Where(fmt.Sprintf("LOWER(%v) = ?", filterName), strings.ToLower(filter.Value[0]))
-		case "not_eq_i":
-			query = query.Where(fmt.Sprintf("LOWER(%v) != ?", filterName), strings.ToLower(filter.Value[0]))
-		case "contains_i":
-			query = query.Where(fmt.Sprintf("%v ILIKE ?", filterName), fmt.Sprintf("%%%s%%", filter.Value[0]))
-		case "starts_with_i":
-			query = query.Where(fmt.Sprintf("%v ILIKE ?", filterName), fmt.Sprintf("%s%%", filter.Value[0]))
-		case "ends_with_i":
-			query = query.Where(fmt.Sprintf("%v ILIKE ?", filterName), fmt.Sprintf("%%%s", filter.Value[0]))
+		case util.OrFilterOperation:
+			return query, fmt.Sprintf("(%s)", strings.Join(conditions, " OR ")), args, nil
+		case util.NotFilterOperation:
+			return query, fmt.Sprintf("NOT (%s)", strings.Join(conditions, " AND ")), args, nil
 		default:
-			return nil, fmt.Errorf("unsupported operation %v", filter.Operation)
+			return query, fmt.Sprintf("(%s)", strings.Join(conditions, " AND ")), args, nil
 		}
 	}
 
-	return query, nil
+	if filter.Name != "" && !util.IsValidColumnName(filter.Name) {
+		return nil, "", nil, fmt.Errorf("invalid filter parameter")
+	}
+
+	if filter.Name != "" && !isColumnAllowed(query.Statement.Table, filter.Subresource, filter.Name) {
+		return nil, "", nil, fmt.Errorf("invalid filter parameter")
+	}
+
+	var filterName string
+
+	// subresource filtering!
+	if filter.Subresource != "" {
+		var err error
+		query, err = joinSubresource(query, filter.Subresource, fc.alreadyJoined, "filter based on")
+		if err != nil {
+			return nil, "", nil, err
+		}
+
+		filterName = fmt.Sprintf("%v.%v", subresourceToAlias[filter.Subresource], filter.Name)
+	} else if query.Statement.Table != "" {
+		filterName = fmt.Sprintf("%v.%v", query.Statement.Table, filter.Name)
+	} else {
+		filterName = filter.Name
+	}
+
+	// this can happen sometimes via graphql.
+	if len(filter.Value) == 0 {
+		return nil, "", nil, fmt.Errorf("bad filter, no value")
+	}
+
+	condition, args, err := filterCondition(filterName, filter)
+	if err != nil {
+		return nil, "", nil, err
+	}
+
+	if subquery, ok := sourceSubqueries[filter.Subresource]; ok {
+		condition = fmt.Sprintf("EXISTS (SELECT 1 FROM %s WHERE %s AND %s)", subquery.from, subquery.where, condition)
+	} else if (filter.Operation == "" || filter.Operation == "eq") && len(filter.Value) > 1 {
+		fc.distinct = true
+	}
+
+	return query, condition, args, nil
+}
+
+// filterCondition returns the SQL condition of the given filter's operation on the given column, along with its
+// arguments.
+func filterCondition(filterName string, filter util.Filter) (string, []interface{}, error) {
+	switch filter.Operation {
+	case "", "eq":
+		if len(filter.Value) > 1 {
+			return fmt.Sprintf("%v IN ?", filterName), []interface{}{filter.Value}, nil
+		}
+
+		return fmt.Sprintf("%v = ?", filterName), []interface{}{filter.Value[0]}, nil
+	case "not_eq":
+		return fmt.Sprintf("%v != ?", filterName), []interface{}{filter.Value[0]}, nil
+	case "gt":
+		return fmt.Sprintf("%v > ?", filterName), []interface{}{filter.Value[0]}, nil
+	case "gte":
+		return fmt.Sprintf("%v >= ?", filterName), []interface{}{filter.Value[0]}, nil
+	case "lt":
+		return fmt.Sprintf("%v < ?", filterName), []interface{}{filter.Value[0]}, nil
+	case "lte":
+		return fmt.Sprintf("%v <= ?", filterName), []interface{}{filter.Value[0]}, nil
+	case "nil":
+		return fmt.Sprintf("%v IS NULL", filterName), nil, nil
+	case "not_nil":
+		return fmt.Sprintf("%v IS NOT NULL", filterName), nil, nil
+	case "contains":
+		return fmt.Sprintf("%v LIKE ?", filterName), []interface{}{fmt.Sprintf("%%%s%%", filter.Value[0])}, nil
+	case "starts_with":
+		return fmt.Sprintf("%v LIKE ?", filterName), []interface{}{fmt.Sprintf("%s%%", filter.Value[0])}, nil
+	case "ends_with":
+		return fmt.Sprintf("%v LIKE ?", filterName), []interface{}{fmt.Sprintf("%%%s", filter.Value[0])}, nil
+	case "eq_i":
+		return fmt.Sprintf("LOWER(%v) = ?", filterName), []interface{}{strings.ToLower(filter.Value[0])}, nil
+	case "not_eq_i":
+		return fmt.Sprintf("LOWER(%v) != ?", filterName), []interface{}{strings.ToLower(filter.Value[0])}, nil
+	case "contains_i":
+		return fmt.Sprintf("%v ILIKE ?", filterName), []interface{}{fmt.Sprintf("%%%s%%", filter.Value[0])}, nil
+	case "starts_with_i":
+		return fmt.Sprintf("%v ILIKE ?", filterName), []interface{}{fmt.Sprintf("%s%%", filter.Value[0])}, nil
+	case "ends_with_i":
+		return fmt.Sprintf("%v ILIKE ?", filterName), []interface{}{fmt.Sprintf("%%%s", filter.Value[0])}, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported operation %v", filter.Operation)
+	}
 }
 
 func applySortBy(query *gorm.DB, filter util.Filter) (*gorm.DB, error) {
@@ -286,6 +408,18 @@ func applySortBy(query *gorm.DB, filter util.Filter) (*gorm.DB, error) {
 			dir = d
 		}
 
+		// the sources get sorted by the lowest or the highest value of their subresources, depending on the direction.
+		if subquery, ok := sourceSubqueries[filter.Subresource]; ok {
+			aggregate := "MIN"
+			if dir == "DESC" {
+				aggregate = "MAX"
+			}
+
+			orderClauses = append(orderClauses, fmt.Sprintf("(SELECT %s(%s.%s) FROM %s WHERE %s) %s", aggregate, subresourceToAlias[filter.Subresource], col, subquery.from, subquery.where, dir))
+
+			continue
+		}
+
 		tablePrefix := query.Statement.Table
 
 		if filter.Subresource != "" {
@@ -4,6 +4,11 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/RedHatInsights/sources-api-go/config"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
 	"github.com/RedHatInsights/sources-api-go/util"
 )
 
@@ -21,6 +26,10 @@ func TestIsColumnAllowed(t *testing.T) {
 		{"valid source_type subresource", "sources", "source_type", "name", true},
 		{"invalid source_type subresource column", "sources", "source_type", "password", false},
 		{"valid application column", "applications", "", "source_id", true},
+		{"valid endpoint subresource", "sources", "endpoint", "host", true},
+		{"valid authentication subresource", "sources", "authentication", "authtype", true},
+		{"invalid authentication subresource column", "sources", "authentication", "password", false},
+		{"valid rhc_connection subresource", "sources", "rhc_connection", "rhc_id", true},
 		{"unknown table", "nonexistent", "", "id", false},
 		{"empty column", "sources", "", "", false},
 		{"sql injection in column", "sources", "", "name; DROP TABLE sources", false},
@@ -45,6 +54,9 @@ func TestSubresourceToAlias(t *testing.T) {
 		{"source_type", `"SourceType"`, true},
 		{"application_type", `"ApplicationType"`, true},
 		{"application", `"Applications"`, true},
+		{"endpoint", `"Endpoints"`, true},
+		{"authentication", `"Authentications"`, true},
+		{"rhc_connection", `"RhcConnections"`, true},
 		{"nonexistent", "", false},
 	}
 
@@ -140,3 +152,179 @@ func TestSubresourceToAliasUsesQuotedIdentifiers(t *testing.T) {
 		}
 	}
 }
+
+// dryRunFilterQuery returns the SQL the given filters produce for the sources, without running it.
+func dryRunFilterQuery(t *testing.T, filters []util.Filter) (string, []interface{}, error) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query, err := applyFilters(db.Model(&m.Source{}), filters)
+	if err != nil {
+		return "", nil, err
+	}
+
+	statement := query.Find(&[]m.Source{}).Statement
+
+	return statement.SQL.String(), statement.Vars, nil
+}
+
+// TestApplyFiltersGroups tests that the "or" and "not" groups get their conditions combined and parenthesized, and that
+// they are ANDed with the rest of the filters.
+func TestApplyFiltersGroups(t *testing.T) {
+	sql, vars, err := dryRunFilterQuery(t, []util.Filter{
+		{Operation: util.OrFilterOperation, Filters: []util.Filter{
+			{Name: "availability_status", Value: []string{"unavailable"}},
+			{Name: "availability_status", Value: []string{"partially_available"}},
+		}},
+		{Subresource: "source_type", Name: "name", Value: []string{"amazon", "azure"}},
+		{Operation: util.NotFilterOperation, Filters: []util.Filter{
+			{Name: "name", Operation: "contains", Value: []string{"test"}},
+			{Operation: util.AndFilterOperation, Filters: []util.Filter{
+				{Name: "paused_at", Operation: "nil", Value: []string{""}},
+			}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := `WHERE ((sources.availability_status = $1 OR sources.availability_status = $2)) AND "SourceType".name IN ($3,$4) AND (NOT (sources.name LIKE $5 AND (sources.paused_at IS NULL)))`
+	if !strings.Contains(sql, want) {
+		t.Errorf("want the conditions %q, got %q", want, sql)
+	}
+
+	if len(vars) != 5 || vars[0] != "unavailable" || vars[1] != "partially_available" || vars[4] != "%test%" {
+		t.Errorf(`unexpected arguments "%v"`, vars)
+	}
+
+	if !strings.Contains(sql, `LEFT JOIN "source_types" "SourceType"`) {
+		t.Errorf("want the source types joined, got %q", sql)
+	}
+}
+
+// TestApplyFiltersGroupsJoinOnce tests that a subresource filtered on in several groups gets joined only once.
+func TestApplyFiltersGroupsJoinOnce(t *testing.T) {
+	sql, _, err := dryRunFilterQuery(t, []util.Filter{
+		{Operation: util.OrFilterOperation, Filters: []util.Filter{
+			{Subresource: "source_type", Name: "name", Value: []string{"amazon"}},
+			{Subresource: "source_type", Name: "vendor", Value: []string{"Microsoft"}},
+		}},
+		{Operation: util.NotFilterOperation, Filters: []util.Filter{
+			{Subresource: "source_type", Name: "category", Value: []string{"Red Hat"}},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if strings.Count(sql, "JOIN") != 1 {
+		t.Errorf("want a single join, got %q", sql)
+	}
+}
+
+// TestApplyFiltersGroupsInvalid tests that the grouped filters go through the same checks as the regular ones, and
+// that the empty groups get rejected.
+func TestApplyFiltersGroupsInvalid(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter util.Filter
+	}{
+		{"empty or group", util.Filter{Operation: util.OrFilterOperation}},
+		{"empty not group", util.Filter{Operation: util.NotFilterOperation}},
+		{"column not allowed", util.Filter{Operation: util.OrFilterOperation, Filters: []util.Filter{
+			{Name: "tenant_id", Value: []string{"1"}},
+		}}},
+		{"invalid column", util.Filter{Operation: util.NotFilterOperation, Filters: []util.Filter{
+			{Name: "name OR 1=1--", Value: []string{"1"}},
+		}}},
+		{"nested column not allowed", util.Filter{Operation: util.OrFilterOperation, Filters: []util.Filter{
+			{Operation: util.AndFilterOperation, Filters: []util.Filter{
+				{Subresource: "source_type", Name: "password", Value: []string{"1"}},
+			}},
+		}}},
+		{"unsupported operation", util.Filter{Operation: util.OrFilterOperation, Filters: []util.Filter{
+			{Name: "name", Operation: "sort_by", Value: []string{"name"}},
+		}}},
+		{"no value", util.Filter{Operation: util.OrFilterOperation, Filters: []util.Filter{
+			{Name: "name"},
+		}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := dryRunFilterQuery(t, []util.Filter{tt.filter})
+			if err == nil {
+				t.Errorf("want an error for the filter %+v, got none", tt.filter)
+			}
+		})
+	}
+}
+
+// TestApplyFiltersToManySubresources tests that the endpoints, the authentications and the rhc connections of the
+// sources get looked up for filtering and sorting with subqueries, so that the sources do not show up once per each of
+// them.
+func TestApplyFiltersToManySubresources(t *testing.T) {
+	sql, vars, err := dryRunFilterQuery(t, []util.Filter{
+		{Subresource: "endpoint", Name: "host", Value: []string{"example.com"}},
+		{Operation: util.OrFilterOperation, Filters: []util.Filter{
+			{Subresource: "rhc_connection", Name: "rhc_id", Value: []string{"a", "b"}},
+			{Subresource: "authentication", Name: "authtype", Value: []string{"token"}},
+		}},
+		{Subresource: "endpoint", Operation: "sort_by", Value: []string{"host DESC"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	wants := []string{
+		`EXISTS (SELECT 1 FROM endpoints "Endpoints" WHERE "Endpoints".source_id = sources.id AND "Endpoints".host = $1)`,
+		`EXISTS (SELECT 1 FROM source_rhc_connections INNER JOIN rhc_connections "RhcConnections" ON "RhcConnections".id = source_rhc_connections.rhc_connection_id WHERE source_rhc_connections.source_id = sources.id AND "RhcConnections".rhc_id IN ($2,$3))`,
+		`EXISTS (SELECT 1 FROM authentications "Authentications" WHERE "Authentications".source_id = sources.id AND "Authentications".authtype = $4)`,
+		`ORDER BY (SELECT MAX("Endpoints".host) FROM endpoints "Endpoints" WHERE "Endpoints".source_id = sources.id) DESC`,
+	}
+
+	for _, want := range wants {
+		if !strings.Contains(sql, want) {
+			t.Errorf("want %q in the query, got %q", want, sql)
+		}
+	}
+
+	if strings.Contains(sql, "JOIN endpoints") || strings.Contains(sql, "DISTINCT") {
+		t.Errorf("want neither joins nor distinct rows, got %q", sql)
+	}
+
+	if len(vars) != 4 {
+		t.Errorf(`unexpected arguments "%v"`, vars)
+	}
+}
+
+// TestApplyFiltersToManySubresourcesInvalid tests that the subresources can only be joined to the sources, and that
+// the authentications cannot be filtered on when they are stored in Vault.
+func TestApplyFiltersToManySubresourcesInvalid(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = applyFilters(db.Model(&m.Application{}), []util.Filter{{Subresource: "endpoint", Name: "host", Value: []string{"example.com"}}})
+	if err == nil {
+		t.Errorf("want an error when filtering the applications by their source's endpoints, got none")
+	}
+
+	originalSecretStore := conf.SecretStore
+	conf.SecretStore = config.VaultStore
+
+	t.Cleanup(func() { conf.SecretStore = originalSecretStore })
+
+	for _, filter := range []util.Filter{
+		{Subresource: "authentication", Name: "authtype", Value: []string{"token"}},
+		{Subresource: "authentication", Operation: "sort_by", Value: []string{"authtype"}},
+	} {
+		_, _, err = dryRunFilterQuery(t, []util.Filter{filter})
+		if err == nil || !strings.Contains(err.Error(), "Vault") {
+			t.Errorf(`want a Vault error for the filter %+v, got "%v"`, filter, err)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package dao
 
 import (
+	"time"
+
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
@@ -20,11 +22,21 @@ type SourceDao interface {
 	GetById(id *int64) (*m.Source, error)
 	Create(src *m.Source) error
 	Update(src *m.Source) error
+	// UpdateIfUnmodified updates the source only when it has not been modified since the given time, and returns a
+	// precondition failed error otherwise.
+	UpdateIfUnmodified(src *m.Source, updatedAt time.Time) error
 	Delete(id *int64) (*m.Source, error)
 	Tenant() *int64
 	User() *int64
 	NameExistsInCurrentTenant(name string) bool
 	GetByIdWithPreload(id *int64, preloads ...string) (*m.Source, error)
+	// ListByIdsWithPreload fetches the given sources in a single query, and preloads any specified relations.
+	ListByIdsWithPreload(ids []int64, preloads ...string) ([]m.Source, error)
+	// Stream calls the given function for every source which matches the filters, reading them from a database
+	// cursor instead of loading them all in memory.
+	Stream(filters []util.Filter, fn func(*m.Source) error) error
+	// Stats counts the sources which match the filters, grouped by the given dimensions.
+	Stats(groupBy []string, filters []util.Filter) ([]m.StatsGroup, error)
 	// ListForRhcConnection gets all the sources that are related to a given rhcConnection id.
 	ListForRhcConnection(rhcConnectionId *int64, limit, offset int, filters []util.Filter) ([]m.Source, int64, error)
 	BulkMessage(resource util.Resource) (map[string]interface{}, error)
@@ -38,8 +50,19 @@ type SourceDao interface {
 	// DeleteCascade deletes the source along with all its related sub resources. It returns all the deleted
 	// sub resources and the source itself.
 	DeleteCascade(sourceId int64) ([]m.ApplicationAuthentication, []m.Application, []m.Endpoint, []m.RhcConnection, *m.Source, error)
+	// DeleteCascadeIfUnmodified works like "DeleteCascade", but only deletes the source when it has not been modified
+	// since the given time, and returns a precondition failed error otherwise.
+	DeleteCascadeIfUnmodified(sourceId int64, updatedAt time.Time) ([]m.ApplicationAuthentication, []m.Application, []m.Endpoint, []m.RhcConnection, *m.Source, error)
 	// Exists returns true if the source exists.
 	Exists(sourceId int64) (bool, error)
+	// SoftDelete marks the source as deleted, which hides it until it gets either restored or purged once its
+	// retention window expires.
+	SoftDelete(id int64) (*m.Source, error)
+	// SoftDeleteIfUnmodified works like "SoftDelete", but only marks the source as deleted when it has not been
+	// modified since the given time, and returns a precondition failed error otherwise.
+	SoftDeleteIfUnmodified(id int64, updatedAt time.Time) (*m.Source, error)
+	// Restore brings back the given soft deleted source.
+	Restore(id int64) (*m.Source, error)
 }
 
 type ApplicationDao interface {
@@ -48,6 +71,9 @@ type ApplicationDao interface {
 	GetById(id *int64) (*m.Application, error)
 	Create(src *m.Application) error
 	Update(src *m.Application) error
+	// UpdateIfUnmodified updates the application only when it has not been modified since the given time, and returns
+	// a precondition failed error otherwise.
+	UpdateIfUnmodified(src *m.Application, updatedAt time.Time) error
 	Delete(id *int64) (*m.Application, error)
 	Tenant() *int64
 	User() *int64
@@ -59,9 +85,19 @@ type ApplicationDao interface {
 	// Unpause resumes the application.
 	Unpause(id int64) error
 	GetByIdWithPreload(id *int64, preloads ...string) (*m.Application, error)
+	// ListByIdsWithPreload fetches the given applications in a single query, and preloads any specified relations.
+	ListByIdsWithPreload(ids []int64, preloads ...string) ([]m.Application, error)
+	// Stream calls the given function for every application which matches the filters, reading them from a
+	// database cursor instead of loading them all in memory.
+	Stream(filters []util.Filter, fn func(*m.Application) error) error
+	// Stats counts the applications which match the filters, grouped by the given dimensions.
+	Stats(groupBy []string, filters []util.Filter) ([]m.StatsGroup, error)
 	IsSuperkey(id int64) bool
 	// DeleteCascade deletes the application along with all its related application authentications.
 	DeleteCascade(applicationId int64) ([]m.ApplicationAuthentication, *m.Application, error)
+	// DeleteCascadeIfUnmodified works like "DeleteCascade", but only deletes the application when it has not been
+	// modified since the given time, and returns a precondition failed error otherwise.
+	DeleteCascadeIfUnmodified(applicationId int64, updatedAt time.Time) ([]m.ApplicationAuthentication, *m.Application, error)
 	// Exists returns true if the application exists.
 	Exists(applicationId int64) (bool, error)
 }
@@ -73,10 +109,25 @@ type AuthenticationDao interface {
 	ListForApplication(applicationID int64, limit, offset int, filters []util.Filter) ([]m.Authentication, int64, error)
 	ListForApplicationAuthentication(appAuthID int64, limit, offset int, filters []util.Filter) ([]m.Authentication, int64, error)
 	ListForEndpoint(endpointID int64, limit, offset int, filters []util.Filter) ([]m.Authentication, int64, error)
+	// ListForSources fetches the authentications of all the given sources at once.
+	ListForSources(sourceIDs []int64) ([]m.Authentication, error)
+	// ListForApplications fetches the authentications of all the given applications at once.
+	ListForApplications(applicationIDs []int64) ([]m.Authentication, error)
+	// ListSecretsForSources fetches the authentications of all the given sources at once, along with their passwords
+	// from the secret store.
+	ListSecretsForSources(sourceIDs []int64) ([]m.Authentication, error)
+	// ListByIds fetches the given authentications at once. The ones that cannot be found are left out.
+	ListByIds(ids []string) ([]m.Authentication, error)
 	Create(src *m.Authentication) error
 	BulkCreate(src *m.Authentication) error
 	Update(src *m.Authentication) error
+	// UpdateIfMatch updates the authentication only when its stored representation still has the given entity tag, and
+	// returns a precondition failed error otherwise.
+	UpdateIfMatch(src *m.Authentication, etag string) error
 	Delete(id string) (*m.Authentication, error)
+	// DeleteIfMatch deletes the authentication only when its stored representation still has the given entity tag,
+	// and returns a precondition failed error otherwise.
+	DeleteIfMatch(id string, etag string) (*m.Authentication, error)
 	Tenant() *int64
 	AuthenticationsByResource(authentication *m.Authentication) ([]m.Authentication, error)
 	BulkMessage(resource util.Resource) (map[string]interface{}, error)
@@ -116,9 +167,17 @@ type EndpointDao interface {
 	List(limit, offset int, filters []util.Filter) ([]m.Endpoint, int64, error)
 	SubCollectionList(primaryCollection interface{}, limit, offset int, filters []util.Filter) ([]m.Endpoint, int64, error)
 	GetById(id *int64) (*m.Endpoint, error)
+	// ListByIds fetches the given endpoints in a single query. The ones that cannot be found are left out.
+	ListByIds(ids []int64) ([]m.Endpoint, error)
 	Create(src *m.Endpoint) error
 	Update(src *m.Endpoint) error
+	// UpdateIfUnmodified updates the endpoint only when it has not been modified since the given time, and returns a
+	// precondition failed error otherwise.
+	UpdateIfUnmodified(src *m.Endpoint, updatedAt time.Time) error
 	Delete(id *int64) (*m.Endpoint, error)
+	// DeleteIfUnmodified deletes the endpoint only when it has not been modified since the given time, and returns a
+	// precondition failed error otherwise.
+	DeleteIfUnmodified(id *int64, updatedAt time.Time) (*m.Endpoint, error)
 	Tenant() *int64
 	// CanEndpointBeSetAsDefaultForSource checks if the endpoint can be set as default, by checking if the given source
 	// id already has another endpoint marked as default.
@@ -166,11 +225,31 @@ type RhcConnectionDao interface {
 	GetById(id *int64) (*m.RhcConnection, error)
 	Create(rhcConnection *m.RhcConnection) (*m.RhcConnection, error)
 	Update(rhcConnection *m.RhcConnection) error
+	// UpdateIfUnmodified updates the connection only when it has not been modified since the given time, and returns a
+	// precondition failed error otherwise.
+	UpdateIfUnmodified(rhcConnection *m.RhcConnection, updatedAt time.Time) error
 	Delete(id *int64) (*m.RhcConnection, error)
+	// DeleteIfUnmodified deletes the connection only when it has not been modified since the given time, and returns a
+	// precondition failed error otherwise.
+	DeleteIfUnmodified(id *int64, updatedAt time.Time) (*m.RhcConnection, error)
 	// ListForSource gets all the related connections to the given source id.
 	ListForSource(sourceId *int64, limit, offset int, filters []util.Filter) ([]m.RhcConnection, int64, error)
 }
 
+type WebhookDao interface {
+	List(limit, offset int, filters []util.Filter) ([]m.Webhook, int64, error)
+	GetById(id *int64) (*m.Webhook, error)
+	Create(webhook *m.Webhook) error
+	Update(webhook *m.Webhook) error
+	Delete(id *int64) (*m.Webhook, error)
+	// ListForEventType returns the enabled webhooks which are subscribed to the given event type.
+	ListForEventType(eventType string) ([]m.Webhook, error)
+	// CreateDeliveries queues the given deliveries for the background worker to send them.
+	CreateDeliveries(deliveries []m.WebhookDelivery) error
+	// ListDeliveries returns the delivery log of the given webhook.
+	ListDeliveries(webhookID int64, limit, offset int, filters []util.Filter) ([]m.WebhookDelivery, int64, error)
+}
+
 type TenantDao interface {
 	// GetOrCreateTenant returns the tenant associated with the provided identity. It tries to fetch the tenant by its
 	// OrgId, and if it is not present, by its EBS account number.
@@ -192,3 +271,12 @@ type TenantDao interface {
 type UserDao interface {
 	FindOrCreate(userID string) (*m.User, error)
 }
+
+type AuditEventDao interface {
+	// Create records the given audit event for the tenant.
+	Create(auditEvent *m.AuditEvent) error
+	// List gets the audit events of the tenant.
+	List(limit, offset int, filters []util.Filter) ([]m.AuditEvent, int64, error)
+	// ListForResource gets the audit events of the given resource.
+	ListForResource(resourceType, resourceID string, limit, offset int, filters []util.Filter) ([]m.AuditEvent, int64, error)
+}
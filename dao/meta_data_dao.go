@@ -1,6 +1,7 @@
 package dao
 
 import (
+	"slices"
 	"strings"
 
 	m "github.com/RedHatInsights/sources-api-go/model"
@@ -47,11 +48,20 @@ func (md *metaDataDaoImpl) SubCollectionList(primaryCollection interface{}, limi
 	count := int64(0)
 	query.Model(&m.MetaData{}).Count(&count)
 
-	result := query.Limit(limit).Offset(offset).Find(&metadatas)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&metadatas)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(metadatas)
+	}
+
 	return metadatas, count, result.Error
 }
 
@@ -67,11 +77,20 @@ func (md *metaDataDaoImpl) List(limit int, offset int, filters []util.Filter) ([
 	count := int64(0)
 	query.Count(&count)
 
-	result := query.Limit(limit).Offset(offset).Find(&metaData)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&metaData)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(metaData)
+	}
+
 	return metaData, count, nil
 }
 
@@ -0,0 +1,145 @@
+package dao
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// paginate limits the query to the requested page. By default the page is selected with the given limit and offset,
+// but when the filters carry a keyset cursor the page is anchored on the cursor's "(created_at, id)" tuple instead,
+// which keeps the pages stable when the rows change between requests. The lists which are not sorted by the client get
+// sorted by the same tuple on every page, so that the cursors of their first pages point to the right items. It must
+// be called after the count has been taken, since the keyset condition would otherwise be counted too.
+//
+// The pages fetched from a "previous" cursor come in descending order, so the caller is expected to reverse them
+// back when "isPreviousPage" returns true.
+//
+// When the filters carry a sparse fieldset, only the columns backing the requested fields get selected.
+func paginate(query *gorm.DB, limit, offset int, filters []util.Filter) (*gorm.DB, error) {
+	query, err := selectFields(query, util.FieldsFromFilters(filters))
+	if err != nil {
+		return nil, err
+	}
+
+	cursor, err := util.CursorFromFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	if !util.IsKeysetSorted(filters) {
+		return query.Limit(limit).Offset(offset), nil
+	}
+
+	if query.Statement.Table == "" {
+		err := query.Statement.Parse(query.Statement.Model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse statement: %v", err)
+		}
+	}
+
+	table := query.Statement.Table
+	hasCreatedAt := allowedFilterColumns[table]["created_at"]
+
+	// The "Reorder" flag replaces any previous ordering, like the default
+	// "sort_by" one, since the keyset only holds when the rows are sorted by
+	// the keyset columns.
+	var orderColumns []clause.OrderByColumn
+
+	if hasCreatedAt {
+		orderColumns = append(orderColumns, clause.OrderByColumn{Column: clause.Column{Table: table, Name: "created_at"}, Desc: cursor.IsPrevious(), Reorder: true})
+	}
+
+	orderColumns = append(orderColumns, clause.OrderByColumn{Column: clause.Column{Table: table, Name: "id"}, Desc: cursor.IsPrevious(), Reorder: len(orderColumns) == 0})
+	query = query.Order(clause.OrderBy{Columns: orderColumns})
+
+	if cursor == nil {
+		return query.Limit(limit).Offset(offset), nil
+	}
+
+	comparison := ">"
+	if cursor.Previous {
+		comparison = "<"
+	}
+
+	if hasCreatedAt {
+		if cursor.CreatedAt == nil {
+			return nil, errors.New("invalid cursor")
+		}
+
+		query = query.Where(fmt.Sprintf(`("%[1]s"."created_at", "%[1]s"."id") %[2]s (?, ?)`, table, comparison), *cursor.CreatedAt, cursor.ID)
+	} else {
+		query = query.Where(fmt.Sprintf(`"%s"."id" %s ?`, table, comparison), cursor.ID)
+	}
+
+	return query.Limit(limit), nil
+}
+
+// isPreviousPage returns true when the filters carry a cursor which points to the page before its anchor item.
+func isPreviousPage(filters []util.Filter) bool {
+	cursor, err := util.CursorFromFilters(filters)
+	if err != nil {
+		return false
+	}
+
+	return cursor.IsPrevious()
+}
+
+// selectFields narrows the selected columns down to the ones backing the given sparse fieldset. The "id",
+// "created_at" and "updated_at" columns are always selected, since the cursors and the links depend on them. The
+// query is left untouched when it already selects its own columns, or when any of the fields is not backed by a
+// column of the same name, since the field could otherwise be computed out of the wrong values.
+func selectFields(query *gorm.DB, fields []string) (*gorm.DB, error) {
+	if len(fields) == 0 || len(query.Statement.Selects) > 0 {
+		return query, nil
+	}
+
+	if _, ok := query.Statement.Clauses["SELECT"]; ok {
+		return query, nil
+	}
+
+	if query.Statement.Schema == nil {
+		if query.Statement.Model == nil {
+			return query, nil
+		}
+
+		err := query.Statement.Parse(query.Statement.Model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse statement: %v", err)
+		}
+	}
+
+	var (
+		columns  []string
+		selected = make(map[string]bool)
+	)
+
+	for _, column := range []string{"id", "created_at", "updated_at"} {
+		if _, ok := query.Statement.Schema.FieldsByDBName[column]; ok {
+			columns = append(columns, column)
+			selected[column] = true
+		}
+	}
+
+	for _, field := range fields {
+		if selected[field] {
+			continue
+		}
+
+		if _, ok := query.Statement.Schema.FieldsByDBName[field]; !ok {
+			return query, nil
+		}
+
+		columns = append(columns, field)
+		selected[field] = true
+	}
+
+	for i, column := range columns {
+		columns[i] = fmt.Sprintf(`"%s"."%s"`, query.Statement.Table, column)
+	}
+
+	return query.Select(columns), nil
+}
@@ -10,7 +10,10 @@ import (
 type RequestParams struct {
 	TenantID *int64
 	UserID   *int64
-	ctx      context.Context
+	// IncludeSoftDeleted lets the jobs which clean up the soft deleted sources reach the resources of those sources,
+	// which are hidden otherwise.
+	IncludeSoftDeleted bool
+	ctx                context.Context
 }
 
 func NewRequestParamsFromContext(c echo.Context) (*RequestParams, error) {
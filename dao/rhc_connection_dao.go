@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/dao/mappers"
 	"github.com/RedHatInsights/sources-api-go/logger"
@@ -48,6 +50,9 @@ func (s *rhcConnectionDaoImpl) List(limit, offset int, filters []util.Filter) ([
 		Where(`"jt"."tenant_id" = ?`, s.TenantID).
 		Group(`"rhc_connections"."id"`)
 
+	// the links to the soft deleted sources are hidden, and so are the connections which are only linked to them.
+	query = withoutSoftDeletedSources(query, "jt")
+
 	query, err := applyFilters(query, filters)
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
@@ -57,8 +62,13 @@ func (s *rhcConnectionDaoImpl) List(limit, offset int, filters []util.Filter) ([
 	count := int64(0)
 	query.Count(&count)
 
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
 	// Run the actual query.
-	result, err := query.Limit(limit).Offset(offset).Rows()
+	result, err := query.Rows()
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
@@ -94,6 +104,10 @@ func (s *rhcConnectionDaoImpl) List(limit, offset int, filters []util.Filter) ([
 		return nil, 0, err
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(rhcConnections)
+	}
+
 	return rhcConnections, count, nil
 }
 
@@ -106,6 +120,8 @@ func (s *rhcConnectionDaoImpl) GetById(id *int64) (*m.RhcConnection, error) {
 		Where(`"jt"."tenant_id" = ?`, s.TenantID).
 		Group(`"rhc_connections"."id"`)
 
+	query = withoutSoftDeletedSources(query, "jt")
+
 	// Run the actual query.
 	result, err := query.Rows()
 	if err != nil {
@@ -154,6 +170,7 @@ func (s *rhcConnectionDaoImpl) Create(rhcConnection *m.RhcConnection) (*m.RhcCon
 		Select(`1`).
 		Where(`id = ?`, rhcConnection.Sources[0].ID).
 		Where(`tenant_id = ?`, s.TenantID).
+		Where(`deleted_at IS NULL`).
 		Scan(&sourceExists).
 		Error
 
@@ -242,7 +259,30 @@ func (s *rhcConnectionDaoImpl) Update(rhcConnection *m.RhcConnection) error {
 	}
 }
 
+func (s *rhcConnectionDaoImpl) UpdateIfUnmodified(rhcConnection *m.RhcConnection, updatedAt time.Time) error {
+	err := updateIfUnmodified(s.getDb().Omit(clause.Associations).Select("*"), rhcConnection, updatedAt)
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": *s.TenantID, "source_ids": rhcConnection.Sources, "rhc_connection_id": rhcConnection.ID}).Errorf("Unable to update RHC connection: %s", err)
+
+		return err
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": *s.TenantID, "source_ids": rhcConnection.Sources, "rhc_connection_id": rhcConnection.ID}).Info("RHC connection updated")
+
+	return nil
+}
+
 func (s *rhcConnectionDaoImpl) Delete(id *int64) (*m.RhcConnection, error) {
+	return s.delete(id, nil)
+}
+
+func (s *rhcConnectionDaoImpl) DeleteIfUnmodified(id *int64, updatedAt time.Time) (*m.RhcConnection, error) {
+	return s.delete(id, &updatedAt)
+}
+
+// delete deletes the connection. When the given time is not nil, the connection only gets deleted if it has not been
+// modified since then.
+func (s *rhcConnectionDaoImpl) delete(id *int64, updatedAt *time.Time) (*m.RhcConnection, error) {
 	var rhcConnection m.RhcConnection
 
 	// Check if rhc connection exists for given tenant
@@ -253,15 +293,22 @@ func (s *rhcConnectionDaoImpl) Delete(id *int64) (*m.RhcConnection, error) {
 
 	// The foreign key and the "cascade on delete" in the join table takes care of deleting the related
 	// "source_rhc_connection" row.
-	result := s.getDb().
+	query := s.getDb().
 		Clauses(clause.Returning{}).
-		Where("id = ?", id).
-		Delete(&rhcConnection)
+		Where("id = ?", id)
+
+	if updatedAt != nil {
+		query = query.Where("updated_at = ?", *updatedAt)
+	}
+
+	result := query.Delete(&rhcConnection)
 
 	if result.Error != nil {
 		logger.Log.WithFields(logrus.Fields{"tenant_id": *s.TenantID, "source_ids": rhcConnection.Sources, "rhc_connection_id": *id}).Errorf("Unable to delete RHC connection: %s", err)
 
 		return nil, fmt.Errorf(`failed to delete rhcConnection with id "%d": %s`, id, result.Error)
+	} else if result.RowsAffected == 0 && updatedAt != nil {
+		return nil, errModifiedSinceFetched
 	} else {
 		logger.Log.WithFields(logrus.Fields{"tenant_id": *s.TenantID, "source_ids": rhcConnection.Sources, "rhc_connection_id": rhcConnection.ID}).Info("RHC connection deleted")
 
@@ -278,6 +325,8 @@ func (s *rhcConnectionDaoImpl) ListForSource(sourceId *int64, limit, offset int,
 		Where(`"sr"."source_id" = ?`, sourceId).
 		Where(`"sr"."tenant_id" = ?`, s.TenantID)
 
+	query = withoutSoftDeletedSources(query, "sr")
+
 	query, err := applyFilters(query, filters)
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
@@ -288,10 +337,19 @@ func (s *rhcConnectionDaoImpl) ListForSource(sourceId *int64, limit, offset int,
 	query.Count(&count)
 
 	// Run the actual query.
-	err = query.Limit(limit).Offset(offset).Find(&rhcConnections).Error
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	err = query.Find(&rhcConnections).Error
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(rhcConnections)
+	}
+
 	return rhcConnections, count, nil
 }
@@ -2,6 +2,7 @@ package dao
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/RedHatInsights/sources-api-go/logger"
 	m "github.com/RedHatInsights/sources-api-go/model"
@@ -118,15 +119,22 @@ func (secret *secretDaoDbImpl) List(limit, offset int, filters []util.Filter) ([
 
 	secrets := make([]m.Authentication, 0, limit)
 
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
 	err = query.
-		Limit(limit).
-		Offset(offset).
 		Find(&secrets).
 		Error
 	if err != nil {
 		return nil, 0, util.NewErrBadRequest(err)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(secrets)
+	}
+
 	return secrets, count, nil
 }
 
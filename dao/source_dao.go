@@ -2,9 +2,12 @@ package dao
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"time"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/logger"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
@@ -62,12 +65,56 @@ func (s *sourceDaoImpl) useUserForDB(query *gorm.DB, table string) *gorm.DB {
 	return query
 }
 
+// withoutSoftDeleted hides the soft deleted sources, which are only kept around until their retention window expires
+// so that they can be restored.
+func withoutSoftDeleted(query *gorm.DB, table string) *gorm.DB {
+	var whereCondition string
+	if table != "" {
+		whereCondition = fmt.Sprintf("%s.", table)
+	}
+
+	return query.Where(whereCondition + "deleted_at IS NULL")
+}
+
+// withoutSoftDeletedSources hides the resources of the soft deleted sources, which are hidden along with their source.
+// The given table must have a "source_id" column.
+func withoutSoftDeletedSources(query *gorm.DB, table string) *gorm.DB {
+	return query.Where(fmt.Sprintf(`NOT EXISTS (SELECT 1 FROM "sources" AS "soft_deleted_sources" WHERE "soft_deleted_sources"."id" = "%s"."source_id" AND "soft_deleted_sources"."deleted_at" IS NOT NULL)`, table))
+}
+
+// softDeletedSourceIds returns the IDs of the given tenant's soft deleted sources, for the secret stores which cannot
+// hide their resources with a query.
+func softDeletedSourceIds(tenantId int64) (map[int64]bool, error) {
+	var ids []int64
+
+	err := DB.Debug().
+		Model(&m.Source{}).
+		Where("tenant_id = ?", tenantId).
+		Where("deleted_at IS NOT NULL").
+		Pluck("id", &ids).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		out[id] = true
+	}
+
+	return out, nil
+}
+
 func (s *sourceDaoImpl) getDb() *gorm.DB {
 	return s.getDbWithTable(DB.Debug().WithContext(s.ctx), "")
 }
 
 func (s *sourceDaoImpl) getDbWithModel() *gorm.DB {
-	return s.getDb().Model(&m.Source{})
+	if s.IncludeSoftDeleted {
+		return s.getDb().Model(&m.Source{})
+	}
+
+	return withoutSoftDeleted(s.getDb(), "").Model(&m.Source{})
 }
 
 func (s *sourceDaoImpl) useDbWithModel(query *gorm.DB) *gorm.DB {
@@ -86,7 +133,7 @@ func (s *sourceDaoImpl) SubCollectionList(primaryCollection interface{}, limit,
 
 	query := relationObject.HasMany(&m.Source{}, DB.Debug())
 
-	query = s.getDbWithTable(query, "sources")
+	query = withoutSoftDeleted(s.getDbWithTable(query, "sources"), "sources")
 
 	query, err = applyFilters(query, filters)
 	if err != nil {
@@ -98,17 +145,26 @@ func (s *sourceDaoImpl) SubCollectionList(primaryCollection interface{}, limit,
 	query.Model(&m.Source{}).Count(&count)
 
 	// limiting + running the actual query.
-	result := query.Limit(limit).Offset(offset).Find(&sources)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&sources)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(sources)
+	}
+
 	return sources, count, nil
 }
 
 func (s *sourceDaoImpl) List(limit, offset int, filters []util.Filter) ([]m.Source, int64, error) {
 	sources := make([]m.Source, 0, limit)
-	query := s.getDbWithTable(DB.Debug(), "sources").Model(&m.Source{})
+	query := withoutSoftDeleted(s.getDbWithTable(DB.Debug(), "sources"), "sources").Model(&m.Source{})
 
 	query, err := applyFilters(query, filters)
 	if err != nil {
@@ -120,18 +176,43 @@ func (s *sourceDaoImpl) List(limit, offset int, filters []util.Filter) ([]m.Sour
 	query.Count(&count)
 
 	// limiting + running the actual query.
-	result := query.Limit(limit).Offset(offset).Find(&sources)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&sources)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(sources)
+	}
+
 	return sources, count, nil
 }
 
+func (s *sourceDaoImpl) Stream(filters []util.Filter, fn func(*m.Source) error) error {
+	query := withoutSoftDeleted(s.getDbWithTable(DB.Debug(), "sources"), "sources").Model(&m.Source{})
+
+	query, err := applyFilters(query, filters)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	return streamRows(query, fn)
+}
+
+func (s *sourceDaoImpl) Stats(groupBy []string, filters []util.Filter) ([]m.StatsGroup, error) {
+	return countBy(withoutSoftDeleted(s.getDbWithTable(DB.Debug(), "sources"), "sources").Model(&m.Source{}), sourceStatsDimensions, groupBy, filters)
+}
+
 func (s *sourceDaoImpl) ListInternal(limit, offset int, filters []util.Filter, skipEmptySources bool) ([]m.Source, int64, error) {
 	query := DB.Debug().
 		Model(&m.Source{}).
-		Select(`sources.id, sources.availability_status, "Tenant".external_tenant, "Tenant".org_id`)
+		Select(`sources.id, sources.availability_status, "Tenant".external_tenant, "Tenant".org_id`).
+		Where("sources.deleted_at IS NULL")
 
 	query, err := applyFilters(query, filters)
 	if err != nil {
@@ -231,6 +312,29 @@ func (s *sourceDaoImpl) GetByIdWithPreload(id *int64, preloads ...string) (*m.So
 	return &src, nil
 }
 
+// ListByIdsWithPreload fetches the given sources and preloads any specified relations, which gets every relation
+// loaded with one query instead of one per source.
+func (s *sourceDaoImpl) ListByIdsWithPreload(ids []int64, preloads ...string) ([]m.Source, error) {
+	q := s.getDbWithModel().
+		Where("id IN ?", ids)
+
+	for _, preload := range preloads {
+		q = q.Preload(preload)
+	}
+
+	sources := make([]m.Source, 0, len(ids))
+
+	err := q.
+		Order("id").
+		Find(&sources).
+		Error
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	return sources, nil
+}
+
 func (s *sourceDaoImpl) Create(src *m.Source) error {
 	src.TenantID = *s.TenantID // the TenantID gets injected in the middleware
 	result := DB.Debug().Create(src)
@@ -260,6 +364,19 @@ func (s *sourceDaoImpl) Update(src *m.Source) error {
 	}
 }
 
+func (s *sourceDaoImpl) UpdateIfUnmodified(src *m.Source, updatedAt time.Time) error {
+	err := updateIfUnmodified(s.getDb().Omit(clause.Associations), src, updatedAt)
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": s.TenantID, "source_id": src.ID}).Errorf(`Unable to update source: %s`, err)
+
+		return err
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": s.TenantID, "source_id": src.ID}).Info("Source updated")
+
+	return nil
+}
+
 func (s *sourceDaoImpl) Delete(id *int64) (*m.Source, error) {
 	var source m.Source
 
@@ -283,6 +400,109 @@ func (s *sourceDaoImpl) Delete(id *int64) (*m.Source, error) {
 	return &source, nil
 }
 
+func (s *sourceDaoImpl) SoftDelete(id int64) (*m.Source, error) {
+	return s.softDelete(id, nil)
+}
+
+func (s *sourceDaoImpl) SoftDeleteIfUnmodified(id int64, updatedAt time.Time) (*m.Source, error) {
+	return s.softDelete(id, &updatedAt)
+}
+
+// softDelete marks the source as deleted. When the given time is not nil, the source only gets marked if it has not
+// been modified since then.
+func (s *sourceDaoImpl) softDelete(id int64, updatedAt *time.Time) (*m.Source, error) {
+	var source m.Source
+
+	query := withoutSoftDeleted(s.getDb(), "").
+		Model(&source).
+		Clauses(clause.Returning{}).
+		Where("id = ?", id)
+
+	if updatedAt != nil {
+		query = query.Where("updated_at = ?", *updatedAt)
+	}
+
+	result := query.Update("deleted_at", time.Now())
+
+	if result.Error != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": *s.TenantID, "source_id": id}).Errorf(`Unable to soft delete source: %s`, result.Error)
+
+		return nil, fmt.Errorf(`failed to soft delete source with id "%d": %w`, id, result.Error)
+	}
+
+	if result.RowsAffected == 0 && updatedAt != nil {
+		return nil, errModifiedSinceFetched
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, util.NewErrNotFound("source")
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": *s.TenantID, "source_id": id}).Info("Source soft deleted")
+
+	return &source, nil
+}
+
+func (s *sourceDaoImpl) Restore(id int64) (*m.Source, error) {
+	var source m.Source
+
+	err := DB.Debug().WithContext(s.ctx).Transaction(func(tx *gorm.DB) error {
+		// The source is locked so that it cannot be restored twice at the same time.
+		err := s.useDbWithModel(tx).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			Where("deleted_at IS NOT NULL").
+			// the purge job may not have caught up with the sources whose retention window has expired yet.
+			Where("deleted_at > ?", time.Now().Add(-time.Duration(config.Get().SourceRetention)*time.Second)).
+			First(&source).
+			Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return util.NewErrNotFound("deleted source")
+		}
+
+		if err != nil {
+			return err
+		}
+
+		// A new source may have taken the deleted source's name during the retention window, and the names must be
+		// unique, the same way "ValidateSourceCreationRequest" enforces it.
+		var nameTaken bool
+
+		err = withoutSoftDeleted(s.useDbWithModel(tx), "").
+			Select("1").
+			Where("name = ?", source.Name).
+			Where("id <> ?", id).
+			Scan(&nameTaken).
+			Error
+		if err != nil {
+			return err
+		}
+
+		if nameTaken {
+			return util.NewErrConflict(fmt.Sprintf(`a source with the name "%s" already exists`, source.Name))
+		}
+
+		return tx.
+			Model(&source).
+			Clauses(clause.Returning{}).
+			Update("deleted_at", nil).
+			Error
+	})
+	if errors.As(err, &util.ErrNotFound{}) || errors.As(err, &util.ErrConflict{}) {
+		return nil, err
+	}
+
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": *s.TenantID, "source_id": id}).Errorf(`Unable to restore source: %s`, err)
+
+		return nil, fmt.Errorf(`failed to restore source with id "%d": %w`, id, err)
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": *s.TenantID, "source_id": id}).Info("Source restored")
+
+	return &source, nil
+}
+
 func (s *sourceDaoImpl) User() *int64 {
 	return s.UserID
 }
@@ -386,7 +606,7 @@ func (s *sourceDaoImpl) ListForRhcConnection(rhcConnectionId *int64, limit, offs
 		Where(`"sr"."rhc_connection_id" = ?`, rhcConnectionId).
 		Where(`"sr"."tenant_id" = ?`, s.TenantID)
 
-	query = s.useUserForDB(query, "sources")
+	query = withoutSoftDeleted(s.useUserForDB(query, "sources"), "sources")
 
 	query, err := applyFilters(query, filters)
 	if err != nil {
@@ -398,11 +618,20 @@ func (s *sourceDaoImpl) ListForRhcConnection(rhcConnectionId *int64, limit, offs
 	query.Count(&count)
 
 	// Run the actual query.
-	err = query.Limit(limit).Offset(offset).Find(&sources).Error
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	err = query.Find(&sources).Error
 	if err != nil {
 		return nil, count, util.NewErrBadRequest(err)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(sources)
+	}
+
 	return sources, count, nil
 }
 
@@ -473,6 +702,16 @@ func (s *sourceDaoImpl) Unpause(id int64) error {
 }
 
 func (s *sourceDaoImpl) DeleteCascade(sourceId int64) ([]m.ApplicationAuthentication, []m.Application, []m.Endpoint, []m.RhcConnection, *m.Source, error) {
+	return s.deleteCascade(sourceId, nil)
+}
+
+func (s *sourceDaoImpl) DeleteCascadeIfUnmodified(sourceId int64, updatedAt time.Time) ([]m.ApplicationAuthentication, []m.Application, []m.Endpoint, []m.RhcConnection, *m.Source, error) {
+	return s.deleteCascade(sourceId, &updatedAt)
+}
+
+// deleteCascade deletes the source along with its dependants. When the given time is not nil, the source only gets
+// deleted if it has not been modified since then.
+func (s *sourceDaoImpl) deleteCascade(sourceId int64, updatedAt *time.Time) ([]m.ApplicationAuthentication, []m.Application, []m.Endpoint, []m.RhcConnection, *m.Source, error) {
 	var (
 		applicationAuthentications []m.ApplicationAuthentication
 		applications               []m.Application
@@ -490,6 +729,14 @@ func (s *sourceDaoImpl) DeleteCascade(sourceId int64) ([]m.ApplicationAuthentica
 	err := DB.
 		Debug().
 		Transaction(func(tx *gorm.DB) error {
+			// The source stays locked until it gets deleted, so that it cannot be modified in the meantime.
+			if updatedAt != nil {
+				err := lockIfUnmodified(tx, "sources", sourceId, s.TenantID, *updatedAt)
+				if err != nil {
+					return err
+				}
+			}
+
 			// Fetch and delete the application authentications.
 			err := tx.
 				Model(&m.ApplicationAuthentication{}).
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
 	m "github.com/RedHatInsights/sources-api-go/model"
@@ -716,6 +717,214 @@ func TestSourceNotExists(t *testing.T) {
 	DropSchema("exists")
 }
 
+// TestSourceSoftDeleteAndRestore tests that the soft deleted sources are hidden until they get restored.
+func TestSourceSoftDeleteAndRestore(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("soft_delete")
+
+	sourceDao := GetSourceDao(&RequestParams{TenantID: &fixtures.TestTenantData[0].Id})
+	sourceID := fixtures.TestSourceData[0].ID
+
+	deleted, err := sourceDao.SoftDelete(sourceID)
+	if err != nil {
+		t.Errorf(`unexpected error when soft deleting the source: %s`, err)
+	}
+
+	if deleted.ID != sourceID || deleted.DeletedAt == nil {
+		t.Errorf(`want source "%d" marked as deleted, got source "%d" deleted at "%v"`, sourceID, deleted.ID, deleted.DeletedAt)
+	}
+
+	_, err = sourceDao.GetById(&sourceID)
+	if !errors.As(err, &util.ErrNotFound{}) {
+		t.Errorf(`want the soft deleted source to be hidden, got "%v"`, err)
+	}
+
+	sources, _, err := sourceDao.List(100, 0, []util.Filter{})
+	if err != nil {
+		t.Errorf(`unexpected error when listing the sources: %s`, err)
+	}
+
+	for _, src := range sources {
+		if src.ID == sourceID {
+			t.Errorf(`want the soft deleted source "%d" to be hidden from the list`, sourceID)
+		}
+	}
+
+	_, err = sourceDao.SoftDelete(sourceID)
+	if !errors.As(err, &util.ErrNotFound{}) {
+		t.Errorf(`want a not found error when soft deleting the source twice, got "%v"`, err)
+	}
+
+	// the resources of the soft deleted source are hidden along with it.
+	_, err = GetApplicationDao(&RequestParams{TenantID: &fixtures.TestTenantData[0].Id}).GetById(&fixtures.TestApplicationData[0].ID)
+	if !errors.As(err, &util.ErrNotFound{}) {
+		t.Errorf(`want the soft deleted source's application to be hidden, got "%v"`, err)
+	}
+
+	_, err = GetEndpointDao(&fixtures.TestTenantData[0].Id).GetById(&fixtures.TestEndpointData[0].ID)
+	if !errors.As(err, &util.ErrNotFound{}) {
+		t.Errorf(`want the soft deleted source's endpoint to be hidden, got "%v"`, err)
+	}
+
+	_, err = GetApplicationDao(&RequestParams{TenantID: &fixtures.TestTenantData[0].Id, IncludeSoftDeleted: true}).GetById(&fixtures.TestApplicationData[0].ID)
+	if err != nil {
+		t.Errorf(`want the soft deleted source's application to be reachable when asked for, got "%s"`, err)
+	}
+
+	restored, err := sourceDao.Restore(sourceID)
+	if err != nil {
+		t.Errorf(`unexpected error when restoring the source: %s`, err)
+	}
+
+	if restored.ID != sourceID || restored.DeletedAt != nil {
+		t.Errorf(`want source "%d" restored, got source "%d" deleted at "%v"`, sourceID, restored.ID, restored.DeletedAt)
+	}
+
+	_, err = sourceDao.GetById(&sourceID)
+	if err != nil {
+		t.Errorf(`want the restored source to be visible again, got "%s"`, err)
+	}
+
+	_, err = sourceDao.Restore(sourceID)
+	if !errors.As(err, &util.ErrNotFound{}) {
+		t.Errorf(`want a not found error when restoring a source which is not deleted, got "%v"`, err)
+	}
+
+	// the sources whose retention window has expired cannot be restored, even if they have not been purged yet.
+	expired := time.Now().Add(-time.Duration(config.Get().SourceRetention+60) * time.Second)
+
+	err = DB.Model(&m.Source{}).Where("id = ?", sourceID).Update("deleted_at", expired).Error
+	if err != nil {
+		t.Errorf(`unexpected error when expiring the source: %s`, err)
+	}
+
+	_, err = sourceDao.Restore(sourceID)
+	if !errors.As(err, &util.ErrNotFound{}) {
+		t.Errorf(`want a not found error when restoring a source whose retention window expired, got "%v"`, err)
+	}
+
+	DropSchema("soft_delete")
+}
+
+// TestSourceRestoreNameTaken tests that a soft deleted source cannot be restored when a new source took its name.
+func TestSourceRestoreNameTaken(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("restore_name_taken")
+
+	tenantId := fixtures.TestTenantData[0].Id
+	sourceDao := GetSourceDao(&RequestParams{TenantID: &tenantId})
+	sourceID := fixtures.TestSourceData[0].ID
+
+	_, err := sourceDao.SoftDelete(sourceID)
+	if err != nil {
+		t.Errorf(`unexpected error when soft deleting the source: %s`, err)
+	}
+
+	newSource := m.Source{
+		Name:         fixtures.TestSourceData[0].Name,
+		SourceTypeID: fixtures.TestSourceTypeData[0].Id,
+		TenantID:     tenantId,
+		Uid:          util.StringRef("restore-name-taken"),
+	}
+
+	err = sourceDao.Create(&newSource)
+	if err != nil {
+		t.Errorf(`unexpected error when creating the source with the deleted source's name: %s`, err)
+	}
+
+	_, err = sourceDao.Restore(sourceID)
+	if !errors.As(err, &util.ErrConflict{}) {
+		t.Errorf(`want a conflict error when restoring a source whose name was taken, got "%v"`, err)
+	}
+
+	_, err = sourceDao.GetById(&sourceID)
+	if !errors.As(err, &util.ErrNotFound{}) {
+		t.Errorf(`want the source to stay deleted, got "%v"`, err)
+	}
+
+	DropSchema("restore_name_taken")
+}
+
+// TestSourceUpdateIfUnmodified tests that the conditional update only goes through when the source has not been
+// modified since the given time.
+func TestSourceUpdateIfUnmodified(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("update_if_unmodified")
+
+	sourceDao := GetSourceDao(&RequestParams{TenantID: &fixtures.TestTenantData[0].Id})
+
+	src, err := sourceDao.GetById(&fixtures.TestSourceData[0].ID)
+	if err != nil {
+		t.Errorf(`unexpected error when fetching the source: %s`, err)
+	}
+
+	fetchedAt := src.UpdatedAt
+
+	src.Name = "updated-if-unmodified"
+
+	err = sourceDao.UpdateIfUnmodified(src, fetchedAt)
+	if err != nil {
+		t.Errorf(`want the unmodified source to be updated, got "%s"`, err)
+	}
+
+	src.Name = "updated-from-a-stale-version"
+
+	err = sourceDao.UpdateIfUnmodified(src, fetchedAt)
+	if !errors.As(err, &util.ErrPreconditionFailed{}) {
+		t.Errorf(`want a precondition failed error when updating from a stale version, got "%v"`, err)
+	}
+
+	got, err := sourceDao.GetById(&fixtures.TestSourceData[0].ID)
+	if err != nil {
+		t.Errorf(`unexpected error when fetching the source: %s`, err)
+	}
+
+	if got.Name != "updated-if-unmodified" {
+		t.Errorf(`want the source's name to be "updated-if-unmodified", got "%s"`, got.Name)
+	}
+
+	DropSchema("update_if_unmodified")
+}
+
+// TestSourceDeleteCascadeIfUnmodified tests that the conditional cascade delete only goes through when the source has
+// not been modified since the given time.
+func TestSourceDeleteCascadeIfUnmodified(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("delete_cascade_if_unmodified")
+
+	sourceDao := GetSourceDao(&RequestParams{TenantID: &fixtures.TestTenantData[0].Id})
+
+	src, err := sourceDao.GetById(&fixtures.TestSourceData[0].ID)
+	if err != nil {
+		t.Errorf(`unexpected error when fetching the source: %s`, err)
+	}
+
+	_, _, _, _, _, err = sourceDao.DeleteCascadeIfUnmodified(src.ID, src.UpdatedAt.Add(-time.Hour))
+	if !errors.As(err, &util.ErrPreconditionFailed{}) {
+		t.Errorf(`want a precondition failed error when deleting from a stale version, got "%v"`, err)
+	}
+
+	exists, err := sourceDao.Exists(src.ID)
+	if err != nil {
+		t.Errorf(`unexpected error when checking the source: %s`, err)
+	}
+
+	if !exists {
+		t.Errorf(`want the source to be kept when deleting from a stale version`)
+	}
+
+	_, _, _, _, deletedSource, err := sourceDao.DeleteCascadeIfUnmodified(src.ID, src.UpdatedAt)
+	if err != nil {
+		t.Errorf(`want the unmodified source to be deleted, got "%s"`, err)
+	}
+
+	if deletedSource.ID != src.ID {
+		t.Errorf(`incorrect source deleted. Want id "%d", got "%d"`, src.ID, deletedSource.ID)
+	}
+
+	DropSchema("delete_cascade_if_unmodified")
+}
+
 // TestSourceSubcollectionListWithOffsetAndLimit tests that SubCollectionList() in source dao returns
 // correct count value and correct count of returned objects
 func TestSourceSubcollectionListWithOffsetAndLimit(t *testing.T) {
@@ -1273,3 +1482,103 @@ func TestSourceSubcollectionWithUserOwnership(t *testing.T) {
 
 	DropSchema(schema)
 }
+
+// TestSourceStream tests that the streamed sources are the same ones the list returns for the same filters.
+func TestSourceStream(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("stream")
+
+	sourceDao := GetSourceDao(&RequestParams{TenantID: &fixtures.TestTenantData[0].Id})
+
+	filters := []util.Filter{
+		{Name: "availability_status", Value: []string{"available"}},
+		{Operation: "sort_by", Value: []string{"id ASC"}},
+	}
+
+	want, _, err := sourceDao.List(1000, 0, filters)
+	if err != nil {
+		t.Fatalf(`unexpected error when listing the sources: %s`, err)
+	}
+
+	var got []int64
+
+	err = sourceDao.Stream(filters, func(src *m.Source) error {
+		got = append(got, src.ID)
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf(`unexpected error when streaming the sources: %s`, err)
+	}
+
+	if len(got) != len(want) {
+		t.Fatalf(`want %d streamed sources, got %d`, len(want), len(got))
+	}
+
+	for i := range want {
+		if want[i].ID != got[i] {
+			t.Errorf(`want the source "%d" streamed in position %d, got "%d"`, want[i].ID, i, got[i])
+		}
+	}
+
+	// the iteration stops at the first error.
+	stopErr := errors.New("stop")
+	streamed := 0
+
+	err = sourceDao.Stream(filters, func(_ *m.Source) error {
+		streamed++
+
+		return stopErr
+	})
+	if !errors.Is(err, stopErr) || streamed != 1 {
+		t.Errorf(`want the stream to stop at the first error, got "%v" after %d sources`, err, streamed)
+	}
+
+	DropSchema("stream")
+}
+
+// TestSourceListSubresourceFilters tests that the sources can be filtered on their endpoints and rhc connections, and
+// that the sources with several matching subresources only show up once.
+func TestSourceListSubresourceFilters(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("subresource_filters")
+
+	sourceDao := GetSourceDao(&RequestParams{TenantID: &fixtures.TestTenantData[0].Id})
+
+	testCases := []struct {
+		filter util.Filter
+		want   []int64
+	}{
+		{filter: util.Filter{Subresource: "endpoint", Name: "host", Value: []string{*fixtures.TestEndpointData[0].Host}}, want: []int64{1}},
+		{filter: util.Filter{Subresource: "endpoint", Name: "host", Operation: "ends_with", Value: []string{"example.com"}}, want: []int64{1, 2}},
+		{filter: util.Filter{Subresource: "rhc_connection", Name: "rhc_id", Value: []string{"a", "b", "c"}}, want: []int64{1, 2}},
+		{filter: util.Filter{Operation: util.NotFilterOperation, Filters: []util.Filter{
+			{Subresource: "rhc_connection", Name: "rhc_id", Value: []string{"c"}},
+		}}, want: []int64{1}},
+	}
+
+	for _, tc := range testCases {
+		sources, count, err := sourceDao.List(100, 0, []util.Filter{
+			tc.filter,
+			{Name: "id", Value: []string{"1", "2"}},
+			{Operation: "sort_by", Value: []string{"id ASC"}},
+		})
+		if err != nil {
+			t.Fatalf(`unexpected error for the filter %+v: %s`, tc.filter, err)
+		}
+
+		if count != int64(len(tc.want)) || len(sources) != len(tc.want) {
+			t.Errorf(`want %d sources for the filter %+v, got %d (count %d)`, len(tc.want), tc.filter, len(sources), count)
+
+			continue
+		}
+
+		for i, id := range tc.want {
+			if sources[i].ID != id {
+				t.Errorf(`want the source "%d" in position %d for the filter %+v, got "%d"`, id, i, tc.filter, sources[i].ID)
+			}
+		}
+	}
+
+	DropSchema("subresource_filters")
+}
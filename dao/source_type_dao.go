@@ -3,6 +3,7 @@ package dao
 import (
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
+	"slices"
 )
 
 // GetSourceTypeDao is a function definition that can be replaced in runtime in case some other DAO provider is
@@ -38,11 +39,20 @@ func (st *sourceTypeDaoImpl) List(limit, offset int, filters []util.Filter) ([]m
 	query.Count(&count)
 
 	// limiting + running the actual query.
-	result := query.Limit(limit).Offset(offset).Find(&sourceTypes)
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&sourceTypes)
 	if result.Error != nil {
 		return nil, 0, util.NewErrBadRequest(result.Error)
 	}
 
+	if isPreviousPage(filters) {
+		slices.Reverse(sourceTypes)
+	}
+
 	return sourceTypes, count, nil
 }
 
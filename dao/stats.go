@@ -0,0 +1,134 @@
+package dao
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/gorm"
+)
+
+// statsDimension is a dimension the resources can be counted by, with the column it groups on and the subresource
+// that column belongs to, if any.
+type statsDimension struct {
+	column      string
+	subresource string
+}
+
+var sourceStatsDimensions = map[string]statsDimension{
+	"availability_status":   {column: "sources.availability_status"},
+	"app_creation_workflow": {column: "sources.app_creation_workflow"},
+	"source_type":           {column: `"SourceType".name`, subresource: "source_type"},
+}
+
+var applicationStatsDimensions = map[string]statsDimension{
+	"availability_status": {column: "applications.availability_status"},
+	"application_type":    {column: `"ApplicationType".name`, subresource: "application_type"},
+}
+
+// countBy counts the rows of the query which match the filters with a "GROUP BY" on the columns of the given
+// dimensions. Without dimensions a single group holds the count of every matching row.
+func countBy(query *gorm.DB, dimensions map[string]statsDimension, groupBy []string, filters []util.Filter) ([]m.StatsGroup, error) {
+	query, err := statsQuery(query, dimensions, groupBy, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := query.Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := make([]m.StatsGroup, 0)
+	for rows.Next() {
+		var (
+			values = make([]*string, len(groupBy))
+			group  = m.StatsGroup{Dimensions: make(map[string]*string, len(groupBy))}
+			dest   = make([]interface{}, 0, len(groupBy)+1)
+		)
+
+		for i := range values {
+			dest = append(dest, &values[i])
+		}
+
+		err = rows.Scan(append(dest, &group.Count)...)
+		if err != nil {
+			return nil, err
+		}
+
+		for i, name := range groupBy {
+			group.Dimensions[name] = values[i]
+		}
+
+		groups = append(groups, group)
+	}
+
+	return groups, rows.Err()
+}
+
+// statsQuery returns the "GROUP BY" query which selects the values of the given dimensions along with the count of
+// the rows which match the filters. The filtered rows are grouped from a subquery, since the joined subresources bring
+// their own columns along.
+func statsQuery(query *gorm.DB, dimensions map[string]statsDimension, groupBy []string, filters []util.Filter) (*gorm.DB, error) {
+	if query.Statement.Table == "" {
+		err := query.Statement.Parse(query.Statement.Model)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse statement: %v", err)
+		}
+	}
+
+	var (
+		alreadyJoined = make(map[string]bool)
+		columns       = []string{fmt.Sprintf("%s.id AS id", query.Statement.Table)}
+		groupColumns  = make([]string, 0, len(groupBy))
+	)
+
+	for _, name := range groupBy {
+		dimension, ok := dimensions[name]
+		if !ok {
+			return nil, util.NewErrBadRequest(fmt.Sprintf("invalid group_by dimension %q", name))
+		}
+
+		if slices.Contains(groupColumns, name) {
+			return nil, util.NewErrBadRequest(fmt.Sprintf("duplicated group_by dimension %q", name))
+		}
+
+		if dimension.subresource != "" {
+			var err error
+			query, err = joinSubresource(query, dimension.subresource, alreadyJoined, "group by")
+			if err != nil {
+				return nil, util.NewErrBadRequest(err)
+			}
+		}
+
+		columns = append(columns, fmt.Sprintf("%s AS %s", dimension.column, name))
+		groupColumns = append(groupColumns, name)
+	}
+
+	// the sorting does not apply to the groups.
+	unsorted := make([]util.Filter, 0, len(filters))
+	for _, filter := range filters {
+		if filter.Operation != "sort_by" {
+			unsorted = append(unsorted, filter)
+		}
+	}
+
+	query, err := applyFiltersWithJoins(query, unsorted, alreadyJoined)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	// the joined subresources could make the rows repeat, hence the distinct count.
+	stats := query.Session(&gorm.Session{NewDB: true}).
+		Table("(?) AS stats", query.Select(strings.Join(columns, ", "))).
+		Select(strings.Join(append(groupColumns, "COUNT(DISTINCT id) AS count"), ", "))
+
+	if len(groupColumns) > 0 {
+		stats = stats.Group(strings.Join(groupColumns, ", ")).Order(strings.Join(groupColumns, ", "))
+	}
+
+	return stats, nil
+}
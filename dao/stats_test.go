@@ -0,0 +1,95 @@
+package dao
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// TestStatsQuery tests that the filtered rows get grouped by the requested dimensions, that the subresources get
+// joined once and that the sorting is left out.
+func TestStatsQuery(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query, err := statsQuery(db.Model(&m.Source{}), sourceStatsDimensions, []string{"availability_status", "source_type"}, []util.Filter{
+		{Subresource: "source_type", Name: "name", Value: []string{"amazon", "azure"}},
+		{Operation: "sort_by", Value: []string{"id ASC"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	sql := query.Find(&[]m.StatsGroup{}).Statement.SQL.String()
+
+	wants := []string{
+		`SELECT availability_status, source_type, COUNT(DISTINCT id) AS count FROM (SELECT DISTINCT sources.id AS id, sources.availability_status AS availability_status, "SourceType".name AS source_type`,
+		`WHERE "SourceType".name IN ($1,$2)) AS stats GROUP BY availability_status, source_type ORDER BY availability_status, source_type`,
+	}
+
+	for _, want := range wants {
+		if !strings.Contains(sql, want) {
+			t.Errorf("want %q in the query, got %q", want, sql)
+		}
+	}
+
+	if strings.Count(sql, "JOIN") != 1 || strings.Contains(sql, "sources.id ASC") {
+		t.Errorf("want a single join and no sorting, got %q", sql)
+	}
+}
+
+// TestStatsQueryInvalidDimensions tests that the unknown and the repeated dimensions get rejected.
+func TestStatsQueryInvalidDimensions(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, groupBy := range [][]string{{"tenant_id"}, {"application_type"}, {"availability_status", "availability_status"}} {
+		_, err = statsQuery(db.Model(&m.Source{}), sourceStatsDimensions, groupBy, nil)
+		if err == nil {
+			t.Errorf("want an error when grouping the sources by %v, got none", groupBy)
+		}
+	}
+}
+
+// TestSourceStats tests that the sources get counted by their availability status.
+func TestSourceStats(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("stats")
+
+	sourceDao := GetSourceDao(&RequestParams{TenantID: &fixtures.TestTenantData[0].Id})
+
+	groups, err := sourceDao.Stats([]string{"availability_status"}, []util.Filter{})
+	if err != nil {
+		t.Fatalf(`unexpected error when counting the sources: %s`, err)
+	}
+
+	want := make(map[string]int64)
+	for _, src := range fixtures.TestSourceData {
+		if src.TenantID == fixtures.TestTenantData[0].Id {
+			want[src.AvailabilityStatus]++
+		}
+	}
+
+	if len(groups) != len(want) {
+		t.Fatalf(`want %d groups, got "%+v"`, len(want), groups)
+	}
+
+	for _, group := range groups {
+		status := util.ValueOrBlank(group.Dimensions["availability_status"])
+		if want[status] != group.Count {
+			t.Errorf(`want %d sources with the "%s" status, got %d`, want[status], status, group.Count)
+		}
+	}
+
+	DropSchema("stats")
+}
@@ -0,0 +1,32 @@
+package dao
+
+import (
+	"gorm.io/gorm"
+)
+
+// streamRows runs the query and calls the given function for every row, which gets read from the database cursor
+// one at a time instead of loading the whole result set in memory. The iteration stops at the first error, which is
+// returned.
+func streamRows[T any](query *gorm.DB, fn func(*T) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var record T
+
+		err = query.ScanRows(rows, &record)
+		if err != nil {
+			return err
+		}
+
+		err = fn(&record)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -0,0 +1,212 @@
+package dao
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/sirupsen/logrus"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GetWebhookDao is a function definition that can be replaced in runtime in case some other DAO provider is
+// needed.
+var GetWebhookDao func(*RequestParams) WebhookDao
+
+// getDefaultWebhookDao gets the default DAO implementation which will have the given tenant ID.
+func getDefaultWebhookDao(params *RequestParams) WebhookDao {
+	return &webhookDaoImpl{
+		TenantID: params.TenantID,
+		ctx:      params.ctx,
+	}
+}
+
+// init sets the default DAO implementation so that other packages can request it easily.
+func init() {
+	GetWebhookDao = getDefaultWebhookDao
+}
+
+type webhookDaoImpl struct {
+	TenantID *int64
+	ctx      context.Context
+}
+
+func (w *webhookDaoImpl) getDb() *gorm.DB {
+	return DB.Debug().WithContext(w.ctx)
+}
+
+func (w *webhookDaoImpl) List(limit, offset int, filters []util.Filter) ([]m.Webhook, int64, error) {
+	webhooks := make([]m.Webhook, 0, limit)
+
+	query := w.getDb().
+		Model(&m.Webhook{}).
+		Where("webhooks.tenant_id = ?", w.TenantID)
+
+	query, err := applyFilters(query, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	// getting the total count (filters included) for pagination
+	count := int64(0)
+	query.Count(&count)
+
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&webhooks)
+	if result.Error != nil {
+		return nil, 0, util.NewErrBadRequest(result.Error)
+	}
+
+	if isPreviousPage(filters) {
+		slices.Reverse(webhooks)
+	}
+
+	return webhooks, count, nil
+}
+
+func (w *webhookDaoImpl) GetById(id *int64) (*m.Webhook, error) {
+	var webhook m.Webhook
+
+	err := w.getDb().
+		Where("id = ?", *id).
+		Where("tenant_id = ?", w.TenantID).
+		First(&webhook).
+		Error
+	if err != nil {
+		return nil, util.NewErrNotFound("webhook")
+	}
+
+	return &webhook, nil
+}
+
+func (w *webhookDaoImpl) Create(webhook *m.Webhook) error {
+	webhook.TenantID = *w.TenantID
+
+	err := w.getDb().Create(webhook).Error
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": *w.TenantID}).Errorf(`Unable to create webhook: %s`, err)
+
+		return err
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": *w.TenantID, "webhook_id": webhook.ID}).Info("Webhook created")
+
+	return nil
+}
+
+func (w *webhookDaoImpl) Update(webhook *m.Webhook) error {
+	// The "Select" makes Gorm update the webhooks which get disabled too, since it otherwise skips the zero values.
+	err := w.getDb().
+		Model(webhook).
+		Select("url", "event_types", "enabled", "updated_at").
+		Where("tenant_id = ?", w.TenantID).
+		Updates(webhook).
+		Error
+	if err != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": *w.TenantID, "webhook_id": webhook.ID}).Errorf(`Unable to update webhook: %s`, err)
+
+		return err
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": *w.TenantID, "webhook_id": webhook.ID}).Info("Webhook updated")
+
+	return nil
+}
+
+func (w *webhookDaoImpl) Delete(id *int64) (*m.Webhook, error) {
+	var webhook m.Webhook
+
+	result := w.getDb().
+		Clauses(clause.Returning{}).
+		Where("id = ?", id).
+		Where("tenant_id = ?", w.TenantID).
+		Delete(&webhook)
+
+	if result.Error != nil {
+		logger.Log.WithFields(logrus.Fields{"tenant_id": *w.TenantID, "webhook_id": *id}).Errorf(`Unable to delete webhook: %s`, result.Error)
+
+		return nil, fmt.Errorf(`failed to delete webhook with id "%d": %w`, *id, result.Error)
+	}
+
+	if result.RowsAffected == 0 {
+		return nil, util.NewErrNotFound("webhook")
+	}
+
+	logger.Log.WithFields(logrus.Fields{"tenant_id": *w.TenantID, "webhook_id": *id}).Info("Webhook deleted")
+
+	return &webhook, nil
+}
+
+func (w *webhookDaoImpl) ListForEventType(eventType string) ([]m.Webhook, error) {
+	webhooks := make([]m.Webhook, 0)
+
+	err := w.getDb().
+		Where("tenant_id = ?", w.TenantID).
+		Where("enabled").
+		Where("event_types @> ?", datatypes.JSONSlice[string]{eventType}).
+		Find(&webhooks).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return webhooks, nil
+}
+
+func (w *webhookDaoImpl) CreateDeliveries(deliveries []m.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	for i := range deliveries {
+		deliveries[i].TenantID = *w.TenantID
+	}
+
+	return w.getDb().
+		Omit(clause.Associations).
+		Create(&deliveries).
+		Error
+}
+
+func (w *webhookDaoImpl) ListDeliveries(webhookID int64, limit, offset int, filters []util.Filter) ([]m.WebhookDelivery, int64, error) {
+	deliveries := make([]m.WebhookDelivery, 0, limit)
+
+	query := w.getDb().
+		Model(&m.WebhookDelivery{}).
+		Where("webhook_deliveries.webhook_id = ?", webhookID).
+		Where("webhook_deliveries.tenant_id = ?", w.TenantID)
+
+	query, err := applyFilters(query, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	// getting the total count (filters included) for pagination
+	count := int64(0)
+	query.Count(&count)
+
+	query, err = paginate(query, limit, offset, filters)
+	if err != nil {
+		return nil, 0, util.NewErrBadRequest(err)
+	}
+
+	result := query.Find(&deliveries)
+	if result.Error != nil {
+		return nil, 0, util.NewErrBadRequest(result.Error)
+	}
+
+	if isPreviousPage(filters) {
+		slices.Reverse(deliveries)
+	}
+
+	return deliveries, count, nil
+}
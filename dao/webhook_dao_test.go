@@ -0,0 +1,119 @@
+package dao
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// TestWebhookListForEventType tests that only the tenant's enabled webhooks which are subscribed to the event type get
+// returned, and that the disabled webhooks stop getting returned once they are updated.
+func TestWebhookListForEventType(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("webhooks")
+
+	tenantID := fixtures.TestTenantData[0].Id
+	webhookDao := GetWebhookDao(&RequestParams{TenantID: &tenantID})
+
+	webhooks, err := webhookDao.ListForEventType("Source.update")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(webhooks) != 1 || webhooks[0].ID != fixtures.TestWebhookData[0].ID {
+		t.Fatalf(`want the tenant's webhook subscribed to "Source.update", got "%+v"`, webhooks)
+	}
+
+	// The disabled webhook is subscribed to the event type, but it does not get any deliveries.
+	webhooks, err = webhookDao.ListForEventType("Application.destroy")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(webhooks) != 0 {
+		t.Errorf(`want no webhooks for "Application.destroy", got "%+v"`, webhooks)
+	}
+
+	webhook, err := webhookDao.GetById(&fixtures.TestWebhookData[0].ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	webhook.Enabled = false
+
+	err = webhookDao.Update(webhook)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	webhooks, err = webhookDao.ListForEventType("Source.update")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(webhooks) != 0 {
+		t.Errorf(`want no webhooks once the webhook is disabled, got "%+v"`, webhooks)
+	}
+
+	DropSchema("webhooks")
+}
+
+// TestWebhookDeliveries tests that the queued deliveries are listed for their webhook only, and that they are removed
+// along with their webhook.
+func TestWebhookDeliveries(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+	SwitchSchema("webhook_deliveries")
+
+	tenantID := fixtures.TestTenantData[0].Id
+	webhookDao := GetWebhookDao(&RequestParams{TenantID: &tenantID})
+
+	webhookID := fixtures.TestWebhookData[0].ID
+
+	err := webhookDao.CreateDeliveries([]m.WebhookDelivery{
+		{EventType: "Source.create", Payload: []byte(`{}`), Status: m.WebhookDeliveryPending, WebhookID: webhookID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	deliveries, count, err := webhookDao.ListDeliveries(webhookID, 100, 0, []util.Filter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if count != 3 {
+		t.Errorf("want 3 deliveries for the webhook, got %d", count)
+	}
+
+	for _, delivery := range deliveries {
+		if delivery.WebhookID != webhookID || delivery.TenantID != tenantID {
+			t.Errorf(`unexpected delivery "%+v"`, delivery)
+		}
+	}
+
+	// The other tenant's webhook is not found.
+	_, err = webhookDao.Delete(&fixtures.TestWebhookData[2].ID)
+	if !errors.As(err, &util.ErrNotFound{}) {
+		t.Errorf(`want a not found error, got "%v"`, err)
+	}
+
+	_, err = webhookDao.Delete(&webhookID)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	_, count, err = webhookDao.ListDeliveries(webhookID, 100, 0, []util.Filter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if count != 0 {
+		t.Errorf("want the deliveries to be removed along with the webhook, got %d", count)
+	}
+
+	DropSchema("webhook_deliveries")
+}
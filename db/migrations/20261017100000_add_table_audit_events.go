@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// AddTableAuditEvents creates the table which holds the audit trail of the changes made to the tenants' resources.
+func AddTableAuditEvents() *gormigrate.Migration {
+	type Tenant struct {
+		Id int64
+	}
+
+	type User struct {
+		Id int64
+	}
+
+	type AuditEvent struct {
+		ID        int64     `gorm:"primarykey"`
+		CreatedAt time.Time `gorm:"type: TIMESTAMP WITHOUT TIME ZONE NOT NULL"`
+
+		ActorType    string         `gorm:"not null"`
+		ActorID      string         `gorm:"not null; default:''"`
+		ResourceType string         `gorm:"not null; index:audit_events_tenant_id_resource_idx"`
+		ResourceID   string         `gorm:"not null; index:audit_events_tenant_id_resource_idx"`
+		Action       string         `gorm:"not null"`
+		Changes      datatypes.JSON `gorm:"type: JSONB NOT NULL; default:'{}'"`
+
+		UserID *int64
+		User   User `gorm:"constraint:OnDelete:SET NULL"`
+
+		TenantID int64 `gorm:"not null; index:audit_events_tenant_id_resource_idx,priority:1"`
+		Tenant   Tenant
+	}
+
+	return &gormigrate.Migration{
+		ID: "20261017100000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add_table_audit_events" started`)
+			defer logging.Log.Info(`Migration "add_table_audit_events" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().CreateTable(&AuditEvent{})
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&AuditEvent{})
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,44 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddSourcesDeletedAtColumn adds the column which marks the sources as soft deleted, along with an index so that the
+// purge job can find the expired ones quickly.
+func AddSourcesDeletedAtColumn() *gormigrate.Migration {
+	type Source struct {
+		DeletedAt *time.Time `gorm:"type: TIMESTAMP WITHOUT TIME ZONE; index:sources_deleted_at_idx"`
+	}
+
+	return &gormigrate.Migration{
+		ID: "20261017110000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add_sources_deleted_at_column" started`)
+			defer logging.Log.Info(`Migration "add_sources_deleted_at_column" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				err := tx.Migrator().AddColumn(&Source{}, "DeletedAt")
+				if err != nil {
+					return err
+				}
+
+				return tx.Migrator().CreateIndex(&Source{}, "sources_deleted_at_idx")
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&Source{}, "DeletedAt")
+			})
+
+			return err
+		},
+	}
+}
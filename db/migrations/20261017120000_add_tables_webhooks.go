@@ -0,0 +1,74 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// AddTablesWebhooks creates the tables which hold the tenants' webhook subscriptions and the log of their deliveries.
+func AddTablesWebhooks() *gormigrate.Migration {
+	type Tenant struct {
+		Id int64
+	}
+
+	type Webhook struct {
+		ID        int64     `gorm:"primarykey"`
+		CreatedAt time.Time `gorm:"type: TIMESTAMP WITHOUT TIME ZONE NOT NULL"`
+		UpdatedAt time.Time `gorm:"type: TIMESTAMP WITHOUT TIME ZONE NOT NULL"`
+
+		URL        string         `gorm:"not null"`
+		EventTypes datatypes.JSON `gorm:"type: JSONB NOT NULL; default:'[]'"`
+		Secret     string         `gorm:"not null"`
+		Enabled    bool           `gorm:"not null; default:true"`
+
+		TenantID int64 `gorm:"not null; index"`
+		Tenant   Tenant
+	}
+
+	type WebhookDelivery struct {
+		ID        int64     `gorm:"primarykey"`
+		CreatedAt time.Time `gorm:"type: TIMESTAMP WITHOUT TIME ZONE NOT NULL"`
+		UpdatedAt time.Time `gorm:"type: TIMESTAMP WITHOUT TIME ZONE NOT NULL"`
+
+		EventType      string         `gorm:"not null"`
+		Payload        datatypes.JSON `gorm:"type: JSONB NOT NULL"`
+		Status         string         `gorm:"not null; index:webhook_deliveries_status_next_attempt_at_idx"`
+		Attempts       int            `gorm:"not null; default:0"`
+		NextAttemptAt  *time.Time     `gorm:"type: TIMESTAMP WITHOUT TIME ZONE; index:webhook_deliveries_status_next_attempt_at_idx"`
+		LastAttemptAt  *time.Time     `gorm:"type: TIMESTAMP WITHOUT TIME ZONE"`
+		ResponseStatus *int
+		Error          string `gorm:"not null; default:''"`
+
+		WebhookID int64   `gorm:"not null; index"`
+		Webhook   Webhook `gorm:"constraint:OnDelete:CASCADE"`
+
+		TenantID int64 `gorm:"not null"`
+		Tenant   Tenant
+	}
+
+	return &gormigrate.Migration{
+		ID: "20261017120000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add_tables_webhooks" started`)
+			defer logging.Log.Info(`Migration "add_tables_webhooks" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().CreateTable(&Webhook{}, &WebhookDelivery{})
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&WebhookDelivery{}, &Webhook{})
+			})
+
+			return err
+		},
+	}
+}
@@ -0,0 +1,39 @@
+package migrations
+
+import (
+	"time"
+
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+)
+
+// AddSourcesPurgeEnqueuedAtColumn adds the column which marks the expired superkey sources whose destroy job has
+// already been enqueued, so that the purge job does not enqueue it again on every run.
+func AddSourcesPurgeEnqueuedAtColumn() *gormigrate.Migration {
+	type Source struct {
+		PurgeEnqueuedAt *time.Time `gorm:"type: TIMESTAMP WITHOUT TIME ZONE"`
+	}
+
+	return &gormigrate.Migration{
+		ID: "20261017130000",
+		Migrate: func(db *gorm.DB) error {
+			logging.Log.Info(`Migration "add_sources_purge_enqueued_at_column" started`)
+			defer logging.Log.Info(`Migration "add_sources_purge_enqueued_at_column" ended`)
+
+			// Perform the migration.
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().AddColumn(&Source{}, "PurgeEnqueuedAt")
+			})
+
+			return err
+		},
+		Rollback: func(db *gorm.DB) error {
+			err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.Migrator().DropColumn(&Source{}, "PurgeEnqueuedAt")
+			})
+
+			return err
+		},
+	}
+}
@@ -30,6 +30,10 @@ var MigrationsCollection = []*gormigrate.Migration{
 	AvailabilityStatusColumnsNotNullConstraintDefaultValue(),
 	MigrateAwsProvisioningToImageBuilder(),
 	CleanupProvisioningAuthentications(),
+	AddTableAuditEvents(),
+	AddSourcesDeletedAtColumn(),
+	AddTablesWebhooks(),
+	AddSourcesPurgeEnqueuedAtColumn(),
 }
 
 var ctx = context.Background()
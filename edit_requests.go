@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// isPatchDocument returns true when the request's body is a JSON Merge Patch or a JSON Patch document.
+func isPatchDocument(c echo.Context) bool {
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+
+	return mediaType == util.MergePatchContentType || mediaType == util.JSONPatchContentType
+}
+
+// bindEditRequest binds the request's body into the given edit request. Besides the regular partial JSON payloads, it
+// accepts JSON Merge Patch (RFC 7396) and JSON Patch (RFC 6902) documents, which get applied on top of the resource's
+// current representation. Only the members that the patch modified end up in the edit request, so that the patched
+// payload goes through the same validations as a regular edit.
+func bindEditRequest(c echo.Context, current interface{}, editRequest interface{}) error {
+	if !isPatchDocument(c) {
+		return c.Bind(editRequest)
+	}
+
+	patch, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	document, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(c.Request().Header.Get(echo.HeaderContentType))
+
+	var patched []byte
+	if mediaType == util.MergePatchContentType {
+		patched, err = util.ApplyMergePatch(document, patch)
+	} else {
+		patched, err = util.ApplyJSONPatch(document, patch)
+	}
+
+	if errors.Is(err, util.ErrJSONPatchTestFailed) {
+		return util.NewErrConflict(err.Error())
+	}
+
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	diff, err := util.JSONDiff(document, patched)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	// Nothing changed, so there is nothing to bind.
+	if len(diff) == 0 {
+		return nil
+	}
+
+	var currentMembers map[string]interface{}
+
+	err = json.Unmarshal(document, &currentMembers)
+	if err != nil {
+		return err
+	}
+
+	// Removing an object member clears it. The rest of the members cannot be removed, since the edit requests would
+	// silently ignore them.
+	for member, value := range diff {
+		if value != nil {
+			continue
+		}
+
+		if _, isObject := currentMembers[member].(map[string]interface{}); !isObject {
+			return util.NewErrBadRequest(fmt.Sprintf(`"%s" cannot be removed`, member))
+		}
+
+		diff[member] = map[string]interface{}{}
+	}
+
+	body, err := json.Marshal(diff)
+	if err != nil {
+		return err
+	}
+
+	c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+	return c.Bind(editRequest)
+}
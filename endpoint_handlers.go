@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -63,7 +64,7 @@ func SourceListEndpoint(c echo.Context) error {
 		out[i] = endpoints[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(endpoints)...))
 }
 
 func EndpointList(c echo.Context) error {
@@ -99,7 +100,7 @@ func EndpointList(c echo.Context) error {
 		out[i] = endpoints[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(endpoints)...))
 }
 
 func EndpointGet(c echo.Context) error {
@@ -120,7 +121,7 @@ func EndpointGet(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, app.ToResponse())
+	return sendResource(c, app, app.ToResponse())
 }
 
 func EndpointCreate(c echo.Context) error {
@@ -201,6 +202,16 @@ func EndpointEdit(c echo.Context) error {
 		return err
 	}
 
+	err = checkIfMatch(c, endpoint)
+	if err != nil {
+		return err
+	}
+
+	// Keep the fetched version, so that the update does not go through if the endpoint gets modified in the meantime.
+	updatedAt := endpoint.UpdatedAt
+
+	setAuditPrevious(c, endpoint)
+
 	// Store the previous status before updating the endpoint.
 	previousStatus := endpoint.AvailabilityStatus
 
@@ -208,7 +219,7 @@ func EndpointEdit(c echo.Context) error {
 	if endpoint.PausedAt != nil {
 		input := &m.ResourceEditPausedRequest{}
 
-		err := c.Bind(input)
+		err := bindEditRequest(c, endpoint.ToResponse(), input)
 		if err != nil {
 			return err
 		}
@@ -220,7 +231,7 @@ func EndpointEdit(c echo.Context) error {
 	} else {
 		input := &m.EndpointEditRequest{}
 
-		err := c.Bind(input)
+		err := bindEditRequest(c, endpoint.ToResponse(), input)
 		if err != nil {
 			return err
 		}
@@ -233,13 +244,19 @@ func EndpointEdit(c echo.Context) error {
 		endpoint.UpdateFromRequest(input)
 	}
 
-	err = endpointDao.Update(endpoint)
+	if hasIfMatch(c) {
+		err = endpointDao.UpdateIfUnmodified(endpoint, updatedAt)
+	} else {
+		err = endpointDao.Update(endpoint)
+	}
+
 	if err != nil {
 		return err
 	}
 
 	setNotificationForAvailabilityStatus(c, previousStatus, endpoint)
 	setEventStreamResource(c, endpoint)
+	setETag(c, endpoint)
 
 	return c.JSON(http.StatusOK, endpoint.ToResponse())
 }
@@ -265,6 +282,18 @@ func EndpointDelete(c echo.Context) error {
 		return util.NewErrNotFound("endpoint")
 	}
 
+	// The endpoint's last state goes to the audit trail, and the client might want to make sure it is deleting the
+	// representation it last saw.
+	endpoint, err := endpointDao.GetById(&id)
+	if err != nil {
+		return err
+	}
+
+	err = checkIfMatch(c, endpoint)
+	if err != nil {
+		return err
+	}
+
 	c.Logger().Infof("Deleting Endpoint Id %v", id)
 
 	// Cascade delete the endpoint.
@@ -273,11 +302,22 @@ func EndpointDelete(c echo.Context) error {
 		return err
 	}
 
-	err = service.DeleteCascade(endpointDao.Tenant(), nil, "Endpoint", id, forwardableHeaders)
+	if hasIfMatch(c) {
+		err = service.DeleteCascadeIfUnmodified(endpointDao.Tenant(), nil, "Endpoint", id, endpoint.UpdatedAt, forwardableHeaders)
+	} else {
+		err = service.DeleteCascade(endpointDao.Tenant(), nil, "Endpoint", id, forwardableHeaders)
+	}
+
+	if errors.As(err, &util.ErrPreconditionFailed{}) {
+		return err
+	}
+
 	if err != nil {
 		return util.NewErrBadRequest(err)
 	}
 
+	setAuditDestroyedResource(c, endpoint)
+
 	return c.NoContent(http.StatusNoContent)
 }
 
@@ -312,5 +352,5 @@ func EndpointListAuthentications(c echo.Context) error {
 		out[i] = auths[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(auths)...))
 }
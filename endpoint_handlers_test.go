@@ -107,7 +107,7 @@ func TestSourceEndpointSubcollectionList(t *testing.T) {
 		t.Error(err)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestSourceEndpointSubcollectionListEmptyList tests that empty list is
@@ -363,7 +363,7 @@ func TestEndpointList(t *testing.T) {
 		t.Error(err)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestEndpointListTenantNotExist tests that empty list is returned for
@@ -1345,7 +1345,7 @@ func TestEndpointListAuthentications(t *testing.T) {
 		t.Error(err)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestEndpointListAuthenticationsEmptyList tests that empty list is returned
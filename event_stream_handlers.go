@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// mimeTextEventStream is the media type of the server-sent events streams.
+	mimeTextEventStream = "text/event-stream"
+	// eventStreamHeartbeatInterval is how often a comment is sent down the idle streams, so that the proxies in
+	// between do not close them.
+	eventStreamHeartbeatInterval = 15 * time.Second
+	// eventStreamRetry is how long the clients wait before reconnecting to a closed stream.
+	eventStreamRetry = 5 * time.Second
+	// eventStreamBufferSize is the number of events held for a slow client before dropping them.
+	eventStreamBufferSize = 64
+)
+
+// EventStream pushes the create, update and destroy events of the tenant's resources, along with their availability
+// status transitions, as server-sent events. The events are broadcast through Valkey, so the stream gets the events
+// raised by any of the API pods and by the status listener.
+func EventStream(c echo.Context) error {
+	// The events are not filtered by source type, so the cert-auth callers, which are locked down to the satellite
+	// sources, can not subscribe to them.
+	if c.Get("cert-auth") != nil {
+		return util.NewErrBadRequest("Unauthorized.")
+	}
+
+	orgID, _ := c.Get(h.OrgID).(string)
+	accountNumber, _ := c.Get(h.AccountNumber).(string)
+
+	logEntry := handlerLogEntry(c)
+
+	ctx, cancel := context.WithCancel(c.Request().Context())
+	defer cancel()
+
+	streamEvents := make(chan service.StreamEvent, eventStreamBufferSize)
+	subscriptionErr := make(chan error, 1)
+
+	go func() {
+		subscriptionErr <- service.Broadcaster.Subscribe(ctx, service.EventStreamChannels(orgID, accountNumber), func(message []byte) {
+			var event service.StreamEvent
+
+			err := json.Unmarshal(message, &event)
+			if err != nil {
+				logEntry.Warnf("Unable to unmarshal the stream event: %s", err)
+				return
+			}
+
+			// the subscription must not be held back by a slow client, so the events it can not keep up with are
+			// dropped.
+			select {
+			case streamEvents <- event:
+			default:
+				logEntry.WithFields(logrus.Fields{"event_type": event.EventType}).Warn("Dropping the stream event for a slow client")
+			}
+		})
+	}()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, mimeTextEventStream)
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	// disables the response buffering of the nginx proxies.
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	_, err := fmt.Fprintf(res, "retry: %d\n\n", eventStreamRetry.Milliseconds())
+	if err != nil {
+		return nil
+	}
+
+	res.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-subscriptionErr:
+			// the client reconnects once the stream is closed, so the stream just ends here.
+			if err != nil && ctx.Err() == nil {
+				logEntry.Errorf("Unable to subscribe to the tenant's events: %s", err)
+			}
+
+			return nil
+		case event := <-streamEvents:
+			_, err = fmt.Fprintf(res, "event: %s\ndata: %s\n\n", event.EventType, event.Data)
+		case <-heartbeat.C:
+			_, err = fmt.Fprint(res, ": heartbeat\n\n")
+		}
+
+		if err != nil {
+			return nil
+		}
+
+		res.Flush()
+	}
+}
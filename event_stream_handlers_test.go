@@ -0,0 +1,140 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+)
+
+// TestEventStream tests that the tenant's resource events and availability transitions get pushed down the stream as
+// server-sent events, and that the other tenants' events do not.
+func TestEventStream(t *testing.T) {
+	originalBroadcaster := service.Broadcaster
+	defer func() { service.Broadcaster = originalBroadcaster }()
+
+	broadcaster := &mocks.MockEventBroadcaster{}
+	service.Broadcaster = broadcaster
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/events/stream",
+		nil,
+		map[string]interface{}{
+			h.TenantID:      int64(1),
+			h.OrgID:         "12345",
+			h.AccountNumber: "67890",
+		},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	done := make(chan error)
+	go func() { done <- EventStream(c) }()
+
+	if !broadcaster.WaitForSubscribers(service.EventStreamChannels("12345", "")[0], time.Second) {
+		t.Fatalf("the stream did not subscribe to the tenant's events")
+	}
+
+	headers := []kafka.Header{{Key: h.OrgID, Value: []byte("12345")}, {Key: h.AccountNumber, Value: []byte("67890")}}
+	otherHeaders := []kafka.Header{{Key: h.OrgID, Value: []byte("99999")}}
+
+	err := service.BroadcastEvent("Source.update", []byte(`{"id": 1, "name": "updated"}`), headers)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = service.BroadcastEvent("Source.update", []byte(`{"id": 2}`), otherHeaders)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// the events which are not about the resources do not get broadcast.
+	err = service.BroadcastEvent("Records.update", []byte(`{}`), headers)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = service.BroadcastAvailabilityTransition(&m.Tenant{Id: 1, OrgID: "12345", ExternalTenant: "67890"}, "Application", "5", m.Unavailable, m.Available)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	// give the handler some time to write the events before closing the stream.
+	time.Sleep(100 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("the stream was not closed along with the request")
+	}
+
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != mimeTextEventStream {
+		t.Fatalf("want an event stream, got status code %d and content type %s", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	body := rec.Body.String()
+
+	want := []string{
+		"retry: 5000\n\n",
+		"event: Source.update\ndata: {\"id\":1,\"name\":\"updated\"}\n\n",
+		"event: Application.availability_status\ndata: {\"availability_status\":\"available\",\"id\":\"5\",\"previous_status\":\"unavailable\"}\n\n",
+	}
+
+	for _, w := range want {
+		if !strings.Contains(body, w) {
+			t.Errorf(`want "%s" in the stream, got "%s"`, w, body)
+		}
+	}
+
+	if strings.Contains(body, `"id":2`) || strings.Contains(body, "Records.update") {
+		t.Errorf(`unexpected events in the stream "%s"`, body)
+	}
+}
+
+// TestEventStreamCertAuth tests that the cert-auth callers can not subscribe to the tenant's events.
+func TestEventStreamCertAuth(t *testing.T) {
+	originalBroadcaster := service.Broadcaster
+	defer func() { service.Broadcaster = originalBroadcaster }()
+
+	broadcaster := &mocks.MockEventBroadcaster{}
+	service.Broadcaster = broadcaster
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/events/stream",
+		nil,
+		map[string]interface{}{
+			h.TenantID:  int64(1),
+			h.OrgID:     "12345",
+			"cert-auth": true,
+		},
+	)
+
+	err := ErrorHandlingContext(EventStream)(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	templates.BadRequestTest(t, rec)
+
+	if broadcaster.WaitForSubscribers(service.EventStreamChannels("12345", "")[0], 10*time.Millisecond) {
+		t.Errorf("want the cert-auth caller not to be subscribed to the tenant's events")
+	}
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// The formats the resources can be streamed in.
+const (
+	exportFormatNDJSON = "ndjson"
+	exportFormatCSV    = "csv"
+)
+
+// mimeTextCSV is the media type of the CSV documents.
+const mimeTextCSV = "text/csv"
+
+// exportStreamFlushSize is the number of records written before they get flushed to the client.
+const exportStreamFlushSize = 100
+
+var (
+	sourceExportColumns      = []string{"id", "uid", "name", "source_type_id", "app_creation_workflow", "availability_status", "last_checked_at", "last_available_at", "paused_at", "created_at", "updated_at"}
+	applicationExportColumns = []string{"id", "source_id", "application_type_id", "availability_status", "availability_status_error", "last_checked_at", "last_available_at", "paused_at", "created_at", "updated_at"}
+)
+
+// SourceExportStream streams every source of the tenant which matches the given filters, either as newline delimited
+// JSON or as CSV.
+func SourceExportStream(c echo.Context) error {
+	sourcesDB, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	filters, err := getFilters(c)
+	if err != nil {
+		return err
+	}
+
+	// When listing sources via cert-auth we want to lock them down to only the
+	// satellite source type.
+	if c.Get("cert-auth") != nil {
+		satelliteId := strconv.Itoa(int(dao.Static.GetSourceTypeId("satellite")))
+		filters = append(filters, util.Filter{Name: "source_type_id", Value: []string{satelliteId}})
+	}
+
+	return streamExport(c, "sources", sourceExportColumns,
+		func(fn func(*m.Source) error) error { return sourcesDB.Stream(filters, fn) },
+		func(src *m.Source) interface{} { return src.ToResponse() },
+		func(src *m.Source) []string {
+			return []string{
+				strconv.FormatInt(src.ID, 10),
+				util.ValueOrBlank(src.Uid),
+				src.Name,
+				strconv.FormatInt(src.SourceTypeID, 10),
+				src.AppCreationWorkflow,
+				src.AvailabilityStatus,
+				util.DateTimePointerToRFC3339(src.LastCheckedAt),
+				util.DateTimePointerToRFC3339(src.LastAvailableAt),
+				util.DateTimePointerToRFC3339(src.PausedAt),
+				util.DateTimeToRFC3339(src.CreatedAt),
+				util.DateTimeToRFC3339(src.UpdatedAt),
+			}
+		},
+	)
+}
+
+// ApplicationExportStream streams every application of the tenant which matches the given filters, either as newline
+// delimited JSON or as CSV.
+func ApplicationExportStream(c echo.Context) error {
+	applicationDB, err := getApplicationDao(c)
+	if err != nil {
+		return err
+	}
+
+	filters, err := getFilters(c)
+	if err != nil {
+		return err
+	}
+
+	return streamExport(c, "applications", applicationExportColumns,
+		func(fn func(*m.Application) error) error { return applicationDB.Stream(filters, fn) },
+		func(app *m.Application) interface{} { return app.ToResponse() },
+		func(app *m.Application) []string {
+			return []string{
+				strconv.FormatInt(app.ID, 10),
+				strconv.FormatInt(app.SourceID, 10),
+				strconv.FormatInt(app.ApplicationTypeID, 10),
+				app.AvailabilityStatus,
+				app.AvailabilityStatusError,
+				util.DateTimePointerToRFC3339(app.LastCheckedAt),
+				util.DateTimePointerToRFC3339(app.LastAvailableAt),
+				util.DateTimePointerToRFC3339(app.PausedAt),
+				util.DateTimeToRFC3339(app.CreatedAt),
+				util.DateTimeToRFC3339(app.UpdatedAt),
+			}
+		},
+	)
+}
+
+// exportFormat returns the requested export format, which defaults to newline delimited JSON.
+func exportFormat(c echo.Context) (string, error) {
+	format := c.QueryParam("format")
+
+	switch format {
+	case "":
+		return exportFormatNDJSON, nil
+	case exportFormatNDJSON, exportFormatCSV:
+		return format, nil
+	default:
+		return "", util.NewErrBadRequest(fmt.Sprintf(`invalid format "%s", must be either "%s" or "%s"`, format, exportFormatNDJSON, exportFormatCSV))
+	}
+}
+
+// streamExport writes the records the given stream function reads, as they come, with a chunked response. The
+// records are encoded as their JSON responses or as CSV rows under the given columns. The errors raised before the
+// first record is written get the regular error response, but once the response has started the only thing left to
+// do is to cut it short.
+func streamExport[T any](c echo.Context, resource string, columns []string, stream func(func(*T) error) error, toJSON func(*T) interface{}, toRow func(*T) []string) error {
+	format, err := exportFormat(c)
+	if err != nil {
+		return err
+	}
+
+	var (
+		started   bool
+		count     int
+		encoder   = json.NewEncoder(c.Response())
+		csvWriter = csv.NewWriter(c.Response())
+	)
+
+	start := func() error {
+		started = true
+
+		if format == exportFormatCSV {
+			c.Response().Header().Set(echo.HeaderContentType, mimeTextCSV)
+			c.Response().WriteHeader(http.StatusOK)
+
+			return csvWriter.Write(columns)
+		}
+
+		c.Response().Header().Set(echo.HeaderContentType, mimeApplicationNDJSON)
+		c.Response().WriteHeader(http.StatusOK)
+
+		return nil
+	}
+
+	err = stream(func(record *T) error {
+		if !started {
+			err := start()
+			if err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if format == exportFormatCSV {
+			err = csvWriter.Write(escapeCSVFormulas(toRow(record)))
+		} else {
+			err = encoder.Encode(toJSON(record))
+		}
+
+		if err != nil {
+			return err
+		}
+
+		count++
+		if count%exportStreamFlushSize == 0 {
+			flushExportStream(csvWriter, c)
+		}
+
+		return nil
+	})
+
+	// an empty export still gets its CSV header.
+	if err == nil && !started {
+		err = start()
+	}
+
+	if err == nil {
+		flushExportStream(csvWriter, c)
+
+		err = csvWriter.Error()
+	}
+
+	if err != nil {
+		if !started {
+			return err
+		}
+
+		handlerLogEntry(c).WithFields(logrus.Fields{"resource": resource, "records_written": count}).Errorf("Unable to finish the export stream: %s", err)
+
+		return nil
+	}
+
+	handlerLogEntry(c).WithFields(logrus.Fields{
+		"resource":        resource,
+		"format":          format,
+		"records_written": count,
+	}).Infof("export streamed")
+
+	return nil
+}
+
+// escapeCSVFormulas prefixes with a single quote the cells which spreadsheet applications would otherwise evaluate as
+// formulas, so that a user controlled value such as a source's name cannot run anything when the export gets opened.
+func escapeCSVFormulas(row []string) []string {
+	for i, cell := range row {
+		if cell != "" && strings.ContainsRune("=+-@\t\r", rune(cell[0])) {
+			row[i] = "'" + cell
+		}
+	}
+
+	return row
+}
+
+// flushExportStream sends the records written so far to the client. The response writers which are not able to
+// flush, such as the sparse fieldsets' buffered one, hold the whole response back anyway.
+func flushExportStream(csvWriter *csv.Writer, c echo.Context) {
+	csvWriter.Flush()
+
+	_ = http.NewResponseController(c.Response().Writer).Flush()
+}
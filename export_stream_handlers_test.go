@@ -0,0 +1,154 @@
+package main
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/parser"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// exportStreamRequest streams the export of the given handler with the given query.
+func exportStreamRequest(t *testing.T, handler echo.HandlerFunc, path string) *httptest.ResponseRecorder {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		path,
+		nil,
+		map[string]interface{}{
+			h.TenantID: int64(1),
+			"filters":  []util.Filter{},
+		},
+	)
+
+	err := ErrorHandlingContext(handler)(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	return rec
+}
+
+// TestSourceExportStream tests that the sources get streamed as newline delimited JSON by default.
+func TestSourceExportStream(t *testing.T) {
+	rec := exportStreamRequest(t, SourceExportStream, "/api/sources/v3.1/exports/sources")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	if rec.Header().Get(echo.HeaderContentType) != mimeApplicationNDJSON {
+		t.Errorf(`want the "%s" content type, got "%s"`, mimeApplicationNDJSON, rec.Header().Get(echo.HeaderContentType))
+	}
+
+	var ids []string
+
+	scanner := bufio.NewScanner(rec.Body)
+	for scanner.Scan() {
+		var src map[string]interface{}
+
+		err := json.Unmarshal(scanner.Bytes(), &src)
+		if err != nil {
+			t.Fatalf("unable to unmarshal the line %q: %s", scanner.Text(), err)
+		}
+
+		id, ok := src["id"].(string)
+		if !ok {
+			t.Fatalf(`want the source's id, got "%v"`, src)
+		}
+
+		ids = append(ids, id)
+	}
+
+	if !parser.RunningIntegrationTests && len(ids) != len(fixtures.TestSourceData) {
+		t.Errorf("want %d streamed sources, got %d", len(fixtures.TestSourceData), len(ids))
+	}
+}
+
+// TestApplicationExportStreamCSV tests that the applications get streamed as CSV, with a header row.
+func TestApplicationExportStreamCSV(t *testing.T) {
+	rec := exportStreamRequest(t, ApplicationExportStream, "/api/sources/v3.1/exports/applications?format=csv")
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	if rec.Header().Get(echo.HeaderContentType) != mimeTextCSV {
+		t.Errorf(`want the "%s" content type, got "%s"`, mimeTextCSV, rec.Header().Get(echo.HeaderContentType))
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("unable to read the CSV: %s", err)
+	}
+
+	if strings.Join(records[0], ",") != strings.Join(applicationExportColumns, ",") {
+		t.Errorf(`want the header "%v", got "%v"`, applicationExportColumns, records[0])
+	}
+
+	if !parser.RunningIntegrationTests && len(records)-1 != len(fixtures.TestApplicationData) {
+		t.Errorf("want %d streamed applications, got %d", len(fixtures.TestApplicationData), len(records)-1)
+	}
+
+	for _, record := range records[1:] {
+		if len(record) != len(applicationExportColumns) || record[0] == "" {
+			t.Errorf(`unexpected row "%v"`, record)
+		}
+	}
+}
+
+// TestExportStreamBadFormat tests that the unknown formats get rejected before anything gets streamed.
+func TestExportStreamBadFormat(t *testing.T) {
+	templates.BadRequestTest(t, exportStreamRequest(t, SourceExportStream, "/api/sources/v3.1/exports/sources?format=xml"))
+}
+
+// TestExportStreamCSVEscapesFormulas tests that the cells which start like a spreadsheet formula get prefixed with a
+// single quote, and that the rest of them are left untouched.
+func TestExportStreamCSVEscapesFormulas(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/exports/sources?format=csv",
+		nil,
+		map[string]interface{}{
+			h.TenantID: int64(1),
+		},
+	)
+
+	cells := []string{"=HYPERLINK(\"http://example.com\")", "+1", "-1", "@SUM(A1)", "\tcmd", "\rcmd", "source-name", "", "a=b"}
+	want := []string{"'=HYPERLINK(\"http://example.com\")", "'+1", "'-1", "'@SUM(A1)", "'\tcmd", "'\rcmd", "source-name", "", "a=b"}
+
+	err := streamExport(c, "sources", cells,
+		func(fn func(*string) error) error {
+			record := "record"
+			return fn(&record)
+		},
+		func(record *string) interface{} { return record },
+		func(_ *string) []string { return slices.Clone(cells) },
+	)
+	if err != nil {
+		t.Fatalf(`unexpected error: %s`, err)
+	}
+
+	records, err := csv.NewReader(rec.Body).ReadAll()
+	if err != nil {
+		t.Fatalf("unable to read the CSV: %s", err)
+	}
+
+	if len(records) != 2 {
+		t.Fatalf("want a header and a row, got %d records", len(records))
+	}
+
+	if !slices.Equal(records[1], want) {
+		t.Errorf(`want the row "%q", got "%q"`, want, records[1])
+	}
+}
@@ -7,9 +7,23 @@ import (
 	"github.com/RedHatInsights/sources-api-go/util"
 )
 
+// filterSubresourcePrefixes maps the prefixes of the filters' names to the subresources they filter on.
+var filterSubresourcePrefixes = map[string]string{
+	"source_type.":     "source_type",
+	"applications.":    "application",
+	"endpoints.":       "endpoint",
+	"authentications.": "authentication",
+	"rhc_connections.": "rhc_connection",
+}
+
 // parses all the arguments for us - both sort_by + filters
-func parseArgs(sortBy []*generated_model.SortBy, filters []*generated_model.Filter) []util.Filter {
-	return append(parseSortBy(sortBy), parseFilters(filters)...)
+func parseArgs(sortBy []*generated_model.SortBy, filters []*generated_model.Filter) ([]util.Filter, error) {
+	parsed, err := parseFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(parseSortBy(sortBy), parsed...), nil
 }
 
 func parseSortBy(sortBy []*generated_model.SortBy) []util.Filter {
@@ -44,11 +58,37 @@ func parseSortBy(sortBy []*generated_model.SortBy) []util.Filter {
 	return sorts
 }
 
-func parseFilters(filters []*generated_model.Filter) []util.Filter {
-	outFilters := make([]util.Filter, len(filters))
+func parseFilters(filters []*generated_model.Filter) ([]util.Filter, error) {
+	return parseFilterGroup(filters, 0)
+}
+
+// parseFilterGroup parses the filters of a group nested in the given number of groups. The filters can be nested in
+// as many groups as the REST API's ones.
+func parseFilterGroup(filters []*generated_model.Filter, depth int) ([]util.Filter, error) {
+	outFilters := make([]util.Filter, 0, len(filters))
+
+	for _, f := range filters {
+		if f == nil {
+			continue
+		}
+
+		filter, err := parseFilter(f, depth)
+		if err != nil {
+			return nil, err
+		}
+
+		outFilters = append(outFilters, filter)
+	}
+
+	return outFilters, nil
+}
+
+// parseFilter parses a single filter, which must match its column and all of its groups at once.
+func parseFilter(f *generated_model.Filter, depth int) (util.Filter, error) {
+	filters := make([]util.Filter, 0)
 
 	// parse the filter struct - including subresource filtering
-	for i, f := range filters {
+	if f.Name != nil {
 		filter := util.Filter{Value: f.Value}
 
 		// operation can be nil (defaults to ""/eq)
@@ -57,18 +97,52 @@ func parseFilters(filters []*generated_model.Filter) []util.Filter {
 		}
 
 		// handle subresource filtering
-		if trimmed, ok := strings.CutPrefix(f.Name, "source_type."); ok {
-			filter.Name = trimmed
-			filter.Subresource = "source_type"
-		} else if trimmed, ok := strings.CutPrefix(f.Name, "applications."); ok {
-			filter.Name = trimmed
-			filter.Subresource = "application"
-		} else {
-			filter.Name = f.Name
+		filter.Name = *f.Name
+		for prefix, subresource := range filterSubresourcePrefixes {
+			if trimmed, ok := strings.CutPrefix(*f.Name, prefix); ok {
+				filter.Name = trimmed
+				filter.Subresource = subresource
+			}
 		}
 
-		outFilters[i] = filter
+		filters = append(filters, filter)
+	}
+
+	if (f.Or != nil || f.And != nil || f.Not != nil) && depth >= util.MaxFilterGroupDepth {
+		return util.Filter{}, util.NewErrBadRequest("too many nested filter groups")
+	}
+
+	if f.Or != nil {
+		group, err := parseFilterGroup(f.Or, depth+1)
+		if err != nil {
+			return util.Filter{}, err
+		}
+
+		filters = append(filters, util.Filter{Operation: util.OrFilterOperation, Filters: group})
+	}
+
+	if f.And != nil {
+		group, err := parseFilterGroup(f.And, depth+1)
+		if err != nil {
+			return util.Filter{}, err
+		}
+
+		filters = append(filters, util.Filter{Operation: util.AndFilterOperation, Filters: group})
+	}
+
+	if f.Not != nil {
+		not, err := parseFilter(f.Not, depth+1)
+		if err != nil {
+			return util.Filter{}, err
+		}
+
+		filters = append(filters, util.Filter{Operation: util.NotFilterOperation, Filters: []util.Filter{not}})
+	}
+
+	if len(filters) == 1 {
+		return filters[0], nil
 	}
 
-	return outFilters
+	// a filter without a name nor groups ends up as an empty group, which does not get past the DAOs.
+	return util.Filter{Operation: util.AndFilterOperation, Filters: filters}, nil
 }
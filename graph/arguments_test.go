@@ -1,9 +1,12 @@
 package graph
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	generated_model "github.com/RedHatInsights/sources-api-go/graph/model"
+	"github.com/RedHatInsights/sources-api-go/util"
 )
 
 func TestParseSortByDefaultAsc(t *testing.T) {
@@ -108,3 +111,109 @@ func TestParseSortByMultiple(t *testing.T) {
 		t.Errorf("expected %q, got %q", "id DESC", sorts[1].Value[0])
 	}
 }
+
+func TestParseFiltersGroups(t *testing.T) {
+	status, name, contains := "availability_status", "source_type.name", "contains"
+
+	filters, err := parseFilters([]*generated_model.Filter{
+		{
+			Name:  &name,
+			Value: []string{"amazon", "azure"},
+			Or: []*generated_model.Filter{
+				{Name: &status, Value: []string{"unavailable"}},
+				{Name: &status, Value: []string{"partially_available"}},
+			},
+		},
+		nil,
+		{Not: &generated_model.Filter{Name: &status, Operation: &contains, Value: []string{"available"}}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(filters) != 2 {
+		t.Fatalf("expected 2 filters, got %d", len(filters))
+	}
+
+	and := filters[0]
+	if and.Operation != util.AndFilterOperation || len(and.Filters) != 2 {
+		t.Fatalf(`expected an "and" group of the name and the "or" group, got %+v`, and)
+	}
+
+	if and.Filters[0].Subresource != "source_type" || and.Filters[0].Name != "name" {
+		t.Errorf("expected the source type's name filter, got %+v", and.Filters[0])
+	}
+
+	if and.Filters[1].Operation != util.OrFilterOperation || len(and.Filters[1].Filters) != 2 {
+		t.Errorf(`expected an "or" group of 2 filters, got %+v`, and.Filters[1])
+	}
+
+	not := filters[1]
+	if not.Operation != util.NotFilterOperation || len(not.Filters) != 1 || not.Filters[0].Operation != "contains" {
+		t.Errorf(`expected a "not" group of the "contains" filter, got %+v`, not)
+	}
+}
+
+func TestParseFiltersEmpty(t *testing.T) {
+	filters, err := parseFilters([]*generated_model.Filter{{}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(filters) != 1 || filters[0].Operation != util.AndFilterOperation || len(filters[0].Filters) != 0 {
+		t.Errorf("expected an empty group, got %+v", filters)
+	}
+}
+
+func TestParseFiltersSubresources(t *testing.T) {
+	names := map[string]string{
+		"source_type.name":         "source_type",
+		"applications.extra":       "application",
+		"endpoints.host":           "endpoint",
+		"authentications.authtype": "authentication",
+		"rhc_connections.rhc_id":   "rhc_connection",
+		"name":                     "",
+	}
+
+	for name, subresource := range names {
+		filters, err := parseFilters([]*generated_model.Filter{{Name: &name, Value: []string{"value"}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if filters[0].Subresource != subresource {
+			t.Errorf("expected the %q subresource for %q, got %q", subresource, name, filters[0].Subresource)
+		}
+
+		if strings.Contains(filters[0].Name, ".") {
+			t.Errorf("expected the prefix trimmed from %q, got %q", name, filters[0].Name)
+		}
+	}
+}
+
+func TestParseFiltersDepth(t *testing.T) {
+	name := "name"
+
+	// nest the filter in as many groups as allowed, alternating between the group operations.
+	filter := &generated_model.Filter{Name: &name, Value: []string{"value"}}
+	for i := 0; i < util.MaxFilterGroupDepth; i++ {
+		switch i % 3 {
+		case 0:
+			filter = &generated_model.Filter{Or: []*generated_model.Filter{filter}}
+		case 1:
+			filter = &generated_model.Filter{And: []*generated_model.Filter{filter}}
+		default:
+			filter = &generated_model.Filter{Not: filter}
+		}
+	}
+
+	_, err := parseFilters([]*generated_model.Filter{filter})
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	_, err = parseFilters([]*generated_model.Filter{{Not: filter}})
+	if !errors.As(err, &util.ErrBadRequest{}) {
+		t.Errorf("expected a bad request error for a group too deep, got %v", err)
+	}
+}
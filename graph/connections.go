@@ -0,0 +1,151 @@
+package graph
+
+import (
+	generated_model "github.com/RedHatInsights/sources-api-go/graph/model"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// defaultConnectionSize is the size of a connection's page when "first" is not given.
+const defaultConnectionSize = 100
+
+// keyset columns the connections are sorted by, which are the ones the cursors are anchored on. The authentications
+// do not have a "created_at" column, so they are sorted by their id only.
+var (
+	createdAtKeyset = []string{"created_at ASC", "id ASC"}
+	idKeyset        = []string{"id ASC"}
+)
+
+// connectionArgs parses the arguments of a connection into the size of its page and the filters which fetch it: the
+// given filters, the sorting the cursors follow, and the cursor the page starts after. The page's nodes are fetched
+// with a limit of one more than its size, so that the extra node tells whether there is a next page.
+func connectionArgs(first *int, after *string, filter []*generated_model.Filter, keyset []string) (int, []util.Filter, error) {
+	size := defaultConnectionSize
+	if first != nil {
+		if *first < 0 {
+			return 0, nil, util.NewErrBadRequest(`"first" must not be negative`)
+		}
+
+		size = *first
+	}
+
+	parsed, err := parseFilters(filter)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	filters := []util.Filter{{Operation: "sort_by", Value: keyset}}
+	filters = append(filters, parsed...)
+
+	if after != nil {
+		// the connections only page forward, so the cursors pointing to a
+		// previous page are not valid.
+		cursor, err := util.ParseCursor(*after)
+		if err != nil || cursor.Previous {
+			return 0, nil, util.NewErrBadRequest(`invalid "after" cursor`)
+		}
+
+		filters = append(filters, util.Filter{Operation: util.CursorFilterOperation, Value: []string{*after}})
+	}
+
+	return size, filters, nil
+}
+
+// page is a page of a connection's nodes, along with their cursors and what the connection tells about them.
+type page[T any] struct {
+	nodes      []*T
+	cursors    []string
+	pageInfo   *generated_model.PageInfo
+	totalCount int
+}
+
+// newPage builds the page out of the nodes fetched with the arguments "connectionArgs" parsed, which hold one more node
+// than the page's size when there is a next page. The count is the one of the nodes matching the filters, regardless
+// of the page.
+func newPage[T any, P interface {
+	*T
+	util.Paginable
+}](nodes []T, size int, after *string, count int64) page[T] {
+	hasNextPage := len(nodes) > size
+	if hasNextPage {
+		nodes = nodes[:size]
+	}
+
+	p := page[T]{
+		nodes:      make([]*T, len(nodes)),
+		cursors:    make([]string, len(nodes)),
+		pageInfo:   &generated_model.PageInfo{HasNextPage: hasNextPage, HasPreviousPage: after != nil},
+		totalCount: int(count),
+	}
+
+	for i := range nodes {
+		p.nodes[i] = &nodes[i]
+
+		// the nodes which cannot be used as anchors, like the authentications
+		// stored in Vault, get an empty cursor.
+		if cursor := P(&nodes[i]).Cursor(); cursor != nil {
+			p.cursors[i] = cursor.Encode()
+		}
+	}
+
+	if len(nodes) > 0 {
+		p.pageInfo.StartCursor = &p.cursors[0]
+		p.pageInfo.EndCursor = &p.cursors[len(nodes)-1]
+	}
+
+	return p
+}
+
+func sourceConnection(p page[m.Source]) *generated_model.SourceConnection {
+	edges := make([]*generated_model.SourceEdge, len(p.nodes))
+	for i := range p.nodes {
+		edges[i] = &generated_model.SourceEdge{Node: p.nodes[i], Cursor: p.cursors[i]}
+	}
+
+	return &generated_model.SourceConnection{Edges: edges, PageInfo: p.pageInfo, TotalCount: p.totalCount}
+}
+
+func sourceTypeConnection(p page[m.SourceType]) *generated_model.SourceTypeConnection {
+	edges := make([]*generated_model.SourceTypeEdge, len(p.nodes))
+	for i := range p.nodes {
+		edges[i] = &generated_model.SourceTypeEdge{Node: p.nodes[i], Cursor: p.cursors[i]}
+	}
+
+	return &generated_model.SourceTypeConnection{Edges: edges, PageInfo: p.pageInfo, TotalCount: p.totalCount}
+}
+
+func applicationConnection(p page[m.Application]) *generated_model.ApplicationConnection {
+	edges := make([]*generated_model.ApplicationEdge, len(p.nodes))
+	for i := range p.nodes {
+		edges[i] = &generated_model.ApplicationEdge{Node: p.nodes[i], Cursor: p.cursors[i]}
+	}
+
+	return &generated_model.ApplicationConnection{Edges: edges, PageInfo: p.pageInfo, TotalCount: p.totalCount}
+}
+
+func endpointConnection(p page[m.Endpoint]) *generated_model.EndpointConnection {
+	edges := make([]*generated_model.EndpointEdge, len(p.nodes))
+	for i := range p.nodes {
+		edges[i] = &generated_model.EndpointEdge{Node: p.nodes[i], Cursor: p.cursors[i]}
+	}
+
+	return &generated_model.EndpointConnection{Edges: edges, PageInfo: p.pageInfo, TotalCount: p.totalCount}
+}
+
+func authenticationConnection(p page[m.Authentication]) *generated_model.AuthenticationConnection {
+	edges := make([]*generated_model.AuthenticationEdge, len(p.nodes))
+	for i := range p.nodes {
+		edges[i] = &generated_model.AuthenticationEdge{Node: p.nodes[i], Cursor: p.cursors[i]}
+	}
+
+	return &generated_model.AuthenticationConnection{Edges: edges, PageInfo: p.pageInfo, TotalCount: p.totalCount}
+}
+
+func rhcConnectionConnection(p page[m.RhcConnection]) *generated_model.RhcConnectionConnection {
+	edges := make([]*generated_model.RhcConnectionEdge, len(p.nodes))
+	for i := range p.nodes {
+		edges[i] = &generated_model.RhcConnectionEdge{Node: p.nodes[i], Cursor: p.cursors[i]}
+	}
+
+	return &generated_model.RhcConnectionConnection{Edges: edges, PageInfo: p.pageInfo, TotalCount: p.totalCount}
+}
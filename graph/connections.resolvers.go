@@ -0,0 +1,178 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.80
+
+import (
+	"context"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	generated_model "github.com/RedHatInsights/sources-api-go/graph/model"
+	"github.com/RedHatInsights/sources-api-go/model"
+)
+
+// AuthenticationsConnection is the resolver for the authentications_connection field.
+func (r *applicationResolver) AuthenticationsConnection(ctx context.Context, obj *model.Application, first *int, after *string, filter []*generated_model.Filter) (*generated_model.AuthenticationConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, idKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	auths, count, err := dao.GetAuthenticationDao(getRequestDataFromCtx(ctx).requestParams()).ListForApplication(obj.ID, size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return authenticationConnection(newPage(auths, size, after, count)), nil
+}
+
+// SourcesConnection is the resolver for the sources_connection field.
+func (r *applicationTypeResolver) SourcesConnection(ctx context.Context, obj *model.ApplicationType, first *int, after *string, filter []*generated_model.Filter) (*generated_model.SourceConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, createdAtKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	srces, count, err := dao.GetSourceDao(getRequestDataFromCtx(ctx).requestParams()).SubCollectionList(model.ApplicationType{Id: obj.Id}, size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return sourceConnection(newPage(srces, size, after, count)), nil
+}
+
+// AuthenticationsConnection is the resolver for the authentications_connection field.
+func (r *endpointResolver) AuthenticationsConnection(ctx context.Context, obj *model.Endpoint, first *int, after *string, filter []*generated_model.Filter) (*generated_model.AuthenticationConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, idKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	auths, count, err := dao.GetAuthenticationDao(getRequestDataFromCtx(ctx).requestParams()).ListForEndpoint(obj.ID, size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return authenticationConnection(newPage(auths, size, after, count)), nil
+}
+
+// SourcesConnection is the resolver for the sources_connection field.
+func (r *queryResolver) SourcesConnection(ctx context.Context, first *int, after *string, filter []*generated_model.Filter) (*generated_model.SourceConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, createdAtKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	srces, count, err := dao.GetSourceDao(getRequestDataFromCtx(ctx).requestParams()).List(size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return sourceConnection(newPage(srces, size, after, count)), nil
+}
+
+// SourceTypesConnection is the resolver for the source_types_connection field.
+func (r *queryResolver) SourceTypesConnection(ctx context.Context, first *int, after *string, filter []*generated_model.Filter) (*generated_model.SourceTypeConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, createdAtKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceTypes, count, err := dao.GetSourceTypeDao().List(size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return sourceTypeConnection(newPage(sourceTypes, size, after, count)), nil
+}
+
+// EndpointsConnection is the resolver for the endpoints_connection field.
+func (r *queryResolver) EndpointsConnection(ctx context.Context, first *int, after *string, filter []*generated_model.Filter) (*generated_model.EndpointConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, createdAtKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	endpts, count, err := dao.GetEndpointDao(tenantIdFromCtx(ctx)).List(size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return endpointConnection(newPage(endpts, size, after, count)), nil
+}
+
+// RhcConnectionsConnection is the resolver for the rhc_connections_connection field.
+func (r *queryResolver) RhcConnectionsConnection(ctx context.Context, first *int, after *string, filter []*generated_model.Filter) (*generated_model.RhcConnectionConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, createdAtKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	rhcConnections, count, err := dao.GetRhcConnectionDao(getRequestDataFromCtx(ctx).requestParams()).List(size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return rhcConnectionConnection(newPage(rhcConnections, size, after, count)), nil
+}
+
+// SourcesConnection is the resolver for the sources_connection field.
+func (r *rhcConnectionResolver) SourcesConnection(ctx context.Context, obj *model.RhcConnection, first *int, after *string, filter []*generated_model.Filter) (*generated_model.SourceConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, createdAtKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	srces, count, err := dao.GetSourceDao(getRequestDataFromCtx(ctx).requestParams()).ListForRhcConnection(&obj.ID, size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return sourceConnection(newPage(srces, size, after, count)), nil
+}
+
+// ApplicationsConnection is the resolver for the applications_connection field.
+func (r *sourceResolver) ApplicationsConnection(ctx context.Context, obj *model.Source, first *int, after *string, filter []*generated_model.Filter) (*generated_model.ApplicationConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, createdAtKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	apps, count, err := dao.GetApplicationDao(getRequestDataFromCtx(ctx).requestParams()).SubCollectionList(model.Source{ID: obj.ID}, size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return applicationConnection(newPage(apps, size, after, count)), nil
+}
+
+// EndpointsConnection is the resolver for the endpoints_connection field.
+func (r *sourceResolver) EndpointsConnection(ctx context.Context, obj *model.Source, first *int, after *string, filter []*generated_model.Filter) (*generated_model.EndpointConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, createdAtKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	endpts, count, err := dao.GetEndpointDao(tenantIdFromCtx(ctx)).SubCollectionList(model.Source{ID: obj.ID}, size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return endpointConnection(newPage(endpts, size, after, count)), nil
+}
+
+// AuthenticationsConnection is the resolver for the authentications_connection field.
+func (r *sourceResolver) AuthenticationsConnection(ctx context.Context, obj *model.Source, first *int, after *string, filter []*generated_model.Filter) (*generated_model.AuthenticationConnection, error) {
+	size, f, err := connectionArgs(first, after, filter, idKeyset)
+	if err != nil {
+		return nil, err
+	}
+
+	auths, count, err := dao.GetAuthenticationDao(getRequestDataFromCtx(ctx).requestParams()).ListForSource(obj.ID, size+1, 0, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return authenticationConnection(newPage(auths, size, after, count)), nil
+}
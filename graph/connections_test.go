@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	generated_model "github.com/RedHatInsights/sources-api-go/graph/model"
+	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+func TestConnectionArgs(t *testing.T) {
+	first := 10
+	after := util.NewCursor(nil, 5).Encode()
+	name := "name"
+
+	size, filters, err := connectionArgs(&first, &after, []*generated_model.Filter{{Name: &name, Value: []string{"source"}}}, idKeyset)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if size != first {
+		t.Errorf("want the size %d, got %d", first, size)
+	}
+
+	if len(filters) != 3 {
+		t.Fatalf("want the sorting, the filter and the cursor, got %+v", filters)
+	}
+
+	if filters[0].Operation != "sort_by" || !slices.Equal(filters[0].Value, idKeyset) {
+		t.Errorf("want the filters to be sorted by the keyset, got %+v", filters[0])
+	}
+
+	if filters[1].Name != name {
+		t.Errorf("want the %q filter, got %+v", name, filters[1])
+	}
+
+	cursor, err := util.CursorFromFilters(filters)
+	if err != nil || cursor == nil || cursor.ID != 5 {
+		t.Errorf("want the cursor of the id 5, got %+v and %v", cursor, err)
+	}
+}
+
+func TestConnectionArgsDefaults(t *testing.T) {
+	size, filters, err := connectionArgs(nil, nil, nil, createdAtKeyset)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if size != defaultConnectionSize {
+		t.Errorf("want the default size %d, got %d", defaultConnectionSize, size)
+	}
+
+	if len(filters) != 1 || !slices.Equal(filters[0].Value, createdAtKeyset) {
+		t.Errorf("want the keyset sorting only, got %+v", filters)
+	}
+}
+
+func TestConnectionArgsInvalid(t *testing.T) {
+	negative := -1
+	previous := &util.Cursor{ID: 5, Previous: true}
+	previousCursor := previous.Encode()
+	garbage := "garbage"
+
+	tests := []struct {
+		name  string
+		first *int
+		after *string
+	}{
+		{name: "negative first", first: &negative},
+		{name: "invalid cursor", after: &garbage},
+		{name: "previous page cursor", after: &previousCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := connectionArgs(tt.first, tt.after, nil, idKeyset)
+			if !errors.As(err, &util.ErrBadRequest{}) {
+				t.Errorf("want a bad request error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewPage(t *testing.T) {
+	createdAt := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sources := []model.Source{{ID: 1, CreatedAt: createdAt}, {ID: 2, CreatedAt: createdAt}, {ID: 3, CreatedAt: createdAt}}
+
+	// the extra source tells that there is a next page.
+	p := newPage(sources, 2, nil, 7)
+
+	if len(p.nodes) != 2 || p.nodes[0].ID != 1 || p.nodes[1].ID != 2 {
+		t.Fatalf("want the first two sources, got %+v", p.nodes)
+	}
+
+	if !p.pageInfo.HasNextPage || p.pageInfo.HasPreviousPage {
+		t.Errorf("want a next page and no previous page, got %+v", p.pageInfo)
+	}
+
+	if p.totalCount != 7 {
+		t.Errorf("want the total count 7, got %d", p.totalCount)
+	}
+
+	if p.pageInfo.StartCursor == nil || *p.pageInfo.StartCursor != sources[0].Cursor().Encode() {
+		t.Errorf("want the start cursor to point at the first source, got %v", p.pageInfo.StartCursor)
+	}
+
+	if p.pageInfo.EndCursor == nil || *p.pageInfo.EndCursor != p.cursors[1] || p.cursors[1] != sources[1].Cursor().Encode() {
+		t.Errorf("want the end cursor to point at the second source, got %v", p.pageInfo.EndCursor)
+	}
+
+	after := p.cursors[1]
+
+	p = newPage(sources[2:], 2, &after, 7)
+
+	if len(p.nodes) != 1 || p.pageInfo.HasNextPage || !p.pageInfo.HasPreviousPage {
+		t.Errorf("want the last page, got %+v with %+v", p.nodes, p.pageInfo)
+	}
+}
+
+func TestNewPageEmpty(t *testing.T) {
+	p := newPage([]model.Authentication{}, 10, nil, 0)
+
+	if len(p.nodes) != 0 || p.pageInfo.HasNextPage || p.pageInfo.StartCursor != nil || p.pageInfo.EndCursor != nil {
+		t.Errorf("want an empty page without cursors, got %+v with %+v", p.nodes, p.pageInfo)
+	}
+}
@@ -2,13 +2,27 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
+	"strconv"
 
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/RedHatInsights/sources-api-go/dao"
 	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
 )
 
+// the key the request data is stored under in the context. The request data
+// itself cannot be the key, since it is not comparable.
+type requestDataKey struct{}
+
+// WithRequestData returns a copy of the context which carries the request data
+func WithRequestData(ctx context.Context, rd *RequestData) context.Context {
+	return context.WithValue(ctx, requestDataKey{}, rd)
+}
+
 // fetches the request data from the context
 func getRequestDataFromCtx(ctx context.Context) *RequestData {
-	r, ok := ctx.Value(RequestData{}).(*RequestData)
+	r, ok := ctx.Value(requestDataKey{}).(*RequestData)
 	if !ok {
 		panic("could not pull tenant id from context")
 	}
@@ -47,10 +61,127 @@ func authenticationsFromCtx(ctx context.Context, resourceType string, id int64)
 	return out
 }
 
+func endpointAuthenticationsFromCtx(ctx context.Context, id int64) []m.Authentication {
+	mp := *getRequestDataFromCtx(ctx).endpointAuthenticationMap
+	return mp[id]
+}
+
+// returns the connection's sources which were found, since the sources being
+// deleted are not listed
+func rhcConnectionSourcesFromCtx(ctx context.Context, ids []int64) []m.Source {
+	mp := *getRequestDataFromCtx(ctx).rhcConnectionSourceMap
+	out := make([]m.Source, 0, len(ids))
+
+	for _, id := range ids {
+		if src, ok := mp[id]; ok {
+			out = append(out, src)
+		}
+	}
+
+	return out
+}
+
+// decodeJSON decodes the JSON column of a resource for the "Any" fields
+func decodeJSON(raw []byte) (any, error) {
+	if raw == nil {
+		return nil, nil
+	}
+
+	var out any
+	err := json.Unmarshal(raw, &out)
+	return out, err
+}
+
+// rootField returns the name of the root field the current field is nested in,
+// such as "sources". The subresources only get batch loaded under the listing
+// which stores the IDs they are loaded for, and anywhere else they get fetched
+// for each resource, like for the resources the mutations return.
+func rootField(ctx context.Context) string {
+	root := ""
+
+	// the outermost field context with a field is the root field's, since the
+	// operation itself has one too
+	for fc := graphql.GetFieldContext(ctx); fc != nil; fc = fc.Parent {
+		if fc.Field.Field != nil {
+			root = fc.Field.Name
+		}
+	}
+
+	return root
+}
+
+// fetchSourceApplications fetches the applications of a source which does not come from the "sources" listing.
+func fetchSourceApplications(ctx context.Context, sourceID int64) ([]*m.Application, error) {
+	apps, _, err := dao.GetApplicationDao(getRequestDataFromCtx(ctx).requestParams()).SubCollectionList(m.Source{ID: sourceID}, defaultLimit, 0, []util.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*m.Application, len(apps))
+	for i := range apps {
+		out[i] = &apps[i]
+	}
+
+	return out, nil
+}
+
+// fetchSourceEndpoints fetches the endpoints of a source which does not come from the "sources" listing.
+func fetchSourceEndpoints(ctx context.Context, sourceID int64) ([]*m.Endpoint, error) {
+	endpts, _, err := dao.GetEndpointDao(tenantIdFromCtx(ctx)).SubCollectionList(m.Source{ID: sourceID}, defaultLimit, 0, []util.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*m.Endpoint, len(endpts))
+	for i := range endpts {
+		out[i] = &endpts[i]
+	}
+
+	return out, nil
+}
+
+// fetchRhcConnectionSources fetches the sources of a connection which does not come from the "rhc_connections"
+// listing.
+func fetchRhcConnectionSources(ctx context.Context, rhcConnectionID int64) ([]*m.Source, error) {
+	srces, _, err := dao.GetSourceDao(getRequestDataFromCtx(ctx).requestParams()).ListForRhcConnection(&rhcConnectionID, defaultLimit, 0, []util.Filter{})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*m.Source, len(srces))
+	for i := range srces {
+		out[i] = &srces[i]
+	}
+
+	return out, nil
+}
+
+// fetchResourceAuthentications fetches the authentications of a resource which does not come from a listing that batch
+// loads them, and which belongs to the given source.
+func fetchResourceAuthentications(ctx context.Context, sourceID int64, resourceType string, resourceID int64) ([]*m.Authentication, error) {
+	auths, _, err := dao.GetAuthenticationDao(getRequestDataFromCtx(ctx).requestParams()).List(defaultLimit, 0, []util.Filter{{Name: "source_id", Value: []string{strconv.FormatInt(sourceID, 10)}}})
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]*m.Authentication, 0)
+	for i := range auths {
+		if auths[i].ResourceType == resourceType && auths[i].ResourceID == resourceID {
+			out = append(out, &auths[i])
+		}
+	}
+
+	return out, nil
+}
+
 // sends the count into the requests channel, if the count wasn't requested we
-// fetch it anyway since the DAO returns it
+// fetch it anyway since the DAO returns it. Only the first listing's count is
+// kept, so that the other listings of the query do not block on a full channel
 func sendCount(ctx context.Context, count int64) {
-	getRequestDataFromCtx(ctx).CountChan <- int(count)
+	select {
+	case getRequestDataFromCtx(ctx).CountChan <- int(count):
+	default:
+	}
 }
 
 // gets the source count value from the ctx's channel
@@ -0,0 +1,148 @@
+package graph
+
+import (
+	"context"
+	"math"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/RedHatInsights/sources-api-go/graph/generated"
+	generated_model "github.com/RedHatInsights/sources-api-go/graph/model"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// defaultListSize is the number of items the root lists return when no "limit" is given.
+	defaultListSize = 100
+	// unpaginatedListSize is the number of items the lists which cannot be paginated, like the sources'
+	// applications, are assumed to hold when scoring the operations.
+	unpaginatedListSize = 10
+
+	errDepthLimit = "DEPTH_LIMIT_EXCEEDED"
+)
+
+// Complexity returns the functions which score the fields of the operations for the complexity limit. Every field
+// costs one plus the cost of its selections, and the lists multiply the cost of their items by the number of items
+// they return at most, which is their "limit" or "first" argument.
+func Complexity() generated.ComplexityRoot {
+	var c generated.ComplexityRoot
+
+	c.Query.Sources = listComplexity
+	c.Query.ApplicationTypes = listComplexity
+	c.Query.SourceTypes = listComplexity
+	c.Query.AppMetaData = listComplexity
+	c.Query.Endpoints = listComplexity
+	c.Query.RhcConnections = listComplexity
+
+	c.Query.SourcesConnection = connectionComplexity
+	c.Query.SourceTypesConnection = connectionComplexity
+	c.Query.EndpointsConnection = connectionComplexity
+	c.Query.RhcConnectionsConnection = connectionComplexity
+
+	c.Source.Applications = unpaginatedListComplexity
+	c.Source.Endpoints = unpaginatedListComplexity
+	c.Source.Authentications = unpaginatedListComplexity
+	c.Application.Authentications = unpaginatedListComplexity
+	c.Endpoint.Authentications = unpaginatedListComplexity
+	c.ApplicationType.Sources = unpaginatedListComplexity
+	c.RhcConnection.Sources = unpaginatedListComplexity
+
+	c.Source.ApplicationsConnection = connectionComplexity
+	c.Source.EndpointsConnection = connectionComplexity
+	c.Source.AuthenticationsConnection = connectionComplexity
+	c.Application.AuthenticationsConnection = connectionComplexity
+	c.Endpoint.AuthenticationsConnection = connectionComplexity
+	c.ApplicationType.SourcesConnection = connectionComplexity
+	c.RhcConnection.SourcesConnection = connectionComplexity
+
+	return c
+}
+
+func listComplexity(childComplexity int, limit *int, _ *int, _ []*generated_model.SortBy, _ []*generated_model.Filter) int {
+	return listCost(childComplexity, limit, defaultListSize)
+}
+
+func connectionComplexity(childComplexity int, first *int, _ *string, _ []*generated_model.Filter) int {
+	return listCost(childComplexity, first, defaultConnectionSize)
+}
+
+func unpaginatedListComplexity(childComplexity int) int {
+	return listCost(childComplexity, nil, unpaginatedListSize)
+}
+
+// listCost returns the cost of a list of the given size, or of the default size when it is not given. The cost
+// saturates instead of overflowing, so that the huge sizes do not wrap it around below the limit.
+func listCost(childComplexity int, size *int, defaultSize int) int {
+	n := defaultSize
+	if size != nil && *size >= 0 {
+		n = *size
+	}
+
+	if n > 0 && childComplexity > (math.MaxInt-1)/n {
+		return math.MaxInt
+	}
+
+	return 1 + n*childComplexity
+}
+
+// DepthLimit rejects the operations whose selections are nested deeper than the limit. The introspection fields are
+// not taken into account, since the introspection queries nest the types deeply.
+type DepthLimit struct {
+	Limit int
+}
+
+var _ interface {
+	graphql.OperationContextMutator
+	graphql.HandlerExtension
+} = DepthLimit{}
+
+func (DepthLimit) ExtensionName() string {
+	return "DepthLimit"
+}
+
+func (DepthLimit) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d DepthLimit) MutateOperationContext(_ context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	if op == nil {
+		return nil
+	}
+
+	depth := selectionDepth(op.SelectionSet)
+	if depth > d.Limit {
+		err := gqlerror.Errorf("operation has depth %d, which exceeds the limit of %d", depth, d.Limit)
+		errcode.Set(err, errDepthLimit)
+		return err
+	}
+
+	return nil
+}
+
+// selectionDepth returns how deep the fields of the selection set are nested. The fragments' fields count as if they
+// were selected where the fragments are spread.
+func selectionDepth(selectionSet ast.SelectionSet) int {
+	depth := 0
+
+	for _, selection := range selectionSet {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+
+			depth = max(depth, 1+selectionDepth(s.SelectionSet))
+		case *ast.InlineFragment:
+			depth = max(depth, selectionDepth(s.SelectionSet))
+		case *ast.FragmentSpread:
+			if s.Definition != nil {
+				depth = max(depth, selectionDepth(s.Definition.SelectionSet))
+			}
+		}
+	}
+
+	return depth
+}
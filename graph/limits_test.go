@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/RedHatInsights/sources-api-go/graph/generated"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// loadOperation parses and validates the query against the schema, and returns its only operation.
+func loadOperation(t *testing.T, es graphql.ExecutableSchema, query string) *ast.OperationDefinition {
+	doc, errs := gqlparser.LoadQuery(es.Schema(), query)
+	if errs != nil {
+		t.Fatalf("want a valid query, got %v", errs)
+	}
+
+	return doc.Operations[0]
+}
+
+func TestComplexity(t *testing.T) {
+	es := generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{}, Complexity: Complexity()})
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "list with a limit", query: `{ sources(limit: 10) { id } }`, want: 1 + 10*1},
+		{name: "list without a limit", query: `{ sources { id name } }`, want: 1 + defaultListSize*2},
+		{name: "nested list", query: `{ sources(limit: 10) { applications { id } } }`, want: 1 + 10*(1+unpaginatedListSize*1)},
+		{name: "connection", query: `{ sources_connection(first: 5) { totalCount edges { node { id } } } }`, want: 1 + 5*(1+(1+(1+1)))},
+		{name: "nested connection", query: `{ sources(limit: 2) { applications_connection { edges { node { id } } } } }`, want: 1 + 2*(1+defaultConnectionSize*(1+(1+1)))},
+		{name: "unlisted fields", query: `{ meta { count } }`, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := complexity.Calculate(context.Background(), es, loadOperation(t, es, tt.query), nil)
+			if got != tt.want {
+				t.Errorf("want the complexity %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestListCostSaturates(t *testing.T) {
+	size := math.MaxInt32
+
+	cost := listCost(listCost(listCost(1000, &size, 0), &size, 0), &size, 0)
+	if cost != math.MaxInt {
+		t.Errorf("want the cost to saturate, got %d", cost)
+	}
+}
+
+func TestSelectionDepth(t *testing.T) {
+	es := generated.NewExecutableSchema(generated.Config{Resolvers: &Resolver{}})
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{name: "root fields", query: `{ meta { count } }`, want: 2},
+		{name: "nested fields", query: `{ sources { id applications { authentications { id } } } }`, want: 4},
+		{name: "fragments", query: `{ sources { ...apps } } fragment apps on Source { applications { ... on Application { authentications { id } } } }`, want: 4},
+		{name: "introspection", query: `{ __schema { types { fields { type { ofType { ofType { name } } } } } } meta { count } }`, want: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := selectionDepth(loadOperation(t, es, tt.query).SelectionSet)
+			if got != tt.want {
+				t.Errorf("want the depth %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
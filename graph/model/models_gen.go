@@ -7,26 +7,214 @@ import (
 	"fmt"
 	"io"
 	"strconv"
+
+	"github.com/RedHatInsights/sources-api-go/model"
 )
 
+type ApplicationConnection struct {
+	Edges      []*ApplicationEdge `json:"edges"`
+	PageInfo   *PageInfo          `json:"pageInfo"`
+	TotalCount int                `json:"totalCount"`
+}
+
+type ApplicationCreateInput struct {
+	SourceID          string `json:"source_id"`
+	ApplicationTypeID string `json:"application_type_id"`
+	Extra             any    `json:"extra,omitempty"`
+}
+
+type ApplicationEdge struct {
+	Node   *model.Application `json:"node"`
+	Cursor string             `json:"cursor"`
+}
+
+type ApplicationUpdateInput struct {
+	Extra                   any     `json:"extra,omitempty"`
+	AvailabilityStatus      *string `json:"availability_status,omitempty"`
+	AvailabilityStatusError *string `json:"availability_status_error,omitempty"`
+	LastCheckedAt           *string `json:"last_checked_at,omitempty"`
+	LastAvailableAt         *string `json:"last_available_at,omitempty"`
+}
+
+type AuthenticationConnection struct {
+	Edges      []*AuthenticationEdge `json:"edges"`
+	PageInfo   *PageInfo             `json:"pageInfo"`
+	TotalCount int                   `json:"totalCount"`
+}
+
+type AuthenticationCreateInput struct {
+	ResourceType            string  `json:"resource_type"`
+	ResourceID              string  `json:"resource_id"`
+	Authtype                string  `json:"authtype"`
+	Name                    *string `json:"name,omitempty"`
+	Username                *string `json:"username,omitempty"`
+	Password                *string `json:"password,omitempty"`
+	Extra                   any     `json:"extra,omitempty"`
+	AvailabilityStatusError *string `json:"availability_status_error,omitempty"`
+}
+
+type AuthenticationEdge struct {
+	Node   *model.Authentication `json:"node"`
+	Cursor string                `json:"cursor"`
+}
+
+type AuthenticationUpdateInput struct {
+	Name                    *string `json:"name,omitempty"`
+	Authtype                *string `json:"authtype,omitempty"`
+	Username                *string `json:"username,omitempty"`
+	Password                *string `json:"password,omitempty"`
+	Extra                   any     `json:"extra,omitempty"`
+	AvailabilityStatus      *string `json:"availability_status,omitempty"`
+	AvailabilityStatusError *string `json:"availability_status_error,omitempty"`
+}
+
+type AvailabilityTransition struct {
+	ID                 string `json:"id"`
+	PreviousStatus     string `json:"previous_status"`
+	AvailabilityStatus string `json:"availability_status"`
+}
+
+type DeleteResult struct {
+	ID      string `json:"id"`
+	Pending bool   `json:"pending"`
+}
+
+type EndpointConnection struct {
+	Edges      []*EndpointEdge `json:"edges"`
+	PageInfo   *PageInfo       `json:"pageInfo"`
+	TotalCount int             `json:"totalCount"`
+}
+
+type EndpointCreateInput struct {
+	SourceID             string  `json:"source_id"`
+	Default              *bool   `json:"default,omitempty"`
+	ReceptorNode         *string `json:"receptor_node,omitempty"`
+	Role                 *string `json:"role,omitempty"`
+	Scheme               *string `json:"scheme,omitempty"`
+	Host                 *string `json:"host,omitempty"`
+	Port                 *int    `json:"port,omitempty"`
+	Path                 *string `json:"path,omitempty"`
+	VerifySsl            *bool   `json:"verify_ssl,omitempty"`
+	CertificateAuthority *string `json:"certificate_authority,omitempty"`
+	AvailabilityStatus   *string `json:"availability_status,omitempty"`
+}
+
+type EndpointEdge struct {
+	Node   *model.Endpoint `json:"node"`
+	Cursor string          `json:"cursor"`
+}
+
+type EndpointUpdateInput struct {
+	Default                 *bool   `json:"default,omitempty"`
+	ReceptorNode            *string `json:"receptor_node,omitempty"`
+	Role                    *string `json:"role,omitempty"`
+	Scheme                  *string `json:"scheme,omitempty"`
+	Host                    *string `json:"host,omitempty"`
+	Port                    *int    `json:"port,omitempty"`
+	Path                    *string `json:"path,omitempty"`
+	VerifySsl               *bool   `json:"verify_ssl,omitempty"`
+	CertificateAuthority    *string `json:"certificate_authority,omitempty"`
+	AvailabilityStatus      *string `json:"availability_status,omitempty"`
+	AvailabilityStatusError *string `json:"availability_status_error,omitempty"`
+	LastCheckedAt           *string `json:"last_checked_at,omitempty"`
+	LastAvailableAt         *string `json:"last_available_at,omitempty"`
+}
+
 type Filter struct {
-	Name      string   `json:"name"`
-	Operation *string  `json:"operation,omitempty"`
-	Value     []string `json:"value"`
+	Name      *string   `json:"name,omitempty"`
+	Operation *string   `json:"operation,omitempty"`
+	Value     []string  `json:"value,omitempty"`
+	Or        []*Filter `json:"or,omitempty"`
+	And       []*Filter `json:"and,omitempty"`
+	Not       *Filter   `json:"not,omitempty"`
 }
 
 type Meta struct {
-	Count int `json:"count"`
+	Count            int                  `json:"count"`
+	SourceStats      *model.StatsResponse `json:"source_stats"`
+	ApplicationStats *model.StatsResponse `json:"application_stats"`
+}
+
+type Mutation struct {
+}
+
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor,omitempty"`
+	EndCursor       *string `json:"endCursor,omitempty"`
 }
 
 type Query struct {
 }
 
+type RhcConnectionConnection struct {
+	Edges      []*RhcConnectionEdge `json:"edges"`
+	PageInfo   *PageInfo            `json:"pageInfo"`
+	TotalCount int                  `json:"totalCount"`
+}
+
+type RhcConnectionEdge struct {
+	Node   *model.RhcConnection `json:"node"`
+	Cursor string               `json:"cursor"`
+}
+
 type SortBy struct {
 	Name      string     `json:"name"`
 	Direction *Direction `json:"direction,omitempty"`
 }
 
+type SourceConnection struct {
+	Edges      []*SourceEdge `json:"edges"`
+	PageInfo   *PageInfo     `json:"pageInfo"`
+	TotalCount int           `json:"totalCount"`
+}
+
+type SourceCreateInput struct {
+	Name                string  `json:"name"`
+	SourceTypeID        string  `json:"source_type_id"`
+	UID                 *string `json:"uid,omitempty"`
+	Version             *string `json:"version,omitempty"`
+	Imported            *string `json:"imported,omitempty"`
+	SourceRef           *string `json:"source_ref,omitempty"`
+	AppCreationWorkflow *string `json:"app_creation_workflow,omitempty"`
+	AvailabilityStatus  *string `json:"availability_status,omitempty"`
+}
+
+type SourceEdge struct {
+	Node   *model.Source `json:"node"`
+	Cursor string        `json:"cursor"`
+}
+
+type SourceTypeConnection struct {
+	Edges      []*SourceTypeEdge `json:"edges"`
+	PageInfo   *PageInfo         `json:"pageInfo"`
+	TotalCount int               `json:"totalCount"`
+}
+
+type SourceTypeEdge struct {
+	Node   *model.SourceType `json:"node"`
+	Cursor string            `json:"cursor"`
+}
+
+type SourceUpdateInput struct {
+	Name               *string `json:"name,omitempty"`
+	Version            *string `json:"version,omitempty"`
+	Imported           *string `json:"imported,omitempty"`
+	SourceRef          *string `json:"source_ref,omitempty"`
+	AvailabilityStatus *string `json:"availability_status,omitempty"`
+	LastCheckedAt      *string `json:"last_checked_at,omitempty"`
+	LastAvailableAt    *string `json:"last_available_at,omitempty"`
+}
+
+type StatsDimension struct {
+	Name  string  `json:"name"`
+	Value *string `json:"value,omitempty"`
+}
+
+type Subscription struct {
+}
+
 type Direction string
 
 const (
@@ -0,0 +1,241 @@
+package graph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/jobs"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// authorize runs the REST API's permission check for the write operation the HTTP method and the path stand for, so
+// that a mutation needs the same permissions as its REST counterpart.
+func authorize(ctx context.Context, method, path string) error {
+	rd := getRequestDataFromCtx(ctx)
+	if rd.Authorize == nil {
+		return fmt.Errorf("unable to authorize the mutation")
+	}
+
+	return rd.Authorize(method, path)
+}
+
+// decodeInput converts the mutation's input into the REST API's request, so that both get validated the same way.
+// The inputs only carry the fields the client set, and the fields the request does not have are rejected like the
+// REST API rejects them, which happens when editing the paused resources.
+func decodeInput(input interface{}, request interface{}) error {
+	encoded, err := json.Marshal(input)
+	if err != nil {
+		return err
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(encoded))
+	decoder.DisallowUnknownFields()
+
+	err = decoder.Decode(request)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	return nil
+}
+
+// parseID parses the ID of the resource the mutation changes.
+func parseID(id string) (int64, error) {
+	parsed, err := strconv.ParseInt(id, 10, 64)
+	if err != nil {
+		return 0, util.NewErrBadRequest(err)
+	}
+
+	return parsed, nil
+}
+
+// raiseEvent raises the resource's event asynchronously, like the "RaiseEvent" middleware does for the REST API.
+func raiseEvent(ctx context.Context, eventType string, resource m.Event) {
+	// every event gets its own copy of the headers, since raising the event appends to them.
+	headers := slices.Clone(getRequestDataFromCtx(ctx).Headers)
+
+	go func() {
+		err := service.RaiseEvent(eventType, resource, headers)
+		if err != nil {
+			logging.Log.Warnf("Error raising event %v: %v", eventType, err)
+		}
+	}()
+}
+
+// raiseEventNow raises the resource's event synchronously, like the pause and unpause handlers do.
+func raiseEventNow(ctx context.Context, eventType string, resource m.Event) error {
+	return service.RaiseEvent(eventType, resource, slices.Clone(getRequestDataFromCtx(ctx).Headers))
+}
+
+// auditSnapshot takes the snapshot of the resource before the mutation changes it, so that the audit trail is able to
+// tell which fields changed.
+func auditSnapshot(resource m.Event) []byte {
+	snapshot, err := service.AuditSnapshot(resource)
+	if err != nil {
+		logging.Log.Warnf("Unable to take the audit snapshot: %v", err)
+		return nil
+	}
+
+	return snapshot
+}
+
+// recordAuditEvent records the change in the audit trail, like the "AuditTrail" middleware does for the REST API. The
+// change has already been committed at this point, so failing to record it does not fail the mutation.
+func recordAuditEvent(ctx context.Context, eventType string, resource m.Event, previous []byte) {
+	rd := getRequestDataFromCtx(ctx)
+
+	err := service.RecordAuditEvent(rd.requestParams(), eventType, resource, previous, rd.AuditActorType, rd.AuditActorID)
+	if err != nil {
+		logging.Log.Warnf("Unable to record the audit event %v: %v", eventType, err)
+	}
+}
+
+// notifyAvailabilityStatus emits the availability status notification when the resource's status changed, like the
+// "Notifier" middleware does for the REST API.
+func notifyAvailabilityStatus(ctx context.Context, previousStatus string, resource m.EmailNotification) error {
+	emailNotificationInfo := resource.ToEmail(previousStatus)
+	if emailNotificationInfo.PreviousAvailabilityStatus == emailNotificationInfo.CurrentAvailabilityStatus {
+		return nil
+	}
+
+	rd := getRequestDataFromCtx(ctx)
+	if rd.Identity == nil {
+		return fmt.Errorf("failed to fetch the identity header")
+	}
+
+	return service.EmitAvailabilityStatusNotification(&rd.Identity.Identity, emailNotificationInfo, "notifier-middleware")
+}
+
+// enqueueSuperKeyDelete enqueues the job which has the superkey worker clean up the cloud resources of the source or
+// the application before they get deleted.
+func enqueueSuperKeyDelete(ctx context.Context, model string, id int64) {
+	rd := getRequestDataFromCtx(ctx)
+
+	jobs.Enqueue(&jobs.SuperkeyDestroyJob{
+		Headers:  slices.Clone(rd.Headers),
+		Tenant:   rd.TenantID,
+		Identity: rd.XRHID,
+		Model:    model,
+		Id:       id,
+	})
+}
+
+// setSourcePaused pauses or unpauses the source along with its applications, and raises their events like the
+// "SourcePause" and "SourceUnpause" handlers do.
+func setSourcePaused(ctx context.Context, id string, paused bool) (*m.Source, error) {
+	sourceID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	action := "unpause"
+	if paused {
+		action = "pause"
+	}
+
+	err = authorize(ctx, http.MethodPost, "/sources/"+id+"/"+action)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceDao := dao.GetSourceDao(getRequestDataFromCtx(ctx).requestParams())
+
+	srcExists, err := sourceDao.Exists(sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !srcExists {
+		return nil, util.NewErrNotFound("source")
+	}
+
+	if paused {
+		err = sourceDao.Pause(sourceID)
+	} else {
+		err = sourceDao.Unpause(sourceID)
+	}
+
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	source, err := sourceDao.GetByIdWithPreload(&sourceID, "Applications")
+	if err != nil {
+		return nil, err
+	}
+
+	err = raiseEventNow(ctx, "Source."+action, source)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range source.Applications {
+		err = raiseEventNow(ctx, "Application."+action, &source.Applications[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return source, nil
+}
+
+// setApplicationPaused pauses or unpauses the application, and raises its event like the "ApplicationPause" and
+// "ApplicationUnpause" handlers do.
+func setApplicationPaused(ctx context.Context, id string, paused bool) (*m.Application, error) {
+	applicationID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	action := "unpause"
+	if paused {
+		action = "pause"
+	}
+
+	err = authorize(ctx, http.MethodPost, "/applications/"+id+"/"+action)
+	if err != nil {
+		return nil, err
+	}
+
+	applicationDao := dao.GetApplicationDao(getRequestDataFromCtx(ctx).requestParams())
+
+	appExists, err := applicationDao.Exists(applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !appExists {
+		return nil, util.NewErrNotFound("application")
+	}
+
+	if paused {
+		err = applicationDao.Pause(applicationID)
+	} else {
+		err = applicationDao.Unpause(applicationID)
+	}
+
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	application, err := applicationDao.GetById(&applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = raiseEventNow(ctx, "Application."+action, application)
+	if err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
@@ -0,0 +1,704 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.80
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/graph/generated"
+	generated_model "github.com/RedHatInsights/sources-api-go/graph/model"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// CreateSource is the resolver for the create_source field.
+func (r *mutationResolver) CreateSource(ctx context.Context, input generated_model.SourceCreateInput) (*model.Source, error) {
+	err := authorize(ctx, http.MethodPost, "/sources")
+	if err != nil {
+		return nil, err
+	}
+
+	request := &model.SourceCreateRequest{}
+
+	err = decodeInput(input, request)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceDao := dao.GetSourceDao(getRequestDataFromCtx(ctx).requestParams())
+
+	err = service.ValidateSourceCreationRequest(sourceDao, request)
+	if err != nil {
+		return nil, util.NewErrBadRequest(fmt.Sprintf("Validation failed: %v", err))
+	}
+
+	source := &model.Source{
+		Name:                *request.Name,
+		Uid:                 request.Uid,
+		Version:             request.Version,
+		Imported:            request.Imported,
+		SourceRef:           request.SourceRef,
+		AppCreationWorkflow: request.AppCreationWorkflow,
+		AvailabilityStatus:  request.AvailabilityStatus,
+		SourceTypeID:        *request.SourceTypeID,
+	}
+
+	err = sourceDao.Create(source)
+	if err != nil {
+		return nil, err
+	}
+
+	raiseEvent(ctx, "Source.create", source)
+	recordAuditEvent(ctx, "Source.create", source, nil)
+
+	return source, nil
+}
+
+// UpdateSource is the resolver for the update_source field.
+func (r *mutationResolver) UpdateSource(ctx context.Context, id string, input generated_model.SourceUpdateInput) (*model.Source, error) {
+	sourceID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorize(ctx, http.MethodPatch, "/sources/"+id)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceDao := dao.GetSourceDao(getRequestDataFromCtx(ctx).requestParams())
+
+	source, err := sourceDao.GetById(&sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := auditSnapshot(source)
+	previousStatus := source.AvailabilityStatus
+
+	// If "PausedAt" contains a date it means that the source was paused back then.
+	if source.PausedAt != nil {
+		request := &model.SourcePausedEditRequest{}
+
+		err = decodeInput(input, request)
+		if err != nil {
+			return nil, err
+		}
+
+		err = source.UpdateFromRequestPaused(request)
+		if err != nil {
+			return nil, util.NewErrBadRequest(err)
+		}
+	} else {
+		request := &model.SourceEditRequest{}
+
+		err = decodeInput(input, request)
+		if err != nil {
+			return nil, err
+		}
+
+		err = service.ValidateSourceEditRequest(sourceDao, request)
+		if err != nil {
+			return nil, util.NewErrBadRequest(err)
+		}
+
+		source.UpdateFromRequest(request)
+	}
+
+	err = sourceDao.Update(source)
+	if err != nil {
+		return nil, err
+	}
+
+	err = notifyAvailabilityStatus(ctx, previousStatus, source)
+	if err != nil {
+		return nil, err
+	}
+
+	raiseEvent(ctx, "Source.update", source)
+	recordAuditEvent(ctx, "Source.update", source, previous)
+
+	return source, nil
+}
+
+// DeleteSource is the resolver for the delete_source field.
+func (r *mutationResolver) DeleteSource(ctx context.Context, id string) (*generated_model.DeleteResult, error) {
+	sourceID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorize(ctx, http.MethodDelete, "/sources/"+id)
+	if err != nil {
+		return nil, err
+	}
+
+	rd := getRequestDataFromCtx(ctx)
+	sourceDao := dao.GetSourceDao(rd.requestParams())
+
+	source, err := sourceDao.GetById(&sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	// We only allow delete with cert auth if source type is Satellite.
+	if rd.CertAuth && source.SourceTypeID != dao.Static.GetSourceTypeId("satellite") {
+		return nil, util.NewErrBadRequest("Unauthorized.")
+	}
+
+	result := &generated_model.DeleteResult{ID: strconv.FormatInt(sourceID, 10)}
+
+	// Keep the source around for the retention window so that it can be restored.
+	if config.Get().SourceRetention > 0 {
+		_, err = sourceDao.SoftDelete(sourceID)
+		if err != nil {
+			return nil, err
+		}
+
+		recordAuditEvent(ctx, "Source.destroy", source, nil)
+
+		return result, nil
+	}
+
+	// Superkey sources are deleted asynchronously, once the superkey worker cleans up the cloud resources.
+	if sourceDao.IsSuperkey(sourceID) {
+		enqueueSuperKeyDelete(ctx, "source", sourceID)
+
+		result.Pending = true
+		return result, nil
+	}
+
+	err = service.DeleteCascade(sourceDao.Tenant(), sourceDao.User(), "Source", sourceID, slices.Clone(rd.Headers))
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	recordAuditEvent(ctx, "Source.destroy", source, nil)
+
+	return result, nil
+}
+
+// PauseSource is the resolver for the pause_source field.
+func (r *mutationResolver) PauseSource(ctx context.Context, id string) (*model.Source, error) {
+	return setSourcePaused(ctx, id, true)
+}
+
+// UnpauseSource is the resolver for the unpause_source field.
+func (r *mutationResolver) UnpauseSource(ctx context.Context, id string) (*model.Source, error) {
+	return setSourcePaused(ctx, id, false)
+}
+
+// CreateApplication is the resolver for the create_application field.
+func (r *mutationResolver) CreateApplication(ctx context.Context, input generated_model.ApplicationCreateInput) (*model.Application, error) {
+	err := authorize(ctx, http.MethodPost, "/applications")
+	if err != nil {
+		return nil, err
+	}
+
+	request := &model.ApplicationCreateRequest{}
+
+	err = decodeInput(input, request)
+	if err != nil {
+		return nil, err
+	}
+
+	rd := getRequestDataFromCtx(ctx)
+
+	err = service.ValidateApplicationCreateRequest(rd.requestParams(), request)
+	if err != nil {
+		return nil, util.NewErrBadRequest(fmt.Sprintf("Validation failed: %v", err))
+	}
+
+	application := &model.Application{
+		Extra:             request.Extra,
+		ApplicationTypeID: request.ApplicationTypeID,
+		SourceID:          request.SourceID,
+	}
+
+	applicationDao := dao.GetApplicationDao(rd.requestParams())
+
+	err = applicationDao.Create(application)
+	if err != nil {
+		return nil, err
+	}
+
+	application.Tenant = model.Tenant{Id: application.TenantID, ExternalTenant: rd.AccountNumber}
+
+	// do not raise if it is a superkey application. The worker will post back
+	// with the resources and then we raise the create event.
+	if applicationDao.IsSuperkey(application.ID) {
+		headers := slices.Clone(rd.Headers)
+
+		go func() {
+			err := r.SuperKeyService.SendCreateRequest(application, headers)
+			if err != nil {
+				logging.Log.Warnf("Error sending Superkey Create Request: %v", err)
+			}
+		}()
+	} else {
+		raiseEvent(ctx, "Application.create", application)
+	}
+
+	recordAuditEvent(ctx, "Application.create", application, nil)
+
+	return application, nil
+}
+
+// UpdateApplication is the resolver for the update_application field.
+func (r *mutationResolver) UpdateApplication(ctx context.Context, id string, input generated_model.ApplicationUpdateInput) (*model.Application, error) {
+	applicationID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorize(ctx, http.MethodPatch, "/applications/"+id)
+	if err != nil {
+		return nil, err
+	}
+
+	applicationDao := dao.GetApplicationDao(getRequestDataFromCtx(ctx).requestParams())
+
+	application, err := applicationDao.GetByIdWithPreload(&applicationID, "Tenant", "Source")
+	if err != nil {
+		return nil, util.NewErrNotFound("application")
+	}
+
+	previous := auditSnapshot(application)
+	previousStatus := application.AvailabilityStatus
+
+	var statusFromRequest *string
+
+	if application.PausedAt != nil {
+		request := &model.ResourceEditPausedRequest{}
+
+		err = decodeInput(input, request)
+		if err != nil {
+			return nil, err
+		}
+
+		statusFromRequest = request.AvailabilityStatus
+
+		err = application.UpdateFromRequestPaused(request)
+		if err != nil {
+			return nil, util.NewErrBadRequest(err)
+		}
+	} else {
+		request := &model.ApplicationEditRequest{}
+
+		err = decodeInput(input, request)
+		if err != nil {
+			return nil, err
+		}
+
+		err = service.ValidateApplicationEditRequest(request)
+		if err != nil {
+			return nil, util.NewErrBadRequest(fmt.Errorf(`invalid payload: %w`, err))
+		}
+
+		statusFromRequest = request.AvailabilityStatus
+		application.UpdateFromRequest(request)
+	}
+
+	err = applicationDao.Update(application)
+	if err != nil {
+		return nil, err
+	}
+
+	if statusFromRequest != nil {
+		err = service.UpdateSourceFromApplicationAvailabilityStatus(application, previousStatus)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = notifyAvailabilityStatus(ctx, previousStatus, application)
+	if err != nil {
+		return nil, err
+	}
+
+	// for this model we ONLY raise the update for superkey sources once the
+	// worker posts back.
+	eventType := "Application.update"
+	if applicationDao.IsSuperkey(application.ID) && application.GotSuperkeyUpdate {
+		eventType = "Application.create"
+	}
+
+	raiseEvent(ctx, eventType, application)
+	recordAuditEvent(ctx, "Application.update", application, previous)
+
+	return application, nil
+}
+
+// DeleteApplication is the resolver for the delete_application field.
+func (r *mutationResolver) DeleteApplication(ctx context.Context, id string) (*generated_model.DeleteResult, error) {
+	applicationID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorize(ctx, http.MethodDelete, "/applications/"+id)
+	if err != nil {
+		return nil, err
+	}
+
+	rd := getRequestDataFromCtx(ctx)
+	applicationDao := dao.GetApplicationDao(rd.requestParams())
+
+	applicationExists, err := applicationDao.Exists(applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !applicationExists {
+		return nil, util.NewErrNotFound("application")
+	}
+
+	application, err := applicationDao.GetById(&applicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &generated_model.DeleteResult{ID: strconv.FormatInt(applicationID, 10)}
+
+	// Superkey applications are deleted asynchronously, once the superkey worker cleans up the cloud resources.
+	if applicationDao.IsSuperkey(applicationID) {
+		enqueueSuperKeyDelete(ctx, "application", applicationID)
+
+		result.Pending = true
+		return result, nil
+	}
+
+	err = service.DeleteCascade(applicationDao.Tenant(), applicationDao.User(), "Application", applicationID, slices.Clone(rd.Headers))
+	if err != nil {
+		return nil, err
+	}
+
+	recordAuditEvent(ctx, "Application.destroy", application, nil)
+
+	return result, nil
+}
+
+// PauseApplication is the resolver for the pause_application field.
+func (r *mutationResolver) PauseApplication(ctx context.Context, id string) (*model.Application, error) {
+	return setApplicationPaused(ctx, id, true)
+}
+
+// UnpauseApplication is the resolver for the unpause_application field.
+func (r *mutationResolver) UnpauseApplication(ctx context.Context, id string) (*model.Application, error) {
+	return setApplicationPaused(ctx, id, false)
+}
+
+// CreateEndpoint is the resolver for the create_endpoint field.
+func (r *mutationResolver) CreateEndpoint(ctx context.Context, input generated_model.EndpointCreateInput) (*model.Endpoint, error) {
+	err := authorize(ctx, http.MethodPost, "/endpoints")
+	if err != nil {
+		return nil, err
+	}
+
+	request := &model.EndpointCreateRequest{}
+
+	err = decodeInput(input, request)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check that source exists
+	sourceID, err := util.InterfaceToInt64(request.SourceIDRaw)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	rd := getRequestDataFromCtx(ctx)
+
+	sourceExists, err := dao.GetSourceDao(rd.requestParams()).Exists(sourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !sourceExists {
+		return nil, util.NewErrBadRequest("source id not found")
+	}
+
+	endpointDao := dao.GetEndpointDao(&rd.TenantID)
+
+	err = service.ValidateEndpointCreateRequest(endpointDao, request)
+	if err != nil {
+		return nil, util.NewErrBadRequest(fmt.Sprintf("Validation failed: %s", err))
+	}
+
+	endpoint := &model.Endpoint{
+		Default:              &request.Default,
+		ReceptorNode:         request.ReceptorNode,
+		Role:                 &request.Role,
+		Scheme:               request.Scheme,
+		Host:                 &request.Host,
+		Port:                 request.Port,
+		Path:                 &request.Path,
+		VerifySsl:            request.VerifySsl,
+		CertificateAuthority: request.CertificateAuthority,
+		AvailabilityStatus:   request.AvailabilityStatus,
+		SourceID:             request.SourceID,
+	}
+
+	err = endpointDao.Create(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	raiseEvent(ctx, "Endpoint.create", endpoint)
+	recordAuditEvent(ctx, "Endpoint.create", endpoint, nil)
+
+	return endpoint, nil
+}
+
+// UpdateEndpoint is the resolver for the update_endpoint field.
+func (r *mutationResolver) UpdateEndpoint(ctx context.Context, id string, input generated_model.EndpointUpdateInput) (*model.Endpoint, error) {
+	endpointID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorize(ctx, http.MethodPatch, "/endpoints/"+id)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointDao := dao.GetEndpointDao(tenantIdFromCtx(ctx))
+
+	endpoint, err := endpointDao.GetById(&endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := auditSnapshot(endpoint)
+	previousStatus := endpoint.AvailabilityStatus
+
+	// If "PausedAt" contains a date it means that the endpoint was paused back then.
+	if endpoint.PausedAt != nil {
+		request := &model.ResourceEditPausedRequest{}
+
+		err = decodeInput(input, request)
+		if err != nil {
+			return nil, err
+		}
+
+		err = endpoint.UpdateFromRequestPaused(request)
+		if err != nil {
+			return nil, util.NewErrBadRequest(err)
+		}
+	} else {
+		request := &model.EndpointEditRequest{}
+
+		err = decodeInput(input, request)
+		if err != nil {
+			return nil, err
+		}
+
+		err = service.ValidateEndpointEditRequest(endpointDao, endpoint.SourceID, request)
+		if err != nil {
+			return nil, util.NewErrBadRequest(err)
+		}
+
+		endpoint.UpdateFromRequest(request)
+	}
+
+	err = endpointDao.Update(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	err = notifyAvailabilityStatus(ctx, previousStatus, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	raiseEvent(ctx, "Endpoint.update", endpoint)
+	recordAuditEvent(ctx, "Endpoint.update", endpoint, previous)
+
+	return endpoint, nil
+}
+
+// DeleteEndpoint is the resolver for the delete_endpoint field.
+func (r *mutationResolver) DeleteEndpoint(ctx context.Context, id string) (*generated_model.DeleteResult, error) {
+	endpointID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	err = authorize(ctx, http.MethodDelete, "/endpoints/"+id)
+	if err != nil {
+		return nil, err
+	}
+
+	endpointDao := dao.GetEndpointDao(tenantIdFromCtx(ctx))
+
+	endpointExists, err := endpointDao.Exists(endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !endpointExists {
+		return nil, util.NewErrNotFound("endpoint")
+	}
+
+	endpoint, err := endpointDao.GetById(&endpointID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.DeleteCascade(endpointDao.Tenant(), nil, "Endpoint", endpointID, slices.Clone(getRequestDataFromCtx(ctx).Headers))
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	recordAuditEvent(ctx, "Endpoint.destroy", endpoint, nil)
+
+	return &generated_model.DeleteResult{ID: strconv.FormatInt(endpointID, 10)}, nil
+}
+
+// CreateAuthentication is the resolver for the create_authentication field.
+func (r *mutationResolver) CreateAuthentication(ctx context.Context, input generated_model.AuthenticationCreateInput) (*model.Authentication, error) {
+	err := authorize(ctx, http.MethodPost, "/authentications")
+	if err != nil {
+		return nil, err
+	}
+
+	request := &model.AuthenticationCreateRequest{}
+
+	err = decodeInput(input, request)
+	if err != nil {
+		return nil, err
+	}
+
+	err = service.ValidateAuthenticationCreationRequest(request)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	auth := &model.Authentication{
+		Name:         request.Name,
+		AuthType:     request.AuthType,
+		Username:     request.Username,
+		ResourceType: request.ResourceType,
+		ResourceID:   request.ResourceID,
+	}
+
+	err = auth.SetExtra(request.Extra)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	err = auth.SetPassword(request.Password)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	rd := getRequestDataFromCtx(ctx)
+
+	err = dao.GetAuthenticationDao(rd.requestParams()).Create(auth)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	auth.Tenant = model.Tenant{Id: auth.TenantID, ExternalTenant: rd.AccountNumber}
+
+	raiseEvent(ctx, "Authentication.create", auth)
+	recordAuditEvent(ctx, "Authentication.create", auth, nil)
+
+	return auth, nil
+}
+
+// UpdateAuthentication is the resolver for the update_authentication field.
+func (r *mutationResolver) UpdateAuthentication(ctx context.Context, id string, input generated_model.AuthenticationUpdateInput) (*model.Authentication, error) {
+	err := authorize(ctx, http.MethodPatch, "/authentications/"+id)
+	if err != nil {
+		return nil, err
+	}
+
+	request := &model.AuthenticationEditRequest{}
+
+	err = decodeInput(input, request)
+	if err != nil {
+		return nil, err
+	}
+
+	rd := getRequestDataFromCtx(ctx)
+	authDao := dao.GetAuthenticationDao(rd.requestParams())
+
+	auth, err := authDao.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	previous := auditSnapshot(auth)
+
+	err = service.ValidateAuthenticationEditRequest(request)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	previousStatus := ""
+	if auth.AvailabilityStatus != nil {
+		previousStatus = *auth.AvailabilityStatus
+	}
+
+	err = auth.UpdateFromRequest(request)
+	if err != nil {
+		return nil, util.NewErrBadRequest(`invalid JSON given in "extra" field`)
+	}
+
+	err = authDao.Update(auth)
+	if err != nil {
+		return nil, util.NewErrBadRequest(err)
+	}
+
+	source, err := dao.GetSourceDao(rd.requestParams()).GetById(&auth.SourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	auth.Source = *source
+
+	err = notifyAvailabilityStatus(ctx, previousStatus, auth)
+	if err != nil {
+		return nil, err
+	}
+
+	raiseEvent(ctx, "Authentication.update", auth)
+	recordAuditEvent(ctx, "Authentication.update", auth, previous)
+
+	return auth, nil
+}
+
+// DeleteAuthentication is the resolver for the delete_authentication field.
+func (r *mutationResolver) DeleteAuthentication(ctx context.Context, id string) (*generated_model.DeleteResult, error) {
+	err := authorize(ctx, http.MethodDelete, "/authentications/"+id)
+	if err != nil {
+		return nil, err
+	}
+
+	auth, err := dao.GetAuthenticationDao(getRequestDataFromCtx(ctx).requestParams()).Delete(id)
+	if err != nil {
+		return nil, err
+	}
+
+	raiseEvent(ctx, "Authentication.destroy", auth)
+	recordAuditEvent(ctx, "Authentication.destroy", auth, nil)
+
+	return &generated_model.DeleteResult{ID: id}, nil
+}
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+type mutationResolver struct{ *Resolver }
@@ -0,0 +1,75 @@
+package graph
+
+import (
+	"errors"
+	"testing"
+
+	generated_model "github.com/RedHatInsights/sources-api-go/graph/model"
+	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+func TestDecodeInputCreateRequest(t *testing.T) {
+	workflow := "account_authorization"
+	input := generated_model.SourceCreateInput{Name: "source", SourceTypeID: "1", AppCreationWorkflow: &workflow}
+
+	request := &model.SourceCreateRequest{}
+
+	err := decodeInput(input, request)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if request.Name == nil || *request.Name != "source" {
+		t.Errorf("want the name %q, got %v", "source", request.Name)
+	}
+
+	if request.SourceTypeIDRaw != "1" {
+		t.Errorf("want the source type id %q, got %v", "1", request.SourceTypeIDRaw)
+	}
+
+	if request.AppCreationWorkflow != workflow {
+		t.Errorf("want the workflow %q, got %q", workflow, request.AppCreationWorkflow)
+	}
+
+	if request.Imported != nil || request.SourceRef != nil {
+		t.Errorf("want the fields which were not set to be left empty, got %+v", request)
+	}
+}
+
+func TestDecodeInputUnknownField(t *testing.T) {
+	name := "renamed"
+	input := generated_model.SourceUpdateInput{Name: &name}
+
+	// the paused sources only take the availability fields, so the name must be rejected like the REST API does.
+	err := decodeInput(input, &model.SourcePausedEditRequest{})
+	if !errors.As(err, &util.ErrBadRequest{}) {
+		t.Errorf("want a bad request error, got %v", err)
+	}
+
+	status := "available"
+	input = generated_model.SourceUpdateInput{AvailabilityStatus: &status}
+
+	request := &model.SourcePausedEditRequest{}
+
+	err = decodeInput(input, request)
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if request.AvailabilityStatus == nil || *request.AvailabilityStatus != status {
+		t.Errorf("want the availability status %q, got %v", status, request.AvailabilityStatus)
+	}
+}
+
+func TestParseID(t *testing.T) {
+	id, err := parseID("12")
+	if err != nil || id != 12 {
+		t.Errorf("want the id 12, got %d and %v", id, err)
+	}
+
+	_, err = parseID("abc")
+	if !errors.As(err, &util.ErrBadRequest{}) {
+		t.Errorf("want a bad request error, got %v", err)
+	}
+}
@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/99designs/gqlgen/graphql/errcode"
+	"github.com/RedHatInsights/sources-api-go/graph/generated"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/RedHatInsights/sources-api-go/redis"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+const (
+	// apqKeyPrefix is the prefix of the keys the queries the clients persist are cached under, by their hash.
+	apqKeyPrefix = "sources_api:graphql:apq:"
+	// allowlistKeyPrefix is the prefix of the keys the registered queries are stored under, by their hash.
+	allowlistKeyPrefix = "sources_api:graphql:allowlist:"
+
+	errPersistedQueryNotAllowed = "PERSISTED_QUERY_NOT_ALLOWED"
+)
+
+// PersistedQueryStore stores the GraphQL queries by their hash.
+type PersistedQueryStore interface {
+	// Get returns the stored query for the given key, or an empty string if the key does not exist.
+	Get(ctx context.Context, key string) (string, error)
+	// Set stores the query, which expires after the TTL, or never if the TTL is zero.
+	Set(ctx context.Context, key string, query string, ttl time.Duration) error
+}
+
+// PersistedQueries is the store the persisted queries are kept in. It is a variable so that it can be replaced in the
+// tests.
+var PersistedQueries PersistedQueryStore = valkeyPersistedQueryStore{}
+
+// valkeyPersistedQueryStore is the default store, backed by Valkey.
+type valkeyPersistedQueryStore struct{}
+
+func (valkeyPersistedQueryStore) Get(ctx context.Context, key string) (string, error) {
+	query, err := redis.Client.Do(ctx, redis.Client.B().Get().Key(key).Build()).ToString()
+	if redis.IsNil(err) {
+		return "", nil
+	}
+
+	return query, err
+}
+
+func (valkeyPersistedQueryStore) Set(ctx context.Context, key string, query string, ttl time.Duration) error {
+	if ttl == 0 {
+		return redis.Client.Do(ctx, redis.Client.B().Set().Key(key).Value(query).Build()).Error()
+	}
+
+	return redis.Client.Do(ctx, redis.Client.B().Set().Key(key).Value(query).Px(ttl).Build()).Error()
+}
+
+// APQCache is the cache of the automatic persisted queries, which lets the clients send the hash of a query instead of
+// the whole query once they have sent it. The registered queries are found by their hash too. In the allowlist mode,
+// the clients cannot persist any queries, and only the registered ones are found.
+type APQCache struct {
+	TTL       time.Duration
+	Allowlist bool
+}
+
+var _ graphql.Cache[string] = APQCache{}
+
+func (a APQCache) Get(ctx context.Context, hash string) (string, bool) {
+	keys := []string{allowlistKeyPrefix + hash}
+	if !a.Allowlist {
+		keys = append([]string{apqKeyPrefix + hash}, keys...)
+	}
+
+	for _, key := range keys {
+		query, err := PersistedQueries.Get(ctx, key)
+		if err != nil {
+			logging.Log.Warnf(`Unable to look up the persisted query "%s": %s`, hash, err)
+			return "", false
+		}
+
+		if query != "" {
+			return query, true
+		}
+	}
+
+	return "", false
+}
+
+func (a APQCache) Add(ctx context.Context, hash string, query string) {
+	if a.Allowlist {
+		return
+	}
+
+	// the query runs anyway, so failing to persist it only means that the
+	// client has to send it again.
+	err := PersistedQueries.Set(ctx, apqKeyPrefix+hash, query, a.TTL)
+	if err != nil {
+		logging.Log.Warnf(`Unable to persist the query "%s": %s`, hash, err)
+	}
+}
+
+// PersistedQueryAllowlist rejects the operations whose query was not registered, whether the clients send the query's
+// hash or the whole query.
+type PersistedQueryAllowlist struct{}
+
+var _ interface {
+	graphql.OperationParameterMutator
+	graphql.HandlerExtension
+} = PersistedQueryAllowlist{}
+
+func (PersistedQueryAllowlist) ExtensionName() string {
+	return "PersistedQueryAllowlist"
+}
+
+func (PersistedQueryAllowlist) Validate(_ graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (PersistedQueryAllowlist) MutateOperationParameters(ctx context.Context, rawParams *graphql.RawParams) *gqlerror.Error {
+	hash := queryHash(rawParams.Query)
+
+	// the persisted queries' hashes are checked against their queries by the
+	// automatic persisted queries extension, which runs after this one.
+	if persistedQuery, ok := rawParams.Extensions["persistedQuery"].(map[string]interface{}); ok {
+		if sha256Hash, ok := persistedQuery["sha256Hash"].(string); ok {
+			hash = sha256Hash
+		}
+	}
+
+	query, err := PersistedQueries.Get(ctx, allowlistKeyPrefix+hash)
+	if err != nil {
+		logging.Log.Warnf(`Unable to look up the persisted query "%s": %s`, hash, err)
+		return gqlerror.Errorf("unable to look up the persisted query")
+	}
+
+	if query == "" {
+		gqlErr := gqlerror.Errorf("the query is not in the allowlist")
+		errcode.Set(gqlErr, errPersistedQueryNotAllowed)
+		return gqlErr
+	}
+
+	// saves looking the query up again when the client only sent its hash.
+	if rawParams.Query == "" {
+		rawParams.Query = query
+	}
+
+	return nil
+}
+
+// RegisterPersistedQuery validates the query against the schema and registers it, so that it is allowed in the
+// allowlist mode and can be run by its hash. The registered queries do not expire.
+func RegisterPersistedQuery(ctx context.Context, query string) (string, error) {
+	_, errs := gqlparser.LoadQuery(generated.NewExecutableSchema(generated.Config{}).Schema(), query)
+	if len(errs) > 0 {
+		return "", util.NewErrBadRequest(errs.Error())
+	}
+
+	hash := queryHash(query)
+
+	err := PersistedQueries.Set(ctx, allowlistKeyPrefix+hash, query, 0)
+	if err != nil {
+		return "", err
+	}
+
+	return hash, nil
+}
+
+// queryHash returns the hex encoded SHA-256 hash of the query, which is the hash the clients send.
+func queryHash(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,153 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// usePersistedQueryStore replaces the persisted query store for the duration of the test.
+func usePersistedQueryStore(t *testing.T) *mocks.MockPersistedQueryStore {
+	backup := PersistedQueries
+	t.Cleanup(func() { PersistedQueries = backup })
+
+	store := &mocks.MockPersistedQueryStore{}
+	PersistedQueries = store
+
+	return store
+}
+
+func TestAPQCache(t *testing.T) {
+	usePersistedQueryStore(t)
+
+	ctx := context.Background()
+	cache := APQCache{}
+
+	cache.Add(ctx, "hash", "{ __typename }")
+
+	query, ok := cache.Get(ctx, "hash")
+	if !ok || query != "{ __typename }" {
+		t.Errorf("want the persisted query, got %q", query)
+	}
+
+	_, ok = cache.Get(ctx, "unknown")
+	if ok {
+		t.Errorf("want no query for an unknown hash")
+	}
+}
+
+// TestAPQCacheAllowlist tests that the clients cannot persist queries in the allowlist mode, and that only the
+// registered queries are found.
+func TestAPQCacheAllowlist(t *testing.T) {
+	usePersistedQueryStore(t)
+
+	ctx := context.Background()
+
+	APQCache{}.Add(ctx, queryHash("{ __typename }"), "{ __typename }")
+
+	cache := APQCache{Allowlist: true}
+	cache.Add(ctx, queryHash("{ meta { count } }"), "{ meta { count } }")
+
+	for _, query := range []string{"{ __typename }", "{ meta { count } }"} {
+		_, ok := cache.Get(ctx, queryHash(query))
+		if ok {
+			t.Errorf("want no query for %q, which was not registered", query)
+		}
+	}
+
+	hash, err := RegisterPersistedQuery(ctx, "{ __typename }")
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	query, ok := cache.Get(ctx, hash)
+	if !ok || query != "{ __typename }" {
+		t.Errorf("want the registered query, got %q", query)
+	}
+}
+
+func TestRegisterPersistedQuery(t *testing.T) {
+	store := usePersistedQueryStore(t)
+
+	hash, err := RegisterPersistedQuery(context.Background(), "{ sources { id } }")
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if hash != queryHash("{ sources { id } }") {
+		t.Errorf("want the query's hash, got %q", hash)
+	}
+
+	if store.Queries[allowlistKeyPrefix+hash] != "{ sources { id } }" {
+		t.Errorf("want the query to be registered, got %v", store.Queries)
+	}
+
+	_, err = RegisterPersistedQuery(context.Background(), "{ unknown_field }")
+	if !errors.As(err, &util.ErrBadRequest{}) {
+		t.Errorf("want a bad request error, got %v", err)
+	}
+}
+
+func TestPersistedQueryAllowlist(t *testing.T) {
+	usePersistedQueryStore(t)
+
+	hash, err := RegisterPersistedQuery(context.Background(), "{ __typename }")
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	tests := []struct {
+		name      string
+		params    graphql.RawParams
+		wantQuery string
+		wantCode  string
+	}{
+		{
+			name:      "registered hash",
+			params:    graphql.RawParams{Extensions: map[string]interface{}{"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": hash}}},
+			wantQuery: "{ __typename }",
+		},
+		{
+			name:      "registered query",
+			params:    graphql.RawParams{Query: "{ __typename }"},
+			wantQuery: "{ __typename }",
+		},
+		{
+			name:     "unregistered hash",
+			params:   graphql.RawParams{Extensions: map[string]interface{}{"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": queryHash("{ sources { id } }")}}},
+			wantCode: errPersistedQueryNotAllowed,
+		},
+		{
+			name:     "unregistered query",
+			params:   graphql.RawParams{Query: "{ sources { id } }"},
+			wantCode: errPersistedQueryNotAllowed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := tt.params
+
+			gqlErr := PersistedQueryAllowlist{}.MutateOperationParameters(context.Background(), &params)
+			if tt.wantCode != "" {
+				if gqlErr == nil || gqlErr.Extensions["code"] != tt.wantCode {
+					t.Errorf("want the %q error code, got %v", tt.wantCode, gqlErr)
+				}
+
+				return
+			}
+
+			if gqlErr != nil {
+				t.Fatalf("want no error, got %q", gqlErr)
+			}
+
+			if params.Query != tt.wantQuery {
+				t.Errorf("want the query %q, got %q", tt.wantQuery, params.Query)
+			}
+		})
+	}
+}
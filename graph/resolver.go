@@ -1,9 +1,14 @@
 package graph
 
+import "github.com/RedHatInsights/sources-api-go/service"
+
 //go:generate go run github.com/99designs/gqlgen generate
 
 // This file will not be regenerated automatically.
 //
 // It serves as dependency injection for your app, add any dependencies you require here.
 
-type Resolver struct{}
+type Resolver struct {
+	// SuperKeyService sends the superkey applications' create requests to the superkey worker.
+	SuperKeyService service.SuperKeyProducer
+}
@@ -2,11 +2,12 @@ package graph
 
 // This file will be automatically regenerated based on the schema, any resolver implementations
 // will be copied through when generating and any unknown code will be moved to the end.
-// Code generated by github.com/99designs/gqlgen version v0.17.72
+// Code generated by github.com/99designs/gqlgen version v0.17.80
 
 import (
 	"context"
 	"encoding/json"
+	"sort"
 	"strconv"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
@@ -16,6 +17,21 @@ import (
 	"github.com/RedHatInsights/sources-api-go/util"
 )
 
+// ID is the resolver for the id field.
+func (r *appMetaDataResolver) ID(ctx context.Context, obj *model.MetaData) (string, error) {
+	return strconv.FormatInt(obj.ID, 10), nil
+}
+
+// ApplicationTypeID is the resolver for the application_type_id field.
+func (r *appMetaDataResolver) ApplicationTypeID(ctx context.Context, obj *model.MetaData) (string, error) {
+	return strconv.FormatInt(obj.ApplicationTypeID, 10), nil
+}
+
+// Payload is the resolver for the payload field.
+func (r *appMetaDataResolver) Payload(ctx context.Context, obj *model.MetaData) (any, error) {
+	return decodeJSON(obj.Payload)
+}
+
 // ID is the resolver for the id field.
 func (r *applicationResolver) ID(ctx context.Context, obj *model.Application) (string, error) {
 	return strconv.Itoa(int(obj.ID)), nil
@@ -39,6 +55,10 @@ func (r *applicationResolver) Extra(ctx context.Context, obj *model.Application)
 
 // Authentications is the resolver for the authentications field.
 func (r *applicationResolver) Authentications(ctx context.Context, obj *model.Application) ([]*model.Authentication, error) {
+	if rootField(ctx) != "sources" {
+		return fetchResourceAuthentications(ctx, obj.SourceID, "Application", obj.ID)
+	}
+
 	err := getRequestDataFromCtx(ctx).EnsureAuthenticationsAreLoaded()
 	if err != nil {
 		return nil, err
@@ -109,19 +129,41 @@ func (r *endpointResolver) ID(ctx context.Context, obj *model.Endpoint) (string,
 	return strconv.Itoa(int(obj.ID)), nil
 }
 
+// SourceID is the resolver for the source_id field.
+func (r *endpointResolver) SourceID(ctx context.Context, obj *model.Endpoint) (string, error) {
+	return strconv.FormatInt(obj.SourceID, 10), nil
+}
+
 // Authentications is the resolver for the authentications field.
 func (r *endpointResolver) Authentications(ctx context.Context, obj *model.Endpoint) ([]*model.Authentication, error) {
-	err := getRequestDataFromCtx(ctx).EnsureAuthenticationsAreLoaded()
-	if err != nil {
-		return nil, err
+	var auths []model.Authentication
+
+	// the endpoints are batch loaded by both the "sources" and the "endpoints"
+	// listings
+	switch rootField(ctx) {
+	case "sources":
+		err := getRequestDataFromCtx(ctx).EnsureAuthenticationsAreLoaded()
+		if err != nil {
+			return nil, err
+		}
+
+		auths = authenticationsFromCtx(ctx, "Endpoint", obj.ID)
+	case "endpoints":
+		err := getRequestDataFromCtx(ctx).EnsureEndpointAuthenticationsAreLoaded()
+		if err != nil {
+			return nil, err
+		}
+
+		auths = endpointAuthenticationsFromCtx(ctx, obj.ID)
+	default:
+		return fetchResourceAuthentications(ctx, obj.SourceID, "Endpoint", obj.ID)
 	}
 
-	auths := authenticationsFromCtx(ctx, "Endpoint", obj.ID)
 	out := make([]*model.Authentication, len(auths))
 	for i := range auths {
 		out[i] = &auths[i]
 	}
-	return out, err
+	return out, nil
 }
 
 // TenantID is the resolver for the tenant_id field.
@@ -129,6 +171,41 @@ func (r *endpointResolver) TenantID(ctx context.Context, obj *model.Endpoint) (s
 	return strconv.Itoa(int(*tenantIdFromCtx(ctx))), nil
 }
 
+// Count is the resolver for the count field.
+func (r *metaResolver) Count(ctx context.Context, obj *generated_model.Meta) (int, error) {
+	return getCount(ctx), nil
+}
+
+// SourceStats is the resolver for the source_stats field.
+func (r *metaResolver) SourceStats(ctx context.Context, obj *generated_model.Meta, groupBy []string, filter []*generated_model.Filter) (*model.StatsResponse, error) {
+	filters, err := parseFilters(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := dao.GetSourceDao(&dao.RequestParams{TenantID: tenantIdFromCtx(ctx), UserID: userIdFromCtx(ctx)}).Stats(groupBy, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewStatsResponse(groupBy, groups), nil
+}
+
+// ApplicationStats is the resolver for the application_stats field.
+func (r *metaResolver) ApplicationStats(ctx context.Context, obj *generated_model.Meta, groupBy []string, filter []*generated_model.Filter) (*model.StatsResponse, error) {
+	filters, err := parseFilters(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	groups, err := dao.GetApplicationDao(&dao.RequestParams{TenantID: tenantIdFromCtx(ctx), UserID: userIdFromCtx(ctx)}).Stats(groupBy, filters)
+	if err != nil {
+		return nil, err
+	}
+
+	return model.NewStatsResponse(groupBy, groups), nil
+}
+
 // Sources is the resolver for the sources field.
 func (r *queryResolver) Sources(ctx context.Context, limit *int, offset *int, sortBy []*generated_model.SortBy, filter []*generated_model.Filter) ([]*model.Source, error) {
 	// default limit and offset
@@ -142,7 +219,10 @@ func (r *queryResolver) Sources(ctx context.Context, limit *int, offset *int, so
 	}
 
 	// parse any filters passed along the request
-	f := parseArgs(sortBy, filter)
+	f, err := parseArgs(sortBy, filter)
+	if err != nil {
+		return nil, err
+	}
 
 	// list the sources with filters en tote!
 	srces, count, err := dao.GetSourceDao(&dao.RequestParams{TenantID: tenantIdFromCtx(ctx), UserID: userIdFromCtx(ctx)}).List(*limit, *offset, f)
@@ -177,7 +257,10 @@ func (r *queryResolver) ApplicationTypes(ctx context.Context, limit *int, offset
 	}
 
 	// parse any filters passed along the request
-	f := parseArgs(sortBy, filter)
+	f, err := parseArgs(sortBy, filter)
+	if err != nil {
+		return nil, err
+	}
 	appTypes, count, err := dao.GetApplicationTypeDao(tenantIdFromCtx(ctx)).List(*limit, *offset, f)
 	sendCount(ctx, count)
 
@@ -188,9 +271,179 @@ func (r *queryResolver) ApplicationTypes(ctx context.Context, limit *int, offset
 	return out, err
 }
 
+// SourceTypes is the resolver for the source_types field.
+func (r *queryResolver) SourceTypes(ctx context.Context, limit *int, offset *int, sortBy []*generated_model.SortBy, filter []*generated_model.Filter) ([]*model.SourceType, error) {
+	// default limit and offset
+	if limit == nil {
+		limit = new(int)
+		*limit = 100
+	}
+	if offset == nil {
+		offset = new(int)
+		*offset = 0
+	}
+
+	// parse any filters passed along the request
+	f, err := parseArgs(sortBy, filter)
+	if err != nil {
+		return nil, err
+	}
+	sourceTypes, count, err := dao.GetSourceTypeDao().List(*limit, *offset, f)
+	sendCount(ctx, count)
+
+	out := make([]*model.SourceType, len(sourceTypes))
+	for i := range sourceTypes {
+		out[i] = &sourceTypes[i]
+	}
+	return out, err
+}
+
+// AppMetaData is the resolver for the app_meta_data field.
+func (r *queryResolver) AppMetaData(ctx context.Context, limit *int, offset *int, sortBy []*generated_model.SortBy, filter []*generated_model.Filter) ([]*model.MetaData, error) {
+	// default limit and offset
+	if limit == nil {
+		limit = new(int)
+		*limit = 100
+	}
+	if offset == nil {
+		offset = new(int)
+		*offset = 0
+	}
+
+	// parse any filters passed along the request
+	f, err := parseArgs(sortBy, filter)
+	if err != nil {
+		return nil, err
+	}
+	metaData, count, err := dao.GetMetaDataDao().List(*limit, *offset, f)
+	sendCount(ctx, count)
+
+	out := make([]*model.MetaData, len(metaData))
+	for i := range metaData {
+		out[i] = &metaData[i]
+	}
+	return out, err
+}
+
+// Endpoints is the resolver for the endpoints field.
+func (r *queryResolver) Endpoints(ctx context.Context, limit *int, offset *int, sortBy []*generated_model.SortBy, filter []*generated_model.Filter) ([]*model.Endpoint, error) {
+	// default limit and offset
+	if limit == nil {
+		limit = new(int)
+		*limit = 100
+	}
+	if offset == nil {
+		offset = new(int)
+		*offset = 0
+	}
+
+	// parse any filters passed along the request
+	f, err := parseArgs(sortBy, filter)
+	if err != nil {
+		return nil, err
+	}
+	endpts, count, err := dao.GetEndpointDao(tenantIdFromCtx(ctx)).List(*limit, *offset, f)
+	sendCount(ctx, count)
+
+	// storing the IDs of the endpoints' sources, which their authentications
+	// get loaded by
+	sourceIDs := make([]string, len(endpts))
+
+	out := make([]*model.Endpoint, len(endpts))
+	for i := range endpts {
+		out[i] = &endpts[i]
+		sourceIDs[i] = strconv.FormatInt(endpts[i].SourceID, 10)
+	}
+
+	// this will let the subresources go
+	if err := getRequestDataFromCtx(ctx).SetEndpointSourceIDs(sourceIDs); err != nil {
+		return nil, err
+	}
+
+	return out, err
+}
+
+// RhcConnections is the resolver for the rhc_connections field.
+func (r *queryResolver) RhcConnections(ctx context.Context, limit *int, offset *int, sortBy []*generated_model.SortBy, filter []*generated_model.Filter) ([]*model.RhcConnection, error) {
+	// default limit and offset
+	if limit == nil {
+		limit = new(int)
+		*limit = 100
+	}
+	if offset == nil {
+		offset = new(int)
+		*offset = 0
+	}
+
+	// parse any filters passed along the request
+	f, err := parseArgs(sortBy, filter)
+	if err != nil {
+		return nil, err
+	}
+	rhcConnections, count, err := dao.GetRhcConnectionDao(&dao.RequestParams{TenantID: tenantIdFromCtx(ctx), UserID: userIdFromCtx(ctx)}).List(*limit, *offset, f)
+	sendCount(ctx, count)
+
+	// storing the IDs of the connections' sources, so that all of them get
+	// loaded at once
+	sourceIDs := make([]string, 0, len(rhcConnections))
+
+	out := make([]*model.RhcConnection, len(rhcConnections))
+	for i := range rhcConnections {
+		out[i] = &rhcConnections[i]
+		sourceIDs = append(sourceIDs, rhcConnections[i].SourceIDs()...)
+	}
+
+	// this will let the subresources go
+	if err := getRequestDataFromCtx(ctx).SetRhcConnectionSourceIDs(sourceIDs); err != nil {
+		return nil, err
+	}
+
+	return out, err
+}
+
 // Meta is the resolver for the meta field.
 func (r *queryResolver) Meta(ctx context.Context) (*generated_model.Meta, error) {
-	return &generated_model.Meta{Count: getCount(ctx)}, nil
+	return &generated_model.Meta{}, nil
+}
+
+// ID is the resolver for the id field.
+func (r *rhcConnectionResolver) ID(ctx context.Context, obj *model.RhcConnection) (string, error) {
+	return strconv.FormatInt(obj.ID, 10), nil
+}
+
+// Extra is the resolver for the extra field.
+func (r *rhcConnectionResolver) Extra(ctx context.Context, obj *model.RhcConnection) (any, error) {
+	return decodeJSON(obj.Extra)
+}
+
+// Sources is the resolver for the sources field.
+func (r *rhcConnectionResolver) Sources(ctx context.Context, obj *model.RhcConnection) ([]*model.Source, error) {
+	if rootField(ctx) != "rhc_connections" {
+		return fetchRhcConnectionSources(ctx, obj.ID)
+	}
+
+	err := getRequestDataFromCtx(ctx).EnsureRhcConnectionSourcesAreLoaded()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int64, len(obj.Sources))
+	for i := range obj.Sources {
+		ids[i] = obj.Sources[i].ID
+	}
+
+	srces := rhcConnectionSourcesFromCtx(ctx, ids)
+	out := make([]*model.Source, len(srces))
+	for i := range srces {
+		out[i] = &srces[i]
+	}
+
+	return out, nil
+}
+
+// TenantID is the resolver for the tenant_id field.
+func (r *rhcConnectionResolver) TenantID(ctx context.Context, obj *model.RhcConnection) (string, error) {
+	return strconv.Itoa(int(*tenantIdFromCtx(ctx))), nil
 }
 
 // ID is the resolver for the id field.
@@ -205,6 +458,10 @@ func (r *sourceResolver) SourceTypeID(ctx context.Context, obj *model.Source) (s
 
 // Authentications is the resolver for the authentications field.
 func (r *sourceResolver) Authentications(ctx context.Context, obj *model.Source) ([]*model.Authentication, error) {
+	if rootField(ctx) != "sources" {
+		return fetchResourceAuthentications(ctx, obj.ID, "Source", obj.ID)
+	}
+
 	err := getRequestDataFromCtx(ctx).EnsureAuthenticationsAreLoaded()
 	if err != nil {
 		return nil, err
@@ -220,6 +477,10 @@ func (r *sourceResolver) Authentications(ctx context.Context, obj *model.Source)
 
 // Endpoints is the resolver for the endpoints field.
 func (r *sourceResolver) Endpoints(ctx context.Context, obj *model.Source) ([]*model.Endpoint, error) {
+	if rootField(ctx) != "sources" {
+		return fetchSourceEndpoints(ctx, obj.ID)
+	}
+
 	err := getRequestDataFromCtx(ctx).EnsureEndpointsAreLoaded()
 	if err != nil {
 		return nil, err
@@ -236,6 +497,10 @@ func (r *sourceResolver) Endpoints(ctx context.Context, obj *model.Source) ([]*m
 
 // Applications is the resolver for the applications field.
 func (r *sourceResolver) Applications(ctx context.Context, obj *model.Source) ([]*model.Application, error) {
+	if rootField(ctx) != "sources" {
+		return fetchSourceApplications(ctx, obj.ID)
+	}
+
 	err := getRequestDataFromCtx(ctx).EnsureApplicationsAreLoaded()
 	if err != nil {
 		return nil, err
@@ -255,6 +520,32 @@ func (r *sourceResolver) TenantID(ctx context.Context, obj *model.Source) (strin
 	return strconv.Itoa(int(*tenantIdFromCtx(ctx))), nil
 }
 
+// ID is the resolver for the id field.
+func (r *sourceTypeResolver) ID(ctx context.Context, obj *model.SourceType) (string, error) {
+	return strconv.FormatInt(obj.Id, 10), nil
+}
+
+// Schema is the resolver for the schema field.
+func (r *sourceTypeResolver) Schema(ctx context.Context, obj *model.SourceType) (any, error) {
+	return decodeJSON(obj.Schema)
+}
+
+// Dimensions is the resolver for the dimensions field.
+func (r *statsGroupResolver) Dimensions(ctx context.Context, obj *model.StatsGroup) ([]*generated_model.StatsDimension, error) {
+	out := make([]*generated_model.StatsDimension, 0, len(obj.Dimensions))
+	for name, value := range obj.Dimensions {
+		out = append(out, &generated_model.StatsDimension{Name: name, Value: value})
+	}
+
+	// the dimensions come in a map, so they are sorted to keep the output stable.
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+
+	return out, nil
+}
+
+// AppMetaData returns generated.AppMetaDataResolver implementation.
+func (r *Resolver) AppMetaData() generated.AppMetaDataResolver { return &appMetaDataResolver{r} }
+
 // Application returns generated.ApplicationResolver implementation.
 func (r *Resolver) Application() generated.ApplicationResolver { return &applicationResolver{r} }
 
@@ -271,15 +562,32 @@ func (r *Resolver) Authentication() generated.AuthenticationResolver {
 // Endpoint returns generated.EndpointResolver implementation.
 func (r *Resolver) Endpoint() generated.EndpointResolver { return &endpointResolver{r} }
 
+// Meta returns generated.MetaResolver implementation.
+func (r *Resolver) Meta() generated.MetaResolver { return &metaResolver{r} }
+
 // Query returns generated.QueryResolver implementation.
 func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
 
+// RhcConnection returns generated.RhcConnectionResolver implementation.
+func (r *Resolver) RhcConnection() generated.RhcConnectionResolver { return &rhcConnectionResolver{r} }
+
 // Source returns generated.SourceResolver implementation.
 func (r *Resolver) Source() generated.SourceResolver { return &sourceResolver{r} }
 
+// SourceType returns generated.SourceTypeResolver implementation.
+func (r *Resolver) SourceType() generated.SourceTypeResolver { return &sourceTypeResolver{r} }
+
+// StatsGroup returns generated.StatsGroupResolver implementation.
+func (r *Resolver) StatsGroup() generated.StatsGroupResolver { return &statsGroupResolver{r} }
+
+type appMetaDataResolver struct{ *Resolver }
 type applicationResolver struct{ *Resolver }
 type applicationTypeResolver struct{ *Resolver }
 type authenticationResolver struct{ *Resolver }
 type endpointResolver struct{ *Resolver }
+type metaResolver struct{ *Resolver }
 type queryResolver struct{ *Resolver }
+type rhcConnectionResolver struct{ *Resolver }
 type sourceResolver struct{ *Resolver }
+type sourceTypeResolver struct{ *Resolver }
+type statsGroupResolver struct{ *Resolver }
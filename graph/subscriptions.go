@@ -0,0 +1,70 @@
+package graph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	generated_model "github.com/RedHatInsights/sources-api-go/graph/model"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/RedHatInsights/sources-api-go/service"
+)
+
+// subscriptionBufferSize is the number of transitions held for a slow client before dropping them.
+const subscriptionBufferSize = 64
+
+// availabilityTransitions subscribes to the availability transitions of the given resource type, which the status
+// listener broadcasts to the tenant's event stream. The subscription lasts until the client stops it or the connection
+// goes away, which is when the context is done.
+func availabilityTransitions(ctx context.Context, resourceType string) (<-chan *generated_model.AvailabilityTransition, error) {
+	rd := getRequestDataFromCtx(ctx)
+
+	channels := service.EventStreamChannels(rd.OrgID, rd.AccountNumber)
+	if len(channels) == 0 {
+		return nil, fmt.Errorf("unable to subscribe without an org id or an account number")
+	}
+
+	eventType := resourceType + ".availability_status"
+	transitions := make(chan *generated_model.AvailabilityTransition, subscriptionBufferSize)
+
+	go func() {
+		// closing the channel ends the subscription, so that the client knows
+		// when it has to subscribe again.
+		defer close(transitions)
+
+		err := service.Broadcaster.Subscribe(ctx, channels, func(message []byte) {
+			var event service.StreamEvent
+
+			err := json.Unmarshal(message, &event)
+			if err != nil {
+				logging.Log.Warnf("Unable to unmarshal the stream event: %s", err)
+				return
+			}
+
+			if event.EventType != eventType {
+				return
+			}
+
+			transition := &generated_model.AvailabilityTransition{}
+
+			err = json.Unmarshal(event.Data, transition)
+			if err != nil {
+				logging.Log.Warnf("Unable to unmarshal the availability transition: %s", err)
+				return
+			}
+
+			// the subscription must not be held back by a slow client, so the
+			// transitions it can not keep up with are dropped.
+			select {
+			case transitions <- transition:
+			default:
+				logging.Log.Warnf(`Dropping the "%s" transition for a slow client`, eventType)
+			}
+		})
+		if err != nil && ctx.Err() == nil {
+			logging.Log.Errorf("Unable to subscribe to the tenant's availability transitions: %s", err)
+		}
+	}()
+
+	return transitions, nil
+}
@@ -0,0 +1,27 @@
+package graph
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.80
+
+import (
+	"context"
+
+	"github.com/RedHatInsights/sources-api-go/graph/generated"
+	generated_model "github.com/RedHatInsights/sources-api-go/graph/model"
+)
+
+// SourceAvailabilityChanged is the resolver for the sourceAvailabilityChanged field.
+func (r *subscriptionResolver) SourceAvailabilityChanged(ctx context.Context) (<-chan *generated_model.AvailabilityTransition, error) {
+	return availabilityTransitions(ctx, "Source")
+}
+
+// ApplicationAvailabilityChanged is the resolver for the applicationAvailabilityChanged field.
+func (r *subscriptionResolver) ApplicationAvailabilityChanged(ctx context.Context) (<-chan *generated_model.AvailabilityTransition, error) {
+	return availabilityTransitions(ctx, "Application")
+}
+
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+type subscriptionResolver struct{ *Resolver }
@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+)
+
+// TestAvailabilityTransitions tests that the subscription only gets the transitions of the resource type it was made
+// for, and that it ends when its context is done.
+func TestAvailabilityTransitions(t *testing.T) {
+	originalBroadcaster := service.Broadcaster
+	defer func() { service.Broadcaster = originalBroadcaster }()
+
+	broadcaster := &mocks.MockEventBroadcaster{}
+	service.Broadcaster = broadcaster
+
+	ctx, cancel := context.WithCancel(WithRequestData(context.Background(), &RequestData{TenantID: 1, OrgID: "12345"}))
+	defer cancel()
+
+	transitions, err := availabilityTransitions(ctx, "Source")
+	if err != nil {
+		t.Fatalf("want no error, got %q", err)
+	}
+
+	if !broadcaster.WaitForSubscribers(service.EventStreamChannels("12345", "")[0], time.Second) {
+		t.Fatalf("the subscription did not subscribe to the tenant's events")
+	}
+
+	tenant := &m.Tenant{Id: 1, OrgID: "12345"}
+
+	err = service.BroadcastAvailabilityTransition(tenant, "Application", "5", m.Unavailable, m.Available)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = service.BroadcastAvailabilityTransition(tenant, "Source", "3", m.Available, m.Unavailable)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	select {
+	case transition := <-transitions:
+		if transition.ID != "3" || transition.PreviousStatus != m.Available || transition.AvailabilityStatus != m.Unavailable {
+			t.Errorf("want the source's transition, got %+v", transition)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("the source's transition was not received")
+	}
+
+	cancel()
+
+	select {
+	case transition, ok := <-transitions:
+		if ok {
+			t.Errorf("want the subscription to end, got %+v", transition)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("the subscription did not end")
+	}
+}
+
+func TestAvailabilityTransitionsWithoutTenant(t *testing.T) {
+	_, err := availabilityTransitions(WithRequestData(context.Background(), &RequestData{TenantID: 1}), "Source")
+	if err == nil {
+		t.Errorf("want an error, got none")
+	}
+}
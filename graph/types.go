@@ -4,8 +4,10 @@ import (
 	"sync"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/kafka"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
 )
 
 const defaultLimit = 500
@@ -28,6 +30,48 @@ type RequestData struct {
 	authenticationMap   *map[string][]m.Authentication
 	SourceMutex         *sync.Mutex
 	sourceIdList        *[]string
+
+	// the same for the subresources of the other listings: the endpoints and
+	// the RHC connections listings close their ready channel once they stored
+	// the IDs of the sources their resources belong to. Since the IDs can only
+	// be stored once, a query can only list each of them once.
+	EndpointListReady           chan struct{}
+	endpointListOnce            sync.Once
+	endpointSourceIdList        *[]string
+	EndpointAuthenticationMutex *sync.Mutex
+	endpointAuthenticationMap   *map[int64][]m.Authentication
+	RhcConnectionReady          chan struct{}
+	rhcConnectionOnce           sync.Once
+	rhcConnectionSourceIdList   *[]string
+	RhcConnectionSourceMutex    *sync.Mutex
+	rhcConnectionSourceMap      *map[int64]m.Source
+
+	// what the mutations need to change the resources the same way the REST
+	// API does.
+	//
+	// Authorize runs the REST API's permission check for the write operation
+	// the HTTP method and the path stand for.
+	Authorize func(method, path string) error
+	// the headers the events get raised with
+	Headers []kafka.Header
+	// the request's identity, for the availability status notifications and
+	// the superkey jobs
+	Identity      *identity.XRHID
+	XRHID         string
+	AccountNumber string
+	// the tenant's OrgId, which along with the account number picks the
+	// event stream the subscriptions listen to
+	OrgID string
+	// whether the request was authenticated with a certificate
+	CertAuth bool
+	// who the audit trail records the changes were made by
+	AuditActorType string
+	AuditActorID   string
+}
+
+// requestParams returns the parameters the DAOs get for the request's tenant and user.
+func (rd *RequestData) requestParams() *dao.RequestParams {
+	return &dao.RequestParams{TenantID: &rd.TenantID, UserID: rd.UserID}
 }
 
 // wrapper around a mutex that only loads applications up one time and makes any
@@ -132,3 +176,106 @@ func (rd *RequestData) SetSourceIDs(ids []string) {
 	rd.sourceIdList = &ids
 	rd.SourceMutex.Unlock()
 }
+
+// loads the authentications of the endpoints the "endpoints" listing returned,
+// which are the ones of their sources that belong to an endpoint
+func (rd *RequestData) EnsureEndpointAuthenticationsAreLoaded() error {
+	// the map is only checked while holding the mutex, since the endpoints'
+	// authentications load concurrently
+	rd.EndpointAuthenticationMutex.Lock()
+	defer rd.EndpointAuthenticationMutex.Unlock()
+
+	// waiting until the endpoints' sourceIDs are loaded
+	<-rd.EndpointListReady
+
+	if rd.endpointAuthenticationMap == nil {
+		// no endpoints were listed, so there is nothing to load
+		if len(*rd.endpointSourceIdList) == 0 {
+			rd.endpointAuthenticationMap = &map[int64][]m.Authentication{}
+			return nil
+		}
+
+		auths, _, err := dao.GetAuthenticationDao(&dao.RequestParams{TenantID: &rd.TenantID}).List(defaultLimit, 0, []util.Filter{{Name: "source_id", Value: *rd.endpointSourceIdList}})
+		if err != nil {
+			return err
+		}
+
+		mp := make(map[int64][]m.Authentication)
+		for _, auth := range auths {
+			if auth.ResourceType == "Endpoint" {
+				mp[auth.ResourceID] = append(mp[auth.ResourceID], auth)
+			}
+		}
+
+		rd.endpointAuthenticationMap = &mp
+	}
+
+	return nil
+}
+
+// loads the sources of the connections the "rhc_connections" listing returned
+func (rd *RequestData) EnsureRhcConnectionSourcesAreLoaded() error {
+	rd.RhcConnectionSourceMutex.Lock()
+	defer rd.RhcConnectionSourceMutex.Unlock()
+
+	// waiting until the connections' sourceIDs are loaded
+	<-rd.RhcConnectionReady
+
+	if rd.rhcConnectionSourceMap == nil {
+		// no connections were listed, so there is nothing to load
+		if len(*rd.rhcConnectionSourceIdList) == 0 {
+			rd.rhcConnectionSourceMap = &map[int64]m.Source{}
+			return nil
+		}
+
+		srces, _, err := dao.GetSourceDao(rd.requestParams()).List(defaultLimit, 0, []util.Filter{{Name: "id", Value: *rd.rhcConnectionSourceIdList}})
+		if err != nil {
+			return err
+		}
+
+		mp := make(map[int64]m.Source)
+		for _, src := range srces {
+			mp[src.ID] = src
+		}
+
+		rd.rhcConnectionSourceMap = &mp
+	}
+
+	return nil
+}
+
+// sets the IDs of the listed endpoints' sources and lets the endpoints'
+// subresources load. It fails when the endpoints were already listed in the
+// same query.
+func (rd *RequestData) SetEndpointSourceIDs(ids []string) error {
+	stored := false
+	rd.endpointListOnce.Do(func() {
+		rd.endpointSourceIdList = &ids
+		close(rd.EndpointListReady)
+		stored = true
+	})
+
+	if !stored {
+		return util.NewErrBadRequest("the endpoints can only be listed once per query")
+	}
+
+	return nil
+}
+
+// sets the IDs of the listed connections' sources and lets the connections'
+// subresources load. It fails when the connections were already listed in the
+// same query.
+func (rd *RequestData) SetRhcConnectionSourceIDs(ids []string) error {
+	stored := false
+	rd.rhcConnectionOnce.Do(func() {
+		rd.rhcConnectionSourceIdList = &ids
+		close(rd.RhcConnectionReady)
+		stored = true
+	})
+
+	if !stored {
+		return util.NewErrBadRequest("the RHC connections can only be listed once per query")
+	}
+
+	return nil
+}
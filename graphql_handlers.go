@@ -1,75 +1,138 @@
 package main
 
 import (
-	"context"
 	"fmt"
 	"os"
 	"sync"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/graph"
 	"github.com/RedHatInsights/sources-api-go/graph/generated"
+	"github.com/RedHatInsights/sources-api-go/middleware"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/labstack/echo/v4"
+	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
 )
 
-var (
-	// the graphql handler server - initialized in the init() function below
-	srv *handler.Server
-	// the wrapped wrapper function for graphql
-	wrapper echo.HandlerFunc
-)
-
-func init() {
-	// setup the graphQL server
-	srv = handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: &graph.Resolver{}}))
+// newGraphQLServer sets up the graphQL server with the given resolver, which enforces the configured complexity and
+// depth limits, and serves the automatic persisted queries.
+func newGraphQLServer(resolver *graph.Resolver) *handler.Server {
+	srv := handler.New(generated.NewExecutableSchema(generated.Config{Resolvers: resolver, Complexity: graph.Complexity()}))
 	srv.AddTransport(transport.POST{})
+	// the queries can be sent with GET requests too, so that they can be
+	// cached. The mutations are only allowed with POST requests.
+	srv.AddTransport(transport.GET{})
+	// the subscriptions are served over WebSocket. The keep-alive messages
+	// stop the proxies in between from closing the idle connections.
+	srv.AddTransport(transport.Websocket{KeepAlivePingInterval: eventStreamHeartbeatInterval})
+
+	// the operations which are too expensive or too deeply nested are rejected
+	// before they run, since a single one could list the whole tenant.
+	srv.Use(extension.FixedComplexityLimit(config.Get().GraphQLMaxComplexity))
+	srv.Use(graph.DepthLimit{Limit: config.Get().GraphQLMaxDepth})
+
+	// the clients send the hashes of the queries they persisted instead of the
+	// whole queries. In the allowlist mode only the registered queries run,
+	// so the allowlist goes first.
+	if config.Get().GraphQLAPQAllowlist {
+		srv.Use(graph.PersistedQueryAllowlist{})
+	}
+
+	srv.Use(extension.AutomaticPersistedQuery{Cache: graph.APQCache{
+		TTL:       time.Duration(config.Get().GraphQLAPQTTL) * time.Second,
+		Allowlist: config.Get().GraphQLAPQAllowlist,
+	}})
 
 	// only set up introspection if we're not on stage/prod
 	if os.Getenv("SOURCES_ENV") != "stage" && os.Getenv("SOURCES_ENV") != "prod" {
 		srv.Use(extension.Introspection{})
 	}
 
-	wrapper = echo.WrapHandler(srv)
+	return srv
 }
 
-func GraphQLQuery(c echo.Context) error {
-	requestParams, err := dao.NewRequestParamsFromContext(c)
-	if requestParams == nil || err != nil {
-		return fmt.Errorf("unable to process user id or tenant id from request")
-	}
+// GraphQLQuery serves the graphQL queries, mutations and subscriptions. The mutations are authorized with the same
+// authorizer the REST API's permission check uses, and the superkey applications they create are sent to the superkey
+// worker. The subscriptions listen to the event stream of the tenant the "Tenancy" middleware resolved.
+func GraphQLQuery(superKeySvc service.SuperKeyProducer, authorize middleware.Authorizer) echo.HandlerFunc {
+	// the wrapped wrapper function for graphql
+	wrapper := echo.WrapHandler(newGraphQLServer(&graph.Resolver{SuperKeyService: superKeySvc}))
+
+	return func(c echo.Context) error {
+		requestParams, err := dao.NewRequestParamsFromContext(c)
+		if requestParams == nil || err != nil {
+			return fmt.Errorf("unable to process user id or tenant id from request")
+		}
+
+		// the headers the mutations raise the events with
+		forwardableHeaders, err := service.ForwadableHeaders(c)
+		if err != nil {
+			return err
+		}
+
+		accountNumber, err := getAccountNumberFromEchoContext(c)
+		if err != nil {
+			c.Logger().Debug(err)
+		}
 
-	// locking the initial sourceID mutex in order to load sources before
-	// fetching any and all subresources
-	sourceIdMutex := sync.Mutex{}
-	sourceIdMutex.Lock()
-
-	// store the `RequestData` we need for this request - this is the way we can
-	// store certain data about the request for usage in the resolvers. kind of
-	// like the graphql request context.
-	c.SetRequest(
-		c.Request().WithContext(context.WithValue(
-			c.Request().Context(),
-			graph.RequestData{},
-			&graph.RequestData{
-				// the current tenant
-				TenantID: *requestParams.TenantID,
-				UserID:   requestParams.UserID,
-				// using a buffered channel so it does not block whens ending if
-				// the count wasn't requested. it will be GC'd when the request
-				// is done.
-				CountChan: make(chan int, 1),
-				// mutexes to ensure we load up all the source's
-				// subresources _one time_
-				ApplicationMutex:    &sync.Mutex{},
-				EndpointMutex:       &sync.Mutex{},
-				AuthenticationMutex: &sync.Mutex{},
-				SourceMutex:         &sourceIdMutex,
-			},
-		)),
-	)
-
-	return wrapper(c)
+		orgID, _ := c.Get(h.OrgID).(string)
+		parsedIdentity, _ := c.Get(h.ParsedIdentity).(*identity.XRHID)
+		xRhIdentity, _ := c.Get(h.XRHID).(string)
+		auditActorType, auditActorID := middleware.AuditActor(c)
+
+		// locking the initial sourceID mutex in order to load sources before
+		// fetching any and all subresources
+		sourceIdMutex := sync.Mutex{}
+		sourceIdMutex.Lock()
+
+		// store the `RequestData` we need for this request - this is the way we can
+		// store certain data about the request for usage in the resolvers. kind of
+		// like the graphql request context.
+		c.SetRequest(
+			c.Request().WithContext(graph.WithRequestData(
+				c.Request().Context(),
+				&graph.RequestData{
+					// the current tenant
+					TenantID: *requestParams.TenantID,
+					UserID:   requestParams.UserID,
+					// using a buffered channel so it does not block whens ending if
+					// the count wasn't requested. it will be GC'd when the request
+					// is done.
+					CountChan: make(chan int, 1),
+					// mutexes to ensure we load up all the source's
+					// subresources _one time_
+					ApplicationMutex:    &sync.Mutex{},
+					EndpointMutex:       &sync.Mutex{},
+					AuthenticationMutex: &sync.Mutex{},
+					SourceMutex:         &sourceIdMutex,
+					// and the other listings' subresources
+					EndpointListReady:           make(chan struct{}),
+					EndpointAuthenticationMutex: &sync.Mutex{},
+					RhcConnectionReady:          make(chan struct{}),
+					RhcConnectionSourceMutex:    &sync.Mutex{},
+					// the mutations run the same permission check the REST
+					// API runs for the operation they stand for.
+					Authorize: func(method, path string) error {
+						return authorize(c, method, path)
+					},
+					Headers:        forwardableHeaders,
+					Identity:       parsedIdentity,
+					XRHID:          xRhIdentity,
+					AccountNumber:  accountNumber,
+					OrgID:          orgID,
+					CertAuth:       c.Get("cert-auth") != nil,
+					AuditActorType: auditActorType,
+					AuditActorID:   auditActorID,
+				},
+			)),
+		)
+
+		return wrapper(c)
+	}
 }
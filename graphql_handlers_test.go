@@ -1,16 +1,41 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/99designs/gqlgen/client"
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/graph"
+	"github.com/RedHatInsights/sources-api-go/internal/events"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/jobs"
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	"github.com/RedHatInsights/sources-api-go/middleware"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
 )
 
+// bypassAuthorizer authorizes every mutation, like the permission check does when RBAC is bypassed.
+var bypassAuthorizer = middleware.NewAuthorizer(true, nil, nil)
+
 const query = `
 {"query":"{\n meta {\n  count\n }\n sources(\n  offset: 0\n  limit: 50\n  sort_by: { name: \"created_at\", direction: desc }\n  filter: {\n   name: \"source_type.vendor\"\n   operation: \"not_eq\"\n   value: \"Red Hat\"\n  }\n ) {\n  id\n  created_at\n  app_creation_workflow\n  source_type_id\n  name\n  imported\n  availability_status\n  source_ref\n  last_checked_at\n  updated_at\n  last_available_at\n  app_creation_workflow\n  paused_at\n  authentications {\n   authtype\n   username\n  }\n  applications {\n   application_type_id\n   id\n   availability_status_error\n   availability_status\n   paused_at\n   extra\n   authentications {\n    username\n    authtype\n   }\n  }\n  endpoints {\n   id\n   scheme\n   host\n   port\n   path\n   receptor_node\n   role\n   certificate_authority\n   verify_ssl\n   availability_status_error\n   availability_status\n   authentications {\n    authtype\n    availability_status\n    availability_status_error\n   }\n  }\n }\n}\n"}
 `
@@ -28,7 +53,7 @@ func TestGraphQL(t *testing.T) {
 	)
 	c.Request().Header.Set("Content-Type", "application/json")
 
-	err := GraphQLQuery(c)
+	err := GraphQLQuery(&mocks.MockSuperKeyProducer{}, bypassAuthorizer)(c)
 	if err != nil {
 		t.Error(err)
 	}
@@ -51,3 +76,942 @@ func TestGraphQL(t *testing.T) {
 		t.FailNow()
 	}
 }
+
+const statsQuery = `{"query":"{ meta { source_stats(group_by: [\"availability_status\"]) { group_by total groups { count dimensions { name value } } } application_stats { total } } }"}`
+
+// TestGraphQLStats tests that the stats can be requested through the meta object, without listing anything.
+func TestGraphQLStats(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/graphql",
+		strings.NewReader(statsQuery),
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+	c.Request().Header.Set("Content-Type", "application/json")
+
+	err := GraphQLQuery(&mocks.MockSuperKeyProducer{}, bypassAuthorizer)(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	var body struct {
+		Data struct {
+			Meta struct {
+				SourceStats struct {
+					Total  int64 `json:"total"`
+					Groups []struct {
+						Count      int64 `json:"count"`
+						Dimensions []struct {
+							Name string `json:"name"`
+						} `json:"dimensions"`
+					} `json:"groups"`
+				} `json:"source_stats"`
+			} `json:"meta"`
+		} `json:"data"`
+		Errors interface{} `json:"errors"`
+	}
+
+	err = json.Unmarshal(rec.Body.Bytes(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body.Errors != nil {
+		t.Fatalf("errors present: %v", body.Errors)
+	}
+
+	var sum int64
+	for _, group := range body.Data.Meta.SourceStats.Groups {
+		if len(group.Dimensions) != 1 || group.Dimensions[0].Name != "availability_status" {
+			t.Errorf(`want the "availability_status" dimension, got "%+v"`, group.Dimensions)
+		}
+
+		sum += group.Count
+	}
+
+	if sum != body.Data.Meta.SourceStats.Total {
+		t.Errorf("want the groups to add up to %d, got %d", body.Data.Meta.SourceStats.Total, sum)
+	}
+}
+
+// graphQLResponse is the response of a GraphQL request, with the data left for the tests to decode.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message    string                 `json:"message"`
+		Extensions map[string]interface{} `json:"extensions"`
+	} `json:"errors"`
+}
+
+// graphQLRequest sends the GraphQL query with the given authorizer on behalf of the fixtures' tenant.
+func graphQLRequest(t *testing.T, query string, authorize middleware.Authorizer, context map[string]interface{}) graphQLResponse {
+	body, err := json.Marshal(map[string]string{"query": query})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	context["tenantID"] = int64(1)
+
+	c, rec := request.CreateTestContext(http.MethodPost, "/graphql", strings.NewReader(string(body)), context)
+	c.Request().Header.Set("Content-Type", "application/json")
+
+	err = GraphQLQuery(&mocks.MockSuperKeyProducer{}, authorize)(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var response graphQLResponse
+
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return response
+}
+
+// recordingSender records the types of the events raised through it.
+type recordingSender struct {
+	EventTypes []string
+}
+
+func (rs *recordingSender) RaiseEvent(eventType string, _ []byte, _ []kafka.Header) error {
+	rs.EventTypes = append(rs.EventTypes, eventType)
+	return nil
+}
+
+// TestGraphQLMutationUnauthorized tests that the mutations are rejected when the request does not pass the same
+// permission check the REST API runs.
+func TestGraphQLMutationUnauthorized(t *testing.T) {
+	authorize := middleware.NewAuthorizer(false, []string{"good-psk"}, nil)
+
+	response := graphQLRequest(t, `mutation { pause_source(id: "1") { id } }`, authorize, map[string]interface{}{h.PSK: "bad-psk"})
+
+	if len(response.Errors) != 1 {
+		t.Fatalf("want one error, got %+v", response.Errors)
+	}
+
+	if response.Errors[0].Message != "Unauthorized Action: Incorrect PSK" {
+		t.Errorf(`want the "Incorrect PSK" error, got %q`, response.Errors[0].Message)
+	}
+
+	if string(response.Data) != "null" {
+		t.Errorf("want no data, got %s", response.Data)
+	}
+}
+
+// TestGraphQLPauseSourceMutation tests that pausing a source raises the same events as the REST API, one for the
+// source and one for each of its applications.
+func TestGraphQLPauseSourceMutation(t *testing.T) {
+	cleanup := setupSuperKeyTest(false)
+	defer cleanup()
+
+	sender := &recordingSender{}
+	backupProducer := service.Producer
+	service.Producer = func() events.Sender { return sender }
+	defer func() { service.Producer = backupProducer }()
+
+	source := fixtures.TestSourceData[0]
+
+	response := graphQLRequest(t, fmt.Sprintf(`mutation { pause_source(id: "%d") { id name } }`, source.ID), bypassAuthorizer, map[string]interface{}{})
+
+	if len(response.Errors) != 0 {
+		t.Fatalf("want no errors, got %+v", response.Errors)
+	}
+
+	var data struct {
+		PauseSource struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"pause_source"`
+	}
+
+	err := json.Unmarshal(response.Data, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data.PauseSource.ID != strconv.FormatInt(source.ID, 10) || data.PauseSource.Name != source.Name {
+		t.Errorf("want the source %d, got %+v", source.ID, data.PauseSource)
+	}
+
+	wantEventTypes := []string{"Source.pause"}
+	for _, app := range fixtures.TestApplicationData {
+		if app.SourceID == source.ID {
+			wantEventTypes = append(wantEventTypes, "Application.pause")
+		}
+	}
+
+	if !slices.Equal(sender.EventTypes, wantEventTypes) {
+		t.Errorf("want the events %v, got %v", wantEventTypes, sender.EventTypes)
+	}
+}
+
+// TestGraphQLUpdateSourceMutationBadRequest tests that the source updates are validated like the REST API's.
+func TestGraphQLUpdateSourceMutationBadRequest(t *testing.T) {
+	cleanup := setupSuperKeyTest(false)
+	defer cleanup()
+
+	response := graphQLRequest(t, `mutation { update_source(id: "1", input: {availability_status: "wrong"}) { id } }`, bypassAuthorizer, map[string]interface{}{})
+
+	if len(response.Errors) != 1 {
+		t.Fatalf("want one error, got %+v", response.Errors)
+	}
+
+	if !strings.HasPrefix(response.Errors[0].Message, "bad request") {
+		t.Errorf("want a bad request error, got %q", response.Errors[0].Message)
+	}
+}
+
+// TestGraphQLDeleteSuperKeySourceMutation tests that the superkey sources are deleted asynchronously, like the REST
+// API deletes them.
+func TestGraphQLDeleteSuperKeySourceMutation(t *testing.T) {
+	cleanup := setupSuperKeyTest(true)
+	defer cleanup()
+
+	sourceID := fixtures.TestSourceData[0].ID
+
+	response := graphQLRequest(t, fmt.Sprintf(`mutation { delete_source(id: "%d") { id pending } }`, sourceID), bypassAuthorizer, map[string]interface{}{
+		h.XRHID: buildXRHIdentity(),
+	})
+
+	if len(response.Errors) != 0 {
+		t.Fatalf("want no errors, got %+v", response.Errors)
+	}
+
+	var data struct {
+		DeleteSource struct {
+			ID      string `json:"id"`
+			Pending bool   `json:"pending"`
+		} `json:"delete_source"`
+	}
+
+	err := json.Unmarshal(response.Data, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data.DeleteSource.ID != strconv.FormatInt(sourceID, 10) || !data.DeleteSource.Pending {
+		t.Errorf("want the source %d to be pending deletion, got %+v", sourceID, data.DeleteSource)
+	}
+
+	if len(mockEnqueuedJobs) != 1 {
+		t.Fatalf("want one enqueued job, got %d", len(mockEnqueuedJobs))
+	}
+
+	job, ok := mockEnqueuedJobs[0].(*jobs.SuperkeyDestroyJob)
+	if !ok {
+		t.Fatalf("want a superkey destroy job, got %T", mockEnqueuedJobs[0])
+	}
+
+	if job.Model != "source" || job.Id != sourceID || job.Identity == "" {
+		t.Errorf("want the job to destroy the source %d on behalf of the identity, got %+v", sourceID, job)
+	}
+}
+
+// TestGraphQLSourceMutations tests that a source can be created, updated and deleted through the mutations.
+func TestGraphQLSourceMutations(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	backupRetention := conf.SourceRetention
+	conf.SourceRetention = 0
+	defer func() { conf.SourceRetention = backupRetention }()
+
+	createQuery := fmt.Sprintf(`mutation { create_source(input: {name: "graphql source", source_type_id: "%d"}) { id name source_type_id } }`, fixtures.TestSourceTypeData[0].Id)
+
+	response := graphQLRequest(t, createQuery, bypassAuthorizer, map[string]interface{}{})
+	if len(response.Errors) != 0 {
+		t.Fatalf("want no errors, got %+v", response.Errors)
+	}
+
+	var created struct {
+		CreateSource struct {
+			ID           string `json:"id"`
+			Name         string `json:"name"`
+			SourceTypeID string `json:"source_type_id"`
+		} `json:"create_source"`
+	}
+
+	err := json.Unmarshal(response.Data, &created)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if created.CreateSource.Name != "graphql source" || created.CreateSource.SourceTypeID != strconv.FormatInt(fixtures.TestSourceTypeData[0].Id, 10) {
+		t.Errorf("want the created source, got %+v", created.CreateSource)
+	}
+
+	updateQuery := fmt.Sprintf(`mutation { update_source(id: "%s", input: {name: "renamed graphql source"}) { name applications { id } } }`, created.CreateSource.ID)
+
+	response = graphQLRequest(t, updateQuery, bypassAuthorizer, map[string]interface{}{})
+	if len(response.Errors) != 0 {
+		t.Fatalf("want no errors, got %+v", response.Errors)
+	}
+
+	if !strings.Contains(string(response.Data), `"renamed graphql source"`) {
+		t.Errorf("want the renamed source, got %s", response.Data)
+	}
+
+	deleteQuery := fmt.Sprintf(`mutation { delete_source(id: "%s") { id pending } }`, created.CreateSource.ID)
+
+	response = graphQLRequest(t, deleteQuery, bypassAuthorizer, map[string]interface{}{})
+	if len(response.Errors) != 0 {
+		t.Fatalf("want no errors, got %+v", response.Errors)
+	}
+
+	if string(response.Data) != fmt.Sprintf(`{"delete_source":{"id":"%s","pending":false}}`, created.CreateSource.ID) {
+		t.Errorf("want the source to be deleted right away, got %s", response.Data)
+	}
+}
+
+// listCountingSourceDao counts the times the sources get listed.
+type listCountingSourceDao struct {
+	*mocks.MockSourceDao
+	lists int
+}
+
+func (ls *listCountingSourceDao) List(limit, offset int, filters []util.Filter) ([]m.Source, int64, error) {
+	ls.lists++
+	return ls.MockSourceDao.List(limit, offset, filters)
+}
+
+// listCountingAuthenticationDao counts the times the authentications get listed.
+type listCountingAuthenticationDao struct {
+	mocks.MockAuthenticationDao
+	lists int
+}
+
+func (la *listCountingAuthenticationDao) List(limit, offset int, filters []util.Filter) ([]m.Authentication, int64, error) {
+	la.lists++
+	return la.MockAuthenticationDao.List(limit, offset, filters)
+}
+
+// TestGraphQLRhcConnectionSources tests that the sources of all the listed RHC connections are loaded at once.
+func TestGraphQLRhcConnectionSources(t *testing.T) {
+	rhcConnections := []m.RhcConnection{
+		{ID: 1, RhcId: "rhc-1", Sources: []m.Source{{ID: fixtures.TestSourceData[0].ID}}},
+		{ID: 2, RhcId: "rhc-2", Sources: []m.Source{{ID: fixtures.TestSourceData[0].ID}, {ID: fixtures.TestSourceData[1].ID}}},
+	}
+
+	sourceDao := &listCountingSourceDao{MockSourceDao: &mocks.MockSourceDao{Sources: fixtures.TestSourceData}}
+
+	backupRhcConnectionDao, backupSourceDao := dao.GetRhcConnectionDao, dao.GetSourceDao
+	dao.GetRhcConnectionDao = func(*dao.RequestParams) dao.RhcConnectionDao {
+		return &mocks.MockRhcConnectionDao{RhcConnections: rhcConnections}
+	}
+	dao.GetSourceDao = func(*dao.RequestParams) dao.SourceDao { return sourceDao }
+	defer func() { dao.GetRhcConnectionDao, dao.GetSourceDao = backupRhcConnectionDao, backupSourceDao }()
+
+	response := graphQLRequest(t, `{ rhc_connections { id rhc_id sources { id name } } meta { count } }`, bypassAuthorizer, map[string]interface{}{})
+
+	if len(response.Errors) != 0 {
+		t.Fatalf("want no errors, got %+v", response.Errors)
+	}
+
+	var data struct {
+		RhcConnections []struct {
+			ID      string `json:"id"`
+			RhcID   string `json:"rhc_id"`
+			Sources []struct {
+				ID   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"sources"`
+		} `json:"rhc_connections"`
+		Meta struct {
+			Count int `json:"count"`
+		} `json:"meta"`
+	}
+
+	err := json.Unmarshal(response.Data, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data.Meta.Count != len(rhcConnections) || len(data.RhcConnections) != len(rhcConnections) {
+		t.Fatalf("want %d connections, got %+v", len(rhcConnections), data)
+	}
+
+	for i, rhcConnection := range data.RhcConnections {
+		if rhcConnection.RhcID != rhcConnections[i].RhcId || len(rhcConnection.Sources) != len(rhcConnections[i].Sources) {
+			t.Errorf("want the connection %q with %d sources, got %+v", rhcConnections[i].RhcId, len(rhcConnections[i].Sources), rhcConnection)
+			continue
+		}
+
+		for j, src := range rhcConnection.Sources {
+			want := fixtures.TestSourceData[j]
+			if src.ID != strconv.FormatInt(want.ID, 10) || src.Name != want.Name {
+				t.Errorf("want the source %d %q, got %+v", want.ID, want.Name, src)
+			}
+		}
+	}
+
+	if sourceDao.lists != 1 {
+		t.Errorf("want the sources to be listed once, got %d", sourceDao.lists)
+	}
+}
+
+// TestGraphQLEndpointAuthentications tests that the authentications of all the listed endpoints are loaded at once.
+func TestGraphQLEndpointAuthentications(t *testing.T) {
+	authenticationDao := &listCountingAuthenticationDao{MockAuthenticationDao: mocks.MockAuthenticationDao{Authentications: fixtures.TestAuthenticationData}}
+
+	backupEndpointDao, backupAuthenticationDao := dao.GetEndpointDao, dao.GetAuthenticationDao
+	dao.GetEndpointDao = func(*int64) dao.EndpointDao {
+		return &mocks.MockEndpointDao{Endpoints: fixtures.TestEndpointData}
+	}
+	dao.GetAuthenticationDao = func(*dao.RequestParams) dao.AuthenticationDao { return authenticationDao }
+	defer func() { dao.GetEndpointDao, dao.GetAuthenticationDao = backupEndpointDao, backupAuthenticationDao }()
+
+	response := graphQLRequest(t, `{ endpoints { id source_id authentications { resource_type resource_id } } }`, bypassAuthorizer, map[string]interface{}{})
+
+	if len(response.Errors) != 0 {
+		t.Fatalf("want no errors, got %+v", response.Errors)
+	}
+
+	var data struct {
+		Endpoints []struct {
+			ID              string `json:"id"`
+			SourceID        string `json:"source_id"`
+			Authentications []struct {
+				ResourceType string `json:"resource_type"`
+				ResourceID   string `json:"resource_id"`
+			} `json:"authentications"`
+		} `json:"endpoints"`
+	}
+
+	err := json.Unmarshal(response.Data, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data.Endpoints) != len(fixtures.TestEndpointData) {
+		t.Fatalf("want %d endpoints, got %d", len(fixtures.TestEndpointData), len(data.Endpoints))
+	}
+
+	for i, endpoint := range data.Endpoints {
+		want := fixtures.TestEndpointData[i]
+		if endpoint.ID != strconv.FormatInt(want.ID, 10) || endpoint.SourceID != strconv.FormatInt(want.SourceID, 10) {
+			t.Errorf("want the endpoint %d of the source %d, got %+v", want.ID, want.SourceID, endpoint)
+		}
+
+		wantAuthentications := 0
+		for _, auth := range fixtures.TestAuthenticationData {
+			if auth.ResourceType == "Endpoint" && auth.ResourceID == want.ID {
+				wantAuthentications++
+			}
+		}
+
+		if len(endpoint.Authentications) != wantAuthentications {
+			t.Errorf("want %d authentications for the endpoint %d, got %+v", wantAuthentications, want.ID, endpoint.Authentications)
+		}
+
+		for _, auth := range endpoint.Authentications {
+			if auth.ResourceType != "Endpoint" || auth.ResourceID != endpoint.ID {
+				t.Errorf("want the authentications of the endpoint %s, got %+v", endpoint.ID, auth)
+			}
+		}
+	}
+
+	if authenticationDao.lists != 1 {
+		t.Errorf("want the authentications to be listed once, got %d", authenticationDao.lists)
+	}
+}
+
+// TestGraphQLDuplicateListings tests that listing the endpoints or the RHC connections twice in the same query gets
+// the second listing rejected instead of bringing the API down.
+func TestGraphQLDuplicateListings(t *testing.T) {
+	rhcConnections := []m.RhcConnection{
+		{ID: 1, RhcId: "rhc-1", Sources: []m.Source{{ID: fixtures.TestSourceData[0].ID}}},
+	}
+
+	backupEndpointDao, backupAuthenticationDao := dao.GetEndpointDao, dao.GetAuthenticationDao
+	backupRhcConnectionDao, backupSourceDao := dao.GetRhcConnectionDao, dao.GetSourceDao
+	dao.GetEndpointDao = func(*int64) dao.EndpointDao {
+		return &mocks.MockEndpointDao{Endpoints: fixtures.TestEndpointData}
+	}
+	dao.GetAuthenticationDao = func(*dao.RequestParams) dao.AuthenticationDao {
+		return &mocks.MockAuthenticationDao{Authentications: fixtures.TestAuthenticationData}
+	}
+	dao.GetRhcConnectionDao = func(*dao.RequestParams) dao.RhcConnectionDao {
+		return &mocks.MockRhcConnectionDao{RhcConnections: rhcConnections}
+	}
+	dao.GetSourceDao = func(*dao.RequestParams) dao.SourceDao { return &mocks.MockSourceDao{Sources: fixtures.TestSourceData} }
+	defer func() {
+		dao.GetEndpointDao, dao.GetAuthenticationDao = backupEndpointDao, backupAuthenticationDao
+		dao.GetRhcConnectionDao, dao.GetSourceDao = backupRhcConnectionDao, backupSourceDao
+	}()
+
+	response := graphQLRequest(t, `{
+		a: endpoints { id authentications { id } }
+		b: endpoints { id authentications { id } }
+		c: rhc_connections { id sources { id } }
+		d: rhc_connections { id sources { id } }
+	}`, bypassAuthorizer, map[string]interface{}{})
+
+	if len(response.Errors) != 2 {
+		t.Fatalf("want the second endpoints and connections listings to be rejected, got %+v", response.Errors)
+	}
+
+	for _, gqlError := range response.Errors {
+		if !strings.Contains(gqlError.Message, "can only be listed once per query") {
+			t.Errorf("want a duplicate listing error, got %+v", gqlError)
+		}
+	}
+}
+
+// TestGraphQLListings tests that the source types, the applications' metadata, the endpoints and the RHC connections
+// can be listed with their subresources.
+func TestGraphQLListings(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	response := graphQLRequest(t, `{
+		source_types(sort_by: {name: "name"}) { id name product_name vendor schema }
+		app_meta_data(filter: {name: "name", operation: "not_eq", value: ["unknown"]}) { id application_type_id name payload }
+		endpoints(limit: 10) { id source_id host authentications { id authtype } }
+		rhc_connections { id rhc_id sources { id name applications { id } } }
+	}`, bypassAuthorizer, map[string]interface{}{})
+
+	if len(response.Errors) != 0 {
+		t.Fatalf("want no errors, got %+v", response.Errors)
+	}
+
+	var data struct {
+		SourceTypes    []json.RawMessage `json:"source_types"`
+		AppMetaData    []json.RawMessage `json:"app_meta_data"`
+		Endpoints      []json.RawMessage `json:"endpoints"`
+		RhcConnections []struct {
+			Sources []json.RawMessage `json:"sources"`
+		} `json:"rhc_connections"`
+	}
+
+	err := json.Unmarshal(response.Data, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(data.SourceTypes) != len(fixtures.TestSourceTypeData) {
+		t.Errorf("want %d source types, got %d", len(fixtures.TestSourceTypeData), len(data.SourceTypes))
+	}
+
+	if len(data.Endpoints) == 0 || len(data.AppMetaData) == 0 || len(data.RhcConnections) == 0 {
+		t.Errorf("want the fixtures to be listed, got %s", response.Data)
+	}
+
+	for _, rhcConnection := range data.RhcConnections {
+		if len(rhcConnection.Sources) == 0 {
+			t.Errorf("want every connection to have its sources, got %s", response.Data)
+		}
+	}
+}
+
+// TestGraphQLSourcesConnection tests that the sources and their applications are paginated and counted on their own
+// through the connections.
+func TestGraphQLSourcesConnection(t *testing.T) {
+	cleanup := setupSuperKeyTest(false)
+	defer cleanup()
+
+	response := graphQLRequest(t, `{
+		sources_connection(first: 1) {
+			totalCount
+			pageInfo { hasNextPage hasPreviousPage startCursor endCursor }
+			edges {
+				cursor
+				node {
+					id
+					applications_connection(first: 1) { totalCount pageInfo { hasNextPage } edges { node { id } } }
+				}
+			}
+		}
+	}`, bypassAuthorizer, map[string]interface{}{})
+
+	if len(response.Errors) != 0 {
+		t.Fatalf("want no errors, got %+v", response.Errors)
+	}
+
+	var data struct {
+		SourcesConnection struct {
+			TotalCount int `json:"totalCount"`
+			PageInfo   struct {
+				HasNextPage     bool   `json:"hasNextPage"`
+				HasPreviousPage bool   `json:"hasPreviousPage"`
+				StartCursor     string `json:"startCursor"`
+				EndCursor       string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Edges []struct {
+				Cursor string `json:"cursor"`
+				Node   struct {
+					ID                     string `json:"id"`
+					ApplicationsConnection struct {
+						TotalCount int `json:"totalCount"`
+						PageInfo   struct {
+							HasNextPage bool `json:"hasNextPage"`
+						} `json:"pageInfo"`
+						Edges []json.RawMessage `json:"edges"`
+					} `json:"applications_connection"`
+				} `json:"node"`
+			} `json:"edges"`
+		} `json:"sources_connection"`
+	}
+
+	err := json.Unmarshal(response.Data, &data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	connection := data.SourcesConnection
+	source := fixtures.TestSourceData[0]
+
+	if connection.TotalCount != len(fixtures.TestSourceData) || len(connection.Edges) != 1 {
+		t.Fatalf("want one of the %d sources, got %+v", len(fixtures.TestSourceData), connection)
+	}
+
+	if !connection.PageInfo.HasNextPage || connection.PageInfo.HasPreviousPage {
+		t.Errorf("want a next page and no previous page, got %+v", connection.PageInfo)
+	}
+
+	edge := connection.Edges[0]
+	if edge.Node.ID != strconv.FormatInt(source.ID, 10) || edge.Cursor != source.Cursor().Encode() {
+		t.Errorf("want the source %d and its cursor, got %+v", source.ID, edge)
+	}
+
+	if connection.PageInfo.StartCursor != edge.Cursor || connection.PageInfo.EndCursor != edge.Cursor {
+		t.Errorf("want the page's cursors to be the edge's, got %+v", connection.PageInfo)
+	}
+
+	wantApplications := 0
+	for _, app := range fixtures.TestApplicationData {
+		if app.SourceID == source.ID {
+			wantApplications++
+		}
+	}
+
+	applications := edge.Node.ApplicationsConnection
+	if applications.TotalCount != wantApplications || len(applications.Edges) != min(wantApplications, 1) || applications.PageInfo.HasNextPage != (wantApplications > 1) {
+		t.Errorf("want the first of the source's %d applications, got %+v", wantApplications, applications)
+	}
+}
+
+// TestGraphQLConnectionPages tests that the connections' cursors lead to the following pages.
+func TestGraphQLConnectionPages(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	type sourcesPage struct {
+		SourcesConnection struct {
+			TotalCount int `json:"totalCount"`
+			PageInfo   struct {
+				HasNextPage     bool   `json:"hasNextPage"`
+				HasPreviousPage bool   `json:"hasPreviousPage"`
+				EndCursor       string `json:"endCursor"`
+			} `json:"pageInfo"`
+			Edges []struct {
+				Node struct {
+					ID string `json:"id"`
+				} `json:"node"`
+			} `json:"edges"`
+		} `json:"sources_connection"`
+	}
+
+	var ids []string
+
+	after := ""
+	for {
+		arguments := "first: 1"
+		if after != "" {
+			arguments += fmt.Sprintf(", after: %q", after)
+		}
+
+		response := graphQLRequest(t, fmt.Sprintf(`{ sources_connection(%s) { totalCount pageInfo { hasNextPage hasPreviousPage endCursor } edges { node { id } } } }`, arguments), bypassAuthorizer, map[string]interface{}{})
+		if len(response.Errors) != 0 {
+			t.Fatalf("want no errors, got %+v", response.Errors)
+		}
+
+		var data sourcesPage
+
+		err := json.Unmarshal(response.Data, &data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		connection := data.SourcesConnection
+		if len(connection.Edges) != 1 || connection.PageInfo.HasPreviousPage != (after != "") {
+			t.Fatalf("want a page of one source, got %+v", connection)
+		}
+
+		ids = append(ids, connection.Edges[0].Node.ID)
+
+		if len(ids) > connection.TotalCount {
+			t.Fatalf("want at most %d pages, got the sources %v", connection.TotalCount, ids)
+		}
+
+		if !connection.PageInfo.HasNextPage {
+			if len(ids) != connection.TotalCount {
+				t.Errorf("want to page through the %d sources, got %v", connection.TotalCount, ids)
+			}
+
+			break
+		}
+
+		after = connection.PageInfo.EndCursor
+	}
+
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+
+	if len(slices.Compact(sorted)) != len(ids) {
+		t.Errorf("want every source to be on one page only, got %v", ids)
+	}
+}
+
+// TestGraphQLLimits tests that the operations over the complexity or the depth limits are rejected before they run.
+func TestGraphQLLimits(t *testing.T) {
+	backupComplexity, backupDepth := conf.GraphQLMaxComplexity, conf.GraphQLMaxDepth
+	conf.GraphQLMaxComplexity, conf.GraphQLMaxDepth = 1000, 3
+	defer func() { conf.GraphQLMaxComplexity, conf.GraphQLMaxDepth = backupComplexity, backupDepth }()
+
+	tests := []struct {
+		name     string
+		query    string
+		wantCode string
+	}{
+		{
+			name:     "over the complexity limit",
+			query:    `{ sources(limit: 1000) { id name } }`,
+			wantCode: "COMPLEXITY_LIMIT_EXCEEDED",
+		},
+		{
+			name:     "over the depth limit",
+			query:    `{ sources(limit: 1) { applications { authentications { id } } } }`,
+			wantCode: "DEPTH_LIMIT_EXCEEDED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := graphQLRequest(t, tt.query, bypassAuthorizer, map[string]interface{}{})
+
+			if len(response.Errors) != 1 {
+				t.Fatalf("want one error, got %+v", response.Errors)
+			}
+
+			if response.Errors[0].Extensions["code"] != tt.wantCode {
+				t.Errorf("want the %q error code, got %+v", tt.wantCode, response.Errors[0])
+			}
+
+			if len(response.Data) != 0 && string(response.Data) != "null" {
+				t.Errorf("want no data, got %s", response.Data)
+			}
+		})
+	}
+}
+
+// TestGraphQLSubscription tests that the availability transitions of the tenant's sources are pushed to the
+// subscriptions made over WebSocket, and that the other tenants' transitions are not.
+func TestGraphQLSubscription(t *testing.T) {
+	originalBroadcaster := service.Broadcaster
+	defer func() { service.Broadcaster = originalBroadcaster }()
+
+	broadcaster := &mocks.MockEventBroadcaster{}
+	service.Broadcaster = broadcaster
+
+	// the tenancy the "Tenancy" middleware would have resolved for the upgraded request.
+	e := echo.New()
+	e.GET("/graphql", GraphQLQuery(&mocks.MockSuperKeyProducer{}, bypassAuthorizer), func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.Set(h.TenantID, int64(1))
+			c.Set(h.OrgID, "12345")
+			c.Set(h.AccountNumber, "67890")
+
+			return next(c)
+		}
+	})
+
+	subscription := client.New(e, client.Path("/graphql")).Websocket(`subscription { sourceAvailabilityChanged { id previous_status availability_status } }`)
+	defer subscription.Close()
+
+	if !broadcaster.WaitForSubscribers(service.EventStreamChannels("12345", "")[0], time.Second) {
+		t.Fatalf("the subscription did not subscribe to the tenant's events")
+	}
+
+	err := service.BroadcastAvailabilityTransition(&m.Tenant{Id: 2, OrgID: "99999"}, "Source", "2", m.Unavailable, m.Available)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = service.BroadcastAvailabilityTransition(&m.Tenant{Id: 1, OrgID: "12345", ExternalTenant: "67890"}, "Source", "1", m.Unavailable, m.Available)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var response struct {
+		SourceAvailabilityChanged struct {
+			ID                 string `json:"id"`
+			PreviousStatus     string `json:"previous_status"`
+			AvailabilityStatus string `json:"availability_status"`
+		}
+	}
+
+	err = subscription.Next(&response)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	transition := response.SourceAvailabilityChanged
+	if transition.ID != "1" || transition.PreviousStatus != m.Unavailable || transition.AvailabilityStatus != m.Available {
+		t.Errorf("want the tenant's source transition, got %+v", transition)
+	}
+}
+
+// graphQLParamsRequest sends the GraphQL query along with the extensions, in the body of a POST request or in the query
+// string of a GET request. The query can be left empty to send the persisted query's hash only.
+func graphQLParamsRequest(t *testing.T, method string, query string, extensions map[string]interface{}) graphQLResponse {
+	path := "/graphql"
+
+	var body io.Reader
+
+	switch method {
+	case http.MethodGet:
+		values := url.Values{}
+		if query != "" {
+			values.Set("query", query)
+		}
+
+		if extensions != nil {
+			encoded, err := json.Marshal(extensions)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			values.Set("extensions", string(encoded))
+		}
+
+		path += "?" + values.Encode()
+	default:
+		encoded, err := json.Marshal(map[string]interface{}{"query": query, "extensions": extensions})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		body = bytes.NewReader(encoded)
+	}
+
+	c, rec := request.CreateTestContext(method, path, body, map[string]interface{}{"tenantID": int64(1)})
+	c.Request().Header.Set("Content-Type", "application/json")
+
+	err := GraphQLQuery(&mocks.MockSuperKeyProducer{}, bypassAuthorizer)(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var response graphQLResponse
+
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the response %q: %s", rec.Body.String(), err)
+	}
+
+	return response
+}
+
+// persistedQueryExtensions returns the extensions which send the query's hash, like the clients do for the automatic
+// persisted queries.
+func persistedQueryExtensions(query string) map[string]interface{} {
+	sum := sha256.Sum256([]byte(query))
+
+	return map[string]interface{}{"persistedQuery": map[string]interface{}{"version": 1, "sha256Hash": hex.EncodeToString(sum[:])}}
+}
+
+// TestGraphQLPersistedQueries tests that the clients can run the queries they persisted by their hash, with both POST
+// and GET requests.
+func TestGraphQLPersistedQueries(t *testing.T) {
+	backup := graph.PersistedQueries
+	defer func() { graph.PersistedQueries = backup }()
+
+	graph.PersistedQueries = &mocks.MockPersistedQueryStore{}
+
+	query := `{ __typename }`
+	extensions := persistedQueryExtensions(query)
+
+	// the hash is not known until the client sends the query along with it.
+	response := graphQLParamsRequest(t, http.MethodPost, "", extensions)
+	if len(response.Errors) != 1 || response.Errors[0].Extensions["code"] != "PERSISTED_QUERY_NOT_FOUND" {
+		t.Fatalf("want the persisted query not to be found, got %+v", response.Errors)
+	}
+
+	response = graphQLParamsRequest(t, http.MethodPost, query, extensions)
+	if len(response.Errors) != 0 {
+		t.Fatalf("want no errors, got %+v", response.Errors)
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodGet} {
+		response = graphQLParamsRequest(t, method, "", extensions)
+		if len(response.Errors) != 0 {
+			t.Fatalf("want no errors for the %s request, got %+v", method, response.Errors)
+		}
+
+		if string(response.Data) != `{"__typename":"Query"}` {
+			t.Errorf("want the persisted query's data for the %s request, got %s", method, response.Data)
+		}
+	}
+
+	// the mutations cannot be sent with GET requests.
+	response = graphQLParamsRequest(t, http.MethodGet, `mutation { pause_source(id: "1") { id } }`, nil)
+	if len(response.Errors) != 1 {
+		t.Errorf("want the GET mutation to be rejected, got %+v", response)
+	}
+}
+
+// TestGraphQLPersistedQueryAllowlist tests that only the registered queries run in the allowlist mode, and that the
+// clients cannot persist any others.
+func TestGraphQLPersistedQueryAllowlist(t *testing.T) {
+	backup := graph.PersistedQueries
+	defer func() { graph.PersistedQueries = backup }()
+
+	graph.PersistedQueries = &mocks.MockPersistedQueryStore{}
+
+	backupAllowlist := conf.GraphQLAPQAllowlist
+	conf.GraphQLAPQAllowlist = true
+	defer func() { conf.GraphQLAPQAllowlist = backupAllowlist }()
+
+	query := `{ __typename }`
+	extensions := persistedQueryExtensions(query)
+
+	for _, tt := range []struct {
+		query      string
+		extensions map[string]interface{}
+	}{{query, nil}, {query, extensions}, {"", extensions}} {
+		response := graphQLParamsRequest(t, http.MethodPost, tt.query, tt.extensions)
+		if len(response.Errors) != 1 || response.Errors[0].Extensions["code"] != "PERSISTED_QUERY_NOT_ALLOWED" {
+			t.Fatalf("want the unregistered query to be rejected, got %+v", response.Errors)
+		}
+	}
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/internal/v2.0/graphql/persisted_queries",
+		strings.NewReader(`{"query": "{ __typename }"}`),
+		map[string]interface{}{},
+	)
+	c.Request().Header.Set("Content-Type", "application/json")
+
+	err := InternalGraphQLPersistedQueryCreate(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("want the query to be registered, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	for _, tt := range []struct {
+		query      string
+		extensions map[string]interface{}
+	}{{query, nil}, {query, extensions}, {"", extensions}} {
+		response := graphQLParamsRequest(t, http.MethodPost, tt.query, tt.extensions)
+		if len(response.Errors) != 0 {
+			t.Fatalf("want the registered query to run, got %+v", response.Errors)
+		}
+	}
+}
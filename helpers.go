@@ -6,8 +6,11 @@ import (
 	"reflect"
 	"strings"
 
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/middleware"
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
 	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
@@ -54,17 +57,35 @@ func getLimitAndOffset(c echo.Context) (int, int, error) {
 	return limit, offset, nil
 }
 
+// pageBounds returns the first and the last items of the given page, which the collection response uses to build the
+// cursor links. An empty page has no bounds.
+func pageBounds[T any, P interface {
+	*T
+	util.Paginable
+}](page []T) []util.Paginable {
+	if len(page) == 0 {
+		return nil
+	}
+
+	return []util.Paginable{P(&page[0]), P(&page[len(page)-1])}
+}
+
 func setNotificationForAvailabilityStatus(c echo.Context, previousStatus string, resource m.EmailNotification) {
 	c.Set("emailNotificationInfo", resource.ToEmail(previousStatus))
 }
 
-func setEventStreamResource(c echo.Context, model m.Event) {
-	// get the model type we're raising the event for
+// eventResourceType returns the model type we're raising the event for.
+func eventResourceType(model m.Event) string {
 	// 1. Strip the pointer symbol
 	// 2. Strip the package prefix ("model.")
 	t := reflect.TypeOf(model)
 	m := strings.TrimPrefix(t.String(), "*")
-	m = strings.TrimPrefix(m, "model.")
+
+	return strings.TrimPrefix(m, "model.")
+}
+
+func setEventStreamResource(c echo.Context, model m.Event) {
+	m := eventResourceType(model)
 
 	// get the event type that just happened based on the http request
 	event := ""
@@ -84,6 +105,50 @@ func setEventStreamResource(c echo.Context, model m.Event) {
 	c.Set("resource", model)
 }
 
+// setAuditPrevious stores a snapshot of the resource before the handler changes it, so that the "AuditTrail"
+// middleware is able to tell which fields changed.
+func setAuditPrevious(c echo.Context, model m.Event) {
+	snapshot, err := service.AuditSnapshot(model)
+	if err != nil {
+		c.Logger().Warnf("Unable to take the audit snapshot: %v", err)
+		return
+	}
+
+	c.Set("audit_previous", snapshot)
+}
+
+// setAuditDestroyedResource lets the "AuditTrail" middleware know that the resource was destroyed, for the handlers
+// which leave raising the destroy events to the cascade deletion.
+func setAuditDestroyedResource(c echo.Context, model m.Event) {
+	c.Set("audit_event_type", eventResourceType(model)+".destroy")
+	c.Set("audit_resource", model)
+}
+
+// recordAuditDestroyedResource records the destruction of the resource straight away, for the handlers which destroy
+// several resources in a single request, since the "AuditTrail" middleware records a single resource per request.
+func recordAuditDestroyedResource(c echo.Context, model m.Event) {
+	eventType := eventResourceType(model) + ".destroy"
+
+	requestParams, err := dao.NewRequestParamsFromContext(c)
+	if err != nil {
+		c.Logger().Warnf("Unable to record the audit event %v: %v", eventType, err)
+		return
+	}
+
+	actorType, actorID := middleware.AuditActor(c)
+
+	err = service.RecordAuditEvent(requestParams, eventType, model, nil, actorType, actorID)
+	if err != nil {
+		c.Logger().Warnf("Unable to record the audit event %v: %v", eventType, err)
+	}
+}
+
+// setAuditRestoredResource lets the "AuditTrail" middleware know that the soft deleted resource was restored.
+func setAuditRestoredResource(c echo.Context, model m.Event) {
+	c.Set("audit_event_type", eventResourceType(model)+".restore")
+	c.Set("audit_resource", model)
+}
+
 func getAccountNumberFromEchoContext(c echo.Context) (string, error) {
 	id, ok := c.Get(h.ParsedIdentity).(*identity.XRHID)
 	if !ok {
@@ -0,0 +1,249 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// includedAuthenticationsLimit is the maximum number of authentications that get included for every resource, which
+// is the same number the authentications subcollections return.
+const includedAuthenticationsLimit = 100
+
+// sourceIncludes maps the relations that can be included in the source responses to the resource type of their
+// records, which is the one used in the sparse fieldsets.
+var sourceIncludes = map[string]string{
+	"applications":    "applications",
+	"authentications": "authentications",
+	"endpoints":       "endpoints",
+	"rhc_connections": "rhc_connections",
+	"source_type":     "source_types",
+}
+
+// sourcePreloads maps the relations of the sources to the preloads which fetch them. The authentications might live in
+// Vault, so they are fetched through their own DAO instead.
+var sourcePreloads = map[string]string{
+	"applications":    "Applications",
+	"endpoints":       "Endpoints",
+	"rhc_connections": "SourceRhcConnections.RhcConnection",
+	"source_type":     "SourceType",
+}
+
+// applicationIncludes maps the relations that can be included in the application responses to the resource type of
+// their records.
+var applicationIncludes = map[string]string{
+	"application_type": "application_types",
+	"authentications":  "authentications",
+	"source":           "sources",
+}
+
+// applicationPreloads maps the relations of the applications to the preloads which fetch them.
+var applicationPreloads = map[string]string{
+	"application_type": "ApplicationType",
+	"source":           "Source",
+}
+
+// parseIncludes parses the "include=a,b,c" query parameter, and rejects the relations which cannot be included.
+func parseIncludes(c echo.Context, allowed map[string]string) ([]string, error) {
+	includes := make([]string, 0)
+
+	for _, value := range c.QueryParams()["include"] {
+		for _, include := range strings.Split(value, ",") {
+			include = strings.TrimSpace(include)
+			if include == "" {
+				continue
+			}
+
+			if _, ok := allowed[include]; !ok {
+				return nil, util.NewErrBadRequest(fmt.Sprintf(`invalid include "%s"`, include))
+			}
+
+			if !slices.Contains(includes, include) {
+				includes = append(includes, include)
+			}
+		}
+	}
+
+	return includes, nil
+}
+
+// preloadsFor returns the preloads which fetch the given relations.
+func preloadsFor(includes []string, preloads map[string]string) []string {
+	out := make([]string, 0, len(includes))
+
+	for _, include := range includes {
+		if preload, ok := preloads[include]; ok {
+			out = append(out, preload)
+		}
+	}
+
+	return out
+}
+
+// sourceIncluded returns the responses of the given source's related records, keyed by relation. The source is
+// expected to have the relations preloaded, and the authentications are the ones of the source.
+func sourceIncluded(src *m.Source, auths []interface{}, includes []string) map[string]interface{} {
+	included := make(map[string]interface{}, len(includes))
+
+	for _, include := range includes {
+		switch include {
+		case "applications":
+			out := make([]interface{}, len(src.Applications))
+			for i := range src.Applications {
+				out[i] = src.Applications[i].ToResponse()
+			}
+
+			included[include] = out
+		case "authentications":
+			if auths == nil {
+				auths = make([]interface{}, 0)
+			}
+
+			included[include] = auths
+		case "endpoints":
+			out := make([]interface{}, len(src.Endpoints))
+			for i := range src.Endpoints {
+				out[i] = src.Endpoints[i].ToResponse()
+			}
+
+			included[include] = out
+		case "rhc_connections":
+			out := make([]interface{}, len(src.SourceRhcConnections))
+			for i := range src.SourceRhcConnections {
+				out[i] = src.SourceRhcConnections[i].RhcConnection.ToResponse()
+			}
+
+			included[include] = out
+		case "source_type":
+			included[include] = src.SourceType.ToResponse()
+		}
+	}
+
+	return included
+}
+
+// applicationIncluded returns the responses of the given application's related records, keyed by relation. The
+// application is expected to have the relations preloaded, and the authentications are the ones of the application.
+func applicationIncluded(app *m.Application, auths []interface{}, includes []string) map[string]interface{} {
+	included := make(map[string]interface{}, len(includes))
+
+	for _, include := range includes {
+		switch include {
+		case "application_type":
+			included[include] = app.ApplicationType.ToResponse()
+		case "authentications":
+			if auths == nil {
+				auths = make([]interface{}, 0)
+			}
+
+			included[include] = auths
+		case "source":
+			included[include] = app.Source.ToResponse()
+		}
+	}
+
+	return included
+}
+
+// includedAuthentications returns the responses of the authentications of the given sources or applications, keyed
+// by the ID of the resource they belong to. They are all fetched at once, and the responses never carry the
+// authentications' secrets.
+func includedAuthentications(c echo.Context, ids []int64, resourceType string) (map[int64][]interface{}, error) {
+	authDao, err := getAuthenticationDao(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var auths []m.Authentication
+
+	if resourceType == "source" {
+		auths, err = authDao.ListForSources(ids)
+	} else {
+		auths, err = authDao.ListForApplications(ids)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[int64][]interface{}, len(ids))
+
+	for i := range auths {
+		id := auths[i].ResourceID
+		if resourceType == "source" {
+			id = auths[i].SourceID
+		}
+
+		if len(out[id]) < includedAuthenticationsLimit {
+			out[id] = append(out[id], auths[i].ToResponse())
+		}
+	}
+
+	return out, nil
+}
+
+// withIncluded returns the given response with the included records added as members named after their relations.
+// When a sparse fieldset is requested for the records' resource type, the records get trimmed down to it.
+func withIncluded(c echo.Context, response interface{}, included map[string]interface{}, resourceTypes map[string]string) (map[string]interface{}, error) {
+	decoded, err := toJSONValue(response)
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := decoded.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected response type %T", response)
+	}
+
+	fieldsets, _ := c.Get("fieldsets").(map[string][]string)
+
+	for relation, records := range included {
+		value, err := toJSONValue(records)
+		if err != nil {
+			return nil, err
+		}
+
+		if fields, ok := fieldsets[resourceTypes[relation]]; ok {
+			switch v := value.(type) {
+			case map[string]interface{}:
+				util.TrimJSONObject(v, fields)
+			case []interface{}:
+				for _, record := range v {
+					if recordObject, ok := record.(map[string]interface{}); ok {
+						util.TrimJSONObject(recordObject, fields)
+					}
+				}
+			}
+		}
+
+		object[relation] = value
+	}
+
+	return object, nil
+}
+
+// toJSONValue returns the decoded JSON representation of the given value.
+func toJSONValue(value interface{}) (interface{}, error) {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	err = decoder.Decode(&decoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return decoded, nil
+}
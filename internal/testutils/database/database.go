@@ -67,6 +67,11 @@ func CreateFixtures(schema string) {
 
 	dao.DB.Create(&fixtures.TestMetaDataData)
 
+	dao.DB.Create(&fixtures.TestAuditEventData)
+
+	dao.DB.Create(&fixtures.TestWebhookData)
+	dao.DB.Create(&fixtures.TestWebhookDeliveryData)
+
 	UpdateTablesSequences()
 }
 
@@ -123,6 +128,7 @@ func UpdateTablesSequences() {
 		"meta_data",
 		"applications",
 		"application_authentications",
+		"audit_events",
 		"application_types",
 		"authentications",
 		"rhc_connections",
@@ -130,6 +136,8 @@ func UpdateTablesSequences() {
 		"source_rhc_connections",
 		"source_types",
 		"tenants",
+		"webhooks",
+		"webhook_deliveries",
 		"users",
 	}
 
@@ -0,0 +1,49 @@
+package fixtures
+
+import (
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"gorm.io/datatypes"
+)
+
+var TestAuditEventData = []m.AuditEvent{
+	{
+		ID:           1,
+		ActorType:    m.AuditActorUser,
+		ActorID:      "testUser",
+		ResourceType: "Source",
+		ResourceID:   "1",
+		Action:       m.AuditActionCreate,
+		Changes:      datatypes.JSON(`{"name": {"from": null, "to": "Source1"}}`),
+		TenantID:     1,
+	},
+	{
+		ID:           2,
+		ActorType:    m.AuditActorPSK,
+		ActorID:      "pskUser",
+		ResourceType: "Source",
+		ResourceID:   "1",
+		Action:       m.AuditActionUpdate,
+		Changes:      datatypes.JSON(`{"availability_status": {"from": "in_progress", "to": "available"}}`),
+		TenantID:     1,
+	},
+	{
+		ID:           3,
+		ActorType:    m.AuditActorCert,
+		ActorID:      "cluster",
+		ResourceType: "Application",
+		ResourceID:   "1",
+		Action:       m.AuditActionCreate,
+		Changes:      datatypes.JSON(`{"source_id": {"from": null, "to": 1}}`),
+		TenantID:     1,
+	},
+	{
+		ID:           4,
+		ActorType:    m.AuditActorUser,
+		ActorID:      "otherUser",
+		ResourceType: "Source",
+		ResourceID:   "2",
+		Action:       m.AuditActionDestroy,
+		Changes:      datatypes.JSON(`{"name": {"from": "Source2", "to": null}}`),
+		TenantID:     2,
+	},
+}
@@ -0,0 +1,76 @@
+package fixtures
+
+import (
+	"time"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"gorm.io/datatypes"
+)
+
+var TestWebhookData = []m.Webhook{
+	{
+		ID:         1,
+		URL:        "https://example.com/webhooks/sources",
+		EventTypes: datatypes.JSONSlice[string]{"Source.create", "Source.update"},
+		Secret:     "encrypted-secret",
+		Enabled:    true,
+		TenantID:   1,
+	},
+	{
+		ID:         2,
+		URL:        "https://example.com/webhooks/applications",
+		EventTypes: datatypes.JSONSlice[string]{"Application.destroy"},
+		Secret:     "encrypted-secret",
+		Enabled:    false,
+		TenantID:   1,
+	},
+	{
+		ID:         3,
+		URL:        "https://example.org/webhooks",
+		EventTypes: datatypes.JSONSlice[string]{"Source.update"},
+		Secret:     "encrypted-secret",
+		Enabled:    true,
+		TenantID:   2,
+	},
+}
+
+var (
+	webhookDeliveryAttemptedAt       = time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	webhookDeliveryStatusOK          = 200
+	webhookDeliveryStatusUnavailable = 503
+)
+
+var TestWebhookDeliveryData = []m.WebhookDelivery{
+	{
+		ID:             1,
+		EventType:      "Source.create",
+		Payload:        datatypes.JSON(`{"event_type": "Source.create", "data": {"id": 1}}`),
+		Status:         m.WebhookDeliverySucceeded,
+		Attempts:       1,
+		LastAttemptAt:  &webhookDeliveryAttemptedAt,
+		ResponseStatus: &webhookDeliveryStatusOK,
+		WebhookID:      1,
+		TenantID:       1,
+	},
+	{
+		ID:             2,
+		EventType:      "Source.update",
+		Payload:        datatypes.JSON(`{"event_type": "Source.update", "data": {"id": 1}}`),
+		Status:         m.WebhookDeliveryPending,
+		Attempts:       2,
+		NextAttemptAt:  &webhookDeliveryAttemptedAt,
+		LastAttemptAt:  &webhookDeliveryAttemptedAt,
+		ResponseStatus: &webhookDeliveryStatusUnavailable,
+		Error:          "the webhook responded with status 503: ",
+		WebhookID:      1,
+		TenantID:       1,
+	},
+	{
+		ID:        3,
+		EventType: "Source.update",
+		Payload:   datatypes.JSON(`{"event_type": "Source.update", "data": {"id": 3}}`),
+		Status:    m.WebhookDeliveryPending,
+		WebhookID: 3,
+		TenantID:  2,
+	},
+}
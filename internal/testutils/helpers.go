@@ -57,10 +57,15 @@ func GetSourcesWithAppType(appTypeId int64) []model.Source {
 	return sources
 }
 
-func AssertLinks(t *testing.T, path string, links util.Links, limit int, offset int) {
+func AssertLinks(t *testing.T, path string, links util.Links, count int, limit int, offset int) {
 	expectedFirstLink := fmt.Sprintf("%s?limit=%d&offset=%d", path, limit, offset)
 
-	expectedLastLink := fmt.Sprintf("%s?limit=%d&offset=%d", path, limit, limit+offset)
+	lastOffset := 0
+	if count > 0 {
+		lastOffset = ((count - 1) / limit) * limit
+	}
+
+	expectedLastLink := fmt.Sprintf("%s?limit=%d&offset=%d", path, limit, lastOffset)
 	if links.First != expectedFirstLink {
 		t.Error("first link is not correct for " + path)
 	}
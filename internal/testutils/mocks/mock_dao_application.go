@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"strings"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
@@ -46,6 +48,26 @@ func (mockAppDao *MockApplicationDao) List(_ int, _ int, _ []util.Filter) ([]m.A
 	return mockAppDao.Applications, count, nil
 }
 
+func (mockAppDao *MockApplicationDao) Stream(_ []util.Filter, fn func(*m.Application) error) error {
+	for _, app := range mockAppDao.Applications {
+		err := fn(&app)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mockAppDao *MockApplicationDao) Stats(groupBy []string, _ []util.Filter) ([]m.StatsGroup, error) {
+	statuses := make([]string, len(mockAppDao.Applications))
+	for i, app := range mockAppDao.Applications {
+		statuses[i] = app.AvailabilityStatus
+	}
+
+	return mockStats(groupBy, statuses)
+}
+
 func (mockAppDao *MockApplicationDao) GetById(id *int64) (*m.Application, error) {
 	for _, app := range mockAppDao.Applications {
 		if app.ID == *id {
@@ -63,6 +85,14 @@ func (mockAppDao *MockApplicationDao) GetByIdWithPreload(id *int64, preloads ...
 				if strings.Contains(strings.ToLower(preload), "source") {
 					app.Source = fixtures.TestSourceData[0]
 				}
+
+				if preload == "ApplicationType" {
+					for _, appType := range fixtures.TestApplicationTypeData {
+						if appType.Id == app.ApplicationTypeID {
+							app.ApplicationType = appType
+						}
+					}
+				}
 			}
 
 			return &app, nil
@@ -72,6 +102,21 @@ func (mockAppDao *MockApplicationDao) GetByIdWithPreload(id *int64, preloads ...
 	return nil, util.NewErrNotFound("application")
 }
 
+func (mockAppDao *MockApplicationDao) ListByIdsWithPreload(ids []int64, preloads ...string) ([]m.Application, error) {
+	out := make([]m.Application, 0, len(ids))
+
+	for _, id := range ids {
+		app, err := mockAppDao.GetByIdWithPreload(&id, preloads...)
+		if err != nil {
+			continue
+		}
+
+		out = append(out, *app)
+	}
+
+	return out, nil
+}
+
 func (mockAppDao *MockApplicationDao) Create(_ *m.Application) error {
 	return nil
 }
@@ -80,6 +125,10 @@ func (mockAppDao *MockApplicationDao) Update(_ *m.Application) error {
 	return nil
 }
 
+func (mockAppDao *MockApplicationDao) UpdateIfUnmodified(app *m.Application, _ time.Time) error {
+	return mockAppDao.Update(app)
+}
+
 func (mockAppDao *MockApplicationDao) Delete(id *int64) (*m.Application, error) {
 	for _, app := range mockAppDao.Applications {
 		if app.ID == *id {
@@ -116,6 +165,10 @@ func (mockAppDao *MockApplicationDao) DeleteCascade(applicationId int64) ([]m.Ap
 	return fixtures.TestApplicationAuthenticationData, application, nil
 }
 
+func (mockAppDao *MockApplicationDao) DeleteCascadeIfUnmodified(applicationId int64, _ time.Time) ([]m.ApplicationAuthentication, *m.Application, error) {
+	return mockAppDao.DeleteCascade(applicationId)
+}
+
 func (mockAppDao *MockApplicationDao) Exists(applicationId int64) (bool, error) {
 	for _, application := range mockAppDao.Applications {
 		if application.ID == applicationId {
@@ -112,8 +112,14 @@ func (mockAppTypeDao *MockApplicationTypeDao) ApplicationTypeCompatibleWithSourc
 	return errors.New("not compatible")
 }
 
-func (mockAppTypeDao *MockApplicationTypeDao) GetByName(_ string) (*m.ApplicationType, error) {
-	return nil, nil
+func (mockAppTypeDao *MockApplicationTypeDao) GetByName(name string) (*m.ApplicationType, error) {
+	for _, appType := range mockAppTypeDao.ApplicationTypes {
+		if mockAppTypeDao.isAppTypeEnabled(appType) && appType.Name == name {
+			return &appType, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("application type")
 }
 
 // isAppTypeEnabled returns true when the given application type is enabled.
@@ -0,0 +1,33 @@
+package mocks
+
+import (
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+type MockAuditEventDao struct {
+	AuditEvents []m.AuditEvent
+}
+
+func (mockAuditEventDao *MockAuditEventDao) Create(auditEvent *m.AuditEvent) error {
+	mockAuditEventDao.AuditEvents = append(mockAuditEventDao.AuditEvents, *auditEvent)
+
+	return nil
+}
+
+func (mockAuditEventDao *MockAuditEventDao) List(_, _ int, _ []util.Filter) ([]m.AuditEvent, int64, error) {
+	count := int64(len(mockAuditEventDao.AuditEvents))
+	return mockAuditEventDao.AuditEvents, count, nil
+}
+
+func (mockAuditEventDao *MockAuditEventDao) ListForResource(resourceType, resourceID string, _, _ int, _ []util.Filter) ([]m.AuditEvent, int64, error) {
+	auditEvents := make([]m.AuditEvent, 0)
+
+	for _, auditEvent := range mockAuditEventDao.AuditEvents {
+		if auditEvent.ResourceType == resourceType && auditEvent.ResourceID == resourceID {
+			auditEvents = append(auditEvents, auditEvent)
+		}
+	}
+
+	return auditEvents, int64(len(auditEvents)), nil
+}
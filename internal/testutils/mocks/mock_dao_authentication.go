@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
@@ -86,6 +87,49 @@ func (mockAuthDao MockAuthenticationDao) ListForApplication(appId int64, _, _ in
 	return out, int64(len(out)), nil
 }
 
+func (mockAuthDao MockAuthenticationDao) ListForSources(sourceIDs []int64) ([]m.Authentication, error) {
+	out := make([]m.Authentication, 0)
+
+	for _, auth := range mockAuthDao.Authentications {
+		if slices.Contains(sourceIDs, auth.SourceID) {
+			out = append(out, auth)
+		}
+	}
+
+	return out, nil
+}
+
+func (mockAuthDao MockAuthenticationDao) ListSecretsForSources(sourceIDs []int64) ([]m.Authentication, error) {
+	return mockAuthDao.ListForSources(sourceIDs)
+}
+
+func (mockAuthDao MockAuthenticationDao) ListByIds(ids []string) ([]m.Authentication, error) {
+	out := make([]m.Authentication, 0, len(ids))
+
+	for _, id := range ids {
+		auth, err := mockAuthDao.GetById(id)
+		if err != nil {
+			continue
+		}
+
+		out = append(out, *auth)
+	}
+
+	return out, nil
+}
+
+func (mockAuthDao MockAuthenticationDao) ListForApplications(applicationIDs []int64) ([]m.Authentication, error) {
+	out := make([]m.Authentication, 0)
+
+	for _, auth := range fixtures.TestAuthenticationData {
+		if auth.ResourceType == "Application" && slices.Contains(applicationIDs, auth.ResourceID) {
+			out = append(out, auth)
+		}
+	}
+
+	return out, nil
+}
+
 func (mockAuthDao MockAuthenticationDao) ListForApplicationAuthentication(appAuthID int64, _, _ int, _ []util.Filter) ([]m.Authentication, int64, error) {
 	var (
 		appAuthExists bool
@@ -157,6 +201,10 @@ func (mockAuthDao MockAuthenticationDao) Update(auth *m.Authentication) error {
 	return util.NewErrNotFound("authentication")
 }
 
+func (mockAuthDao MockAuthenticationDao) UpdateIfMatch(auth *m.Authentication, _ string) error {
+	return mockAuthDao.Update(auth)
+}
+
 func (mockAuthDao MockAuthenticationDao) Delete(id string) (*m.Authentication, error) {
 	for _, auth := range mockAuthDao.Authentications {
 		// If secret store is database, we compare given ID with different field
@@ -175,6 +223,10 @@ func (mockAuthDao MockAuthenticationDao) Delete(id string) (*m.Authentication, e
 	return nil, util.NewErrNotFound("authentication")
 }
 
+func (mockAuthDao MockAuthenticationDao) DeleteIfMatch(id string, _ string) (*m.Authentication, error) {
+	return mockAuthDao.Delete(id)
+}
+
 func (mockAuthDao MockAuthenticationDao) Tenant() *int64 {
 	fakeTenantId := int64(12345)
 	return &fakeTenantId
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"fmt"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
@@ -64,6 +66,21 @@ func (mockEndpointDao *MockEndpointDao) GetById(id *int64) (*m.Endpoint, error)
 	return nil, util.NewErrNotFound("endpoint")
 }
 
+func (mockEndpointDao *MockEndpointDao) ListByIds(ids []int64) ([]m.Endpoint, error) {
+	out := make([]m.Endpoint, 0, len(ids))
+
+	for _, id := range ids {
+		endpoint, err := mockEndpointDao.GetById(&id)
+		if err != nil {
+			continue
+		}
+
+		out = append(out, *endpoint)
+	}
+
+	return out, nil
+}
+
 func (mockEndpointDao *MockEndpointDao) Create(_ *m.Endpoint) error {
 	return nil
 }
@@ -76,6 +93,10 @@ func (mockEndpointDao *MockEndpointDao) Update(endpoint *m.Endpoint) error {
 	return util.NewErrNotFound("endpoint")
 }
 
+func (mockEndpointDao *MockEndpointDao) UpdateIfUnmodified(endpoint *m.Endpoint, _ time.Time) error {
+	return mockEndpointDao.Update(endpoint)
+}
+
 func (mockEndpointDao *MockEndpointDao) Delete(id *int64) (*m.Endpoint, error) {
 	for i, e := range mockEndpointDao.Endpoints {
 		if e.ID == *id {
@@ -88,6 +109,10 @@ func (mockEndpointDao *MockEndpointDao) Delete(id *int64) (*m.Endpoint, error) {
 	return nil, util.NewErrNotFound("endpoint")
 }
 
+func (mockEndpointDao *MockEndpointDao) DeleteIfUnmodified(id *int64, _ time.Time) (*m.Endpoint, error) {
+	return mockEndpointDao.Delete(id)
+}
+
 func (mockEndpointDao *MockEndpointDao) Tenant() *int64 {
 	tenant := int64(1)
 	return &tenant
@@ -1,6 +1,8 @@
 package mocks
 
 import (
+	"time"
+
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
@@ -78,6 +80,10 @@ func (mockRhcConnectionDao *MockRhcConnectionDao) Update(rhcConnection *m.RhcCon
 	return util.NewErrNotFound("rhcConnection")
 }
 
+func (mockRhcConnectionDao *MockRhcConnectionDao) UpdateIfUnmodified(rhcConnection *m.RhcConnection, _ time.Time) error {
+	return mockRhcConnectionDao.Update(rhcConnection)
+}
+
 func (mockRhcConnectionDao *MockRhcConnectionDao) Delete(id *int64) (*m.RhcConnection, error) {
 	for _, rhcTmp := range mockRhcConnectionDao.RhcConnections {
 		if rhcTmp.ID == *id {
@@ -88,6 +94,10 @@ func (mockRhcConnectionDao *MockRhcConnectionDao) Delete(id *int64) (*m.RhcConne
 	return nil, util.NewErrNotFound("rhcConnection")
 }
 
+func (mockRhcConnectionDao *MockRhcConnectionDao) DeleteIfUnmodified(id *int64, _ time.Time) (*m.RhcConnection, error) {
+	return mockRhcConnectionDao.Delete(id)
+}
+
 func (mockRhcConnectionDao *MockRhcConnectionDao) ListForSource(_ *int64, _, _ int, _ []util.Filter) ([]m.RhcConnection, int64, error) {
 	count := int64(len(mockRhcConnectionDao.RelatedRhcConnections))
 
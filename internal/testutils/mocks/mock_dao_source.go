@@ -2,6 +2,7 @@ package mocks
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
@@ -12,6 +13,7 @@ import (
 type MockSourceDao struct {
 	Sources         []m.Source
 	RelatedSources  []m.Source
+	DeletedSources  []m.Source
 	SuperKeyEnabled bool
 }
 
@@ -71,6 +73,26 @@ func (mockSourceDao *MockSourceDao) List(_, _ int, _ []util.Filter) ([]m.Source,
 	return mockSourceDao.Sources, count, nil
 }
 
+func (mockSourceDao *MockSourceDao) Stream(_ []util.Filter, fn func(*m.Source) error) error {
+	for _, src := range mockSourceDao.Sources {
+		err := fn(&src)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (mockSourceDao *MockSourceDao) Stats(groupBy []string, _ []util.Filter) ([]m.StatsGroup, error) {
+	statuses := make([]string, len(mockSourceDao.Sources))
+	for i, src := range mockSourceDao.Sources {
+		statuses[i] = src.AvailabilityStatus
+	}
+
+	return mockStats(groupBy, statuses)
+}
+
 func (mockSourceDao *MockSourceDao) ListInternal(_, _ int, _ []util.Filter, _ bool) ([]m.Source, int64, error) {
 	count := int64(len(mockSourceDao.Sources))
 	return mockSourceDao.Sources, count, nil
@@ -95,6 +117,10 @@ func (mockSourceDao *MockSourceDao) Update(_ *m.Source) error {
 	return nil
 }
 
+func (mockSourceDao *MockSourceDao) UpdateIfUnmodified(src *m.Source, _ time.Time) error {
+	return mockSourceDao.Update(src)
+}
+
 func (mockSourceDao *MockSourceDao) Delete(id *int64) (*m.Source, error) {
 	for i, source := range mockSourceDao.Sources {
 		if source.ID == *id {
@@ -137,9 +163,14 @@ func (mockSourceDao *MockSourceDao) IsSuperkey(_ int64) bool {
 	return mockSourceDao.SuperKeyEnabled
 }
 
-func (mockSourceDao *MockSourceDao) GetByIdWithPreload(id *int64, _ ...string) (*m.Source, error) {
+// GetByIdWithPreload returns the source with the requested relations populated from the fixtures.
+func (mockSourceDao *MockSourceDao) GetByIdWithPreload(id *int64, preloads ...string) (*m.Source, error) {
 	for _, i := range mockSourceDao.Sources {
 		if i.ID == *id {
+			for _, preload := range preloads {
+				preloadSourceFixtures(&i, preload)
+			}
+
 			return &i, nil
 		}
 	}
@@ -147,6 +178,22 @@ func (mockSourceDao *MockSourceDao) GetByIdWithPreload(id *int64, _ ...string) (
 	return nil, util.NewErrNotFound("source")
 }
 
+// ListByIdsWithPreload returns the given sources with the requested relations populated from the fixtures.
+func (mockSourceDao *MockSourceDao) ListByIdsWithPreload(ids []int64, preloads ...string) ([]m.Source, error) {
+	out := make([]m.Source, 0, len(ids))
+
+	for _, id := range ids {
+		src, err := mockSourceDao.GetByIdWithPreload(&id, preloads...)
+		if err != nil {
+			continue
+		}
+
+		out = append(out, *src)
+	}
+
+	return out, nil
+}
+
 func (mockSourceDao *MockSourceDao) ListForRhcConnection(_ *int64, _, _ int, _ []util.Filter) ([]m.Source, int64, error) {
 	count := int64(len(mockSourceDao.RelatedSources))
 
@@ -169,6 +216,10 @@ func (mockSourceDao *MockSourceDao) DeleteCascade(id int64) ([]m.ApplicationAuth
 	return fixtures.TestApplicationAuthenticationData, fixtures.TestApplicationData, fixtures.TestEndpointData, fixtures.TestRhcConnectionData, source, nil
 }
 
+func (mockSourceDao *MockSourceDao) DeleteCascadeIfUnmodified(id int64, _ time.Time) ([]m.ApplicationAuthentication, []m.Application, []m.Endpoint, []m.RhcConnection, *m.Source, error) {
+	return mockSourceDao.DeleteCascade(id)
+}
+
 func (mockSourceDao *MockSourceDao) BulkMessage(_ util.Resource) (map[string]interface{}, error) {
 	return nil, nil
 }
@@ -188,3 +239,93 @@ func (mockSourceDao *MockSourceDao) Pause(_ int64) error {
 func (mockSourceDao *MockSourceDao) Unpause(_ int64) error {
 	return nil
 }
+
+// SoftDelete returns a copy of the source marked as deleted, leaving the fixtures untouched.
+func (mockSourceDao *MockSourceDao) SoftDelete(id int64) (*m.Source, error) {
+	for _, source := range mockSourceDao.Sources {
+		if source.ID == id {
+			now := time.Now()
+			source.DeletedAt = &now
+
+			return &source, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("source")
+}
+
+func (mockSourceDao *MockSourceDao) SoftDeleteIfUnmodified(id int64, _ time.Time) (*m.Source, error) {
+	return mockSourceDao.SoftDelete(id)
+}
+
+// Restore returns a copy of the given soft deleted source without the deletion mark, unless one of the live sources
+// took its name.
+func (mockSourceDao *MockSourceDao) Restore(id int64) (*m.Source, error) {
+	for _, source := range mockSourceDao.DeletedSources {
+		if source.ID == id {
+			for _, live := range mockSourceDao.Sources {
+				if live.Name == source.Name && live.ID != source.ID {
+					return nil, util.NewErrConflict(fmt.Sprintf(`a source with the name "%s" already exists`, source.Name))
+				}
+			}
+
+			source.DeletedAt = nil
+
+			return &source, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("deleted source")
+}
+
+// preloadSourceFixtures populates the given source's relation with the related fixtures.
+func preloadSourceFixtures(src *m.Source, preload string) {
+	switch preload {
+	case "Applications":
+		for _, app := range fixtures.TestApplicationData {
+			if app.SourceID == src.ID {
+				src.Applications = append(src.Applications, app)
+			}
+		}
+	case "Applications.ApplicationType":
+		for _, app := range fixtures.TestApplicationData {
+			if app.SourceID != src.ID {
+				continue
+			}
+
+			for _, appType := range fixtures.TestApplicationTypeData {
+				if appType.Id == app.ApplicationTypeID {
+					app.ApplicationType = appType
+				}
+			}
+
+			src.Applications = append(src.Applications, app)
+		}
+	case "Endpoints":
+		for _, endpoint := range fixtures.TestEndpointData {
+			if endpoint.SourceID == src.ID {
+				src.Endpoints = append(src.Endpoints, endpoint)
+			}
+		}
+	case "SourceType":
+		for _, sourceType := range fixtures.TestSourceTypeData {
+			if sourceType.Id == src.SourceTypeID {
+				src.SourceType = sourceType
+			}
+		}
+	case "SourceRhcConnections.RhcConnection":
+		for _, sourceRhcConnection := range fixtures.TestSourceRhcConnectionData {
+			if sourceRhcConnection.SourceId != src.ID {
+				continue
+			}
+
+			for _, rhcConnection := range fixtures.TestRhcConnectionData {
+				if rhcConnection.ID == sourceRhcConnection.RhcConnectionId {
+					sourceRhcConnection.RhcConnection = rhcConnection
+				}
+			}
+
+			src.SourceRhcConnections = append(src.SourceRhcConnections, sourceRhcConnection)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package mocks
+
+import (
+	"slices"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+type MockWebhookDao struct {
+	Webhooks   []m.Webhook
+	Deliveries []m.WebhookDelivery
+}
+
+func (mockWebhookDao *MockWebhookDao) List(_, _ int, _ []util.Filter) ([]m.Webhook, int64, error) {
+	count := int64(len(mockWebhookDao.Webhooks))
+	return mockWebhookDao.Webhooks, count, nil
+}
+
+func (mockWebhookDao *MockWebhookDao) GetById(id *int64) (*m.Webhook, error) {
+	for _, webhook := range mockWebhookDao.Webhooks {
+		if webhook.ID == *id {
+			return &webhook, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("webhook")
+}
+
+func (mockWebhookDao *MockWebhookDao) Create(_ *m.Webhook) error {
+	return nil
+}
+
+func (mockWebhookDao *MockWebhookDao) Update(webhook *m.Webhook) error {
+	for _, w := range mockWebhookDao.Webhooks {
+		if w.ID == webhook.ID {
+			return nil
+		}
+	}
+
+	return util.NewErrNotFound("webhook")
+}
+
+func (mockWebhookDao *MockWebhookDao) Delete(id *int64) (*m.Webhook, error) {
+	for _, webhook := range mockWebhookDao.Webhooks {
+		if webhook.ID == *id {
+			return &webhook, nil
+		}
+	}
+
+	return nil, util.NewErrNotFound("webhook")
+}
+
+func (mockWebhookDao *MockWebhookDao) ListForEventType(eventType string) ([]m.Webhook, error) {
+	webhooks := make([]m.Webhook, 0)
+
+	for _, webhook := range mockWebhookDao.Webhooks {
+		if webhook.Enabled && slices.Contains(webhook.EventTypes, eventType) {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+
+	return webhooks, nil
+}
+
+func (mockWebhookDao *MockWebhookDao) CreateDeliveries(_ []m.WebhookDelivery) error {
+	return nil
+}
+
+func (mockWebhookDao *MockWebhookDao) ListDeliveries(webhookID int64, _, _ int, _ []util.Filter) ([]m.WebhookDelivery, int64, error) {
+	deliveries := make([]m.WebhookDelivery, 0)
+
+	for _, delivery := range mockWebhookDao.Deliveries {
+		if delivery.WebhookID == webhookID {
+			deliveries = append(deliveries, delivery)
+		}
+	}
+
+	return deliveries, int64(len(deliveries)), nil
+}
@@ -0,0 +1,77 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MockEventBroadcaster broadcasts the published messages to its subscribers in memory.
+type MockEventBroadcaster struct {
+	mutex       sync.Mutex
+	subscribers map[string][]func(message []byte)
+	// Published holds every published message by channel.
+	Published map[string][][]byte
+}
+
+func (mockEventBroadcaster *MockEventBroadcaster) Publish(_ context.Context, channel string, message []byte) error {
+	mockEventBroadcaster.mutex.Lock()
+	defer mockEventBroadcaster.mutex.Unlock()
+
+	if mockEventBroadcaster.Published == nil {
+		mockEventBroadcaster.Published = make(map[string][][]byte)
+	}
+
+	mockEventBroadcaster.Published[channel] = append(mockEventBroadcaster.Published[channel], message)
+
+	for _, fn := range mockEventBroadcaster.subscribers[channel] {
+		fn(message)
+	}
+
+	return nil
+}
+
+func (mockEventBroadcaster *MockEventBroadcaster) Subscribe(ctx context.Context, channels []string, fn func(message []byte)) error {
+	mockEventBroadcaster.mutex.Lock()
+
+	if mockEventBroadcaster.subscribers == nil {
+		mockEventBroadcaster.subscribers = make(map[string][]func(message []byte))
+	}
+
+	for _, channel := range channels {
+		mockEventBroadcaster.subscribers[channel] = append(mockEventBroadcaster.subscribers[channel], fn)
+	}
+
+	mockEventBroadcaster.mutex.Unlock()
+
+	<-ctx.Done()
+
+	mockEventBroadcaster.mutex.Lock()
+	defer mockEventBroadcaster.mutex.Unlock()
+
+	// the tests only have a subscriber per channel, so the whole channel goes away along with it.
+	for _, channel := range channels {
+		delete(mockEventBroadcaster.subscribers, channel)
+	}
+
+	return ctx.Err()
+}
+
+// WaitForSubscribers waits until the channel has a subscriber, or until the timeout expires.
+func (mockEventBroadcaster *MockEventBroadcaster) WaitForSubscribers(channel string, timeout time.Duration) bool {
+	deadline := time.Now().Add(timeout)
+
+	for time.Now().Before(deadline) {
+		mockEventBroadcaster.mutex.Lock()
+		subscribed := len(mockEventBroadcaster.subscribers[channel]) > 0
+		mockEventBroadcaster.mutex.Unlock()
+
+		if subscribed {
+			return true
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return false
+}
@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MockPersistedQueryStore keeps the persisted queries in memory.
+type MockPersistedQueryStore struct {
+	mutex sync.Mutex
+	// Queries holds every stored query by key.
+	Queries map[string]string
+}
+
+func (mockPersistedQueryStore *MockPersistedQueryStore) Get(_ context.Context, key string) (string, error) {
+	mockPersistedQueryStore.mutex.Lock()
+	defer mockPersistedQueryStore.mutex.Unlock()
+
+	return mockPersistedQueryStore.Queries[key], nil
+}
+
+func (mockPersistedQueryStore *MockPersistedQueryStore) Set(_ context.Context, key string, query string, _ time.Duration) error {
+	mockPersistedQueryStore.mutex.Lock()
+	defer mockPersistedQueryStore.mutex.Unlock()
+
+	if mockPersistedQueryStore.Queries == nil {
+		mockPersistedQueryStore.Queries = make(map[string]string)
+	}
+
+	mockPersistedQueryStore.Queries[key] = query
+
+	return nil
+}
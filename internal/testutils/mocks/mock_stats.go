@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"fmt"
+	"sort"
+
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// mockStats counts the given availability statuses, which is the only dimension the mocked DAOs can group by.
+func mockStats(groupBy []string, statuses []string) ([]m.StatsGroup, error) {
+	if len(groupBy) == 0 {
+		return []m.StatsGroup{{Dimensions: map[string]*string{}, Count: int64(len(statuses))}}, nil
+	}
+
+	if len(groupBy) != 1 || groupBy[0] != "availability_status" {
+		return nil, util.NewErrBadRequest(fmt.Sprintf("invalid group_by dimensions %v", groupBy))
+	}
+
+	counts := make(map[string]int64)
+	for _, status := range statuses {
+		counts[status]++
+	}
+
+	groups := make([]m.StatsGroup, 0, len(counts))
+	for status, count := range counts {
+		groups = append(groups, m.StatsGroup{Dimensions: map[string]*string{"availability_status": util.StringRef(status)}, Count: count})
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return *groups[i].Dimensions["availability_status"] < *groups[j].Dimensions["availability_status"]
+	})
+
+	return groups, nil
+}
@@ -38,5 +38,5 @@ func EmptySubcollectionListTest(t *testing.T, c echo.Context, rec *httptest.Resp
 		t.Errorf("expected 0 objects passed back from DB, got %d", len(out.Data))
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
@@ -6,6 +6,7 @@ import (
 	"strings"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/graph"
 	"github.com/RedHatInsights/sources-api-go/middleware/headers"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
@@ -122,3 +123,27 @@ func InternalSecretGet(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, secret.ToInternalSecretResponse())
 }
+
+// InternalGraphQLPersistedQueryCreate registers a GraphQL query, so that it is allowed when only the registered queries
+// are, and returns the hash the clients run it by. Internal use only.
+func InternalGraphQLPersistedQueryCreate(c echo.Context) error {
+	var input struct {
+		Query string `json:"query"`
+	}
+
+	err := c.Bind(&input)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	if input.Query == "" {
+		return util.NewErrBadRequest(`the "query" is required`)
+	}
+
+	hash, err := graph.RegisterPersistedQuery(c.Request().Context(), input.Query)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{"sha256_hash": hash})
+}
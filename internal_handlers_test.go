@@ -1,14 +1,19 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"strings"
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/graph"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
@@ -103,7 +108,7 @@ func TestSourceListInternal(t *testing.T) {
 		}
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestSourceListInternalSkipEmptySources tests that when the "skip empty sources" header is sent, the empty sources
@@ -343,3 +348,56 @@ func TestInternalSecretGet(t *testing.T) {
 		cleanSecretByID(t, secretID, &dao.RequestParams{TenantID: &tenantIDForSecret, UserID: userID})
 	}
 }
+
+// TestInternalGraphQLPersistedQueryCreate tests that the registered query's hash is returned, and that the queries
+// which are not valid against the schema are rejected.
+func TestInternalGraphQLPersistedQueryCreate(t *testing.T) {
+	backup := graph.PersistedQueries
+	defer func() { graph.PersistedQueries = backup }()
+
+	graph.PersistedQueries = &mocks.MockPersistedQueryStore{}
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/internal/v2.0/graphql/persisted_queries",
+		strings.NewReader(`{"query": "{ sources { id } }"}`),
+		map[string]interface{}{},
+	)
+	c.Request().Header.Set("Content-Type", "application/json")
+
+	err := InternalGraphQLPersistedQueryCreate(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("want status %d, got %d", http.StatusCreated, rec.Code)
+	}
+
+	var body map[string]string
+
+	err = json.Unmarshal(rec.Body.Bytes(), &body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256([]byte("{ sources { id } }"))
+	if body["sha256_hash"] != hex.EncodeToString(sum[:]) {
+		t.Errorf("want the query's hash, got %v", body)
+	}
+
+	for _, payload := range []string{`{"query": ""}`, `{"query": "{ unknown_field }"}`} {
+		c, _ = request.CreateTestContext(
+			http.MethodPost,
+			"/api/internal/v2.0/graphql/persisted_queries",
+			strings.NewReader(payload),
+			map[string]interface{}{},
+		)
+		c.Request().Header.Set("Content-Type", "application/json")
+
+		err = InternalGraphQLPersistedQueryCreate(c)
+		if !errors.As(err, &util.ErrBadRequest{}) {
+			t.Errorf("want a bad request error for %s, got %v", payload, err)
+		}
+	}
+}
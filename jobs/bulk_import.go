@@ -0,0 +1,202 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/redis"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// bulkImportProgressInterval is the minimum time between two progress updates of a running bulk import, so that we
+// do not hammer valkey with big documents on imports with thousands of sources.
+const bulkImportProgressInterval = time.Second
+
+// BulkImportKeyStore stores the progress of the bulk imports.
+type BulkImportKeyStore interface {
+	// Get returns the stored value for the given key, or nil if the key does not exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Set stores the value, overwriting the previous one.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// BulkImportStore is the store for the bulk imports' progress. It is a variable so that it can be replaced in the
+// tests.
+var BulkImportStore BulkImportKeyStore = valkeyBulkImportKeyStore{}
+
+// valkeyBulkImportKeyStore is the default store, backed by Valkey.
+type valkeyBulkImportKeyStore struct{}
+
+func (valkeyBulkImportKeyStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := redis.Client.Do(ctx, redis.Client.B().Get().Key(key).Build()).AsBytes()
+	if redis.IsNil(err) {
+		return nil, nil
+	}
+
+	return value, err
+}
+
+func (valkeyBulkImportKeyStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return redis.Client.Do(ctx, redis.Client.B().Set().Key(key).Value(string(value)).Px(ttl).Build()).Error()
+}
+
+// bulkImportKey returns the store key of the given bulk import. The keys are scoped to the tenant, so that different
+// tenants cannot see each other's imports.
+func bulkImportKey(tenantID int64, id string) string {
+	return fmt.Sprintf("sources_api:bulk_imports:%d:%s", tenantID, id)
+}
+
+// SaveBulkImport stores the progress of the given bulk import.
+func SaveBulkImport(ctx context.Context, tenantID int64, bulkImport *m.BulkImportResponse) error {
+	bulkImport.UpdatedAt = time.Now()
+
+	value, err := json.Marshal(bulkImport)
+	if err != nil {
+		return err
+	}
+
+	return BulkImportStore.Set(ctx, bulkImportKey(tenantID, bulkImport.ID), value, time.Duration(config.Get().BulkImportTTL)*time.Second)
+}
+
+// GetBulkImport returns the progress of the given bulk import, or a not found error if the import does not exist or
+// has expired.
+func GetBulkImport(ctx context.Context, tenantID int64, id string) (*m.BulkImportResponse, error) {
+	value, err := BulkImportStore.Get(ctx, bulkImportKey(tenantID, id))
+	if err != nil {
+		return nil, err
+	}
+
+	if value == nil {
+		return nil, util.NewErrNotFound("bulk import")
+	}
+
+	var bulkImport m.BulkImportResponse
+
+	err = json.Unmarshal(value, &bulkImport)
+	if err != nil {
+		return nil, err
+	}
+
+	return &bulkImport, nil
+}
+
+// BulkImportJob creates the resources of a bulk import document in the background, in the partial success mode of
+// the bulk create, and keeps track of its progress. The request's passwords are expected to be sealed with
+// "service.SealBulkCreatePasswords", so that they do not sit in plaintext in the jobs queue.
+type BulkImportJob struct {
+	ID       string              `json:"id"`
+	Headers  []kafka.Header      `json:"headers"`
+	Identity string              `json:"identity"`
+	Tenant   m.Tenant            `json:"tenant"`
+	User     m.User              `json:"user"`
+	Request  m.BulkCreateRequest `json:"request"`
+}
+
+func (bi BulkImportJob) Delay() time.Duration {
+	// run this job immediately, no delay.
+	return 0
+}
+
+func (bi BulkImportJob) Arguments() map[string]interface{} {
+	return map[string]interface{}{
+		"id":                    bi.ID,
+		"tenant":                bi.Tenant.Id,
+		"sources_count":         len(bi.Request.Sources),
+		"applications_count":    len(bi.Request.Applications),
+		"endpoints_count":       len(bi.Request.Endpoints),
+		"authentications_count": len(bi.Request.Authentications),
+		"rhc_connections_count": len(bi.Request.RhcConnections),
+	}
+}
+
+func (bi BulkImportJob) Name() string {
+	return "BulkImportJob"
+}
+
+func (bi BulkImportJob) Run() (err error) {
+	ctx := context.Background()
+
+	bulkImport, err := GetBulkImport(ctx, bi.Tenant.Id, bi.ID)
+	if err != nil {
+		return err
+	}
+
+	// a panic would take the whole worker down and leave the import running forever.
+	defer func() {
+		if r := recover(); r != nil {
+			bulkImport.Status = m.BulkImportFailed
+			bulkImport.Error = "unexpected error while importing the resources"
+
+			err = fmt.Errorf("bulk import panicked: %v", r)
+
+			saveErr := SaveBulkImport(ctx, bi.Tenant.Id, bulkImport)
+			if saveErr != nil {
+				l.Log.Warnf(`Unable to mark the bulk import "%s" as failed: %s`, bi.ID, saveErr)
+			}
+		}
+	}()
+
+	bulkImport.Status = m.BulkImportRunning
+
+	err = SaveBulkImport(ctx, bi.Tenant.Id, bulkImport)
+	if err != nil {
+		return err
+	}
+
+	err = service.UnsealBulkCreatePasswords(&bi.Request)
+	if err != nil {
+		bulkImport.Status = m.BulkImportFailed
+		bulkImport.Error = "unable to read the passwords of the authentications"
+
+		saveErr := SaveBulkImport(ctx, bi.Tenant.Id, bulkImport)
+		if saveErr != nil {
+			l.Log.Warnf(`Unable to mark the bulk import "%s" as failed: %s`, bi.ID, saveErr)
+		}
+
+		return err
+	}
+
+	var lastSave time.Time
+
+	output := service.BulkAssemblyPartial(bi.Request, &bi.Tenant, &bi.User, false, func(processed int, total int, results *m.BulkCreatePartialResponse) {
+		bulkImport.Processed = processed
+		bulkImport.Total = total
+		bulkImport.Results = results
+
+		if time.Since(lastSave) < bulkImportProgressInterval {
+			return
+		}
+
+		lastSave = time.Now()
+
+		err := SaveBulkImport(ctx, bi.Tenant.Id, bulkImport)
+		if err != nil {
+			l.Log.Warnf(`Unable to update the progress of the bulk import "%s": %s`, bi.ID, err)
+		}
+	})
+
+	for i := range output.Created {
+		service.SendBulkMessages(&output.Created[i], bi.Headers, bi.Identity, superKeySvc)
+	}
+
+	bulkImport.Status = m.BulkImportCompleted
+	bulkImport.Results = &output.Results
+
+	return SaveBulkImport(ctx, bi.Tenant.Id, bulkImport)
+}
+
+func (bi BulkImportJob) ToJSON() []byte {
+	bytes, err := json.Marshal(&bi)
+	if err != nil {
+		panic(err)
+	}
+
+	return bytes
+}
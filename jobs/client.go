@@ -32,6 +32,15 @@ func (jr *JobRequest) Parse() error {
 		}
 
 		jr.Job = &sdj
+	case "BulkImportJob":
+		bij := BulkImportJob{}
+
+		err := json.Unmarshal(jr.JobRaw, &bij)
+		if err != nil {
+			return err
+		}
+
+		jr.Job = &bij
 	case "AsyncDestroyJob":
 		adj := AsyncDestroyJob{}
 
@@ -0,0 +1,91 @@
+package jobs
+
+import (
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/dao"
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// purgeRetryInterval is how long the purge job waits for the destroy job of an expired superkey source to delete it,
+// before enqueuing it again.
+const purgeRetryInterval = time.Hour
+
+type PurgeDeletedSourcesJob struct{}
+
+// implementing the interface - but these functions aren't really needed since
+// this is a scheduled job.
+func (p *PurgeDeletedSourcesJob) Delay() time.Duration              { return 0 }
+func (p *PurgeDeletedSourcesJob) Arguments() map[string]interface{} { return map[string]interface{}{} }
+func (p *PurgeDeletedSourcesJob) Name() string                      { return "PurgeDeletedSourcesJob" }
+func (p *PurgeDeletedSourcesJob) ToJSON() []byte                    { panic("not implemented") }
+
+// Run deletes for good the soft deleted sources whose retention window has expired, raising the destroy events for
+// them and their sub resources.
+func (p *PurgeDeletedSourcesJob) Run() error {
+	retention := time.Duration(config.Get().SourceRetention) * time.Second
+	now := time.Now()
+
+	sources := make([]m.Source, 0)
+
+	// the superkey sources whose destroy job is still pending are skipped, unless the job seems to have been lost.
+	err := dao.DB.
+		Debug().
+		Model(&m.Source{}).
+		Preload("Tenant").
+		Where("deleted_at < ?", now.Add(-retention)).
+		Where("purge_enqueued_at IS NULL OR purge_enqueued_at < ?", now.Add(-purgeRetryInterval)).
+		Find(&sources).
+		Error
+	if err != nil {
+		l.Log.Errorf("Error listing the expired soft deleted sources: %s", err)
+		return err
+	}
+
+	if len(sources) == 0 {
+		l.Log.Debug("No expired soft deleted sources found - returning.")
+		return nil
+	}
+
+	l.Log.Infof("Found %v expired soft deleted sources to purge", len(sources))
+
+	for _, source := range sources {
+		// generate the forwardable headers from what we have in the tenant table
+		headers := source.Tenant.GetHeadersWithGeneratedXRHID()
+
+		// Superkey sources need their cloud resources cleaned up first, and the superkey job deletes them afterwards.
+		if source.AppCreationWorkflow == m.AccountAuth {
+			err := dao.DB.
+				Debug().
+				Model(&m.Source{}).
+				Where("id = ?", source.ID).
+				UpdateColumn("purge_enqueued_at", now).
+				Error
+			if err != nil {
+				l.Log.Warnf("Error marking the soft deleted source %v as being purged: %v", source.ID, err)
+				continue
+			}
+
+			Enqueue(&SuperkeyDestroyJob{
+				Headers:  headers,
+				Tenant:   source.TenantID,
+				Identity: util.GeneratedXRhIdentity(source.Tenant.ExternalTenant, source.Tenant.OrgID),
+				Model:    "source",
+				Id:       source.ID,
+			})
+
+			continue
+		}
+
+		err := service.DeleteCascade(&source.TenantID, nil, "Source", source.ID, headers)
+		if err != nil {
+			l.Log.Warnf("Error purging the soft deleted source %v: %v", source.ID, err)
+		}
+	}
+
+	return nil
+}
@@ -39,6 +39,12 @@ var schedule = []ScheduledJob{
 	// scheduled job that runs every 2 minutes and re-sends any unavailable
 	// sources that haven't ever went available
 	{Interval: 2 * time.Minute, Job: &RetryCreateJob{}},
+	// scheduled job that runs every 10 minutes and deletes for good the soft
+	// deleted sources whose retention window has expired
+	{Interval: 10 * time.Minute, Job: &PurgeDeletedSourcesJob{}},
+	// scheduled job that runs every 30 seconds and sends the webhook
+	// deliveries which are due, retrying the failed ones with a backoff
+	{Interval: 30 * time.Second, Job: &WebhookDeliveryJob{}},
 }
 
 // runScheduledJobs runs all of the jobs on a schedule forever.
@@ -3,6 +3,7 @@ package jobs
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
@@ -56,9 +57,10 @@ func (sk SuperkeyDestroyJob) Run() error {
 func (sk SuperkeyDestroyJob) sendForSource(id int64) error {
 	l.Log.Infof("Sending SuperKey Delete request for source %v", sk.Id)
 
-	a := dao.GetApplicationDao(&dao.RequestParams{TenantID: &sk.Tenant})
+	// the source is soft deleted when its retention window expires, so its applications need to be reachable still.
+	a := dao.GetApplicationDao(&dao.RequestParams{TenantID: &sk.Tenant, IncludeSoftDeleted: true})
 
-	apps, _, err := a.SubCollectionList(m.Source{ID: id}, 100, 0, make([]util.Filter, 0))
+	apps, _, err := a.List(100, 0, []util.Filter{{Name: "source_id", Value: []string{strconv.FormatInt(id, 10)}}})
 	if err != nil {
 		return fmt.Errorf("failed to list applications for source: %v", err)
 	}
@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	l "github.com/RedHatInsights/sources-api-go/logger"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// webhookDeliveryBatchSize is the maximum number of deliveries sent on every run.
+	webhookDeliveryBatchSize = 100
+	// webhookDeliveryConcurrency is the number of deliveries sent at the same time.
+	webhookDeliveryConcurrency = 10
+	// webhookDeliveryLease is how long the claimed deliveries are hidden from the other workers while they are being
+	// sent. It must outlast sending a whole batch.
+	webhookDeliveryLease = 5 * time.Minute
+)
+
+type WebhookDeliveryJob struct{}
+
+// implementing the interface - but these functions aren't really needed since
+// this is a scheduled job.
+func (w *WebhookDeliveryJob) Delay() time.Duration              { return 0 }
+func (w *WebhookDeliveryJob) Arguments() map[string]interface{} { return map[string]interface{}{} }
+func (w *WebhookDeliveryJob) Name() string                      { return "WebhookDeliveryJob" }
+func (w *WebhookDeliveryJob) ToJSON() []byte                    { panic("not implemented") }
+
+// Run sends the pending webhook deliveries which are due, and records their outcome.
+func (w *WebhookDeliveryJob) Run() error {
+	now := time.Now()
+
+	// Claim the due deliveries by pushing their next attempt past the lease, so that the workers running this job at
+	// the same time skip them.
+	due := dao.DB.
+		Model(&m.WebhookDelivery{}).
+		Select("id").
+		Where("status = ?", m.WebhookDeliveryPending).
+		Where("next_attempt_at <= ?", now).
+		Order("next_attempt_at ASC").
+		Limit(webhookDeliveryBatchSize).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	deliveries := make([]m.WebhookDelivery, 0)
+
+	err := dao.DB.
+		Debug().
+		Model(&deliveries).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", due).
+		Update("next_attempt_at", now.Add(webhookDeliveryLease)).
+		Error
+	if err != nil {
+		l.Log.Errorf("Error claiming the due webhook deliveries: %s", err)
+		return err
+	}
+
+	if len(deliveries) == 0 {
+		l.Log.Debug("No due webhook deliveries found - returning.")
+		return nil
+	}
+
+	l.Log.Infof("Found %v due webhook deliveries", len(deliveries))
+
+	webhookIDs := make([]int64, len(deliveries))
+	for i := range deliveries {
+		webhookIDs[i] = deliveries[i].WebhookID
+	}
+
+	webhooks := make([]m.Webhook, 0)
+
+	err = dao.DB.
+		Debug().
+		Where("id IN ?", webhookIDs).
+		Find(&webhooks).
+		Error
+	if err != nil {
+		l.Log.Errorf("Error fetching the webhooks of the due deliveries: %s", err)
+		return err
+	}
+
+	webhooksByID := make(map[int64]m.Webhook, len(webhooks))
+	for _, webhook := range webhooks {
+		webhooksByID[webhook.ID] = webhook
+	}
+
+	var wg sync.WaitGroup
+
+	semaphore := make(chan struct{}, webhookDeliveryConcurrency)
+
+	for i := range deliveries {
+		webhook, ok := webhooksByID[deliveries[i].WebhookID]
+		if !ok {
+			// the webhook was deleted along with its deliveries in the meantime.
+			continue
+		}
+
+		deliveries[i].Webhook = webhook
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+
+		go func(delivery *m.WebhookDelivery) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			service.DeliverWebhook(delivery)
+
+			err := dao.DB.
+				Debug().
+				Model(&m.WebhookDelivery{ID: delivery.ID}).
+				Updates(map[string]interface{}{
+					"status":          delivery.Status,
+					"attempts":        delivery.Attempts,
+					"next_attempt_at": delivery.NextAttemptAt,
+					"last_attempt_at": delivery.LastAttemptAt,
+					"response_status": delivery.ResponseStatus,
+					"error":           delivery.Error,
+					"updated_at":      time.Now(),
+				}).
+				Error
+			if err != nil {
+				l.Log.Warnf("Error recording the outcome of the webhook delivery %v: %v", delivery.ID, err)
+			}
+		}(&deliveries[i])
+	}
+
+	wg.Wait()
+
+	return nil
+}
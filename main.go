@@ -10,6 +10,7 @@ import (
 
 	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/internal/events"
 	"github.com/RedHatInsights/sources-api-go/jobs"
 	"github.com/RedHatInsights/sources-api-go/kafka"
 	l "github.com/RedHatInsights/sources-api-go/logger"
@@ -50,6 +51,16 @@ func main() {
 	l.Log.Info("Initializing database connection and running migrations...")
 	dao.Init()
 
+	// Queue the deliveries for the tenants' webhooks and broadcast the events to the tenants' event streams along
+	// with every raised event.
+	service.Producer = func() events.Sender {
+		return events.EventStreamProducer{
+			Sender: &service.BroadcastSender{
+				Sender: &service.WebhookSender{Sender: &events.EventStreamSender{}},
+			},
+		}
+	}
+
 	// Initialize the shared superkey Kafka producer and inject it into the
 	// SuperKeyService. Using a long-lived writer ensures the internal round-robin
 	// partitioner distributes messages across all partitions instead of always
@@ -150,6 +161,8 @@ func runServer(shutdown chan struct{}, metricsService metrics.MetricsService, sk
 	getEndpointDao = getEndpointDaoWithTenant
 	getMetaDataDao = getMetaDataDaoWithoutTenant
 	getRhcConnectionDao = getDefaultRhcConnectionDao
+	getAuditEventDao = getAuditEventDaoWithTenant
+	getWebhookDao = getWebhookDaoWithTenant
 
 	// hiding the ascii art to make the logs more json-like
 	e.HideBanner = true
@@ -27,6 +27,8 @@ var (
 	mockRhcConnectionDao             dao.RhcConnectionDao
 	mockApplicationAuthenticationDao dao.ApplicationAuthenticationDao
 	mockAuthenticationDao            dao.AuthenticationDao
+	mockAuditEventDao                dao.AuditEventDao
+	mockWebhookDao                   dao.WebhookDao
 )
 
 func TestMain(t *testing.M) {
@@ -47,6 +49,8 @@ func TestMain(t *testing.M) {
 		getSourceTypeDao = getSourceTypeDaoWithoutTenant
 		getMetaDataDao = getMetaDataDaoWithoutTenant
 		getRhcConnectionDao = getDefaultRhcConnectionDao
+		getAuditEventDao = getAuditEventDaoWithTenant
+		getWebhookDao = getWebhookDaoWithTenant
 		getApplicationAuthenticationDao = getApplicationAuthenticationDaoWithTenant
 		getAuthenticationDao = getAuthenticationDaoWithTenant
 
@@ -70,6 +74,8 @@ func TestMain(t *testing.M) {
 		mockRhcConnectionDao = &mocks.MockRhcConnectionDao{RhcConnections: fixtures.TestRhcConnectionData, RelatedRhcConnections: fixtures.TestRhcConnectionData}
 		mockApplicationAuthenticationDao = &mocks.MockApplicationAuthenticationDao{ApplicationAuthentications: fixtures.TestApplicationAuthenticationData}
 		mockAuthenticationDao = &mocks.MockAuthenticationDao{Authentications: fixtures.TestAuthenticationData}
+		mockAuditEventDao = &mocks.MockAuditEventDao{AuditEvents: fixtures.TestAuditEventData}
+		mockWebhookDao = &mocks.MockWebhookDao{Webhooks: fixtures.TestWebhookData, Deliveries: fixtures.TestWebhookDeliveryData}
 
 		getSourceDao = func(c echo.Context) (dao.SourceDao, error) { return mockSourceDao, nil }
 		getApplicationDao = func(c echo.Context) (dao.ApplicationDao, error) { return mockApplicationDao, nil }
@@ -82,6 +88,8 @@ func TestMain(t *testing.M) {
 			return mockApplicationAuthenticationDao, nil
 		}
 		getAuthenticationDao = func(c echo.Context) (dao.AuthenticationDao, error) { return mockAuthenticationDao, nil }
+		getAuditEventDao = func(c echo.Context) (dao.AuditEventDao, error) { return mockAuditEventDao, nil }
+		getWebhookDao = func(c echo.Context) (dao.WebhookDao, error) { return mockWebhookDao, nil }
 
 		err := dao.PopulateMockStaticTypeCache()
 		if err != nil {
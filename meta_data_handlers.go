@@ -48,7 +48,7 @@ func MetaDataList(c echo.Context) error {
 		out[i] = metaDatas[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(metaDatas)...))
 }
 
 func ApplicationTypeListMetaData(c echo.Context) error {
@@ -87,7 +87,7 @@ func ApplicationTypeListMetaData(c echo.Context) error {
 		out[i] = metaDatas[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(metaDatas)...))
 }
 
 func MetaDataGet(c echo.Context) error {
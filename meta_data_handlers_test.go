@@ -76,7 +76,7 @@ func TestApplicationTypeMetaDataSubcollectionList(t *testing.T) {
 		t.Error("ghosts infected the return")
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 func TestApplicationTypeMetaDataSubcollectionListNotFound(t *testing.T) {
@@ -208,7 +208,7 @@ func TestMetaDataList(t *testing.T) {
 		}
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 func TestMetaDataListBadRequestInvalidFilter(t *testing.T) {
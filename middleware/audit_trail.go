@@ -0,0 +1,89 @@
+package middleware
+
+import (
+	"github.com/RedHatInsights/sources-api-go/dao"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/labstack/echo/v4"
+	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+// AuditTrail records who changed which resource once the handler has succeeded. It grabs the changed resource and the
+// event type from the same context values the "RaiseEvent" middleware uses, and the resource's previous state from the
+// "audit_previous" snapshot the edit handlers take before changing it. The handlers which destroy the resources
+// without raising an event themselves set the "audit_resource" and "audit_event_type" values instead.
+//
+// The change has already been committed at this point, so failing to record it does not fail the request.
+func AuditTrail(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		err := next(c)
+		if err != nil {
+			return err
+		}
+
+		resource, eventType, ok := auditedResource(c)
+		if !ok {
+			return nil
+		}
+
+		requestParams, err := dao.NewRequestParamsFromContext(c)
+		if err != nil {
+			c.Logger().Warnf("Unable to record the audit event %v: %v", eventType, err)
+			return nil
+		}
+
+		previous, _ := c.Get("audit_previous").([]byte)
+		actorType, actorID := AuditActor(c)
+
+		err = service.RecordAuditEvent(requestParams, eventType, resource, previous, actorType, actorID)
+		if err != nil {
+			c.Logger().Warnf("Unable to record the audit event %v: %v", eventType, err)
+		}
+
+		return nil
+	}
+}
+
+// auditedResource returns the resource the handler changed along with its event type.
+func auditedResource(c echo.Context) (model.Event, string, bool) {
+	resourceKey, eventTypeKey := "resource", "event_type"
+	if c.Get("audit_resource") != nil {
+		resourceKey, eventTypeKey = "audit_resource", "audit_event_type"
+	}
+
+	resource, ok := c.Get(resourceKey).(model.Event)
+	if !ok {
+		return nil, "", false
+	}
+
+	eventType, ok := c.Get(eventTypeKey).(string)
+	if !ok {
+		return nil, "", false
+	}
+
+	return resource, eventType, true
+}
+
+// AuditActor returns who made the request: a certificate's cluster, a PSK's caller or a user.
+func AuditActor(c echo.Context) (string, string) {
+	xRhIdentity, _ := c.Get(h.ParsedIdentity).(*identity.XRHID)
+
+	switch {
+	case xRhIdentity != nil && xRhIdentity.Identity.System != nil && (xRhIdentity.Identity.System.ClusterId != "" || xRhIdentity.Identity.System.CommonName != ""):
+		if xRhIdentity.Identity.System.ClusterId != "" {
+			return model.AuditActorCert, xRhIdentity.Identity.System.ClusterId
+		}
+
+		return model.AuditActorCert, xRhIdentity.Identity.System.CommonName
+	case c.Get(h.PSK) != nil:
+		// the key itself must never end up in the audit trail, only the user the caller acts on behalf of.
+		userID, _ := c.Get(h.PSKUserID).(string)
+
+		return model.AuditActorPSK, userID
+	case xRhIdentity != nil && xRhIdentity.Identity.User != nil && xRhIdentity.Identity.User.UserID != "":
+		return model.AuditActorUser, xRhIdentity.Identity.User.UserID
+	default:
+		return model.AuditActorUnknown, ""
+	}
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/labstack/echo/v4"
+	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+type fakeAuditedResource struct {
+	ID       int64  `json:"id"`
+	Name     string `json:"name"`
+	Password string `json:"password"`
+}
+
+func (f *fakeAuditedResource) ToEvent() interface{} {
+	return f
+}
+
+// setUpAuditEventDao replaces the audit event DAO with a mock for the duration of the test.
+func setUpAuditEventDao(t *testing.T) *mocks.MockAuditEventDao {
+	mockDao := &mocks.MockAuditEventDao{}
+
+	backup := dao.GetAuditEventDao
+	dao.GetAuditEventDao = func(*dao.RequestParams) dao.AuditEventDao { return mockDao }
+
+	t.Cleanup(func() { dao.GetAuditEventDao = backup })
+
+	return mockDao
+}
+
+// TestAuditTrail tests that an update gets recorded with the changed fields, the secrets masked and the actor.
+func TestAuditTrail(t *testing.T) {
+	mockDao := setUpAuditEventDao(t)
+
+	c, _ := request.CreateTestContext(http.MethodPatch, "/", nil, map[string]interface{}{
+		h.TenantID:       int64(1),
+		h.ParsedIdentity: &identity.XRHID{Identity: identity.Identity{User: &identity.User{UserID: "testUser"}}},
+	})
+
+	f := AuditTrail(func(c echo.Context) error {
+		resource := &fakeAuditedResource{ID: 10, Name: "before", Password: "a"}
+
+		snapshot, err := service.AuditSnapshot(resource)
+		if err != nil {
+			return err
+		}
+
+		c.Set("audit_previous", snapshot)
+
+		resource.Name = "after"
+		resource.Password = "b"
+
+		c.Set("event_type", "Thing.update")
+		c.Set("resource", resource)
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	err := f(c)
+	if err != nil {
+		t.Fatalf("Got an error when none would have been expected: %v", err)
+	}
+
+	if len(mockDao.AuditEvents) != 1 {
+		t.Fatalf("want 1 recorded audit event, got %d", len(mockDao.AuditEvents))
+	}
+
+	auditEvent := mockDao.AuditEvents[0]
+	if auditEvent.ResourceType != "Thing" || auditEvent.ResourceID != "10" || auditEvent.Action != m.AuditActionUpdate {
+		t.Errorf(`unexpected audited resource "%+v"`, auditEvent)
+	}
+
+	if auditEvent.ActorType != m.AuditActorUser || auditEvent.ActorID != "testUser" {
+		t.Errorf(`want the "testUser" user as the actor, got "%s" "%s"`, auditEvent.ActorType, auditEvent.ActorID)
+	}
+
+	var changes map[string]m.AuditChange
+
+	err = json.Unmarshal(auditEvent.Changes, &changes)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the changes: %s", err)
+	}
+
+	if changes["name"].From != "before" || changes["name"].To != "after" {
+		t.Errorf(`unexpected name change "%+v"`, changes["name"])
+	}
+
+	if changes["password"].From != "******" || changes["password"].To != "******" {
+		t.Errorf(`want the password change masked, got "%+v"`, changes["password"])
+	}
+}
+
+// TestAuditTrailDestroyed tests that the resources the handlers flag as destroyed get recorded with their last state.
+func TestAuditTrailDestroyed(t *testing.T) {
+	mockDao := setUpAuditEventDao(t)
+
+	c, _ := request.CreateTestContext(http.MethodDelete, "/", nil, map[string]interface{}{
+		h.TenantID: int64(1),
+	})
+
+	f := AuditTrail(func(c echo.Context) error {
+		c.Set("audit_event_type", "Thing.destroy")
+		c.Set("audit_resource", &fakeAuditedResource{ID: 10, Name: "gone"})
+
+		return c.NoContent(http.StatusNoContent)
+	})
+
+	err := f(c)
+	if err != nil {
+		t.Fatalf("Got an error when none would have been expected: %v", err)
+	}
+
+	if len(mockDao.AuditEvents) != 1 || mockDao.AuditEvents[0].Action != m.AuditActionDestroy {
+		t.Fatalf(`want a recorded destroy audit event, got "%+v"`, mockDao.AuditEvents)
+	}
+
+	if mockDao.AuditEvents[0].ActorType != m.AuditActorUnknown {
+		t.Errorf(`want an unknown actor, got "%s"`, mockDao.AuditEvents[0].ActorType)
+	}
+}
+
+// TestAuditTrailSkipped tests that nothing gets recorded when the handler fails or does not change any resource.
+func TestAuditTrailSkipped(t *testing.T) {
+	mockDao := setUpAuditEventDao(t)
+
+	handlers := []echo.HandlerFunc{
+		func(c echo.Context) error {
+			c.Set("event_type", "Thing.create")
+			c.Set("resource", &fakeAuditedResource{ID: 10})
+
+			return echo.ErrBadRequest
+		},
+		func(c echo.Context) error {
+			return c.NoContent(http.StatusOK)
+		},
+	}
+
+	for _, handler := range handlers {
+		c, _ := request.CreateTestContext(http.MethodPost, "/", nil, map[string]interface{}{
+			h.TenantID: int64(1),
+		})
+
+		_ = AuditTrail(handler)(c)
+	}
+
+	if len(mockDao.AuditEvents) != 0 {
+		t.Errorf(`want no recorded audit events, got "%+v"`, mockDao.AuditEvents)
+	}
+}
+
+// TestAuditActor tests that the certificates' clusters, the PSKs' users and the identities' users are told apart.
+func TestAuditActor(t *testing.T) {
+	testCases := []struct {
+		context   map[string]interface{}
+		actorType string
+		actorID   string
+	}{
+		{
+			context:   map[string]interface{}{h.ParsedIdentity: &identity.XRHID{Identity: identity.Identity{System: &identity.System{ClusterId: "cluster", CommonName: "cn"}}}},
+			actorType: m.AuditActorCert,
+			actorID:   "cluster",
+		},
+		{
+			context:   map[string]interface{}{h.PSK: "1234", h.PSKUserID: "pskUser", h.ParsedIdentity: &emptyIdentity},
+			actorType: m.AuditActorPSK,
+			actorID:   "pskUser",
+		},
+		{
+			context:   map[string]interface{}{h.ParsedIdentity: &identity.XRHID{Identity: identity.Identity{User: &identity.User{UserID: "testUser"}}}},
+			actorType: m.AuditActorUser,
+			actorID:   "testUser",
+		},
+		{
+			context:   map[string]interface{}{h.ParsedIdentity: &emptyIdentity},
+			actorType: m.AuditActorUnknown,
+			actorID:   "",
+		},
+	}
+
+	for _, tc := range testCases {
+		c, _ := request.CreateTestContext(http.MethodPost, "/", nil, tc.context)
+
+		actorType, actorID := AuditActor(c)
+		if actorType != tc.actorType || actorID != tc.actorID {
+			t.Errorf(`want the "%s" "%s" actor, got "%s" "%s"`, tc.actorType, tc.actorID, actorType, actorID)
+		}
+	}
+}
@@ -1,9 +1,11 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
 	"github.com/RedHatInsights/sources-api-go/rbac"
@@ -12,8 +14,24 @@ import (
 	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
 )
 
-// PermissionCheck takes the authentication information stored in the context and returns a "401 — Unauthorized" if the
-// given request is not authorized to perform "write" operations such as "POST, PATCH and DELETE".
+// AuthorizationError is returned by the "Authorizer" when the request is not authorized to perform the operation. It
+// carries the status code the REST API responds with, and the methods which are allowed instead, if any.
+type AuthorizationError struct {
+	StatusCode int
+	Message    string
+	Allow      string
+}
+
+func (e *AuthorizationError) Error() string {
+	return e.Message
+}
+
+// Authorizer checks whether the request is authorized to perform the "write" operation which the given HTTP method and
+// path stand for. It returns an "AuthorizationError" when the request is not authorized, or any other error when the
+// authorization could not be checked at all.
+type Authorizer func(c echo.Context, method string, path string) error
+
+// NewAuthorizer returns the authorizer which checks the authentication information stored in the context.
 //
 //   - When "bypassRbac" is "true", all the requests are authenticated and authorized.
 //   - When using a "psk" in the request, the latter gets authorized if the PSK is in our list of authorized PSKs that
@@ -24,82 +42,105 @@ import (
 //     operation on a subset of paths.
 //   - The request is a regularly authenticated one, so we will call RBAC to verify that the principal that comes in
 //     the header has the authorization to perform the operation in Sources.
-func PermissionCheck(bypassRbac bool, authorizedPsks []string, rbacClient rbac.Client) echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			switch {
-			case bypassRbac:
-				c.Logger().Debugf("Skipping authorization check -- disabled in ENV")
-			case c.Get(h.PSK) != nil:
-				psk, ok := c.Get(h.PSK).(string)
-				if !ok {
-					return fmt.Errorf("error casting psk to string: %v", c.Get(h.PSK))
-				}
+func NewAuthorizer(bypassRbac bool, authorizedPsks []string, rbacClient rbac.Client) Authorizer {
+	return func(c echo.Context, method string, path string) error {
+		switch {
+		case bypassRbac:
+			c.Logger().Debugf("Skipping authorization check -- disabled in ENV")
+		case c.Get(h.PSK) != nil:
+			psk, ok := c.Get(h.PSK).(string)
+			if !ok {
+				return fmt.Errorf("error casting psk to string: %v", c.Get(h.PSK))
+			}
 
-				if !pskMatches(authorizedPsks, psk) {
-					return c.JSON(http.StatusUnauthorized, util.NewErrorDoc("Unauthorized Action: Incorrect PSK", "401"))
-				}
+			if !pskMatches(authorizedPsks, psk) {
+				return &AuthorizationError{StatusCode: http.StatusUnauthorized, Message: "Unauthorized Action: Incorrect PSK"}
+			}
 
-			case c.Get(h.XRHID) != nil:
-				// first check the identity (already parsed) to see if it contains
-				// the system key and if it does do some extra checks to authorize
-				// based on some internal rules (operator + satellite)
-				id, ok := c.Get(h.ParsedIdentity).(*identity.XRHID)
-				if !ok {
-					return fmt.Errorf("error casting identity to struct: %+v", c.Get(h.ParsedIdentity))
-				}
+		case c.Get(h.XRHID) != nil:
+			// first check the identity (already parsed) to see if it contains
+			// the system key and if it does do some extra checks to authorize
+			// based on some internal rules (operator + satellite)
+			id, ok := c.Get(h.ParsedIdentity).(*identity.XRHID)
+			if !ok {
+				return fmt.Errorf("error casting identity to struct: %+v", c.Get(h.ParsedIdentity))
+			}
 
-				// For system based authentications, we need to make sure that
-				// the "Cluster ID" and the "Common name" fields are set in the
-				// identity header.
-				//
-				// We also allow a subset of the HTTP methods when using this
-				// type of authentications.
-				if isUsingCertificateBasedAuthentication(id) {
-					// Make sure that the incoming system-authenticated request
-					// is using the allowed method.
-					method := c.Request().Method
-					if !isMethodAllowedForCertificateBasedAuthentication(method) {
-						c.Response().Header().Set("Allow", "GET, POST, DELETE")
-						return c.JSON(http.StatusMethodNotAllowed, util.NewErrorDoc("Method not allowed", "405"))
-					}
-
-					// Make sure that the deletion operation is allowed.
-					if method == http.MethodDelete && !certDeleteAllowed(c) {
-						c.Response().Header().Set("Allow", "GET, POST")
-						return c.JSON(http.StatusMethodNotAllowed, util.NewErrorDoc("Method not allowed", "405"))
-					}
-
-					// The "Cluster ID" and the "Common name" fields of the
-					// certificate must have been specified in the header.
-					if id.Identity.System.ClusterId == "" && id.Identity.System.CommonName == "" {
-						return c.JSON(http.StatusUnauthorized, util.NewErrorDoc("Unauthorized Action: system authorization only supports cn/cluster_id authorization", "401"))
-					}
-
-					// At this point the request is properly authenticated, so
-					// there is no need to call RBAC.
-					return next(c)
+			// For system based authentications, we need to make sure that
+			// the "Cluster ID" and the "Common name" fields are set in the
+			// identity header.
+			//
+			// We also allow a subset of the HTTP methods when using this
+			// type of authentications.
+			if isUsingCertificateBasedAuthentication(id) {
+				// Make sure that the incoming system-authenticated request
+				// is using the allowed method.
+				if !isMethodAllowedForCertificateBasedAuthentication(method) {
+					return &AuthorizationError{StatusCode: http.StatusMethodNotAllowed, Message: "Method not allowed", Allow: "GET, POST, DELETE"}
 				}
 
-				// For other types of authentications, we need to forward the
-				// "x-rh-identity" header to RBAC to check if the principal
-				// is authorized to perform the call.
-				rhid, ok := c.Get(h.XRHID).(string)
-				if !ok {
-					return fmt.Errorf(`authorization failed. The given "x-rh-identity" header is not a string: %v`, c.Get(h.XRHID))
+				// Make sure that the deletion operation is allowed.
+				if method == http.MethodDelete && !certDeleteAllowed(path) {
+					return &AuthorizationError{StatusCode: http.StatusMethodNotAllowed, Message: "Method not allowed", Allow: "GET, POST"}
 				}
 
-				allowed, err := rbacClient.Allowed(rhid)
-				if err != nil {
-					return fmt.Errorf("authorization failed. Unable to contact RBAC: %w", err)
+				// The "Cluster ID" and the "Common name" fields of the
+				// certificate must have been specified in the header.
+				if id.Identity.System.ClusterId == "" && id.Identity.System.CommonName == "" {
+					return &AuthorizationError{StatusCode: http.StatusUnauthorized, Message: "Unauthorized Action: system authorization only supports cn/cluster_id authorization"}
 				}
 
-				if !allowed {
-					return c.JSON(http.StatusUnauthorized, util.NewErrorDoc("Unauthorized Action: Missing RBAC permissions", "401"))
+				// At this point the request is properly authenticated, so
+				// there is no need to call RBAC.
+				return nil
+			}
+
+			// For other types of authentications, we need to forward the
+			// "x-rh-identity" header to RBAC to check if the principal
+			// is authorized to perform the call.
+			rhid, ok := c.Get(h.XRHID).(string)
+			if !ok {
+				return fmt.Errorf(`authorization failed. The given "x-rh-identity" header is not a string: %v`, c.Get(h.XRHID))
+			}
+
+			allowed, err := rbacClient.Allowed(rhid)
+			if err != nil {
+				return fmt.Errorf("authorization failed. Unable to contact RBAC: %w", err)
+			}
+
+			if !allowed {
+				return &AuthorizationError{StatusCode: http.StatusUnauthorized, Message: "Unauthorized Action: Missing RBAC permissions"}
+			}
+
+		default:
+			return &AuthorizationError{StatusCode: http.StatusUnauthorized, Message: "Authentication required by either [x-rh-identity] or [x-rh-sources-psk]"}
+		}
+
+		return nil
+	}
+}
+
+// PermissionCheck takes the authentication information stored in the context and returns a "401 — Unauthorized" if the
+// given request is not authorized to perform "write" operations such as "POST, PATCH and DELETE". The rules are the
+// ones "NewAuthorizer" describes.
+func PermissionCheck(bypassRbac bool, authorizedPsks []string, rbacClient rbac.Client) echo.MiddlewareFunc {
+	authorize := NewAuthorizer(bypassRbac, authorizedPsks, rbacClient)
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := authorize(c, c.Request().Method, c.Request().URL.Path)
+
+			var authorizationErr *AuthorizationError
+			if errors.As(err, &authorizationErr) {
+				if authorizationErr.Allow != "" {
+					c.Response().Header().Set("Allow", authorizationErr.Allow)
 				}
 
-			default:
-				return c.JSON(http.StatusUnauthorized, util.NewErrorDoc("Authentication required by either [x-rh-identity] or [x-rh-sources-psk]", "401"))
+				return c.JSON(authorizationErr.StatusCode, util.NewErrorDoc(authorizationErr.Message, strconv.Itoa(authorizationErr.StatusCode)))
+			}
+
+			if err != nil {
+				return err
 			}
 
 			return next(c)
@@ -109,9 +150,9 @@ func PermissionCheck(bypassRbac bool, authorizedPsks []string, rbacClient rbac.C
 
 // certDeleteAllowed returns true when the given "DELETE" request authenticated by a certificate has been sent to the
 // paths which we allow those request to be sent for.
-func certDeleteAllowed(c echo.Context) bool {
+func certDeleteAllowed(path string) bool {
 	//Limit to "sources" endpoint - further filtering done by source handler.
-	return regexp.MustCompile(`/sources/\d+$`).MatchString(c.Request().URL.Path)
+	return regexp.MustCompile(`/sources/\d+$`).MatchString(path)
 }
 
 // isMethodAllowedForCertificateAuthentication returns true when the request's
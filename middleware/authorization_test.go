@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"testing"
@@ -387,6 +388,66 @@ func TestRbacNoConnection(t *testing.T) {
 	}
 }
 
+// TestAuthorizerMethodAndPath tests that the authorizer checks the given method and path instead of the request's,
+// which is what the GraphQL mutations rely on.
+func TestAuthorizerMethodAndPath(t *testing.T) {
+	testCases := []struct {
+		method     string
+		path       string
+		wantStatus int
+		wantAllow  string
+	}{
+		{method: http.MethodPost, path: "/sources"},
+		{method: http.MethodDelete, path: "/sources/1235"},
+		{method: http.MethodDelete, path: "/applications/1235", wantStatus: http.StatusMethodNotAllowed, wantAllow: "GET, POST"},
+		{method: http.MethodPatch, path: "/sources/1235", wantStatus: http.StatusMethodNotAllowed, wantAllow: "GET, POST, DELETE"},
+	}
+
+	authorize := NewAuthorizer(false, []string{}, &mockRbacClient{})
+
+	for _, tc := range testCases {
+		c, _ := request.CreateTestContext(
+			http.MethodPost,
+			"/graphql",
+			nil,
+			map[string]interface{}{
+				h.XRHID: "dummy",
+				h.ParsedIdentity: &identity.XRHID{
+					Identity: identity.Identity{
+						System: &identity.System{
+							CommonName: "test_cert",
+						},
+					},
+				},
+			},
+		)
+
+		err := authorize(c, tc.method, tc.path)
+
+		if tc.wantStatus == 0 {
+			if err != nil {
+				t.Errorf(`%s "%s": want no error, got "%s"`, tc.method, tc.path, err)
+			}
+
+			continue
+		}
+
+		var authorizationErr *AuthorizationError
+		if !errors.As(err, &authorizationErr) {
+			t.Errorf(`%s "%s": want an authorization error, got "%v"`, tc.method, tc.path, err)
+			continue
+		}
+
+		if authorizationErr.StatusCode != tc.wantStatus {
+			t.Errorf(`%s "%s": want status code "%d", got "%d"`, tc.method, tc.path, tc.wantStatus, authorizationErr.StatusCode)
+		}
+
+		if authorizationErr.Allow != tc.wantAllow {
+			t.Errorf(`%s "%s": want the allowed methods "%s", got "%s"`, tc.method, tc.path, tc.wantAllow, authorizationErr.Allow)
+		}
+	}
+}
+
 // TestIsUsingCertificateBasedAuthentication tests that the function under test
 // correctly identifies a certificate based authentication.
 func TestIsUsingCertificateBasedAuthentication(t *testing.T) {
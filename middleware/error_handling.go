@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"net/http"
+	"strconv"
 
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
 	"github.com/RedHatInsights/sources-api-go/util"
@@ -65,32 +66,55 @@ func logErrorWithContextFields(c echo.Context, err error) {
 	}
 }
 
+// errorStatusCode returns the status code the given error gets reported to the clients with. The errors that are not
+// meant for the clients are internal server errors.
+func errorStatusCode(err error) int {
+	switch err.(type) {
+	case util.ErrNotFound:
+		return http.StatusNotFound
+	case util.ErrBadRequest:
+		return http.StatusBadRequest
+	case util.ErrPreconditionFailed:
+		return http.StatusPreconditionFailed
+	case util.ErrConflict:
+		return http.StatusConflict
+	case util.ErrUnprocessableEntity:
+		return http.StatusUnprocessableEntity
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// ClientErrorMessage returns the message "HandleErrors" would report the given error with. The errors that are not
+// meant for the clients get logged and reported as internal server errors, so that their details do not leak.
+func ClientErrorMessage(c echo.Context, err error) string {
+	if errorStatusCode(err) == http.StatusInternalServerError {
+		logErrorWithContextFields(c, err)
+
+		return "Internal Server Error"
+	}
+
+	return err.Error()
+}
+
 func HandleErrors(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
 		err := next(c)
 		if err != nil {
-			var (
-				statusCode int
-				message    interface{}
-			)
-
-			switch err.(type) {
-			case util.ErrNotFound:
-				statusCode = http.StatusNotFound
-				message = util.NewErrorDoc(err.Error(), "404")
-			case util.ErrBadRequest:
-				statusCode = http.StatusBadRequest
-				message = util.NewErrorDoc(err.Error(), "400")
-			default:
+			var message interface{}
+
+			statusCode := errorStatusCode(err)
+			if statusCode == http.StatusInternalServerError {
 				uuid, ok := c.Get(h.InsightsRequestID).(string)
 				if !ok {
 					uuid = ""
 				}
 
-				statusCode = http.StatusInternalServerError
 				message = util.ErrorDocWithRequestId("Internal Server Error", "500", uuid)
 
 				logErrorWithContextFields(c, err)
+			} else {
+				message = util.NewErrorDoc(err.Error(), strconv.Itoa(statusCode))
 			}
 
 			return c.JSON(statusCode, message)
@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 )
 
@@ -56,3 +57,30 @@ func TestNoError(t *testing.T) {
 		t.Errorf("%v was returned instead of %v", rec.Code, 200)
 	}
 }
+
+// TestClientErrorMessage tests that the errors meant for the clients keep their message, and that the rest of them do
+// not leak their details.
+func TestClientErrorMessage(t *testing.T) {
+	c, _ := request.CreateTestContext(
+		http.MethodGet,
+		"/",
+		nil,
+		map[string]interface{}{},
+	)
+
+	testCases := []struct {
+		Err  error
+		Want string
+	}{
+		{Err: util.NewErrNotFound("source"), Want: "source not found"},
+		{Err: util.NewErrBadRequest("invalid name"), Want: "bad request: invalid name"},
+		{Err: fmt.Errorf(`pq: relation "sources" does not exist`), Want: "Internal Server Error"},
+	}
+
+	for _, tc := range testCases {
+		got := ClientErrorMessage(c, tc.Err)
+		if got != tc.Want {
+			t.Errorf(`want message "%s", got "%s"`, tc.Want, got)
+		}
+	}
+}
@@ -2,13 +2,18 @@ package middleware
 
 import (
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 )
 
-var BadQueryParams = []string{"limit", "offset", "sort_by"}
+var BadQueryParams = []string{"limit", "offset", "sort_by", "cursor", "include", "format", "group_by"}
+
+// FilterSubresources are the subresources which can be filtered on with filter[subresource][field][operation].
+var FilterSubresources = []string{"source_type", "application_type", "endpoint", "authentication", "rhc_connection"}
 
 func SortAndFilter(next echo.HandlerFunc) echo.HandlerFunc {
 	return func(c echo.Context) error {
@@ -21,6 +26,11 @@ func SortAndFilter(next echo.HandlerFunc) echo.HandlerFunc {
 			filters = append(filters, *sort)
 		}
 
+		// the sparse fieldset, when requested, lets the DAOs narrow down the selected columns.
+		if fields, ok := c.Get("fields").([]string); ok {
+			filters = append(filters, util.Filter{Operation: util.FieldsFilterOperation, Value: fields})
+		}
+
 		c.Set("filters", filters)
 
 		return next(c)
@@ -28,50 +38,178 @@ func SortAndFilter(next echo.HandlerFunc) echo.HandlerFunc {
 }
 
 func parseFilter(c echo.Context) ([]util.Filter, error) {
-	f := make([]util.Filter, 0)
+	root := newFilterGroup()
 	for key, values := range c.QueryParams() {
+		// the sparse fieldsets are parsed by their own middleware.
+		if fieldsParamRegex.MatchString(key) {
+			continue
+		}
+
 		if strings.HasPrefix(key, "filter") {
-			matches := util.FilterRegex.FindAllStringSubmatch(key, -1)
-			filter := util.Filter{}
+			matches := util.FilterRegex.FindAllString(key, -1)
 
 			if len(matches) < 2 {
 				return nil, fmt.Errorf("filter")
 			}
 
-			// matching filter[subresource][field][operation]
-			// we only support filtering on source type/application type
-			if matches[1][0] == "source_type" || matches[1][0] == "application_type" {
-				filter = util.Filter{
-					Subresource: matches[1][0],
-					Name:        matches[2][0],
-					Value:       values,
-				}
-
-				// this will only be present if the user used a operation. it
-				// defaults to "" which we use as eq
-				if len(matches) == 4 {
-					filter.Operation = matches[3][0]
-				}
-			} else {
-				// matching filter[field][operation]
-				filter = util.Filter{Name: matches[1][0], Value: values}
-				// this will only be present if the user used a operation. it
-				// defaults to "" which we use as eq
-				if len(matches) == 3 {
-					filter.Operation = matches[2][0]
-				}
+			err := root.add(matches[1:], values, 0)
+			if err != nil {
+				return nil, err
 			}
-
-			f = append(f, filter)
 		} else if !util.SliceContainsString(BadQueryParams, key) {
 			if !util.IsValidColumnName(key) {
 				return nil, fmt.Errorf("invalid filter parameter")
 			}
-			f = append(f, util.Filter{Name: key, Value: values})
+			root.filters = append(root.filters, util.Filter{Name: key, Value: values})
+		}
+	}
+
+	return root.toFilters(), nil
+}
+
+// filterGroup collects the filters which are in the same group of the query parameters. The elements of the "and" and
+// "or" groups are kept by their index, since the query parameters do not come in any particular order.
+type filterGroup struct {
+	filters  []util.Filter
+	elements map[string]map[int]*filterGroup
+	not      *filterGroup
+}
+
+func newFilterGroup() *filterGroup {
+	return &filterGroup{
+		filters:  make([]util.Filter, 0),
+		elements: make(map[string]map[int]*filterGroup),
+	}
+}
+
+// add adds the filter of the given key parts to the group. The parts are the ones after "filter", so that
+//
+// filter[or][0][availability_status][eq]
+// or
+// filter[not][source_type][name]
+//
+// get added as:
+//
+// ["or", "0", "availability_status", "eq"]
+// or
+// ["not", "source_type", "name"]
+func (g *filterGroup) add(parts []string, values []string, depth int) error {
+	switch parts[0] {
+	case util.AndFilterOperation, util.OrFilterOperation:
+		if depth >= util.MaxFilterGroupDepth {
+			return fmt.Errorf("too many nested filter groups")
+		}
+
+		// matching filter[or][index][...]
+		if len(parts) < 3 {
+			return fmt.Errorf("invalid %s filter group", parts[0])
+		}
+
+		index, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return fmt.Errorf("invalid %s filter group index %q", parts[0], parts[1])
+		}
+
+		if g.elements[parts[0]] == nil {
+			g.elements[parts[0]] = make(map[int]*filterGroup)
+		}
+
+		element, ok := g.elements[parts[0]][index]
+		if !ok {
+			element = newFilterGroup()
+			g.elements[parts[0]][index] = element
+		}
+
+		return element.add(parts[2:], values, depth+1)
+	case util.NotFilterOperation:
+		if depth >= util.MaxFilterGroupDepth {
+			return fmt.Errorf("too many nested filter groups")
+		}
+
+		// matching filter[not][...]
+		if len(parts) < 2 {
+			return fmt.Errorf("invalid %s filter group", parts[0])
+		}
+
+		if g.not == nil {
+			g.not = newFilterGroup()
+		}
+
+		return g.not.add(parts[1:], values, depth+1)
+	}
+
+	var filter util.Filter
+
+	// matching filter[subresource][field][operation]
+	// we only support filtering on the subresources below
+	if util.SliceContainsString(FilterSubresources, parts[0]) {
+		if len(parts) < 2 {
+			return fmt.Errorf("invalid %s filter", parts[0])
+		}
+
+		filter = util.Filter{
+			Subresource: parts[0],
+			Name:        parts[1],
+			Value:       values,
+		}
+
+		// this will only be present if the user used a operation. it
+		// defaults to "" which we use as eq
+		if len(parts) == 3 {
+			filter.Operation = parts[2]
+		}
+	} else {
+		// matching filter[field][operation]
+		filter = util.Filter{Name: parts[0], Value: values}
+		// this will only be present if the user used a operation. it
+		// defaults to "" which we use as eq
+		if len(parts) == 2 {
+			filter.Operation = parts[1]
+		}
+	}
+
+	g.filters = append(g.filters, filter)
+
+	return nil
+}
+
+// toFilters returns the filters of the group, followed by its "and", "or" and "not" groups. The elements of the "and"
+// and "or" groups are sorted by their index, and the filters of each element must all match.
+func (g *filterGroup) toFilters() []util.Filter {
+	filters := g.filters
+
+	for _, operation := range []string{util.AndFilterOperation, util.OrFilterOperation} {
+		elements := g.elements[operation]
+		if len(elements) == 0 {
+			continue
+		}
+
+		indexes := make([]int, 0, len(elements))
+		for index := range elements {
+			indexes = append(indexes, index)
+		}
+
+		sort.Ints(indexes)
+
+		group := util.Filter{Operation: operation}
+		for _, index := range indexes {
+			elementFilters := elements[index].toFilters()
+
+			if len(elementFilters) == 1 {
+				group.Filters = append(group.Filters, elementFilters[0])
+			} else {
+				group.Filters = append(group.Filters, util.Filter{Operation: util.AndFilterOperation, Filters: elementFilters})
+			}
 		}
+
+		filters = append(filters, group)
+	}
+
+	if g.not != nil {
+		filters = append(filters, util.Filter{Operation: util.NotFilterOperation, Filters: g.not.toFilters()})
 	}
 
-	return f, nil
+	return filters
 }
 
 func parseSorting(c echo.Context) *util.Filter {
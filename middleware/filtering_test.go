@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/config"
+	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/labstack/echo/v4"
 )
 
@@ -166,6 +167,39 @@ func TestParseSubresourceFilterWithoutOperation(t *testing.T) {
 	}
 }
 
+// TestParseSourceSubresourceFilters tests that the endpoint, authentication and rhc connection subresources get parsed.
+func TestParseSourceSubresourceFilters(t *testing.T) {
+	testCases := []struct {
+		query       string
+		subresource string
+		name        string
+		operation   string
+	}{
+		{query: "filter[endpoint][host][eq]=example.com", subresource: "endpoint", name: "host", operation: "eq"},
+		{query: "filter[authentication][authtype]=token", subresource: "authentication", name: "authtype"},
+		{query: "filter[rhc_connection][rhc_id][starts_with]=abc", subresource: "rhc_connection", name: "rhc_id", operation: "starts_with"},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?"+tc.query, nil)
+		c := e.NewContext(req, nil)
+
+		filters, err := parseFilter(c)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %s", tc.query, err)
+		}
+
+		if len(filters) != 1 {
+			t.Fatalf("wrong number of filters for %q", tc.query)
+		}
+
+		f := filters[0]
+		if f.Subresource != tc.subresource || f.Name != tc.name || f.Operation != tc.operation {
+			t.Errorf(`unexpected filter for %q: "%+v"`, tc.query, f)
+		}
+	}
+}
+
 func TestParseFilteringWithoutFilterArg(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?name=test", nil)
 	c := e.NewContext(req, nil)
@@ -202,6 +236,20 @@ func TestParseFilteringWithoutFilterArgWithLimit(t *testing.T) {
 	}
 }
 
+func TestParseFilteringWithoutFilterArgWithFormat(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/exports/sources?name=test&format=csv", nil)
+	c := e.NewContext(req, nil)
+
+	filters, err := parseFilter(c)
+	if err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	if len(filters) != 1 || filters[0].Name != "name" {
+		t.Errorf("the format should not have been parsed as a filter, got %+v", filters)
+	}
+}
+
 func TestParseFilteringBadFilter(t *testing.T) {
 	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?filter=filter[name][eq]=test", nil)
 	c := e.NewContext(req, nil)
@@ -215,3 +263,73 @@ func TestParseFilteringBadFilter(t *testing.T) {
 		t.Error("got a filter when there should not have been one")
 	}
 }
+
+// TestParseFilterGroups tests that the "or" groups get their elements sorted by index, that the filters under the same
+// index get ANDed, and that the "not" groups get parsed along with the regular filters.
+func TestParseFilterGroups(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?"+
+		"filter[or][1][availability_status]=partially_available&"+
+		"filter[or][0][availability_status]=unavailable&"+
+		"filter[or][0][name][contains]=prod&"+
+		"filter[source_type][name]=amazon&filter[source_type][name]=azure&"+
+		"filter[not][or][0][name][eq]=a&filter[not][or][1][source_type][vendor]=b", nil)
+	c := e.NewContext(req, nil)
+
+	filters, err := parseFilter(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(filters) != 3 {
+		t.Fatalf(`want 3 filters, got "%+v"`, filters)
+	}
+
+	if filters[0].Subresource != "source_type" || len(filters[0].Value) != 2 {
+		t.Errorf(`want the source type filter first, got "%+v"`, filters[0])
+	}
+
+	or := filters[1]
+	if or.Operation != util.OrFilterOperation || len(or.Filters) != 2 {
+		t.Fatalf(`want an "or" group with 2 elements, got "%+v"`, or)
+	}
+
+	if or.Filters[0].Operation != util.AndFilterOperation || len(or.Filters[0].Filters) != 2 {
+		t.Errorf(`want the first element to be an "and" group of 2 filters, got "%+v"`, or.Filters[0])
+	}
+
+	if or.Filters[1].Name != "availability_status" || or.Filters[1].Value[0] != "partially_available" {
+		t.Errorf(`unexpected second element "%+v"`, or.Filters[1])
+	}
+
+	not := filters[2]
+	if not.Operation != util.NotFilterOperation || len(not.Filters) != 1 || not.Filters[0].Operation != util.OrFilterOperation {
+		t.Fatalf(`want a "not" group of an "or" group, got "%+v"`, not)
+	}
+
+	nested := not.Filters[0].Filters
+	if len(nested) != 2 || nested[0].Name != "name" || nested[0].Operation != "eq" || nested[1].Subresource != "source_type" || nested[1].Name != "vendor" {
+		t.Errorf(`unexpected negated filters "%+v"`, nested)
+	}
+}
+
+// TestParseFilterGroupsBad tests that the malformed and the too deeply nested groups get rejected.
+func TestParseFilterGroupsBad(t *testing.T) {
+	queries := []string{
+		"filter[or][name]=test",
+		"filter[or][0]=test",
+		"filter[and][][name]=test",
+		"filter[not]=test",
+		"filter[not][source_type]=test",
+		"filter[not][not][not][not][not][not][name]=test",
+	}
+
+	for _, query := range queries {
+		req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?"+query, nil)
+		c := e.NewContext(req, nil)
+
+		_, err := parseFilter(c)
+		if err == nil {
+			t.Errorf("want an error for %q, got none", query)
+		}
+	}
+}
@@ -12,4 +12,10 @@ const (
 	ParsedIdentity    = "identity"
 	TenantID          = "tenantID"
 	UserID            = "userID"
+	ETag              = "ETag"
+	IfMatch           = "If-Match"
+	IfNoneMatch       = "If-None-Match"
+	IdempotencyKey    = "Idempotency-Key"
+	IdempotentReplay  = "Idempotent-Replayed"
+	EncryptionKey     = "x-rh-sources-encryption-key"
 )
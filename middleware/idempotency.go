@@ -0,0 +1,244 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/config"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/RedHatInsights/sources-api-go/redis"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// maxIdempotencyKeyLength is the maximum length of the "Idempotency-Key" headers we accept.
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL is how long a key stays reserved while its request is being processed. It guards us from
+	// keeping the key locked forever if the pod dies in the middle of the request.
+	idempotencyLockTTL = 5 * time.Minute
+)
+
+// IdempotencyKeyStore stores the responses of the requests that were sent with an "Idempotency-Key" header.
+type IdempotencyKeyStore interface {
+	// Get returns the stored value for the given key, or nil if the key does not exist.
+	Get(ctx context.Context, key string) ([]byte, error)
+	// SetNX stores the value only if the key does not exist, and returns whether it was stored.
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Set stores the value, overwriting the previous one.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Delete removes the key.
+	Delete(ctx context.Context, key string) error
+}
+
+// IdempotencyStore is the store the "Idempotency" middleware uses. It is a variable so that it can be replaced in
+// the tests.
+var IdempotencyStore IdempotencyKeyStore = valkeyIdempotencyKeyStore{}
+
+// valkeyIdempotencyKeyStore is the default store, backed by Valkey.
+type valkeyIdempotencyKeyStore struct{}
+
+func (valkeyIdempotencyKeyStore) Get(ctx context.Context, key string) ([]byte, error) {
+	value, err := redis.Client.Do(ctx, redis.Client.B().Get().Key(key).Build()).AsBytes()
+	if redis.IsNil(err) {
+		return nil, nil
+	}
+
+	return value, err
+}
+
+func (valkeyIdempotencyKeyStore) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	err := redis.Client.Do(ctx, redis.Client.B().Set().Key(key).Value(string(value)).Nx().Px(ttl).Build()).Error()
+	if redis.IsNil(err) {
+		return false, nil
+	}
+
+	return err == nil, err
+}
+
+func (valkeyIdempotencyKeyStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return redis.Client.Do(ctx, redis.Client.B().Set().Key(key).Value(string(value)).Px(ttl).Build()).Error()
+}
+
+func (valkeyIdempotencyKeyStore) Delete(ctx context.Context, key string) error {
+	return redis.Client.Do(ctx, redis.Client.B().Del().Key(key).Build()).Error()
+}
+
+// idempotencyRecord is what gets stored for every idempotency key. The record is stored as "pending" while the
+// original request is being processed, and gets completed with its response once the request finishes.
+type idempotencyRecord struct {
+	RequestHash string `json:"request_hash"`
+	Completed   bool   `json:"completed"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// responseRecorder copies the response body as it gets written to the client.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
+
+// Idempotency makes the requests sent with an "Idempotency-Key" header safe to retry. The first request with a given
+// key is processed normally and its response is stored for the configured TTL. The retries with the same key and the
+// same body get the stored response replayed, without processing the request again. The retries with the same key and
+// a different body are rejected with a "422 Unprocessable Entity" response. Failed requests, either the ones which
+// return an error or the ones which respond with a 4xx or a 5xx status code, are not stored, so that they can be
+// retried with the same key.
+func Idempotency(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		idempotencyKey := c.Request().Header.Get(h.IdempotencyKey)
+		if idempotencyKey == "" {
+			return next(c)
+		}
+
+		if len(idempotencyKey) > maxIdempotencyKeyLength {
+			return util.NewErrBadRequest(fmt.Sprintf(`the "%s" header must not be longer than %d characters`, h.IdempotencyKey, maxIdempotencyKeyLength))
+		}
+
+		// The keys are scoped to the tenant, so that different tenants cannot see each other's responses.
+		tenantId, ok := c.Get(h.TenantID).(int64)
+		if !ok {
+			return fmt.Errorf("failed to pull the tenant from the request's context")
+		}
+
+		requestHash, err := hashRequest(c)
+		if err != nil {
+			return err
+		}
+
+		ctx := c.Request().Context()
+		storeKey := fmt.Sprintf("sources_api:idempotency_keys:%d:%s", tenantId, idempotencyKey)
+
+		pending, err := json.Marshal(idempotencyRecord{RequestHash: requestHash})
+		if err != nil {
+			return err
+		}
+
+		reserved, err := IdempotencyStore.SetNX(ctx, storeKey, pending, idempotencyLockTTL)
+		if err != nil {
+			return err
+		}
+
+		if !reserved {
+			return replayResponse(c, storeKey, requestHash)
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = recorder
+
+		err = next(c)
+
+		c.Response().Writer = recorder.ResponseWriter
+
+		if err != nil || c.Response().Status >= http.StatusBadRequest {
+			deleteErr := IdempotencyStore.Delete(ctx, storeKey)
+			if deleteErr != nil {
+				c.Logger().Warnf(`unable to release the idempotency key "%s": %s`, idempotencyKey, deleteErr)
+			}
+
+			return err
+		}
+
+		completed, err := json.Marshal(idempotencyRecord{
+			RequestHash: requestHash,
+			Completed:   true,
+			StatusCode:  c.Response().Status,
+			ContentType: c.Response().Header().Get(echo.HeaderContentType),
+			Body:        recorder.body.Bytes(),
+		})
+		if err != nil {
+			return err
+		}
+
+		// The response has already been sent at this point, so a failure here only means that the retries will not
+		// get it replayed.
+		err = IdempotencyStore.Set(ctx, storeKey, completed, time.Duration(config.Get().IdempotencyKeyTTL)*time.Second)
+		if err != nil {
+			c.Logger().Warnf(`unable to store the response for the idempotency key "%s": %s`, idempotencyKey, err)
+		}
+
+		return nil
+	}
+}
+
+// replayResponse sends the stored response of the request which originally used the idempotency key.
+func replayResponse(c echo.Context, storeKey string, requestHash string) error {
+	raw, err := IdempotencyStore.Get(c.Request().Context(), storeKey)
+	if err != nil {
+		return err
+	}
+
+	// The original request failed or the key expired right after we tried to reserve it.
+	if raw == nil {
+		return util.NewErrConflict("a request with the same idempotency key has just finished, please retry")
+	}
+
+	var record idempotencyRecord
+
+	err = json.Unmarshal(raw, &record)
+	if err != nil {
+		return err
+	}
+
+	if record.RequestHash != requestHash {
+		return util.NewErrUnprocessableEntity("the idempotency key has already been used for a different request")
+	}
+
+	if !record.Completed {
+		return util.NewErrConflict("a request with the same idempotency key is still being processed")
+	}
+
+	c.Response().Header().Set(h.IdempotentReplay, "true")
+
+	if len(record.Body) == 0 {
+		return c.NoContent(record.StatusCode)
+	}
+
+	return c.Blob(record.StatusCode, record.ContentType, record.Body)
+}
+
+// hashRequest returns a hash which identifies the request's route, query string and body, so that for example a dry
+// run does not get replayed for the real request. JSON bodies are normalized first, so that the formatting and the
+// order of the keys does not make two equal payloads different.
+func hashRequest(c echo.Context) (string, error) {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return "", util.NewErrBadRequest(err)
+	}
+
+	// Put the body back for the handler.
+	c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+	var payload interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	if decoder.Decode(&payload) == nil {
+		normalized, err := json.Marshal(payload)
+		if err == nil {
+			body = normalized
+		}
+	}
+
+	hash := sha256.New()
+	hash.Write([]byte(c.Request().Method + " " + c.Path() + "?" + c.QueryString() + "\x00"))
+	hash.Write(body)
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
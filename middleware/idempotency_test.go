@@ -0,0 +1,296 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// inMemoryIdempotencyKeyStore is a store that keeps the keys in a map, so that the middleware can be tested without
+// a Valkey instance.
+type inMemoryIdempotencyKeyStore struct {
+	mutex  sync.Mutex
+	values map[string][]byte
+}
+
+func (s *inMemoryIdempotencyKeyStore) Get(_ context.Context, key string) ([]byte, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return s.values[key], nil
+}
+
+func (s *inMemoryIdempotencyKeyStore) SetNX(_ context.Context, key string, value []byte, _ time.Duration) (bool, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, ok := s.values[key]; ok {
+		return false, nil
+	}
+
+	s.values[key] = value
+
+	return true, nil
+}
+
+func (s *inMemoryIdempotencyKeyStore) Set(_ context.Context, key string, value []byte, _ time.Duration) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	s.values[key] = value
+
+	return nil
+}
+
+func (s *inMemoryIdempotencyKeyStore) Delete(_ context.Context, key string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	delete(s.values, key)
+
+	return nil
+}
+
+// useInMemoryIdempotencyStore replaces the idempotency store for the duration of the test.
+func useInMemoryIdempotencyStore(t *testing.T) {
+	backup := IdempotencyStore
+	IdempotencyStore = &inMemoryIdempotencyKeyStore{values: map[string][]byte{}}
+
+	t.Cleanup(func() { IdempotencyStore = backup })
+}
+
+// idempotentRequest sends a request with the given idempotency key and body through the middleware, and returns the
+// response's status code and body.
+func idempotentRequest(t *testing.T, handler echo.HandlerFunc, key string, body string) (int, string, http.Header) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/sources",
+		strings.NewReader(body),
+		map[string]interface{}{
+			h.TenantID: int64(1),
+		},
+	)
+
+	c.Request().Header.Set(h.IdempotencyKey, key)
+
+	err := HandleErrors(Idempotency(handler))(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	return rec.Code, rec.Body.String(), rec.Header()
+}
+
+// TestIdempotencyReplay tests that a retried request with the same key and body gets the original response replayed
+// without calling the handler again.
+func TestIdempotencyReplay(t *testing.T) {
+	useInMemoryIdempotencyStore(t)
+
+	calls := 0
+	handler := func(c echo.Context) error {
+		calls++
+
+		return c.JSON(http.StatusCreated, map[string]int{"id": calls})
+	}
+
+	code, body, _ := idempotentRequest(t, handler, "key", `{"name": "source", "source_type_id": 1}`)
+	if code != http.StatusCreated {
+		t.Errorf("want status code %d, got %d", http.StatusCreated, code)
+	}
+
+	// The same payload with a different formatting and keys order is considered the same request.
+	replayedCode, replayedBody, headers := idempotentRequest(t, handler, "key", `{"source_type_id":1,"name":"source"}`)
+	if replayedCode != http.StatusCreated {
+		t.Errorf("want status code %d, got %d", http.StatusCreated, replayedCode)
+	}
+
+	if replayedBody != body {
+		t.Errorf(`want the replayed body "%s", got "%s"`, body, replayedBody)
+	}
+
+	if headers.Get(h.IdempotentReplay) != "true" {
+		t.Errorf(`want the "%s" header to be set in the replayed response`, h.IdempotentReplay)
+	}
+
+	if calls != 1 {
+		t.Errorf("want the handler to be called once, got %d calls", calls)
+	}
+}
+
+// TestIdempotencyDifferentBody tests that reusing a key for a different request gets rejected.
+func TestIdempotencyDifferentBody(t *testing.T) {
+	useInMemoryIdempotencyStore(t)
+
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusCreated, map[string]string{})
+	}
+
+	_, _, _ = idempotentRequest(t, handler, "key", `{"name": "source"}`)
+
+	code, _, _ := idempotentRequest(t, handler, "key", `{"name": "another source"}`)
+	if code != http.StatusUnprocessableEntity {
+		t.Errorf("want status code %d, got %d", http.StatusUnprocessableEntity, code)
+	}
+}
+
+// TestIdempotencyDifferentQuery tests that reusing a key for the same body but a different query string, like a dry
+// run followed by the real request, gets rejected.
+func TestIdempotencyDifferentQuery(t *testing.T) {
+	useInMemoryIdempotencyStore(t)
+
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{})
+	}
+
+	codes := make([]int, 0, 2)
+	for _, url := range []string{"/api/sources/v3.1/bulk_create?dry_run=true", "/api/sources/v3.1/bulk_create"} {
+		c, rec := request.CreateTestContext(http.MethodPost, url, strings.NewReader(`{"sources": []}`), map[string]interface{}{h.TenantID: int64(1)})
+		c.Request().Header.Set(h.IdempotencyKey, "key")
+
+		err := HandleErrors(Idempotency(handler))(c)
+		if err != nil {
+			t.Errorf(`unexpected error: %s`, err)
+		}
+
+		codes = append(codes, rec.Code)
+	}
+
+	if codes[0] != http.StatusOK || codes[1] != http.StatusUnprocessableEntity {
+		t.Errorf("want status codes %d and %d, got %v", http.StatusOK, http.StatusUnprocessableEntity, codes)
+	}
+}
+
+// TestIdempotencyFailedRequest tests that the failed requests are not stored, so that they can be retried with the
+// same key.
+func TestIdempotencyFailedRequest(t *testing.T) {
+	useInMemoryIdempotencyStore(t)
+
+	calls := 0
+	handler := func(c echo.Context) error {
+		calls++
+		if calls == 1 {
+			return util.NewErrBadRequest("try again")
+		}
+
+		return c.JSON(http.StatusCreated, map[string]string{})
+	}
+
+	code, _, _ := idempotentRequest(t, handler, "key", `{"name": "source"}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("want status code %d, got %d", http.StatusBadRequest, code)
+	}
+
+	code, _, _ = idempotentRequest(t, handler, "key", `{"name": "source"}`)
+	if code != http.StatusCreated {
+		t.Errorf("want status code %d, got %d", http.StatusCreated, code)
+	}
+
+	if calls != 2 {
+		t.Errorf("want the handler to be called twice, got %d calls", calls)
+	}
+}
+
+// TestIdempotencyFailedResponse tests that the client error responses which the handlers write themselves are not
+// stored either, so that they can be retried with the same key.
+func TestIdempotencyFailedResponse(t *testing.T) {
+	useInMemoryIdempotencyStore(t)
+
+	calls := 0
+	handler := func(c echo.Context) error {
+		calls++
+		if calls == 1 {
+			return c.JSON(http.StatusUnprocessableEntity, map[string]string{"error": "try again"})
+		}
+
+		return c.JSON(http.StatusCreated, map[string]string{})
+	}
+
+	code, _, _ := idempotentRequest(t, handler, "key", `{"name": "source"}`)
+	if code != http.StatusUnprocessableEntity {
+		t.Errorf("want status code %d, got %d", http.StatusUnprocessableEntity, code)
+	}
+
+	code, _, _ = idempotentRequest(t, handler, "key", `{"name": "source"}`)
+	if code != http.StatusCreated {
+		t.Errorf("want status code %d, got %d", http.StatusCreated, code)
+	}
+
+	if calls != 2 {
+		t.Errorf("want the handler to be called twice, got %d calls", calls)
+	}
+}
+
+// TestIdempotencyInProgress tests that a retry sent while the original request is still being processed gets
+// rejected.
+func TestIdempotencyInProgress(t *testing.T) {
+	useInMemoryIdempotencyStore(t)
+
+	var (
+		retryCode int
+		calls     int
+	)
+
+	var handler echo.HandlerFunc
+
+	handler = func(c echo.Context) error {
+		calls++
+
+		// Simulate the retry arriving in the middle of the original request.
+		if calls == 1 {
+			retryCode, _, _ = idempotentRequest(t, handler, "key", `{"name": "source"}`)
+		}
+
+		return c.JSON(http.StatusCreated, map[string]string{})
+	}
+
+	_, _, _ = idempotentRequest(t, handler, "key", `{"name": "source"}`)
+
+	if retryCode != http.StatusConflict {
+		t.Errorf("want status code %d, got %d", http.StatusConflict, retryCode)
+	}
+
+	if calls != 1 {
+		t.Errorf("want the handler to be called once, got %d calls", calls)
+	}
+}
+
+// TestIdempotencyWithoutKey tests that the requests without the header are processed as usual.
+func TestIdempotencyWithoutKey(t *testing.T) {
+	useInMemoryIdempotencyStore(t)
+
+	calls := 0
+	handler := func(c echo.Context) error {
+		calls++
+
+		return c.JSON(http.StatusCreated, map[string]string{})
+	}
+
+	_, _, _ = idempotentRequest(t, handler, "", `{"name": "source"}`)
+	_, _, _ = idempotentRequest(t, handler, "", `{"name": "source"}`)
+
+	if calls != 2 {
+		t.Errorf("want the handler to be called twice, got %d calls", calls)
+	}
+}
+
+// TestIdempotencyKeyTooLong tests that overly long keys get rejected.
+func TestIdempotencyKeyTooLong(t *testing.T) {
+	useInMemoryIdempotencyStore(t)
+
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusCreated, map[string]string{})
+	}
+
+	code, _, _ := idempotentRequest(t, handler, strings.Repeat("a", maxIdempotencyKeyLength+1), `{}`)
+	if code != http.StatusBadRequest {
+		t.Errorf("want status code %d, got %d", http.StatusBadRequest, code)
+	}
+}
@@ -30,6 +30,34 @@ func parsePaginationIntoContext(c echo.Context) error {
 		c.Set("limit", 100)
 	}
 
+	// the lists the client does not sort are sorted by the keyset columns, so
+	// that the cursors of their links point to the same items on every page.
+	filters, _ := c.Get("filters").([]util.Filter)
+	if c.QueryParam("sort_by") == "" {
+		filters = append(filters, util.Filter{Operation: util.KeysetFilterOperation})
+		c.Set("filters", filters)
+	}
+
+	// the cursor takes precedence over the offset, since the page is anchored
+	// on the cursor's item instead.
+	if c.QueryParam("cursor") != "" {
+		if c.QueryParam("sort_by") != "" {
+			return util.NewErrBadRequest("cursor pagination cannot be combined with sort_by")
+		}
+
+		_, err := util.ParseCursor(c.QueryParam("cursor"))
+		if err != nil {
+			return util.NewErrBadRequest(err)
+		}
+
+		c.Set("offset", 0)
+
+		// pass the cursor down to the DAOs along with the rest of the filters.
+		c.Set("filters", append(filters, util.Filter{Operation: util.CursorFilterOperation, Value: []string{c.QueryParam("cursor")}}))
+
+		return nil
+	}
+
 	if c.QueryParam("offset") != "" {
 		val, err := strconv.Atoi(c.QueryParam("offset"))
 		if err != nil {
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
 	"github.com/RedHatInsights/sources-api-go/util"
@@ -118,3 +119,94 @@ func TestParsePaginationBadRequestInvalidOffset(t *testing.T) {
 		t.Error("Error document not formed correctly")
 	}
 }
+
+// TestParsePaginationCursor tests that a valid cursor resets the offset and gets passed down to the DAOs as a filter.
+func TestParsePaginationCursor(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	cursor := util.NewCursor(&createdAt, 25).Encode()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?limit=10&offset=50&cursor="+cursor, nil)
+	c := e.NewContext(req, nil)
+	c.Set("filters", []util.Filter{{Operation: "sort_by", Value: []string{"id ASC"}}})
+
+	err := parsePaginationIntoContext(c)
+	if err != nil {
+		t.Errorf("unexpected error when parsing a valid cursor: %s", err)
+	}
+
+	offset, ok := c.Get("offset").(int)
+	if !ok || offset != 0 {
+		t.Errorf(`want offset "0", got "%v"`, c.Get("offset"))
+	}
+
+	filters, ok := c.Get("filters").([]util.Filter)
+	if !ok {
+		t.Fatal("filters did not get set correctly")
+	}
+
+	got, err := util.CursorFromFilters(filters)
+	if err != nil {
+		t.Fatalf("unexpected error when extracting the cursor from the filters: %s", err)
+	}
+
+	if got == nil || got.ID != 25 || !got.CreatedAt.Equal(createdAt) {
+		t.Errorf(`want cursor anchored on "(%s, 25)", got "%v"`, createdAt, got)
+	}
+}
+
+// TestParsePaginationKeysetSorting tests that the lists the client does not sort get sorted by the keyset columns, and
+// that the sorted lists do not.
+func TestParsePaginationKeysetSorting(t *testing.T) {
+	for path, want := range map[string]bool{
+		"/api/sources/v3.1/sources":              true,
+		"/api/sources/v3.1/sources?sort_by=name": false,
+	} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		c := e.NewContext(req, nil)
+
+		err := parsePaginationIntoContext(c)
+		if err != nil {
+			t.Errorf("unexpected error: %s", err)
+		}
+
+		filters, _ := c.Get("filters").([]util.Filter)
+		if got := util.IsKeysetSorted(filters); got != want {
+			t.Errorf(`want keyset sorting "%t" for "%s", got "%t"`, want, path, got)
+		}
+	}
+}
+
+// TestParsePaginationBadRequestInvalidCursor tests that malformed cursors are rejected.
+func TestParsePaginationBadRequestInvalidCursor(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?cursor=zzzz", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	badRequestParsePaginationIntoContext := HandleErrors(parsePaginationIntoContext)
+
+	err := badRequestParsePaginationIntoContext(c)
+	if err != nil {
+		t.Error("something went very wrong - error parsing pagination.")
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+// TestParsePaginationBadRequestCursorWithSorting tests that the cursors cannot be combined with custom sorting, since
+// the keyset pages are always sorted by the keyset columns.
+func TestParsePaginationBadRequestCursorWithSorting(t *testing.T) {
+	cursor := util.NewCursor(nil, 25).Encode()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?sort_by=name&cursor="+cursor, nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	badRequestParsePaginationIntoContext := HandleErrors(parsePaginationIntoContext)
+
+	err := badRequestParsePaginationIntoContext(c)
+	if err != nil {
+		t.Error("something went very wrong - error parsing pagination.")
+	}
+
+	templates.BadRequestTest(t, rec)
+}
@@ -0,0 +1,181 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// fieldsParamRegex matches the "fields[<resource>]" query parameters.
+var fieldsParamRegex = regexp.MustCompile(`^fields\[(\w+)\]$`)
+
+// bufferedResponseWriter holds the response back, so that it can be modified before sending it to the client.
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) WriteHeader(statusCode int) {
+	w.statusCode = statusCode
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// SparseFieldsets parses the "fields[<resource>]=a,b,c" query parameters, which restrict the fields returned for the
+// given resource types. The fieldsets get stored in the context under the "fieldsets" key, and the fieldset of the
+// resource type the route returns gets stored under the "fields" key, so that the "SortAndFilter" middleware can pass
+// it down to the DAOs. The JSON responses of the route get trimmed down to the requested fields. Unknown resource
+// types or fields are rejected with a "400 Bad Request" response, and so are the fieldsets of the export routes, since
+// the exported documents need every field to be imported back.
+func SparseFieldsets(next echo.HandlerFunc) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		if c.Request().Method != http.MethodGet {
+			return next(c)
+		}
+
+		fieldsets, err := parseFieldsets(c)
+		if err != nil {
+			return util.NewErrBadRequest(err)
+		}
+
+		if len(fieldsets) == 0 {
+			return next(c)
+		}
+
+		if isExportRoute(c.Path()) {
+			return util.NewErrBadRequest("sparse fieldsets are not supported by the exports")
+		}
+
+		c.Set("fieldsets", fieldsets)
+
+		fields, ok := fieldsets[routeResourceType(c.Path())]
+		if !ok {
+			return next(c)
+		}
+
+		c.Set("fields", fields)
+
+		writer := &bufferedResponseWriter{ResponseWriter: c.Response().Writer}
+		c.Response().Writer = writer
+
+		err = next(c)
+
+		c.Response().Writer = writer.ResponseWriter
+
+		// Nothing was written, which means that the error handler is the one responding.
+		if writer.statusCode == 0 {
+			return err
+		}
+
+		body := writer.body.Bytes()
+
+		mediaType, _, _ := mime.ParseMediaType(c.Response().Header().Get(echo.HeaderContentType))
+		if writer.statusCode == http.StatusOK && mediaType == echo.MIMEApplicationJSON {
+			// The related records requested with the "include" parameter are kept.
+			keep := slices.Clone(fields)
+			for _, include := range c.QueryParams()["include"] {
+				for _, relation := range strings.Split(include, ",") {
+					keep = append(keep, strings.TrimSpace(relation))
+				}
+			}
+
+			trimmed, trimErr := util.TrimJSONFields(body, keep)
+			if trimErr != nil {
+				return trimErr
+			}
+
+			body = trimmed
+		}
+
+		writer.ResponseWriter.WriteHeader(writer.statusCode)
+
+		_, writeErr := writer.ResponseWriter.Write(body)
+		if writeErr != nil {
+			c.Logger().Warnf("unable to write the response: %s", writeErr)
+		}
+
+		return err
+	}
+}
+
+// parseFieldsets parses and validates the "fields[<resource>]" query parameters.
+func parseFieldsets(c echo.Context) (map[string][]string, error) {
+	fieldsets := make(map[string][]string)
+
+	for key, values := range c.QueryParams() {
+		if !strings.HasPrefix(key, "fields") {
+			continue
+		}
+
+		matches := fieldsParamRegex.FindStringSubmatch(key)
+		if matches == nil {
+			return nil, fmt.Errorf(`invalid fieldset parameter "%s"`, key)
+		}
+
+		resourceType := matches[1]
+
+		validFields, ok := model.SparseFieldsetFields(resourceType)
+		if !ok {
+			return nil, fmt.Errorf(`invalid fieldset: unknown resource type "%s"`, resourceType)
+		}
+
+		fields := make([]string, 0)
+		for _, value := range values {
+			for _, field := range strings.Split(value, ",") {
+				field = strings.TrimSpace(field)
+				if field == "" {
+					continue
+				}
+
+				if !validFields[field] {
+					return nil, fmt.Errorf(`invalid fieldset: unknown field "%s" for resource type "%s"`, field, resourceType)
+				}
+
+				fields = append(fields, field)
+			}
+		}
+
+		sort.Strings(fields)
+		fieldsets[resourceType] = fields
+	}
+
+	return fieldsets, nil
+}
+
+// routeResourceType returns the resource type the given route returns, which is the last segment of its path that is
+// not a parameter. For example, "/sources/:source_id/applications" returns applications.
+func routeResourceType(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+
+	for i := len(segments) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(segments[i], ":") {
+			return segments[i]
+		}
+	}
+
+	return ""
+}
+
+// isExportRoute returns true when the given route exports resources, such as "/exports/sources" or
+// "/sources/:id/export".
+func isExportRoute(path string) bool {
+	for _, segment := range strings.Split(strings.Trim(path, "/"), "/") {
+		if segment == "export" || segment == "exports" {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,163 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// sparseFieldsetsRequest sends a GET request to the given path through the middleware, and returns the response's
+// status code and body.
+func sparseFieldsetsRequest(t *testing.T, path string, url string, handler echo.HandlerFunc) (int, string) {
+	c, rec := request.CreateTestContext(http.MethodGet, url, nil, map[string]interface{}{})
+	c.SetPath(path)
+
+	err := HandleErrors(SparseFieldsets(handler))(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	return rec.Code, rec.Body.String()
+}
+
+// TestSparseFieldsetsCollection tests that every item of a collection response gets trimmed down to the requested
+// fields, and that the fieldset is passed down as a filter.
+func TestSparseFieldsetsCollection(t *testing.T) {
+	var filters []util.Filter
+
+	handler := SortAndFilter(func(c echo.Context) error {
+		filters, _ = c.Get("filters").([]util.Filter)
+
+		return c.JSON(http.StatusOK, map[string]interface{}{
+			"meta":  map[string]int{"count": 2},
+			"links": map[string]string{"first": "/sources"},
+			"data": []map[string]string{
+				{"id": "1", "name": "a", "uid": "b", "source_type_id": "1"},
+				{"id": "2", "name": "c", "uid": "d", "source_type_id": "1"},
+			},
+		})
+	})
+
+	code, body := sparseFieldsetsRequest(t, "/api/sources/v3.1/sources", "/api/sources/v3.1/sources?fields[sources]=name,uid&fields[applications]=extra", handler)
+	if code != http.StatusOK {
+		t.Errorf("want status code %d, got %d", http.StatusOK, code)
+	}
+
+	var out struct {
+		Meta  map[string]int      `json:"meta"`
+		Links map[string]string   `json:"links"`
+		Data  []map[string]string `json:"data"`
+	}
+
+	err := json.Unmarshal([]byte(body), &out)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the response: %s", err)
+	}
+
+	if out.Meta["count"] != 2 || out.Links["first"] == "" {
+		t.Errorf(`want the "meta" and "links" members untouched, got "%s"`, body)
+	}
+
+	want := []map[string]string{
+		{"id": "1", "name": "a", "uid": "b"},
+		{"id": "2", "name": "c", "uid": "d"},
+	}
+
+	if !reflect.DeepEqual(out.Data, want) {
+		t.Errorf(`want data "%v", got "%v"`, want, out.Data)
+	}
+
+	fields := util.FieldsFromFilters(filters)
+	if !reflect.DeepEqual(fields, []string{"name", "uid"}) {
+		t.Errorf(`want the sources' fieldset to be passed down as a filter, got "%v"`, filters)
+	}
+}
+
+// TestSparseFieldsetsSingleResource tests that single resource responses get trimmed too.
+func TestSparseFieldsetsSingleResource(t *testing.T) {
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"id": "1", "role": "a", "host": "b"})
+	}
+
+	code, body := sparseFieldsetsRequest(t, "/api/sources/v3.1/endpoints/:id", "/api/sources/v3.1/endpoints/1?fields[endpoints]=host", handler)
+	if code != http.StatusOK {
+		t.Errorf("want status code %d, got %d", http.StatusOK, code)
+	}
+
+	if body != `{"host":"b","id":"1"}` {
+		t.Errorf(`unexpected body "%s"`, body)
+	}
+}
+
+// TestSparseFieldsetsOtherResource tests that the responses are left untouched when the fieldsets are for other
+// resource types.
+func TestSparseFieldsetsOtherResource(t *testing.T) {
+	handler := func(c echo.Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"id": "1", "name": "a"})
+	}
+
+	_, body := sparseFieldsetsRequest(t, "/api/sources/v3.1/sources/:id", "/api/sources/v3.1/sources/1?fields[applications]=extra", handler)
+	if body != "{\"id\":\"1\",\"name\":\"a\"}\n" {
+		t.Errorf(`unexpected body "%s"`, body)
+	}
+}
+
+// TestSparseFieldsetsBadRequest tests that the unknown resource types and fields get rejected.
+func TestSparseFieldsetsBadRequest(t *testing.T) {
+	urls := []string{
+		"/api/sources/v3.1/sources?fields[sources]=name,password",
+		"/api/sources/v3.1/sources?fields[unknown]=name",
+		"/api/sources/v3.1/sources?fields[sources][name]=name",
+	}
+
+	for _, url := range urls {
+		calls := 0
+		handler := func(c echo.Context) error {
+			calls++
+
+			return c.NoContent(http.StatusOK)
+		}
+
+		code, _ := sparseFieldsetsRequest(t, "/api/sources/v3.1/sources", url, handler)
+		if code != http.StatusBadRequest {
+			t.Errorf(`want status code %d for "%s", got %d`, http.StatusBadRequest, url, code)
+		}
+
+		if calls != 0 {
+			t.Errorf(`want the handler not to be called for "%s"`, url)
+		}
+	}
+}
+
+// TestSparseFieldsetsExports tests that the fieldsets get rejected on the export routes, which would otherwise end up
+// exporting incomplete resources.
+func TestSparseFieldsetsExports(t *testing.T) {
+	paths := []string{
+		"/api/sources/v3.1/exports/sources",
+		"/api/sources/v3.1/sources/export",
+		"/api/sources/v3.1/sources/:id/export",
+	}
+
+	for _, path := range paths {
+		calls := 0
+		handler := func(c echo.Context) error {
+			calls++
+
+			return c.NoContent(http.StatusOK)
+		}
+
+		code, _ := sparseFieldsetsRequest(t, path, "/api/sources/v3.1/exports/sources?fields[sources]=name", handler)
+		if code != http.StatusBadRequest {
+			t.Errorf(`want status code %d for "%s", got %d`, http.StatusBadRequest, path, code)
+		}
+
+		if calls != 0 {
+			t.Errorf(`want the handler not to be called for "%s"`, path)
+		}
+	}
+}
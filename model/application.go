@@ -78,6 +78,16 @@ func (app *Application) ToResponse() *ApplicationResponse {
 	}
 }
 
+// Cursor returns the keyset cursor which points at the application.
+func (app *Application) Cursor() *util.Cursor {
+	return util.NewCursor(&app.CreatedAt, app.ID)
+}
+
+// ETag returns the entity tag of the application's current representation.
+func (app *Application) ETag() string {
+	return util.NewETagFromUpdatedAt("Application", app.ID, app.UpdatedAt)
+}
+
 func (app *Application) UpdateFromRequest(req *ApplicationEditRequest) {
 	if req.Extra != nil {
 		// handle superkey update
@@ -65,3 +65,8 @@ func (aa *ApplicationAuthentication) ToResponse() *ApplicationAuthenticationResp
 		AuthenticationID: authId,
 	}
 }
+
+// Cursor returns the keyset cursor which points at the application authentication.
+func (aa *ApplicationAuthentication) Cursor() *util.Cursor {
+	return util.NewCursor(&aa.CreatedAt, aa.ID)
+}
@@ -55,6 +55,11 @@ func (a *ApplicationType) ToResponse() *ApplicationTypeResponse {
 	}
 }
 
+// Cursor returns the keyset cursor which points at the application type.
+func (a *ApplicationType) Cursor() *util.Cursor {
+	return util.NewCursor(&a.CreatedAt, a.Id)
+}
+
 // AvailabilityCheckURL returns the application's availability check URL, e.g. where to send the
 // request for the client to re-check the application's availability status.
 func (at *ApplicationType) AvailabilityCheckURL() *url.URL {
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/datatypes"
+)
+
+// The actors which can change the resources.
+const (
+	AuditActorUser    = "user"
+	AuditActorPSK     = "psk"
+	AuditActorCert    = "cert"
+	AuditActorUnknown = "unknown"
+)
+
+// The actions the audit events record.
+const (
+	AuditActionCreate  = "create"
+	AuditActionUpdate  = "update"
+	AuditActionDestroy = "destroy"
+	AuditActionRestore = "restore"
+)
+
+// AuditEvent records a change made to a resource of the tenant: who made it, and which fields changed from what to
+// what.
+type AuditEvent struct {
+	ID        int64     `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+
+	ActorType    string         `json:"actor_type"`
+	ActorID      string         `json:"actor_id"`
+	ResourceType string         `json:"resource_type"`
+	ResourceID   string         `json:"resource_id"`
+	Action       string         `json:"action"`
+	Changes      datatypes.JSON `json:"changes"`
+
+	UserID *int64 `json:"user_id"`
+
+	TenantID int64
+	Tenant   Tenant
+}
+
+// AuditChange is the value of a field before and after a change. Creating a resource has no previous values, and
+// destroying it has no current ones.
+type AuditChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Cursor returns the keyset cursor which points at the audit event.
+func (ae *AuditEvent) Cursor() *util.Cursor {
+	return util.NewCursor(&ae.CreatedAt, ae.ID)
+}
+
+func (ae *AuditEvent) ToResponse() *AuditEventResponse {
+	return &AuditEventResponse{
+		ID:           strconv.FormatInt(ae.ID, 10),
+		CreatedAt:    util.DateTimeToRFC3339(ae.CreatedAt),
+		ActorType:    ae.ActorType,
+		ActorID:      ae.ActorID,
+		ResourceType: ae.ResourceType,
+		ResourceID:   ae.ResourceID,
+		Action:       ae.Action,
+		Changes:      ae.Changes,
+	}
+}
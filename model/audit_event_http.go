@@ -0,0 +1,15 @@
+package model
+
+import "gorm.io/datatypes"
+
+// AuditEventResponse represents an audit event as it is returned to the users of the API.
+type AuditEventResponse struct {
+	ID           string         `json:"id"`
+	CreatedAt    string         `json:"created_at"`
+	ActorType    string         `json:"actor_type"`
+	ActorID      string         `json:"actor_id,omitempty"`
+	ResourceType string         `json:"resource_type"`
+	ResourceID   string         `json:"resource_id"`
+	Action       string         `json:"action"`
+	Changes      datatypes.JSON `json:"changes"`
+}
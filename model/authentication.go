@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
 	"time"
@@ -73,6 +74,47 @@ func (auth *Authentication) ToResponse() *AuthenticationResponse {
 	}
 }
 
+// Cursor returns the keyset cursor which points at the authentication. The "authentications" table does not have a
+// "created_at" column, so the cursor is anchored on the id only. The authentications that do not come from the
+// database, like the Vault ones, cannot be used as anchors.
+func (auth *Authentication) Cursor() *util.Cursor {
+	if auth.DbID == 0 {
+		return nil
+	}
+
+	return util.NewCursor(nil, auth.DbID)
+}
+
+// ETag returns the entity tag of the authentication's current representation. Vault keeps track of the secrets'
+// versions, so the version is used when present. The "authentications" table does not have an "updated_at" column, so
+// in that case the tag is computed out of the stored values instead, with the passwords only taking part through their
+// keyed digest.
+func (auth *Authentication) ETag() string {
+	if auth.Version != "" {
+		return util.NewETag("Authentication", auth.GetID(), auth.Version)
+	}
+
+	// Marshalling a map sorts its keys, so the output is stable.
+	extra, err := json.Marshal(auth.GetExtra())
+	if err != nil {
+		l.Log.Warnf("failed to marshal extra for the entity tag: %v", err)
+	}
+
+	return util.NewETag(
+		"Authentication",
+		auth.GetID(),
+		util.ValueOrBlank(auth.Name),
+		auth.AuthType,
+		util.ValueOrBlank(auth.Username),
+		util.ETagSecretsDigest(util.ValueOrBlank(auth.Password), util.ValueOrBlank(auth.MiqPassword)),
+		string(extra),
+		util.ValueOrBlank(auth.AvailabilityStatus),
+		util.ValueOrBlank(auth.AvailabilityStatusError),
+		auth.ResourceType,
+		strconv.FormatInt(auth.ResourceID, 10),
+	)
+}
+
 func (auth *Authentication) ToSecretResponse() *SecretResponse {
 	return &SecretResponse{
 		ID:       auth.GetID(),
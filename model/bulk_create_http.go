@@ -1,5 +1,7 @@
 package model
 
+import "github.com/RedHatInsights/sources-api-go/util"
+
 /*
 Bulk Create Request is a request creating 1..n resources in Sources API
 which does all of the linking between resources automatically.
@@ -12,6 +14,10 @@ type BulkCreateRequest struct {
 	Applications    []BulkCreateApplication    `json:"applications"`
 	Endpoints       []BulkCreateEndpoint       `json:"endpoints"`
 	Authentications []BulkCreateAuthentication `json:"authentications"`
+	RhcConnections  []BulkCreateRhcConnection  `json:"rhc_connections"`
+	// Encryption holds the parameters the key of the authentications' encrypted passwords was derived with, as
+	// found in the source exports.
+	Encryption *util.PassphraseKDF `json:"encryption,omitempty"`
 }
 
 type BulkCreateResponse struct {
@@ -19,6 +25,24 @@ type BulkCreateResponse struct {
 	Applications    []ApplicationResponse    `json:"applications"`
 	Endpoints       []EndpointResponse       `json:"endpoints"`
 	Authentications []AuthenticationResponse `json:"authentications"`
+	RhcConnections  []RhcConnectionResponse  `json:"rhc_connections"`
+}
+
+// BulkCreateDryRunResponse is the response of a dry run bulk create. It holds the resources that would have been
+// created, linked up with the IDs they would have had, along with the superkey requests that would have been sent.
+type BulkCreateDryRunResponse struct {
+	BulkCreateResponse
+
+	DryRun           bool                        `json:"dry_run"`
+	SuperKeyRequests []BulkCreateSuperKeyRequest `json:"superkey_requests"`
+}
+
+// BulkCreateSuperKeyRequest describes a superkey create request sent for an application of a bulk create.
+type BulkCreateSuperKeyRequest struct {
+	SourceID        string `json:"source_id"`
+	ApplicationID   string `json:"application_id"`
+	ApplicationType string `json:"application_type"`
+	Provider        string `json:"provider"`
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -52,6 +76,17 @@ type BulkCreateAuthentication struct {
 	AuthenticationCreateRequest
 
 	ResourceName string `json:"resource_name"`
+	// SourceName optionally names the source the authentication's resource belongs to, so that the resource can be
+	// told apart from the ones with the same name in other sources of the request.
+	SourceName string `json:"source_name,omitempty"`
+	// EncryptedPassword is the password encrypted with a caller provided key, as found in the source exports.
+	EncryptedPassword *string `json:"encrypted_password,omitempty"`
+}
+
+type BulkCreateRhcConnection struct {
+	RhcConnectionCreateRequest
+
+	SourceName string `json:"source_name"`
 }
 
 /*
@@ -63,6 +98,7 @@ type BulkCreateOutput struct {
 	Endpoints                  []Endpoint
 	Authentications            []Authentication
 	ApplicationAuthentications []ApplicationAuthentication
+	RhcConnections             []RhcConnection
 }
 
 func (b BulkCreateOutput) ToResponse() *BulkCreateResponse {
@@ -71,6 +107,7 @@ func (b BulkCreateOutput) ToResponse() *BulkCreateResponse {
 		Applications:    make([]ApplicationResponse, len(b.Applications)),
 		Endpoints:       make([]EndpointResponse, len(b.Endpoints)),
 		Authentications: make([]AuthenticationResponse, len(b.Authentications)),
+		RhcConnections:  make([]RhcConnectionResponse, len(b.RhcConnections)),
 	}
 
 	for i := 0; i < len(b.Sources); i++ {
@@ -89,5 +126,49 @@ func (b BulkCreateOutput) ToResponse() *BulkCreateResponse {
 		resp.Authentications[i] = *b.Authentications[i].ToResponse()
 	}
 
+	for i := 0; i < len(b.RhcConnections); i++ {
+		resp.RhcConnections[i] = *b.RhcConnections[i].ToResponse()
+	}
+
 	return &resp
 }
+
+// Bulk create partial success result statuses.
+const (
+	// BulkCreateCreated signals that the resource was created.
+	BulkCreateCreated = "created"
+	// BulkCreateFailed signals that the resource could not be created.
+	BulkCreateFailed = "failed"
+)
+
+/*
+Output from the partial success BulkCreate operation. Every source is created
+along with its dependants as an independent unit, so one invalid unit does not
+prevent the rest from being created.
+*/
+type BulkCreatePartialOutput struct {
+	// Created holds the output of every unit which got created.
+	Created []BulkCreateOutput
+	// Results holds the outcome of every resource of the request.
+	Results BulkCreatePartialResponse
+}
+
+// BulkCreatePartialResponse reports the outcome of every resource of a partial success bulk create request, in the
+// same order the resources were sent. On dry runs, the "created" resources are the ones that would have been created.
+type BulkCreatePartialResponse struct {
+	DryRun          bool               `json:"dry_run"`
+	Sources         []BulkCreateResult `json:"sources"`
+	Applications    []BulkCreateResult `json:"applications"`
+	Endpoints       []BulkCreateResult `json:"endpoints"`
+	Authentications []BulkCreateResult `json:"authentications"`
+	RhcConnections  []BulkCreateResult `json:"rhc_connections"`
+}
+
+// BulkCreateResult is the outcome of a single resource of a bulk create request. The index is the position of the
+// resource in the request.
+type BulkCreateResult struct {
+	Index  int    `json:"index"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
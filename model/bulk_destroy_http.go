@@ -0,0 +1,38 @@
+package model
+
+// Bulk destroy result statuses.
+const (
+	// BulkDestroyDeleted signals that the resource and its dependants were deleted.
+	BulkDestroyDeleted = "deleted"
+	// BulkDestroyAccepted signals that the resource is superkey managed, and that it will be deleted asynchronously
+	// once the superkey worker has cleaned up its cloud resources.
+	BulkDestroyAccepted = "accepted"
+	// BulkDestroyFailed signals that the resource could not be deleted.
+	BulkDestroyFailed = "failed"
+)
+
+/*
+Bulk Destroy Request is a request deleting 1..n resources in Sources API, along
+with their dependants. The IDs can be sent either as numbers or as strings.
+*/
+type BulkDestroyRequest struct {
+	Sources         []interface{} `json:"sources"`
+	Applications    []interface{} `json:"applications"`
+	Endpoints       []interface{} `json:"endpoints"`
+	Authentications []interface{} `json:"authentications"`
+}
+
+// BulkDestroyResponse reports the outcome of every requested deletion.
+type BulkDestroyResponse struct {
+	Sources         []BulkDestroyResult `json:"sources"`
+	Applications    []BulkDestroyResult `json:"applications"`
+	Endpoints       []BulkDestroyResult `json:"endpoints"`
+	Authentications []BulkDestroyResult `json:"authentications"`
+}
+
+// BulkDestroyResult is the outcome of a single deletion.
+type BulkDestroyResult struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
@@ -0,0 +1,32 @@
+package model
+
+import "time"
+
+// Bulk import statuses.
+const (
+	// BulkImportQueued signals that the import is waiting for a background worker to pick it up.
+	BulkImportQueued = "queued"
+	// BulkImportRunning signals that a background worker is creating the resources of the import.
+	BulkImportRunning = "running"
+	// BulkImportCompleted signals that every resource of the import has been processed. The individual resources
+	// might still have failed, which is reported in the results.
+	BulkImportCompleted = "completed"
+	// BulkImportFailed signals that the import could not be processed at all.
+	BulkImportFailed = "failed"
+)
+
+// BulkImportResponse reports the progress of a bulk import. The resources are imported in units of a source and its
+// dependants, so the progress is measured in processed units.
+type BulkImportResponse struct {
+	ID        string    `json:"id"`
+	Status    string    `json:"status"`
+	Processed int       `json:"processed"`
+	Total     int       `json:"total"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	// Results holds the outcome of every resource processed so far, with the created resources' IDs and the
+	// failed resources' errors.
+	Results *BulkCreatePartialResponse `json:"results,omitempty"`
+}
@@ -87,6 +87,16 @@ func (endpoint *Endpoint) ToResponse() *EndpointResponse {
 	}
 }
 
+// Cursor returns the keyset cursor which points at the endpoint.
+func (endpoint *Endpoint) Cursor() *util.Cursor {
+	return util.NewCursor(&endpoint.CreatedAt, endpoint.ID)
+}
+
+// ETag returns the entity tag of the endpoint's current representation.
+func (endpoint *Endpoint) ETag() string {
+	return util.NewETagFromUpdatedAt("Endpoint", endpoint.ID, endpoint.UpdatedAt)
+}
+
 func (endpoint *Endpoint) UpdateFromRequest(req *EndpointEditRequest) {
 	if req.Default != nil {
 		endpoint.Default = req.Default
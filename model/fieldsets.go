@@ -0,0 +1,42 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+)
+
+// sparseFieldsetResponses maps the resource types that can be requested in the "fields[<resource>]" query parameters
+// to the responses which hold their fields. The resource types are named after the paths they are served from.
+var sparseFieldsetResponses = map[string]interface{}{
+	"app_meta_data":               MetaDataResponse{},
+	"application_authentications": ApplicationAuthenticationResponse{},
+	"application_types":           ApplicationTypeResponse{},
+	"applications":                ApplicationResponse{},
+	"authentications":             AuthenticationResponse{},
+	"endpoints":                   EndpointResponse{},
+	"rhc_connections":             RhcConnectionResponse{},
+	"secrets":                     SecretResponse{},
+	"source_types":                SourceTypeResponse{},
+	"sources":                     SourceResponse{},
+}
+
+// SparseFieldsetFields returns the fields that can be requested in the sparse fieldset of the given resource type,
+// and false if the resource type does not exist.
+func SparseFieldsetFields(resourceType string) (map[string]bool, bool) {
+	response, ok := sparseFieldsetResponses[resourceType]
+	if !ok {
+		return nil, false
+	}
+
+	fields := make(map[string]bool)
+
+	responseType := reflect.TypeOf(response)
+	for i := 0; i < responseType.NumField(); i++ {
+		name, _, _ := strings.Cut(responseType.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+
+	return fields, true
+}
@@ -45,3 +45,8 @@ func (app *MetaData) ToResponse() *MetaDataResponse {
 		ApplicationTypeId: appTypeId,
 	}
 }
+
+// Cursor returns the keyset cursor which points at the meta data.
+func (app *MetaData) Cursor() *util.Cursor {
+	return util.NewCursor(&app.CreatedAt, app.ID)
+}
@@ -126,6 +126,7 @@ func (relationObject *RelationObject) checkIfPrimaryRecordExists(query *gorm.DB)
 	case Source:
 		query.Model(relationObject.baseObject).
 			Where("tenant_id = ?", relationObject.CurrentTenantID).
+			Where("deleted_at IS NULL").
 			Find(&result, relationObject.Id)
 
 	case SourceType, ApplicationType:
@@ -61,6 +61,16 @@ func (r *RhcConnection) ToResponse() *RhcConnectionResponse {
 	}
 }
 
+// Cursor returns the keyset cursor which points at the rhc connection.
+func (r *RhcConnection) Cursor() *util.Cursor {
+	return util.NewCursor(&r.CreatedAt, r.ID)
+}
+
+// ETag returns the entity tag of the connection's current representation.
+func (r *RhcConnection) ETag() string {
+	return util.NewETagFromUpdatedAt("RhcConnection", r.ID, r.UpdatedAt)
+}
+
 // helper function to pull the source ids from the object.
 func (r *RhcConnection) SourceIDs() []string {
 	sourceIds := make([]string, len(r.Sources))
@@ -11,7 +11,7 @@ type RhcConnectionCreateRequest struct {
 	RhcId       string         `json:"rhc_id"`
 	Extra       datatypes.JSON `json:"extra"`
 	SourceIdRaw interface{}    `json:"source_id"`
-	SourceId    int64
+	SourceId    int64          `json:"-"`
 }
 
 // RhcConnectionEditRequest represents a request coming from the outside to update a Red Hat Connector connection.
@@ -25,6 +25,9 @@ type Source struct {
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
 	PausedAt  *time.Time `json:"paused_at"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"`
+	// PurgeEnqueuedAt is set when the destroy job of an expired superkey source gets enqueued.
+	PurgeEnqueuedAt *time.Time `json:"-"`
 
 	// standard source fields
 	Name                string  `json:"name"`
@@ -93,6 +96,16 @@ func (src *Source) ToResponse() *SourceResponse {
 	}
 }
 
+// Cursor returns the keyset cursor which points at the source.
+func (src *Source) Cursor() *util.Cursor {
+	return util.NewCursor(&src.CreatedAt, src.ID)
+}
+
+// ETag returns the entity tag of the source's current representation.
+func (src *Source) ETag() string {
+	return util.NewETagFromUpdatedAt("Source", src.ID, src.UpdatedAt)
+}
+
 // ToInternalResponse returns only the fields that "sources-monitor-go" requires.
 func (src *Source) ToInternalResponse() *SourceInternalResponse {
 	id := strconv.FormatInt(src.ID, 10)
@@ -48,6 +48,11 @@ func (st *SourceType) ToResponse() *SourceTypeResponse {
 	}
 }
 
+// Cursor returns the keyset cursor which points at the source type.
+func (st *SourceType) Cursor() *util.Cursor {
+	return util.NewCursor(&st.CreatedAt, st.Id)
+}
+
 func (st *SourceType) SuperkeyAuthType() string {
 	if st.SchemaParsed == nil {
 		schema := sourceTypeScheme{}
@@ -0,0 +1,29 @@
+package model
+
+// StatsResponse holds the number of resources which match the filters, grouped by the requested dimensions.
+type StatsResponse struct {
+	GroupBy []string     `json:"group_by"`
+	Total   int64        `json:"total"`
+	Groups  []StatsGroup `json:"groups"`
+}
+
+// StatsGroup is the number of resources which share the same values for the requested dimensions. The values are nil
+// for the resources which do not have one.
+type StatsGroup struct {
+	Dimensions map[string]*string `json:"dimensions"`
+	Count      int64              `json:"count"`
+}
+
+// NewStatsResponse returns the stats of the given groups, which add up to the total.
+func NewStatsResponse(groupBy []string, groups []StatsGroup) *StatsResponse {
+	if groupBy == nil {
+		groupBy = make([]string, 0)
+	}
+
+	var total int64
+	for _, group := range groups {
+		total += group.Count
+	}
+
+	return &StatsResponse{GroupBy: groupBy, Total: total, Groups: groups}
+}
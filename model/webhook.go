@@ -0,0 +1,116 @@
+package model
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/util"
+	"gorm.io/datatypes"
+)
+
+// The states of the webhook deliveries.
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryFailed    = "failed"
+)
+
+// WebhookEventTypes are the event types the webhooks can subscribe to.
+var WebhookEventTypes = []string{
+	"Source.create", "Source.update", "Source.destroy", "Source.pause", "Source.unpause",
+	"Application.create", "Application.update", "Application.destroy", "Application.pause", "Application.unpause",
+	"Endpoint.create", "Endpoint.update", "Endpoint.destroy",
+	"Authentication.create", "Authentication.update", "Authentication.destroy",
+	"ApplicationAuthentication.create", "ApplicationAuthentication.destroy",
+	"RhcConnection.create", "RhcConnection.update", "RhcConnection.destroy",
+}
+
+// Webhook is a tenant's subscription to the events of its resources, which get delivered to the webhook's URL.
+type Webhook struct {
+	ID        int64     `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	URL        string                      `json:"url"`
+	EventTypes datatypes.JSONSlice[string] `json:"event_types"`
+	// Secret is the encrypted key the deliveries get signed with.
+	Secret  string `json:"-"`
+	Enabled bool   `json:"enabled"`
+
+	TenantID int64 `json:"tenant_id"`
+	Tenant   Tenant
+}
+
+func (w *Webhook) UpdateFromRequest(update *WebhookEditRequest) {
+	if update.URL != nil {
+		w.URL = *update.URL
+	}
+
+	if update.EventTypes != nil {
+		w.EventTypes = update.EventTypes
+	}
+
+	if update.Enabled != nil {
+		w.Enabled = *update.Enabled
+	}
+}
+
+func (w *Webhook) ToResponse() *WebhookResponse {
+	return &WebhookResponse{
+		ID:         strconv.FormatInt(w.ID, 10),
+		CreatedAt:  util.DateTimeToRFC3339(w.CreatedAt),
+		UpdatedAt:  util.DateTimeToRFC3339(w.UpdatedAt),
+		URL:        w.URL,
+		EventTypes: w.EventTypes,
+		Enabled:    w.Enabled,
+	}
+}
+
+// Cursor returns the keyset cursor which points at the webhook.
+func (w *Webhook) Cursor() *util.Cursor {
+	return util.NewCursor(&w.CreatedAt, w.ID)
+}
+
+// WebhookDelivery is the delivery of an event to a webhook, which doubles as the delivery's log entry.
+type WebhookDelivery struct {
+	ID        int64     `gorm:"primarykey" json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	EventType      string         `json:"event_type"`
+	Payload        datatypes.JSON `json:"payload"`
+	Status         string         `json:"status"`
+	Attempts       int            `json:"attempts"`
+	NextAttemptAt  *time.Time     `json:"next_attempt_at"`
+	LastAttemptAt  *time.Time     `json:"last_attempt_at"`
+	ResponseStatus *int           `json:"response_status"`
+	Error          string         `json:"error"`
+
+	WebhookID int64 `json:"webhook_id"`
+	Webhook   Webhook
+
+	TenantID int64 `json:"tenant_id"`
+	Tenant   Tenant
+}
+
+func (wd *WebhookDelivery) ToResponse() *WebhookDeliveryResponse {
+	return &WebhookDeliveryResponse{
+		ID:             strconv.FormatInt(wd.ID, 10),
+		CreatedAt:      util.DateTimeToRFC3339(wd.CreatedAt),
+		UpdatedAt:      util.DateTimeToRFC3339(wd.UpdatedAt),
+		EventType:      wd.EventType,
+		Payload:        wd.Payload,
+		Status:         wd.Status,
+		Attempts:       wd.Attempts,
+		NextAttemptAt:  util.DateTimePointerToRFC3339(wd.NextAttemptAt),
+		LastAttemptAt:  util.DateTimePointerToRFC3339(wd.LastAttemptAt),
+		ResponseStatus: wd.ResponseStatus,
+		Error:          wd.Error,
+		WebhookID:      strconv.FormatInt(wd.WebhookID, 10),
+	}
+}
+
+// Cursor returns the keyset cursor which points at the webhook delivery.
+func (wd *WebhookDelivery) Cursor() *util.Cursor {
+	return util.NewCursor(&wd.CreatedAt, wd.ID)
+}
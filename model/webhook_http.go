@@ -0,0 +1,46 @@
+package model
+
+import "gorm.io/datatypes"
+
+// WebhookCreateRequest represents a request coming from the outside to subscribe a webhook to the tenant's events.
+type WebhookCreateRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+// WebhookEditRequest manages what we can update on the webhook. The secret can not be changed, so a new webhook
+// needs to be created in order to rotate it.
+type WebhookEditRequest struct {
+	URL        *string  `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Enabled    *bool    `json:"enabled"`
+}
+
+// WebhookResponse represents what we return to the users of the API. The secret is only returned once, right after
+// creating the webhook.
+type WebhookResponse struct {
+	ID         string   `json:"id"`
+	CreatedAt  string   `json:"created_at"`
+	UpdatedAt  string   `json:"updated_at"`
+	URL        string   `json:"url"`
+	EventTypes []string `json:"event_types"`
+	Enabled    bool     `json:"enabled"`
+	Secret     string   `json:"secret,omitempty"`
+}
+
+// WebhookDeliveryResponse represents a webhook delivery as it is returned to the users of the API.
+type WebhookDeliveryResponse struct {
+	ID             string         `json:"id"`
+	CreatedAt      string         `json:"created_at"`
+	UpdatedAt      string         `json:"updated_at"`
+	EventType      string         `json:"event_type"`
+	Payload        datatypes.JSON `json:"payload"`
+	Status         string         `json:"status"`
+	Attempts       int            `json:"attempts"`
+	NextAttemptAt  string         `json:"next_attempt_at,omitempty"`
+	LastAttemptAt  string         `json:"last_attempt_at,omitempty"`
+	ResponseStatus *int           `json:"response_status,omitempty"`
+	Error          string         `json:"error,omitempty"`
+	WebhookID      string         `json:"webhook_id"`
+}
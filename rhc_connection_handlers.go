@@ -49,7 +49,7 @@ func RhcConnectionList(c echo.Context) error {
 		out[i] = rhcConnections[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(rhcConnections)...))
 }
 
 func RhcConnectionGetById(c echo.Context) error {
@@ -70,7 +70,7 @@ func RhcConnectionGetById(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, rhcConnection.ToResponse())
+	return sendResource(c, rhcConnection, rhcConnection.ToResponse())
 }
 
 func RhcConnectionCreate(c echo.Context) error {
@@ -117,9 +117,14 @@ func RhcConnectionEdit(c echo.Context) error {
 
 	input := &model.RhcConnectionEditRequest{}
 
-	err = c.Bind(input)
-	if err != nil {
-		return err
+	// The regular payloads can be bound straight away, but the patch documents need to be applied on top of the
+	// current connection.
+	patchDocument := isPatchDocument(c)
+	if !patchDocument {
+		err = c.Bind(input)
+		if err != nil {
+			return err
+		}
 	}
 
 	rhcConnectionDao, err := getRhcConnectionDao(c)
@@ -132,14 +137,37 @@ func RhcConnectionEdit(c echo.Context) error {
 		return err
 	}
 
+	err = checkIfMatch(c, dbRhcConnection)
+	if err != nil {
+		return err
+	}
+
+	// Keep the fetched version, so that the update does not go through if the connection gets modified in the meantime.
+	updatedAt := dbRhcConnection.UpdatedAt
+
+	setAuditPrevious(c, dbRhcConnection)
+
+	if patchDocument {
+		err = bindEditRequest(c, dbRhcConnection.ToResponse(), input)
+		if err != nil {
+			return err
+		}
+	}
+
 	dbRhcConnection.UpdateFromRequest(input)
 
-	err = rhcConnectionDao.Update(dbRhcConnection)
+	if hasIfMatch(c) {
+		err = rhcConnectionDao.UpdateIfUnmodified(dbRhcConnection, updatedAt)
+	} else {
+		err = rhcConnectionDao.Update(dbRhcConnection)
+	}
+
 	if err != nil {
 		return err
 	}
 
 	setEventStreamResource(c, dbRhcConnection)
+	setETag(c, dbRhcConnection)
 
 	return c.JSON(http.StatusOK, dbRhcConnection.ToResponse())
 }
@@ -157,7 +185,25 @@ func RhcConnectionDelete(c echo.Context) error {
 		return err
 	}
 
-	rhcConnection, err := rhcConnectionDao.Delete(&rhcConnectionId)
+	// The connection only needs to be fetched when the client wants to make sure it is deleting the representation
+	// it last saw. In that case, the deletion only goes through if it has not been modified in between.
+	var rhcConnection *model.RhcConnection
+	if hasIfMatch(c) {
+		rhcConnection, err = rhcConnectionDao.GetById(&rhcConnectionId)
+		if err != nil {
+			return err
+		}
+
+		err = checkIfMatch(c, rhcConnection)
+		if err != nil {
+			return err
+		}
+
+		rhcConnection, err = rhcConnectionDao.DeleteIfUnmodified(&rhcConnectionId, rhcConnection.UpdatedAt)
+	} else {
+		rhcConnection, err = rhcConnectionDao.Delete(&rhcConnectionId)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -213,5 +259,5 @@ func RhcConnectionSourcesList(c echo.Context) error {
 		out[i] = sources[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(sources)...))
 }
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
@@ -89,7 +90,7 @@ func TestRhcConnectionList(t *testing.T) {
 		}
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 func TestRhcConnectionListTenantNotExists(t *testing.T) {
@@ -605,6 +606,87 @@ func TestRhcConnectionEdit(t *testing.T) {
 	}
 }
 
+// TestRhcConnectionEditMergePatch tests that a JSON Merge Patch document gets applied on top of the connection.
+func TestRhcConnectionEditMergePatch(t *testing.T) {
+	rhcId := fixtures.TestRhcConnectionData[2].ID
+
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		fmt.Sprintf("/api/sources/v3.1/rhc_connections/%d", rhcId),
+		strings.NewReader(`{"extra": {"hello": "world", "ignored": null}}`),
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.Request().Header.Add("Content-Type", util.MergePatchContentType)
+
+	c.SetParamNames("id")
+	c.SetParamValues(fmt.Sprintf("%d", rhcId))
+
+	err := RhcConnectionEdit(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Want status code %d. Got %d. Body: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var response model.RhcConnectionResponse
+
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Errorf("unable to unmarshal the response: %s", err)
+	}
+
+	want := `{"hello":"world"}`
+	if string(response.Extra) != want {
+		t.Errorf(`want extra "%s", got "%s"`, want, string(response.Extra))
+	}
+}
+
+// TestRhcConnectionEditJSONPatch tests that the JSON Patch documents get applied on top of the connection, and that
+// the patches which try to modify read only members or whose tests fail get rejected.
+func TestRhcConnectionEditJSONPatch(t *testing.T) {
+	rhcId := fixtures.TestRhcConnectionData[2].ID
+
+	testCases := []struct {
+		patch    string
+		wantCode int
+	}{
+		{patch: `[{"op": "add", "path": "/extra", "value": {"hello": "world"}}]`, wantCode: http.StatusOK},
+		{patch: `[{"op": "test", "path": "/rhc_id", "value": "different"}, {"op": "add", "path": "/extra", "value": {}}]`, wantCode: http.StatusConflict},
+		{patch: `[{"op": "replace", "path": "/rhc_id", "value": "different"}]`, wantCode: http.StatusBadRequest},
+		{patch: `[{"op": "remove", "path": "/unknown"}]`, wantCode: http.StatusBadRequest},
+	}
+
+	for _, tc := range testCases {
+		c, rec := request.CreateTestContext(
+			http.MethodPatch,
+			fmt.Sprintf("/api/sources/v3.1/rhc_connections/%d", rhcId),
+			strings.NewReader(tc.patch),
+			map[string]interface{}{
+				"tenantID": int64(1),
+			},
+		)
+
+		c.Request().Header.Add("Content-Type", util.JSONPatchContentType)
+
+		c.SetParamNames("id")
+		c.SetParamValues(fmt.Sprintf("%d", rhcId))
+
+		err := ErrorHandlingContext(RhcConnectionEdit)(c)
+		if err != nil {
+			t.Error(err)
+		}
+
+		if rec.Code != tc.wantCode {
+			t.Errorf("patch %s: want status code %d. Got %d. Body: %s", tc.patch, tc.wantCode, rec.Code, rec.Body.String())
+		}
+	}
+}
+
 // TestRhcConnectionEditInvalidTenant tests situation when the tenant tries to
 // edit existing not owned rhc connection
 func TestRhcConnectionEditInvalidTenant(t *testing.T) {
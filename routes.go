@@ -31,6 +31,9 @@ func setupRoutes(e *echo.Echo, superKeySvc *service.SuperKeyService, metricsServ
 	// Set up the middlewares.
 	permissionCheckMiddleware := middleware.PermissionCheck(config.Get().BypassRbac, config.Get().AuthorizedPsks, rbacClient)
 
+	// The GraphQL mutations are authorized the same way as the REST API's write operations.
+	graphQLHandler := GraphQLQuery(superKeySvc, middleware.NewAuthorizer(config.Get().BypassRbac, config.Get().AuthorizedPsks, rbacClient))
+
 	var (
 		listMiddleware    = []echo.MiddlewareFunc{middleware.SortAndFilter, middleware.Pagination}
 		tenancyMiddleware = []echo.MiddlewareFunc{middleware.Tenancy, middleware.LoggerFields, middleware.UserCatcher}
@@ -39,7 +42,7 @@ func setupRoutes(e *echo.Echo, superKeySvc *service.SuperKeyService, metricsServ
 	var (
 		tenancyWithListMiddleware         = append(tenancyMiddleware, listMiddleware...)
 		permissionMiddlewareWithoutEvents = append(tenancyMiddleware, permissionCheckMiddleware)
-		permissionMiddleware              = append(permissionMiddlewareWithoutEvents, middleware.RaiseEvent)
+		permissionMiddleware              = append(permissionMiddlewareWithoutEvents, middleware.AuditTrail, middleware.RaiseEvent)
 		permissionWithListMiddleware      = append(listMiddleware, permissionCheckMiddleware)
 	)
 
@@ -51,20 +54,33 @@ func setupRoutes(e *echo.Echo, superKeySvc *service.SuperKeyService, metricsServ
 			middleware.HandleErrors,
 			middleware.IdValidation,
 			middleware.ParseHeaders,
+			middleware.SparseFieldsets,
 		)
 
 		// openapi
 		r.GET("/openapi.json", PublicOpenApi(version))
 
 		// Bulk Create
-		r.POST("/bulk_create", BulkCreate(superKeySvc), permissionMiddleware...)
+		r.POST("/bulk_create", BulkCreate(superKeySvc), append(permissionMiddleware, middleware.Idempotency)...)
+
+		// Bulk Destroy
+		r.POST("/bulk_destroy", BulkDestroy, permissionMiddleware...)
+
+		// Bulk Imports
+		r.POST("/bulk_imports", BulkImportCreate, append(permissionMiddleware, middleware.Idempotency)...)
+		r.GET("/bulk_imports/:uid", BulkImportGet, append(tenancyMiddleware, middleware.UuidValidation)...)
 
 		// Sources
 		r.GET("/sources", SourceList, tenancyWithListMiddleware...)
 		r.GET("/sources/:id", SourceGet, tenancyMiddleware...)
-		r.POST("/sources", SourceCreate, permissionMiddleware...)
+		r.GET("/sources/stats", SourceStats, append(tenancyMiddleware, middleware.SortAndFilter)...)
+		r.GET("/sources/export", SourceExportList, append([]echo.MiddlewareFunc{middleware.SortAndFilter}, permissionMiddlewareWithoutEvents...)...)
+		r.GET("/sources/:id/export", SourceExport, permissionMiddlewareWithoutEvents...)
+		r.GET("/sources/:id/history", SourceHistory, tenancyWithListMiddleware...)
+		r.POST("/sources", SourceCreate, append(permissionMiddleware, middleware.Idempotency)...)
 		r.PATCH("/sources/:id", SourceEdit, append(permissionMiddleware, middleware.Notifier)...)
 		r.DELETE("/sources/:id", SourceDelete, permissionMiddleware...)
+		r.POST("/sources/:id/restore", SourceRestore, permissionMiddleware...)
 		r.POST("/sources/:source_id/check_availability", SourceCheckAvailability(metricsService), middleware.Tenancy, middleware.LoggerFields)
 		r.GET("/sources/:source_id/application_types", SourceListApplicationTypes, tenancyWithListMiddleware...)
 		r.GET("/sources/:source_id/applications", SourceListApplications, tenancyWithListMiddleware...)
@@ -74,10 +90,29 @@ func setupRoutes(e *echo.Echo, superKeySvc *service.SuperKeyService, metricsServ
 		r.POST("/sources/:source_id/pause", SourcePause, tenancyMiddleware...)
 		r.POST("/sources/:source_id/unpause", SourceUnpause, tenancyMiddleware...)
 
+		// Audit events
+		r.GET("/audit_events", AuditEventList, tenancyWithListMiddleware...)
+
+		// Webhooks
+		r.GET("/webhooks", WebhookList, tenancyWithListMiddleware...)
+		r.GET("/webhooks/:id", WebhookGet, tenancyMiddleware...)
+		r.POST("/webhooks", WebhookCreate, permissionMiddlewareWithoutEvents...)
+		r.PATCH("/webhooks/:id", WebhookEdit, permissionMiddlewareWithoutEvents...)
+		r.DELETE("/webhooks/:id", WebhookDelete, permissionMiddlewareWithoutEvents...)
+		r.GET("/webhooks/:id/deliveries", WebhookDeliveryList, tenancyWithListMiddleware...)
+
+		// Event stream
+		r.GET("/events/stream", EventStream, tenancyMiddleware...)
+
+		// Exports
+		r.GET("/exports/sources", SourceExportStream, append(tenancyMiddleware, middleware.SortAndFilter)...)
+		r.GET("/exports/applications", ApplicationExportStream, append(tenancyMiddleware, middleware.SortAndFilter)...)
+
 		// Applications
 		r.GET("/applications", ApplicationList, tenancyWithListMiddleware...)
+		r.GET("/applications/stats", ApplicationStats, append(tenancyMiddleware, middleware.SortAndFilter)...)
 		r.GET("/applications/:id", ApplicationGet, tenancyMiddleware...)
-		r.POST("/applications", ApplicationCreate(superKeySvc), permissionMiddleware...)
+		r.POST("/applications", ApplicationCreate(superKeySvc), append(permissionMiddleware, middleware.Idempotency)...)
 		r.PATCH("/applications/:id", ApplicationEdit, append(permissionMiddleware, middleware.Notifier)...)
 		r.DELETE("/applications/:id", ApplicationDelete, permissionMiddleware...)
 		r.GET("/applications/:application_id/authentications", ApplicationListAuthentications, tenancyWithListMiddleware...)
@@ -146,7 +181,10 @@ func setupRoutes(e *echo.Echo, superKeySvc *service.SuperKeyService, metricsServ
 		r.GET("/rhc_connections/:id/sources", RhcConnectionSourcesList, tenancyWithListMiddleware...)
 
 		// GraphQL
-		r.POST("/graphql", GraphQLQuery, tenancyMiddleware...)
+		r.POST("/graphql", graphQLHandler, tenancyMiddleware...)
+		// the subscriptions upgrade the GET requests to WebSocket connections,
+		// and the rest of the GET requests run cacheable queries.
+		r.GET("/graphql", graphQLHandler, tenancyMiddleware...)
 
 		// run the graphQL playground if running locally or in ephemeral. really handy for development!
 		// https://github.com/graphql/graphiql
@@ -171,5 +209,9 @@ func setupRoutes(e *echo.Echo, superKeySvc *service.SuperKeyService, metricsServ
 		// Tenant translation endpoints.
 		r.GET("/untranslated-tenants", GetUntranslatedTenants)
 		r.POST("/translate-tenants", TranslateTenants)
+
+		// GraphQL queries allowed in the persisted query allowlist mode. The allowlist is shared by every tenant, so
+		// registering queries requires the same permissions as the other write operations.
+		r.POST("/graphql/persisted_queries", InternalGraphQLPersistedQueryCreate, permissionMiddlewareWithoutEvents...)
 	}
 }
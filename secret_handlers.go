@@ -98,7 +98,7 @@ func SecretList(c echo.Context) error {
 		out = append(out, *secret.ToSecretResponse())
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(secrets)...))
 }
 
 func SecretGet(c echo.Context) error {
@@ -128,9 +128,14 @@ func SecretEdit(c echo.Context) error {
 
 	updateRequest := &m.SecretEditRequest{}
 
-	err = c.Bind(updateRequest)
-	if err != nil {
-		return err
+	// The regular payloads can be bound straight away, but the patch documents need to be applied on top of the
+	// current secret.
+	patchDocument := isPatchDocument(c)
+	if !patchDocument {
+		err = c.Bind(updateRequest)
+		if err != nil {
+			return err
+		}
 	}
 
 	paramID, err := util.InterfaceToInt64(c.Param("id"))
@@ -143,6 +148,13 @@ func SecretEdit(c echo.Context) error {
 		return err
 	}
 
+	if patchDocument {
+		err = bindEditRequest(c, secret.ToSecretResponse(), updateRequest)
+		if err != nil {
+			return err
+		}
+	}
+
 	err = secret.UpdateSecretFromRequest(updateRequest)
 	if err != nil {
 		return util.NewErrBadRequest(err)
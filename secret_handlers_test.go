@@ -317,7 +317,7 @@ func TestSecretList(t *testing.T) {
 		t.Errorf("Some secret IDs are missing, obtained: %v expected: %v", foundIDs, out.Data)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 
 	secret1ID, err := util.InterfaceToString(secret1.DbID)
 	if err != nil {
@@ -420,7 +420,7 @@ func TestSecretListWithFilter(t *testing.T) {
 		t.Errorf("Some secret IDs are missing, obtained: %v expected: %v", foundIDs, out.Data)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 
 	secret1ID, err := util.InterfaceToString(secret1.DbID)
 	if err != nil {
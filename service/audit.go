@@ -0,0 +1,198 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/model"
+)
+
+// auditMaskedValue replaces the values of the secret fields in the audit events.
+const auditMaskedValue = "******"
+
+// auditSecretFields are the substrings which mark a field as a secret, wherever it is nested in the resource.
+var auditSecretFields = []string{"password", "secret", "token", "private_key"}
+
+// auditIgnoredFields are the fields which change along with any other field, so recording them adds nothing.
+var auditIgnoredFields = map[string]bool{"updated_at": true}
+
+// AuditSnapshot returns the representation of the resource which the audit events compare, which is the same one the
+// events get raised with.
+func AuditSnapshot(resource model.Event) ([]byte, error) {
+	snapshot, err := json.Marshal(resource.ToEvent())
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %+v as an audit snapshot: %w", resource, err)
+	}
+
+	return snapshot, nil
+}
+
+// NewAuditEvent builds the audit event of the given event type, such as "Source.update", out of the resource's
+// snapshots before and after the change. The created resources do not have a previous snapshot and the destroyed ones
+// do not have a current one. The secret fields are masked, so the changes only show that they changed. When an update
+// does not change anything, no audit event is returned.
+func NewAuditEvent(eventType string, previous []byte, current []byte) (*model.AuditEvent, error) {
+	separator := strings.LastIndex(eventType, ".")
+	if separator < 1 {
+		return nil, fmt.Errorf(`invalid event type "%s"`, eventType)
+	}
+
+	resourceType, action := eventType[:separator], eventType[separator+1:]
+
+	switch action {
+	case model.AuditActionCreate, model.AuditActionUpdate, model.AuditActionDestroy, model.AuditActionRestore:
+	default:
+		return nil, fmt.Errorf(`invalid action "%s" for the audit event`, action)
+	}
+
+	previousFields, err := decodeAuditSnapshot(previous)
+	if err != nil {
+		return nil, err
+	}
+
+	currentFields, err := decodeAuditSnapshot(current)
+	if err != nil {
+		return nil, err
+	}
+
+	resourceID := auditResourceID(currentFields)
+	if resourceID == "" {
+		resourceID = auditResourceID(previousFields)
+	}
+
+	if resourceID == "" {
+		return nil, fmt.Errorf(`unable to find the id of the "%s" resource`, resourceType)
+	}
+
+	changes := make(map[string]model.AuditChange)
+
+	for field, value := range currentFields {
+		if !auditIgnoredFields[field] && !reflect.DeepEqual(previousFields[field], value) {
+			changes[field] = model.AuditChange{From: maskAuditSecrets(field, previousFields[field]), To: maskAuditSecrets(field, value)}
+		}
+	}
+
+	for field, value := range previousFields {
+		if _, ok := currentFields[field]; !ok && !auditIgnoredFields[field] && value != nil {
+			changes[field] = model.AuditChange{From: maskAuditSecrets(field, value), To: nil}
+		}
+	}
+
+	if action == model.AuditActionUpdate && len(changes) == 0 {
+		return nil, nil
+	}
+
+	encodedChanges, err := json.Marshal(changes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal the audit changes: %w", err)
+	}
+
+	return &model.AuditEvent{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Action:       action,
+		Changes:      encodedChanges,
+	}, nil
+}
+
+// RecordAuditEvent records the change of the given event type, such as "Source.update", which the actor made to the
+// resource. The previous snapshot is the one taken before the change, and the destroyed resources are recorded in the
+// state they were destroyed in. Nothing is recorded when nothing changed.
+func RecordAuditEvent(requestParams *dao.RequestParams, eventType string, resource model.Event, previous []byte, actorType, actorID string) error {
+	snapshot, err := AuditSnapshot(resource)
+	if err != nil {
+		return err
+	}
+
+	current := snapshot
+
+	// the destroyed resources are only around in their last state.
+	if strings.HasSuffix(eventType, "."+model.AuditActionDestroy) {
+		previous, current = snapshot, nil
+	}
+
+	auditEvent, err := NewAuditEvent(eventType, previous, current)
+	if err != nil {
+		return err
+	}
+
+	// nothing changed, so there is nothing to record.
+	if auditEvent == nil {
+		return nil
+	}
+
+	auditEvent.ActorType, auditEvent.ActorID = actorType, actorID
+
+	return dao.GetAuditEventDao(requestParams).Create(auditEvent)
+}
+
+// decodeAuditSnapshot decodes the fields of the given snapshot, keeping the numbers as they are so that the IDs do
+// not lose any precision.
+func decodeAuditSnapshot(snapshot []byte) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+
+	if len(snapshot) == 0 {
+		return fields, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(snapshot))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&fields)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the audit snapshot: %w", err)
+	}
+
+	return fields, nil
+}
+
+// auditResourceID returns the "id" field of the decoded snapshot, which is either a number or, for the
+// authentications stored in Vault, a string.
+func auditResourceID(fields map[string]interface{}) string {
+	switch id := fields["id"].(type) {
+	case json.Number:
+		return id.String()
+	case string:
+		return id
+	default:
+		return ""
+	}
+}
+
+// maskAuditSecrets replaces the value of the given field with a mask if the field is a secret. The objects and the
+// arrays get their nested secret fields masked too.
+func maskAuditSecrets(field string, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+
+	lowerField := strings.ToLower(field)
+	for _, secret := range auditSecretFields {
+		if strings.Contains(lowerField, secret) {
+			return auditMaskedValue
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		masked := make(map[string]interface{}, len(v))
+		for key, nested := range v {
+			masked[key] = maskAuditSecrets(key, nested)
+		}
+
+		return masked
+	case []interface{}:
+		masked := make([]interface{}, len(v))
+		for i, nested := range v {
+			masked[i] = maskAuditSecrets("", nested)
+		}
+
+		return masked
+	default:
+		return value
+	}
+}
@@ -0,0 +1,111 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/model"
+)
+
+// unmarshalAuditChanges unmarshals the changes of the given audit event.
+func unmarshalAuditChanges(t *testing.T, auditEvent *model.AuditEvent) map[string]model.AuditChange {
+	var changes map[string]model.AuditChange
+
+	err := json.Unmarshal(auditEvent.Changes, &changes)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the changes: %s", err)
+	}
+
+	return changes
+}
+
+// TestNewAuditEvent tests that only the changed fields get recorded, with the nested secrets masked.
+func TestNewAuditEvent(t *testing.T) {
+	previous := []byte(`{"id": 12345678901234567, "name": "a", "updated_at": "2020", "extra": {"role": "x", "client_secret": "s1"}, "version": "1"}`)
+	current := []byte(`{"id": 12345678901234567, "name": "b", "updated_at": "2021", "extra": {"role": "x", "client_secret": "s2"}}`)
+
+	auditEvent, err := NewAuditEvent("Source.update", previous, current)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if auditEvent.ResourceType != "Source" || auditEvent.ResourceID != "12345678901234567" || auditEvent.Action != model.AuditActionUpdate {
+		t.Errorf(`unexpected audit event "%+v"`, auditEvent)
+	}
+
+	changes := unmarshalAuditChanges(t, auditEvent)
+
+	if len(changes) != 3 {
+		t.Errorf(`want the "name", "extra" and "version" changes, got "%+v"`, changes)
+	}
+
+	if changes["name"].From != "a" || changes["name"].To != "b" {
+		t.Errorf(`unexpected name change "%+v"`, changes["name"])
+	}
+
+	if changes["version"].From != "1" || changes["version"].To != nil {
+		t.Errorf(`want the removed version recorded, got "%+v"`, changes["version"])
+	}
+
+	extra, ok := changes["extra"].To.(map[string]interface{})
+	if !ok || extra["client_secret"] != auditMaskedValue || extra["role"] != "x" {
+		t.Errorf(`want the extra's secret masked, got "%+v"`, changes["extra"])
+	}
+}
+
+// TestNewAuditEventCreateAndDestroy tests that the created and the destroyed resources get every field recorded.
+func TestNewAuditEventCreateAndDestroy(t *testing.T) {
+	snapshot := []byte(`{"id": "uuid", "password": "secret", "username": "user"}`)
+
+	created, err := NewAuditEvent("Authentication.create", nil, snapshot)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	changes := unmarshalAuditChanges(t, created)
+	if created.ResourceID != "uuid" || changes["username"].From != nil || changes["username"].To != "user" || changes["password"].To != auditMaskedValue {
+		t.Errorf(`unexpected created audit event "%+v"`, changes)
+	}
+
+	destroyed, err := NewAuditEvent("Authentication.destroy", snapshot, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	changes = unmarshalAuditChanges(t, destroyed)
+	if destroyed.Action != model.AuditActionDestroy || changes["username"].From != "user" || changes["username"].To != nil {
+		t.Errorf(`unexpected destroyed audit event "%+v"`, changes)
+	}
+}
+
+// TestNewAuditEventNoChanges tests that the updates which do not change anything are not recorded.
+func TestNewAuditEventNoChanges(t *testing.T) {
+	auditEvent, err := NewAuditEvent("Source.update", []byte(`{"id": 1, "updated_at": "2020"}`), []byte(`{"id": 1, "updated_at": "2021"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if auditEvent != nil {
+		t.Errorf(`want no audit event, got "%+v"`, auditEvent)
+	}
+}
+
+// TestNewAuditEventInvalid tests that the unknown event types and the resources without an id are rejected.
+func TestNewAuditEventInvalid(t *testing.T) {
+	testCases := []struct {
+		eventType string
+		current   []byte
+	}{
+		{eventType: "Source", current: []byte(`{"id": 1}`)},
+		{eventType: "Source.pause", current: []byte(`{"id": 1}`)},
+		{eventType: "Source.create", current: []byte(`{"name": "a"}`)},
+		{eventType: "Source.create", current: []byte(`[]`)},
+	}
+
+	for _, tc := range testCases {
+		_, err := NewAuditEvent(tc.eventType, nil, tc.current)
+		if err == nil {
+			t.Errorf(`want an error for the "%s" event type with "%s", got none`, tc.eventType, tc.current)
+		}
+	}
+}
@@ -6,16 +6,22 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/kafka"
 	l "github.com/RedHatInsights/sources-api-go/logger"
 	m "github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 	"gorm.io/gorm/clause"
 )
 
+// errDryRunRollback is returned from the dry run transactions so that they always get rolled back.
+var errDryRunRollback = errors.New("bulk create dry run")
+
 /*
 Oh boy. The big one.
 
@@ -32,6 +38,22 @@ given the *_type_name paremeters.
 4. Saving the ApplicationAuthentications if necessary
 */
 func BulkAssembly(req m.BulkCreateRequest, tenant *m.Tenant, user *m.User) (*m.BulkCreateOutput, error) {
+	return bulkAssembly(req, tenant, user, false)
+}
+
+// BulkAssemblyDryRun validates and links up the resources of the request exactly like "BulkAssembly" does, but inside
+// a transaction which always gets rolled back. The authentications are not written to the secret store. The returned
+// output holds the resources that would have been created, with the IDs the transaction generated for them.
+func BulkAssemblyDryRun(req m.BulkCreateRequest, tenant *m.Tenant, user *m.User) (*m.BulkCreateOutput, error) {
+	output, err := bulkAssembly(req, tenant, user, true)
+	if errors.Is(err, errDryRunRollback) {
+		return output, nil
+	}
+
+	return output, err
+}
+
+func bulkAssembly(req m.BulkCreateRequest, tenant *m.Tenant, user *m.User, dryRun bool) (*m.BulkCreateOutput, error) {
 	// the output from this request.
 	var output m.BulkCreateOutput
 
@@ -53,7 +75,7 @@ func BulkAssembly(req m.BulkCreateRequest, tenant *m.Tenant, user *m.User) (*m.B
 		}
 
 		err = tx.Omit(clause.Associations).Create(&output.Sources).Error
-		if err != nil {
+		if err != nil && !errors.Is(err, gorm.ErrEmptySlice) {
 			return err
 		}
 
@@ -77,6 +99,18 @@ func BulkAssembly(req m.BulkCreateRequest, tenant *m.Tenant, user *m.User) (*m.B
 			return err
 		}
 
+		output.RhcConnections, err = parseRhcConnections(req.RhcConnections, &output)
+		if err != nil {
+			return err
+		}
+
+		for i := range output.RhcConnections {
+			err = createRhcConnection(tx, &output.RhcConnections[i], tenant)
+			if err != nil {
+				return err
+			}
+		}
+
 		// link up the authentications to their polymorphic relations.
 		output.Authentications, err = linkUpAuthentications(req, &output, tenant, userResource)
 		if err != nil {
@@ -84,7 +118,12 @@ func BulkAssembly(req m.BulkCreateRequest, tenant *m.Tenant, user *m.User) (*m.B
 		}
 
 		for i := 0; i < len(output.Authentications); i++ {
-			err = dao.GetAuthenticationDao(&dao.RequestParams{TenantID: &tenant.Id}).BulkCreate(&output.Authentications[i])
+			if dryRun {
+				err = dryRunAuthentication(tx, &output.Authentications[i])
+			} else {
+				err = dao.GetAuthenticationDao(&dao.RequestParams{TenantID: &tenant.Id}).BulkCreate(&output.Authentications[i])
+			}
+
 			if err != nil {
 				return err
 			}
@@ -123,12 +162,32 @@ func BulkAssembly(req m.BulkCreateRequest, tenant *m.Tenant, user *m.User) (*m.B
 			return fmt.Errorf(`unable to override the sources' or applications' availability status to "available": %w`, err)
 		}
 
+		if dryRun {
+			return errDryRunRollback
+		}
+
 		return nil
 	})
 
 	return &output, err
 }
 
+// dryRunAuthentication stands in for the secret store when dry running a bulk create. The authentication's secret is
+// dropped, and the authentication gets stored in the given transaction just to obtain its ID. The Vault
+// authentications get their ID generated the same way the Vault DAO does.
+func dryRunAuthentication(tx *gorm.DB, auth *m.Authentication) error {
+	auth.Password = nil
+
+	if config.IsVaultOn() {
+		auth.ID = uuid.New().String()
+		auth.CreatedAt = time.Now()
+
+		return nil
+	}
+
+	return tx.Omit(clause.Associations).Create(auth).Error
+}
+
 func findApplicationTypeIdByApplicationID(applicationID int64, applications []m.Application) *int64 {
 	for _, currentApplication := range applications {
 		if currentApplication.ID == applicationID {
@@ -279,11 +338,16 @@ func parseEndpoints(reqEndpoints []m.BulkCreateEndpoint, current *m.BulkCreateOu
 			return nil, util.NewErrBadRequest(err)
 		}
 
+		e.Default = &endpt.Default
+		e.ReceptorNode = endpt.ReceptorNode
+		e.Role = &endpt.Role
 		e.Scheme = endpt.Scheme
 		e.Host = &endpt.Host
 		e.Path = &endpt.Path
 		e.Port = endpt.Port
 		e.VerifySsl = endpt.VerifySsl
+		e.CertificateAuthority = endpt.CertificateAuthority
+		e.AvailabilityStatus = endpt.AvailabilityStatus
 		e.SourceID = endpt.SourceID
 		e.Tenant = *tenant
 		e.TenantID = tenant.Id
@@ -362,39 +426,51 @@ func linkUpAuthentications(req m.BulkCreateRequest, current *m.BulkCreateOutput,
 			}
 		}
 
+		// there is nothing to link the authentication up to other than an existing resource.
+		if len(current.Sources) == 0 {
+			return nil, util.NewErrBadRequest("failed to link authentication: the resource does not exist")
+		}
+
+		// the source the authentication's resource belongs to, which is the first one of the request unless the
+		// authentication names it.
+		source, applications, endpoints, err := bulkCreateAuthenticationSource(auth, current)
+		if err != nil {
+			return nil, err
+		}
+
 		// lookup the polymorphic resource based on the resource type + name
 		switch strings.ToLower(auth.ResourceType) {
 		case "source":
-			a.ResourceID = current.Sources[0].ID
-			a.SourceID = current.Sources[0].ID
+			a.ResourceID = source.ID
+			a.SourceID = source.ID
 
-			if userResource.OwnershipPresentForSource(current.Sources[0].Name) {
+			if userResource.OwnershipPresentForSource(source.Name) {
 				a.UserID = &userResource.User.Id
 			}
 
 			l.Log.Infof("Source Authentication does not need linked - continuing")
 
 		case "application":
-			id, err := linkupApplication(auth.ResourceName, current.Applications, &tenant.Id)
+			id, err := linkupApplication(auth.ResourceName, applications, &tenant.Id)
 			if err != nil {
 				return nil, util.NewErrBadRequest(err)
 			}
 
 			a.ResourceID = id
-			a.SourceID = current.Sources[0].ID
+			a.SourceID = source.ID
 
-			if userResource.OwnershipPresentForSourceAndApplication(current.Sources[0].Name, auth.ResourceName) {
+			if userResource.OwnershipPresentForSourceAndApplication(source.Name, auth.ResourceName) {
 				a.UserID = &userResource.User.Id
 			}
 
 		case "endpoint":
-			id, err := linkupEndpoint(auth.ResourceName, current.Endpoints)
+			id, err := linkupEndpoint(auth.ResourceName, endpoints)
 			if err != nil {
 				return nil, util.NewErrBadRequest(err)
 			}
 
 			a.ResourceID = id
-			a.SourceID = current.Sources[0].ID
+			a.SourceID = source.ID
 
 		default:
 			return nil, util.NewErrBadRequest("failed to link authentication: no resource type present")
@@ -421,6 +497,43 @@ func linkUpAuthentications(req m.BulkCreateRequest, current *m.BulkCreateOutput,
 	return authentications, nil
 }
 
+// bulkCreateAuthenticationSource returns the source the given authentication's resource belongs to, along with the
+// applications and endpoints the resource can be looked up in. When the authentication does not name its source, the
+// first source of the request is returned along with every application and endpoint, as the authentications have
+// always been linked up that way.
+func bulkCreateAuthenticationSource(auth m.BulkCreateAuthentication, current *m.BulkCreateOutput) (*m.Source, []m.Application, []m.Endpoint, error) {
+	if auth.SourceName == "" {
+		return &current.Sources[0], current.Applications, current.Endpoints, nil
+	}
+
+	for i := range current.Sources {
+		source := &current.Sources[i]
+		if source.Name != auth.SourceName {
+			continue
+		}
+
+		applications := make([]m.Application, 0)
+
+		for _, app := range current.Applications {
+			if app.SourceID == source.ID {
+				applications = append(applications, app)
+			}
+		}
+
+		endpoints := make([]m.Endpoint, 0)
+
+		for _, endpt := range current.Endpoints {
+			if endpt.SourceID == source.ID {
+				endpoints = append(endpoints, endpt)
+			}
+		}
+
+		return source, applications, endpoints, nil
+	}
+
+	return nil, nil, nil, util.NewErrBadRequest(fmt.Sprintf("failed to link authentication: source %q not found in the request", auth.SourceName))
+}
+
 // authentications are attached to an application that has the same "resource
 // name" which is passed in the payload.
 func linkupApplication(name string, apps []m.Application, tenantID *int64) (int64, error) {
@@ -450,6 +563,167 @@ func linkupEndpoint(name string, endpoints []m.Endpoint) (int64, error) {
 	return 0, fmt.Errorf("failed to find endpoint for hostname %v", name)
 }
 
+// parseRhcConnections links up the requested Red Hat Connector connections to the sources they name.
+func parseRhcConnections(reqRhcConnections []m.BulkCreateRhcConnection, current *m.BulkCreateOutput) ([]m.RhcConnection, error) {
+	rhcConnections := make([]m.RhcConnection, 0)
+
+	for _, rhcConnection := range reqRhcConnections {
+		for _, src := range current.Sources {
+			if src.Name != rhcConnection.SourceName {
+				continue
+			}
+
+			rhcConnection.SourceIdRaw = src.ID
+
+			err := ValidateRhcConnectionRequest(&rhcConnection.RhcConnectionCreateRequest)
+			if err != nil {
+				return nil, util.NewErrBadRequest(fmt.Sprintf("Validation failed: %v", err))
+			}
+
+			rhcConnections = append(rhcConnections, m.RhcConnection{
+				RhcId:   rhcConnection.RhcId,
+				Extra:   rhcConnection.Extra,
+				Sources: []m.Source{{ID: src.ID}},
+			})
+		}
+	}
+
+	// if all of the connections did not get linked up - there was a problem
+	// with the request.
+	if len(rhcConnections) != len(reqRhcConnections) {
+		return nil, util.NewErrBadRequest("failed to link up all rhc connections - check to make sure the names match up")
+	}
+
+	return rhcConnections, nil
+}
+
+// createRhcConnection stores the given connection in the transaction, reusing the existing connection with the same
+// RHC ID just like the Red Hat Connector connection DAO does, and links it up to its source.
+func createRhcConnection(tx *gorm.DB, rhcConnection *m.RhcConnection, tenant *m.Tenant) error {
+	err := tx.
+		Where(`rhc_id = ?`, rhcConnection.RhcId).
+		Omit(clause.Associations).
+		FirstOrCreate(rhcConnection).
+		Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Create(&m.SourceRhcConnection{
+		SourceId:        rhcConnection.Sources[0].ID,
+		RhcConnectionId: rhcConnection.ID,
+		TenantId:        tenant.Id,
+	}).Error
+}
+
+// DecryptBulkCreatePasswords decrypts the passwords the request's authentications carry encrypted with a caller
+// provided key, such as the ones in the source exports, with the given key. The key gets derived with the parameters
+// of the request's "encryption" header.
+func DecryptBulkCreatePasswords(req *m.BulkCreateRequest, key string) error {
+	return NewBulkCreatePasswordDecrypter(key).Decrypt(req)
+}
+
+// BulkCreatePasswordDecrypter decrypts the passwords of a stream of bulk create requests with the same caller provided
+// key. The requests must share their key derivation parameters, so that the expensive derivation only happens once for
+// the whole stream.
+type BulkCreatePasswordDecrypter struct {
+	key           string
+	encryption    *util.PassphraseKDF
+	passphraseKey *util.PassphraseKey
+}
+
+// NewBulkCreatePasswordDecrypter returns a decrypter for the requests encrypted with the given key.
+func NewBulkCreatePasswordDecrypter(key string) *BulkCreatePasswordDecrypter {
+	return &BulkCreatePasswordDecrypter{key: key}
+}
+
+// Decrypt decrypts the passwords of the given request, rejecting the requests whose key derivation parameters differ
+// from the ones of the requests decrypted before.
+func (d *BulkCreatePasswordDecrypter) Decrypt(req *m.BulkCreateRequest) error {
+	if req.Encryption != nil {
+		if d.encryption == nil {
+			encryption := *req.Encryption
+			d.encryption = &encryption
+		} else if *d.encryption != *req.Encryption {
+			return util.NewErrBadRequest(`every document must share the same "encryption" parameters`)
+		}
+	}
+
+	for i := range req.Authentications {
+		auth := &req.Authentications[i]
+		if auth.EncryptedPassword == nil {
+			continue
+		}
+
+		if d.key == "" {
+			return util.NewErrBadRequest(fmt.Sprintf("the authentication %d has an encrypted password, but no encryption key was provided", i))
+		}
+
+		if req.Encryption == nil {
+			return util.NewErrBadRequest(fmt.Sprintf(`the authentication %d has an encrypted password, but the document has no "encryption" parameters`, i))
+		}
+
+		// the key derivation is expensive on purpose, so it is only done once.
+		if d.passphraseKey == nil {
+			var err error
+
+			d.passphraseKey, err = d.encryption.DeriveKey(d.key)
+			if err != nil {
+				return util.NewErrBadRequest(fmt.Sprintf("unable to derive the encryption key: %s", err))
+			}
+		}
+
+		password, err := d.passphraseKey.Decrypt(*auth.EncryptedPassword)
+		if err != nil {
+			return util.NewErrBadRequest(fmt.Sprintf("unable to decrypt the password of the authentication %d: %s", i, err))
+		}
+
+		auth.Password = &password
+		auth.EncryptedPassword = nil
+	}
+
+	return nil
+}
+
+// SealBulkCreatePasswords encrypts the request's plaintext passwords with the service's encryption key, so that the
+// request can be stored, such as in the jobs queue, without exposing them. "UnsealBulkCreatePasswords" reverts it.
+func SealBulkCreatePasswords(req *m.BulkCreateRequest) error {
+	for i := range req.Authentications {
+		auth := &req.Authentications[i]
+		if auth.Password == nil {
+			continue
+		}
+
+		sealed, err := util.Encrypt(*auth.Password)
+		if err != nil {
+			return fmt.Errorf("unable to encrypt the password of the authentication %d: %w", i, err)
+		}
+
+		auth.Password = &sealed
+	}
+
+	return nil
+}
+
+// UnsealBulkCreatePasswords decrypts the passwords "SealBulkCreatePasswords" encrypted.
+func UnsealBulkCreatePasswords(req *m.BulkCreateRequest) error {
+	for i := range req.Authentications {
+		auth := &req.Authentications[i]
+		if auth.Password == nil {
+			continue
+		}
+
+		password, err := util.Decrypt(*auth.Password)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt the password of the authentication %d: %w", i, err)
+		}
+
+		auth.Password = &password
+	}
+
+	return nil
+}
+
 // send all the messages on the event-stream for what we created. this involves
 // doing some checks for superkey related things etc.
 func SendBulkMessages(out *m.BulkCreateOutput, headers []kafka.Header, identity string, sk SuperKeyProducer) {
@@ -473,9 +747,18 @@ func SendBulkMessages(out *m.BulkCreateOutput, headers []kafka.Header, identity
 			}
 		}
 
+		for i := range out.RhcConnections {
+			rhcConnection := out.RhcConnections[i]
+
+			err := RaiseEvent("RhcConnection.create", &rhcConnection, headers)
+			if err != nil {
+				l.Log.Warnf("Failed to raise event: %v", err)
+			}
+		}
+
 		for i := range out.Applications {
 			app := out.Applications[i]
-			if out.Sources[0].AppCreationWorkflow == m.AccountAuth {
+			if isSuperKeyBulkCreate(out) {
 				err := sk.SendCreateRequest(&app, headers)
 				if err != nil {
 					l.Log.Warnf("Error sending superkey create request: %v", err)
@@ -509,6 +792,39 @@ func SendBulkMessages(out *m.BulkCreateOutput, headers []kafka.Header, identity
 	}()
 }
 
+// isSuperKeyBulkCreate returns true when the applications of the bulk create are created through superkey requests
+// instead of regular creation events.
+func isSuperKeyBulkCreate(out *m.BulkCreateOutput) bool {
+	return len(out.Sources) > 0 && out.Sources[0].AppCreationWorkflow == m.AccountAuth
+}
+
+// BulkCreateSuperKeyRequests returns the superkey create requests that "SendBulkMessages" sends for the given output.
+func BulkCreateSuperKeyRequests(out *m.BulkCreateOutput) []m.BulkCreateSuperKeyRequest {
+	requests := make([]m.BulkCreateSuperKeyRequest, 0)
+
+	if !isSuperKeyBulkCreate(out) {
+		return requests
+	}
+
+	for _, app := range out.Applications {
+		request := m.BulkCreateSuperKeyRequest{
+			SourceID:        strconv.FormatInt(app.SourceID, 10),
+			ApplicationID:   strconv.FormatInt(app.ID, 10),
+			ApplicationType: app.ApplicationType.Name,
+		}
+
+		for _, src := range out.Sources {
+			if src.ID == app.SourceID {
+				request.Provider = src.SourceType.Name
+			}
+		}
+
+		requests = append(requests, request)
+	}
+
+	return requests
+}
+
 func applicationFromBulkCreateApplication(reqApplication *m.BulkCreateApplication, tenant *m.Tenant) (*m.Application, error) {
 	a := m.Application{}
 
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// bulkCreateUnit is a slice of a bulk create request which gets created independently of the rest of the request. It
+// holds a source along with the resources that link up to it, or a single authentication for an existing resource.
+type bulkCreateUnit struct {
+	request m.BulkCreateRequest
+	// the positions of the unit's resources in the original request.
+	sources         []int
+	applications    []int
+	endpoints       []int
+	authentications []int
+	rhcConnections  []int
+}
+
+// BulkAssemblyProgress receives the number of units of a partial success bulk create that have been processed so far,
+// the total number of units, and the results so far.
+type BulkAssemblyProgress func(processed int, total int, results *m.BulkCreatePartialResponse)
+
+/*
+BulkAssemblyPartial is the partial success mode of "BulkAssembly". Instead of
+rejecting the whole request when a resource is invalid, every source and its
+dependants are created in their own transaction, and the outcome of every
+resource of the request is reported back. The resources which cannot be linked
+up to any source fail on their own, without affecting the rest.
+
+When dry running, every unit gets rolled back like "BulkAssemblyDryRun" does.
+The optional progress function gets called after every processed unit.
+*/
+func BulkAssemblyPartial(req m.BulkCreateRequest, tenant *m.Tenant, user *m.User, dryRun bool, progress BulkAssemblyProgress) *m.BulkCreatePartialOutput {
+	output := m.BulkCreatePartialOutput{
+		Created: make([]m.BulkCreateOutput, 0),
+		Results: m.BulkCreatePartialResponse{
+			DryRun:          dryRun,
+			Sources:         newBulkCreateResults(len(req.Sources)),
+			Applications:    newBulkCreateResults(len(req.Applications)),
+			Endpoints:       newBulkCreateResults(len(req.Endpoints)),
+			Authentications: newBulkCreateResults(len(req.Authentications)),
+			RhcConnections:  newBulkCreateResults(len(req.RhcConnections)),
+		},
+	}
+
+	units := splitBulkCreateRequest(req, tenant, &output.Results)
+
+	for i, unit := range units {
+		assembleBulkCreateUnit(unit, tenant, user, dryRun, &output)
+
+		if progress != nil {
+			progress(i+1, len(units), &output.Results)
+		}
+	}
+
+	return &output
+}
+
+// assembleBulkCreateUnit creates the given unit in its own transaction, and records the outcome of its resources in
+// the given output.
+func assembleBulkCreateUnit(unit *bulkCreateUnit, tenant *m.Tenant, user *m.User, dryRun bool, output *m.BulkCreatePartialOutput) {
+	unitOutput, err := bulkAssembly(unit.request, tenant, user, dryRun)
+	if dryRun && errors.Is(err, errDryRunRollback) {
+		err = nil
+	}
+
+	if err != nil {
+		failBulkCreateUnit(unit, &output.Results, err)
+
+		return
+	}
+
+	for i, index := range unit.sources {
+		output.Results.Sources[index] = createdBulkCreateResult(index, strconv.FormatInt(unitOutput.Sources[i].ID, 10))
+	}
+
+	for i, index := range unit.applications {
+		output.Results.Applications[index] = createdBulkCreateResult(index, strconv.FormatInt(unitOutput.Applications[i].ID, 10))
+	}
+
+	for i, index := range unit.endpoints {
+		output.Results.Endpoints[index] = createdBulkCreateResult(index, strconv.FormatInt(unitOutput.Endpoints[i].ID, 10))
+	}
+
+	for i, index := range unit.authentications {
+		output.Results.Authentications[index] = createdBulkCreateResult(index, unitOutput.Authentications[i].GetID())
+	}
+
+	for i, index := range unit.rhcConnections {
+		output.Results.RhcConnections[index] = createdBulkCreateResult(index, strconv.FormatInt(unitOutput.RhcConnections[i].ID, 10))
+	}
+
+	output.Created = append(output.Created, *unitOutput)
+}
+
+// splitBulkCreateRequest splits the request in one unit per source. The applications, endpoints and rhc connections
+// join the unit of the source they name, and the authentications join the unit which holds the resource they name. The authentications
+// for existing resources get a unit of their own. The resources which do not link up to any unit are marked as failed
+// in the given results.
+func splitBulkCreateRequest(req m.BulkCreateRequest, tenant *m.Tenant, results *m.BulkCreatePartialResponse) []*bulkCreateUnit {
+	units := make([]*bulkCreateUnit, len(req.Sources))
+	unitsBySourceName := make(map[string]*bulkCreateUnit)
+
+	for i, src := range req.Sources {
+		units[i] = &bulkCreateUnit{
+			request: m.BulkCreateRequest{Sources: []m.BulkCreateSource{src}},
+			sources: []int{i},
+		}
+
+		name := util.ValueOrBlank(src.Name)
+		if _, ok := unitsBySourceName[name]; !ok {
+			unitsBySourceName[name] = units[i]
+		}
+	}
+
+	for i, app := range req.Applications {
+		unit, ok := unitsBySourceName[app.SourceName]
+		if !ok {
+			results.Applications[i] = failedBulkCreateResult(i, "failed to link up the application - check to make sure the source name matches up")
+
+			continue
+		}
+
+		unit.request.Applications = append(unit.request.Applications, app)
+		unit.applications = append(unit.applications, i)
+	}
+
+	for i, endpt := range req.Endpoints {
+		unit, ok := unitsBySourceName[endpt.SourceName]
+		if !ok {
+			results.Endpoints[i] = failedBulkCreateResult(i, "failed to link up the endpoint - check to make sure the source name matches up")
+
+			continue
+		}
+
+		unit.request.Endpoints = append(unit.request.Endpoints, endpt)
+		unit.endpoints = append(unit.endpoints, i)
+	}
+
+	for i, rhcConnection := range req.RhcConnections {
+		unit, ok := unitsBySourceName[rhcConnection.SourceName]
+		if !ok {
+			results.RhcConnections[i] = failedBulkCreateResult(i, "failed to link up the rhc connection - check to make sure the source name matches up")
+
+			continue
+		}
+
+		unit.request.RhcConnections = append(unit.request.RhcConnections, rhcConnection)
+		unit.rhcConnections = append(unit.rhcConnections, i)
+	}
+
+	for i, auth := range req.Authentications {
+		unit := authenticationBulkCreateUnit(auth, units, unitsBySourceName, tenant)
+
+		if unit == nil {
+			// the authentications for existing resources are linked up by the resource's ID.
+			if _, err := strconv.ParseInt(auth.ResourceName, 10, 64); err != nil {
+				results.Authentications[i] = failedBulkCreateResult(i, "failed to link up the authentication - check to make sure the resource name matches up")
+
+				continue
+			}
+
+			unit = &bulkCreateUnit{}
+			units = append(units, unit)
+		}
+
+		unit.request.Authentications = append(unit.request.Authentications, auth)
+		unit.authentications = append(unit.authentications, i)
+	}
+
+	return units
+}
+
+// authenticationBulkCreateUnit returns the unit holding the resource the given authentication names, or nil if there
+// is none.
+func authenticationBulkCreateUnit(auth m.BulkCreateAuthentication, units []*bulkCreateUnit, unitsBySourceName map[string]*bulkCreateUnit, tenant *m.Tenant) *bulkCreateUnit {
+	// the resource of an authentication which names its source can only be in that source's unit.
+	if auth.SourceName != "" {
+		return unitsBySourceName[auth.SourceName]
+	}
+
+	switch strings.ToLower(auth.ResourceType) {
+	case "source":
+		if unit, ok := unitsBySourceName[auth.ResourceName]; ok {
+			return unit
+		}
+
+		// the source authentications have always been linked up to the only source of the request, unless they
+		// named an existing source by its ID.
+		if len(units) == 1 {
+			return units[0]
+		}
+
+	case "application":
+		appType, err := dao.GetApplicationTypeDao(&tenant.Id).GetByName(auth.ResourceName)
+		if err != nil {
+			return nil
+		}
+
+		for _, unit := range units {
+			for _, app := range unit.request.Applications {
+				id, err := util.InterfaceToInt64(app.ApplicationTypeIDRaw)
+				if app.ApplicationTypeName == appType.Name || (err == nil && id == appType.Id) {
+					return unit
+				}
+			}
+		}
+
+	case "endpoint":
+		for _, unit := range units {
+			for _, endpt := range unit.request.Endpoints {
+				if strings.Contains(strings.ToLower(endpt.Host), strings.ToLower(auth.ResourceName)) {
+					return unit
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// failBulkCreateUnit marks every resource of the given unit as failed with the given error.
+func failBulkCreateUnit(unit *bulkCreateUnit, results *m.BulkCreatePartialResponse, err error) {
+	for _, index := range unit.sources {
+		results.Sources[index] = failedBulkCreateResult(index, err.Error())
+	}
+
+	for _, index := range unit.applications {
+		results.Applications[index] = failedBulkCreateResult(index, err.Error())
+	}
+
+	for _, index := range unit.endpoints {
+		results.Endpoints[index] = failedBulkCreateResult(index, err.Error())
+	}
+
+	for _, index := range unit.authentications {
+		results.Authentications[index] = failedBulkCreateResult(index, err.Error())
+	}
+
+	for _, index := range unit.rhcConnections {
+		results.RhcConnections[index] = failedBulkCreateResult(index, err.Error())
+	}
+}
+
+// newBulkCreateResults returns the results for the given number of resources, indexed by their position.
+func newBulkCreateResults(count int) []m.BulkCreateResult {
+	results := make([]m.BulkCreateResult, count)
+	for i := range results {
+		results[i].Index = i
+	}
+
+	return results
+}
+
+func createdBulkCreateResult(index int, id string) m.BulkCreateResult {
+	return m.BulkCreateResult{Index: index, Status: m.BulkCreateCreated, ID: id}
+}
+
+func failedBulkCreateResult(index int, message string) m.BulkCreateResult {
+	return m.BulkCreateResult{Index: index, Status: m.BulkCreateFailed, Error: message}
+}
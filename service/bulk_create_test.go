@@ -3,6 +3,7 @@ package service
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"strconv"
 	"testing"
 
@@ -10,6 +11,7 @@ import (
 	"github.com/RedHatInsights/sources-api-go/internal/testutils"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/database"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
 	"github.com/RedHatInsights/sources-api-go/model"
 	"github.com/RedHatInsights/sources-api-go/util"
 	"gorm.io/gorm"
@@ -1324,3 +1326,339 @@ func TestSetCorrespondingResourcesAsAvailable(t *testing.T) {
 		}
 	}
 }
+
+// TestBulkCreateSuperKeyRequests tests that the superkey requests are only listed for the account authorization
+// sources, and that they carry the linked up IDs.
+func TestBulkCreateSuperKeyRequests(t *testing.T) {
+	output := &model.BulkCreateOutput{
+		Sources: []model.Source{
+			{ID: 10, AppCreationWorkflow: model.AccountAuth, SourceType: model.SourceType{Name: "amazon"}},
+		},
+		Applications: []model.Application{
+			{ID: 20, SourceID: 10, ApplicationType: model.ApplicationType{Name: "/insights/platform/cost-management"}},
+		},
+	}
+
+	requests := BulkCreateSuperKeyRequests(output)
+
+	want := []model.BulkCreateSuperKeyRequest{
+		{SourceID: "10", ApplicationID: "20", ApplicationType: "/insights/platform/cost-management", Provider: "amazon"},
+	}
+
+	if !reflect.DeepEqual(requests, want) {
+		t.Errorf(`want superkey requests "%v", got "%v"`, want, requests)
+	}
+
+	output.Sources[0].AppCreationWorkflow = model.ManualConfig
+
+	requests = BulkCreateSuperKeyRequests(output)
+	if len(requests) != 0 {
+		t.Errorf(`want no superkey requests for the manual configuration sources, got "%v"`, requests)
+	}
+}
+
+// TestSplitBulkCreateRequest tests that the partial success bulk creates get split in one unit per source, that the
+// authentications for existing resources get their own unit, and that the resources which do not link up to any
+// source are reported as failed.
+func TestSplitBulkCreateRequest(t *testing.T) {
+	backup := dao.GetApplicationTypeDao
+	dao.GetApplicationTypeDao = func(_ *int64) dao.ApplicationTypeDao {
+		return &mocks.MockApplicationTypeDao{ApplicationTypes: fixtures.TestApplicationTypeData}
+	}
+
+	t.Cleanup(func() { dao.GetApplicationTypeDao = backup })
+
+	first := "first source"
+	second := "second source"
+	appTypeName := fixtures.TestApplicationTypeData[0].Name
+
+	req := model.BulkCreateRequest{
+		Sources: []model.BulkCreateSource{
+			{SourceCreateRequest: model.SourceCreateRequest{Name: &first}},
+			{SourceCreateRequest: model.SourceCreateRequest{Name: &second}},
+		},
+		Applications: []model.BulkCreateApplication{
+			{SourceName: second, ApplicationTypeName: appTypeName},
+			{SourceName: "unknown source", ApplicationTypeName: appTypeName},
+		},
+		Endpoints: []model.BulkCreateEndpoint{
+			{SourceName: first, EndpointCreateRequest: model.EndpointCreateRequest{Host: "example.com"}},
+		},
+		Authentications: []model.BulkCreateAuthentication{
+			{ResourceName: appTypeName, AuthenticationCreateRequest: model.AuthenticationCreateRequest{ResourceType: "application"}},
+			{ResourceName: "example", AuthenticationCreateRequest: model.AuthenticationCreateRequest{ResourceType: "endpoint"}},
+			{ResourceName: first, AuthenticationCreateRequest: model.AuthenticationCreateRequest{ResourceType: "source"}},
+			{ResourceName: "12345", AuthenticationCreateRequest: model.AuthenticationCreateRequest{ResourceType: "source"}},
+			{ResourceName: "unknown", AuthenticationCreateRequest: model.AuthenticationCreateRequest{ResourceType: "endpoint"}},
+		},
+	}
+
+	results := model.BulkCreatePartialResponse{
+		Sources:         newBulkCreateResults(len(req.Sources)),
+		Applications:    newBulkCreateResults(len(req.Applications)),
+		Endpoints:       newBulkCreateResults(len(req.Endpoints)),
+		Authentications: newBulkCreateResults(len(req.Authentications)),
+	}
+
+	units := splitBulkCreateRequest(req, &fixtures.TestTenantData[0], &results)
+
+	if len(units) != 3 {
+		t.Fatalf("want 3 units, got %d", len(units))
+	}
+
+	type unitIndexes struct {
+		sources, applications, endpoints, authentications []int
+	}
+
+	want := []unitIndexes{
+		{sources: []int{0}, endpoints: []int{0}, authentications: []int{1, 2}},
+		{sources: []int{1}, applications: []int{0}, authentications: []int{0}},
+		{authentications: []int{3}},
+	}
+
+	for i, unit := range units {
+		got := unitIndexes{sources: unit.sources, applications: unit.applications, endpoints: unit.endpoints, authentications: unit.authentications}
+		if !reflect.DeepEqual(got, want[i]) {
+			t.Errorf(`want unit %d to hold "%+v", got "%+v"`, i, want[i], got)
+		}
+	}
+
+	if results.Applications[1].Status != model.BulkCreateFailed || results.Applications[1].Error == "" {
+		t.Errorf(`want the unlinked application to fail, got "%+v"`, results.Applications[1])
+	}
+
+	if results.Authentications[4].Status != model.BulkCreateFailed || results.Authentications[4].Error == "" {
+		t.Errorf(`want the unlinked authentication to fail, got "%+v"`, results.Authentications[4])
+	}
+}
+
+// TestSplitBulkCreateRequestBySourceName tests that the authentications which name their source join that source's
+// unit, even if another source holds a resource with the same name, and that the rhc connections join the unit of
+// the source they name.
+func TestSplitBulkCreateRequestBySourceName(t *testing.T) {
+	first := "first source"
+	second := "second source"
+	appTypeName := fixtures.TestApplicationTypeData[0].Name
+
+	req := model.BulkCreateRequest{
+		Sources: []model.BulkCreateSource{
+			{SourceCreateRequest: model.SourceCreateRequest{Name: &first}},
+			{SourceCreateRequest: model.SourceCreateRequest{Name: &second}},
+		},
+		Applications: []model.BulkCreateApplication{
+			{SourceName: first, ApplicationTypeName: appTypeName},
+			{SourceName: second, ApplicationTypeName: appTypeName},
+		},
+		Authentications: []model.BulkCreateAuthentication{
+			{ResourceName: appTypeName, SourceName: second, AuthenticationCreateRequest: model.AuthenticationCreateRequest{ResourceType: "application"}},
+			{ResourceName: "unknown source", SourceName: "unknown source", AuthenticationCreateRequest: model.AuthenticationCreateRequest{ResourceType: "source"}},
+		},
+		RhcConnections: []model.BulkCreateRhcConnection{
+			{SourceName: second, RhcConnectionCreateRequest: model.RhcConnectionCreateRequest{RhcId: "a"}},
+			{SourceName: "unknown source", RhcConnectionCreateRequest: model.RhcConnectionCreateRequest{RhcId: "b"}},
+		},
+	}
+
+	results := model.BulkCreatePartialResponse{
+		Sources:         newBulkCreateResults(len(req.Sources)),
+		Applications:    newBulkCreateResults(len(req.Applications)),
+		Endpoints:       newBulkCreateResults(len(req.Endpoints)),
+		Authentications: newBulkCreateResults(len(req.Authentications)),
+		RhcConnections:  newBulkCreateResults(len(req.RhcConnections)),
+	}
+
+	units := splitBulkCreateRequest(req, &fixtures.TestTenantData[0], &results)
+
+	if len(units) != 2 {
+		t.Fatalf("want 2 units, got %d", len(units))
+	}
+
+	if len(units[0].authentications) != 0 || len(units[0].rhcConnections) != 0 {
+		t.Errorf(`want the first unit to only hold its source and application, got "%+v"`, units[0])
+	}
+
+	if !reflect.DeepEqual(units[1].authentications, []int{0}) || !reflect.DeepEqual(units[1].rhcConnections, []int{0}) {
+		t.Errorf(`want the second unit to hold the authentication and the rhc connection, got "%+v"`, units[1])
+	}
+
+	if results.Authentications[1].Status != model.BulkCreateFailed || results.RhcConnections[1].Status != model.BulkCreateFailed {
+		t.Errorf(`want the resources of the unknown source to fail, got "%+v"`, results)
+	}
+}
+
+// TestBulkCreateAuthenticationSource tests that the authentications get linked up to the resources of the source they
+// name, and to the first source when they do not name any.
+func TestBulkCreateAuthenticationSource(t *testing.T) {
+	current := model.BulkCreateOutput{
+		Sources:      []model.Source{{ID: 1, Name: "first"}, {ID: 2, Name: "second"}},
+		Applications: []model.Application{{ID: 10, SourceID: 1}, {ID: 20, SourceID: 2}},
+		Endpoints:    []model.Endpoint{{ID: 30, SourceID: 2}},
+	}
+
+	source, applications, endpoints, err := bulkCreateAuthenticationSource(model.BulkCreateAuthentication{SourceName: "second"}, &current)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if source.ID != 2 || len(applications) != 1 || applications[0].ID != 20 || len(endpoints) != 1 {
+		t.Errorf(`want the second source's resources, got "%+v", "%+v" and "%+v"`, source, applications, endpoints)
+	}
+
+	source, applications, endpoints, err = bulkCreateAuthenticationSource(model.BulkCreateAuthentication{}, &current)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if source.ID != 1 || len(applications) != 2 || len(endpoints) != 1 {
+		t.Errorf(`want the first source along with every resource, got "%+v", "%+v" and "%+v"`, source, applications, endpoints)
+	}
+
+	_, _, _, err = bulkCreateAuthenticationSource(model.BulkCreateAuthentication{SourceName: "unknown"}, &current)
+	if !errors.As(err, &util.ErrBadRequest{}) {
+		t.Errorf("want a bad request error, got %v", err)
+	}
+}
+
+// TestDecryptBulkCreatePasswords tests that the encrypted passwords get decrypted with the given key, and that they
+// are rejected without the right key.
+func TestDecryptBulkCreatePasswords(t *testing.T) {
+	kdf, err := util.NewPassphraseKDF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := kdf.DeriveKey("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := key.Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRequest := func() model.BulkCreateRequest {
+		return model.BulkCreateRequest{
+			Authentications: []model.BulkCreateAuthentication{
+				{AuthenticationCreateRequest: model.AuthenticationCreateRequest{Password: util.StringRef("plain")}},
+				{EncryptedPassword: &encrypted},
+			},
+			Encryption: kdf,
+		}
+	}
+
+	req := newRequest()
+
+	err = DecryptBulkCreatePasswords(&req, "key")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if *req.Authentications[0].Password != "plain" || *req.Authentications[1].Password != "secret" || req.Authentications[1].EncryptedPassword != nil {
+		t.Errorf(`unexpected passwords "%+v"`, req.Authentications)
+	}
+
+	for _, key := range []string{"", "wrong key"} {
+		req := newRequest()
+
+		err = DecryptBulkCreatePasswords(&req, key)
+		if !errors.As(err, &util.ErrBadRequest{}) {
+			t.Errorf("want a bad request error for the key %q, got %v", key, err)
+		}
+	}
+
+	req = newRequest()
+	req.Encryption = nil
+
+	err = DecryptBulkCreatePasswords(&req, "key")
+	if !errors.As(err, &util.ErrBadRequest{}) {
+		t.Errorf("want a bad request error without the key derivation parameters, got %v", err)
+	}
+}
+
+// TestBulkCreatePasswordDecrypter tests that the requests which share their key derivation parameters get decrypted
+// with the same key, and that the requests with different parameters get rejected.
+func TestBulkCreatePasswordDecrypter(t *testing.T) {
+	kdf, err := util.NewPassphraseKDF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := kdf.DeriveKey("key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newRequest := func(encryption *util.PassphraseKDF, password string) model.BulkCreateRequest {
+		encrypted, err := key.Encrypt(password)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// every document carries its own copy of the parameters.
+		params := *encryption
+
+		return model.BulkCreateRequest{
+			Authentications: []model.BulkCreateAuthentication{{EncryptedPassword: &encrypted}},
+			Encryption:      &params,
+		}
+	}
+
+	decrypter := NewBulkCreatePasswordDecrypter("key")
+
+	for _, password := range []string{"first", "second"} {
+		req := newRequest(kdf, password)
+
+		err = decrypter.Decrypt(&req)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if *req.Authentications[0].Password != password {
+			t.Errorf(`want the password "%s", got "%s"`, password, *req.Authentications[0].Password)
+		}
+	}
+
+	other, err := util.NewPassphraseKDF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := newRequest(other, "third")
+
+	err = decrypter.Decrypt(&req)
+	if !errors.As(err, &util.ErrBadRequest{}) {
+		t.Errorf("want a bad request error for different key derivation parameters, got %v", err)
+	}
+}
+
+// TestSealBulkCreatePasswords tests that the sealed passwords are not stored in plaintext, and that they can be
+// unsealed back.
+func TestSealBulkCreatePasswords(t *testing.T) {
+	util.InitializeEncryption()
+
+	req := model.BulkCreateRequest{
+		Authentications: []model.BulkCreateAuthentication{
+			{AuthenticationCreateRequest: model.AuthenticationCreateRequest{Password: util.StringRef("secret")}},
+			{AuthenticationCreateRequest: model.AuthenticationCreateRequest{}},
+		},
+	}
+
+	err := SealBulkCreatePasswords(&req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if *req.Authentications[0].Password == "secret" || req.Authentications[1].Password != nil {
+		t.Errorf(`want the password to be sealed, got "%+v"`, req.Authentications)
+	}
+
+	err = UnsealBulkCreatePasswords(&req)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if *req.Authentications[0].Password != "secret" || req.Authentications[1].Password != nil {
+		t.Errorf(`want the password to be unsealed, got "%+v"`, req.Authentications)
+	}
+}
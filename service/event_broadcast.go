@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+
+	"github.com/RedHatInsights/sources-api-go/internal/events"
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/redis"
+	"github.com/valkey-io/valkey-go"
+)
+
+// eventStreamChannelPrefix is the prefix of the Valkey channels the tenants' events get broadcast on.
+const eventStreamChannelPrefix = "sources-api:events:"
+
+// EventBroadcaster broadcasts the tenants' events to every API pod, so that they reach the clients listening to the
+// tenant's event stream, no matter which process raised them.
+type EventBroadcaster interface {
+	// Publish sends the message to the subscribers of the channel.
+	Publish(ctx context.Context, channel string, message []byte) error
+	// Subscribe calls fn with every message published to the channels, until the context is done. The calls to fn
+	// must not block, since they hold back the rest of the subscriptions.
+	Subscribe(ctx context.Context, channels []string, fn func(message []byte)) error
+}
+
+// Broadcaster is the broadcaster the events are published with. It is a variable so that it can be replaced in the
+// tests.
+var Broadcaster EventBroadcaster = valkeyEventBroadcaster{}
+
+// valkeyEventBroadcaster is the default broadcaster, backed by Valkey's pub/sub.
+type valkeyEventBroadcaster struct{}
+
+func (valkeyEventBroadcaster) Publish(ctx context.Context, channel string, message []byte) error {
+	return redis.Client.Do(ctx, redis.Client.B().Publish().Channel(channel).Message(string(message)).Build()).Error()
+}
+
+func (valkeyEventBroadcaster) Subscribe(ctx context.Context, channels []string, fn func(message []byte)) error {
+	return redis.Client.Receive(ctx, redis.Client.B().Subscribe().Channel(channels...).Build(), func(msg valkey.PubSubMessage) {
+		fn([]byte(msg.Message))
+	})
+}
+
+// StreamEvent is an event as it gets broadcast to the tenant's event stream.
+type StreamEvent struct {
+	EventType string          `json:"event_type"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// BroadcastSender raises the events through the wrapped sender, and then broadcasts them to the event streams of
+// their tenants.
+type BroadcastSender struct {
+	events.Sender
+}
+
+func (bs *BroadcastSender) RaiseEvent(eventType string, payload []byte, headers []kafka.Header) error {
+	err := bs.Sender.RaiseEvent(eventType, payload, headers)
+
+	// The event streams are a best effort on top of the event stream topic, so they do not make raising the event
+	// fail.
+	broadcastErr := BroadcastEvent(eventType, payload, headers)
+	if broadcastErr != nil {
+		logging.Log.Warnf(`Unable to broadcast the "%s" event: %s`, eventType, broadcastErr)
+	}
+
+	return err
+}
+
+// BroadcastEvent broadcasts the resource event to the event stream of its tenant, which is picked from the same
+// headers the event gets raised with. Only the same resource events the webhooks can subscribe to are broadcast.
+func BroadcastEvent(eventType string, payload []byte, headers []kafka.Header) error {
+	if !slices.Contains(model.WebhookEventTypes, eventType) {
+		return nil
+	}
+
+	var orgID, accountNumber string
+	for _, header := range headers {
+		switch header.Key {
+		case h.OrgID:
+			orgID = string(header.Value)
+		case h.AccountNumber:
+			accountNumber = string(header.Value)
+		}
+	}
+
+	return publishStreamEvent(orgID, accountNumber, StreamEvent{EventType: eventType, Data: payload})
+}
+
+// BroadcastAvailabilityTransition broadcasts the change of the resource's availability status to the event stream of
+// its tenant, as a "<resource type>.availability_status" event.
+func BroadcastAvailabilityTransition(tenant *model.Tenant, resourceType, resourceID, previousStatus, status string) error {
+	data, err := json.Marshal(map[string]string{
+		"id":                  resourceID,
+		"previous_status":     previousStatus,
+		"availability_status": status,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the availability transition: %w", err)
+	}
+
+	return publishStreamEvent(tenant.OrgID, tenant.ExternalTenant, StreamEvent{EventType: resourceType + ".availability_status", Data: data})
+}
+
+// EventStreamChannels returns the channels the events of the tenant with the given identity get broadcast on. The
+// events are published on the OrgId's channel, or on the EBS account number's channel when the event does not carry
+// an OrgId, so the tenant's stream listens to both.
+func EventStreamChannels(orgID, accountNumber string) []string {
+	channels := make([]string, 0, 2)
+
+	if orgID != "" {
+		channels = append(channels, eventStreamChannelPrefix+"org_id:"+orgID)
+	}
+
+	if accountNumber != "" {
+		channels = append(channels, eventStreamChannelPrefix+"account_number:"+accountNumber)
+	}
+
+	return channels
+}
+
+// publishStreamEvent publishes the event on the channel of the tenant with the given identity.
+func publishStreamEvent(orgID, accountNumber string, event StreamEvent) error {
+	channels := EventStreamChannels(orgID, accountNumber)
+	if len(channels) == 0 {
+		return nil
+	}
+
+	message, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal the stream event: %w", err)
+	}
+
+	err = Broadcaster.Publish(context.Background(), channels[0], message)
+	if err != nil {
+		return fmt.Errorf(`failed to publish the "%s" stream event: %w`, event.EventType, err)
+	}
+
+	return nil
+}
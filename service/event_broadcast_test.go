@@ -0,0 +1,80 @@
+package service
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+)
+
+// TestBroadcastEventChannel tests that the events get published on the OrgId's channel, falling back to the EBS
+// account number's channel for the events which do not carry an OrgId.
+func TestBroadcastEventChannel(t *testing.T) {
+	originalBroadcaster := Broadcaster
+	defer func() { Broadcaster = originalBroadcaster }()
+
+	testData := []struct {
+		headers []kafka.Header
+		channel string
+	}{
+		{
+			headers: []kafka.Header{{Key: h.OrgID, Value: []byte("12345")}, {Key: h.AccountNumber, Value: []byte("67890")}},
+			channel: "sources-api:events:org_id:12345",
+		},
+		{
+			headers: []kafka.Header{{Key: h.AccountNumber, Value: []byte("67890")}},
+			channel: "sources-api:events:account_number:67890",
+		},
+	}
+
+	for _, td := range testData {
+		broadcaster := &mocks.MockEventBroadcaster{}
+		Broadcaster = broadcaster
+
+		err := BroadcastEvent("Endpoint.destroy", []byte(`{"id": 1}`), td.headers)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(broadcaster.Published) != 1 || len(broadcaster.Published[td.channel]) != 1 {
+			t.Fatalf(`want the event published on "%s", got "%v"`, td.channel, broadcaster.Published)
+		}
+
+		var event StreamEvent
+
+		err = json.Unmarshal(broadcaster.Published[td.channel][0], &event)
+		if err != nil {
+			t.Fatalf("unable to unmarshal the stream event: %s", err)
+		}
+
+		if event.EventType != "Endpoint.destroy" || string(event.Data) != `{"id":1}` {
+			t.Errorf(`unexpected stream event "%+v"`, event)
+		}
+	}
+}
+
+// TestBroadcastEventSkipped tests that neither the events which are not about the resources nor the events without a
+// tenant get broadcast.
+func TestBroadcastEventSkipped(t *testing.T) {
+	originalBroadcaster := Broadcaster
+	defer func() { Broadcaster = originalBroadcaster }()
+
+	broadcaster := &mocks.MockEventBroadcaster{}
+	Broadcaster = broadcaster
+
+	err := BroadcastEvent("Records.update", []byte(`{}`), []kafka.Header{{Key: h.OrgID, Value: []byte("12345")}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	err = BroadcastEvent("Source.update", []byte(`{}`), []kafka.Header{})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(broadcaster.Published) != 0 {
+		t.Errorf(`want no events broadcast, got "%v"`, broadcaster.Published)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 
 	"github.com/RedHatInsights/sources-api-go/internal/events"
 	"github.com/RedHatInsights/sources-api-go/kafka"
@@ -22,7 +23,9 @@ func RaiseEvent(eventType string, resource model.Event, headers []kafka.Header)
 		return fmt.Errorf("failed to marshal %+v as event: %v", resource, err)
 	}
 
-	headers = append(headers, kafka.Header{Key: "event_type", Value: []byte(eventType)})
+	// the callers raise several events with the same headers, sometimes concurrently, so they must not share the
+	// appended header.
+	headers = append(slices.Clip(headers), kafka.Header{Key: "event_type", Value: []byte(eventType)})
 
 	err = Producer().RaiseEvent(eventType, msg, headers)
 	if err != nil {
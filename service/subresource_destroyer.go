@@ -1,13 +1,16 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/kafka"
 	logging "github.com/RedHatInsights/sources-api-go/logger"
 	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
 	"github.com/sirupsen/logrus"
 )
 
@@ -34,6 +37,18 @@ import (
 // Finally, those authentications are safely encrypted so they can stay in their datastores until we manually remove
 // them.
 func DeleteCascade(tenantId *int64, userId *int64, resourceType string, resourceId int64, headers []kafka.Header) error {
+	return deleteCascade(tenantId, userId, resourceType, resourceId, nil, headers)
+}
+
+// DeleteCascadeIfUnmodified works like DeleteCascade, but it only removes the resource when its "updated_at" column
+// still holds the given time. Otherwise, a "precondition failed" error is returned and nothing gets removed.
+func DeleteCascadeIfUnmodified(tenantId *int64, userId *int64, resourceType string, resourceId int64, updatedAt time.Time, headers []kafka.Header) error {
+	return deleteCascade(tenantId, userId, resourceType, resourceId, &updatedAt, headers)
+}
+
+// deleteCascade removes the resource and its dependants, only if the resource has not been modified since the given
+// time when it is not nil.
+func deleteCascade(tenantId *int64, userId *int64, resourceType string, resourceId int64, updatedAt *time.Time, headers []kafka.Header) error {
 	authenticationsDao := dao.GetAuthenticationDao(&dao.RequestParams{TenantID: tenantId})
 
 	var authentications []model.Authentication
@@ -46,7 +61,25 @@ func DeleteCascade(tenantId *int64, userId *int64, resourceType string, resource
 	case "Source":
 		sourceDao := dao.GetSourceDao(&dao.RequestParams{TenantID: tenantId, UserID: userId})
 
-		applicationAuthentications, applications, endpoints, rhcConnections, source, err := sourceDao.DeleteCascade(resourceId)
+		var (
+			applicationAuthentications []model.ApplicationAuthentication
+			applications               []model.Application
+			endpoints                  []model.Endpoint
+			rhcConnections             []model.RhcConnection
+			source                     *model.Source
+			err                        error
+		)
+
+		if updatedAt != nil {
+			applicationAuthentications, applications, endpoints, rhcConnections, source, err = sourceDao.DeleteCascadeIfUnmodified(resourceId, *updatedAt)
+		} else {
+			applicationAuthentications, applications, endpoints, rhcConnections, source, err = sourceDao.DeleteCascade(resourceId)
+		}
+
+		if errors.As(err, &util.ErrPreconditionFailed{}) {
+			return err
+		}
+
 		if err != nil {
 			return fmt.Errorf(`could not completely delete the source: %s`, err)
 		}
@@ -123,7 +156,22 @@ func DeleteCascade(tenantId *int64, userId *int64, resourceType string, resource
 	case "Application":
 		applicationsDao := dao.GetApplicationDao(&dao.RequestParams{TenantID: tenantId, UserID: userId})
 
-		applicationAuthentications, application, err := applicationsDao.DeleteCascade(resourceId)
+		var (
+			applicationAuthentications []model.ApplicationAuthentication
+			application                *model.Application
+			err                        error
+		)
+
+		if updatedAt != nil {
+			applicationAuthentications, application, err = applicationsDao.DeleteCascadeIfUnmodified(resourceId, *updatedAt)
+		} else {
+			applicationAuthentications, application, err = applicationsDao.DeleteCascade(resourceId)
+		}
+
+		if errors.As(err, &util.ErrPreconditionFailed{}) {
+			return err
+		}
+
 		if err != nil {
 			return fmt.Errorf(`could not completely delete the application: %s`, err)
 		}
@@ -157,7 +205,21 @@ func DeleteCascade(tenantId *int64, userId *int64, resourceType string, resource
 		// Delete the endpoint.
 		endpointDao := dao.GetEndpointDao(tenantId)
 
-		endpoint, err := endpointDao.Delete(&resourceId)
+		var (
+			endpoint *model.Endpoint
+			err      error
+		)
+
+		if updatedAt != nil {
+			endpoint, err = endpointDao.DeleteIfUnmodified(&resourceId, *updatedAt)
+		} else {
+			endpoint, err = endpointDao.Delete(&resourceId)
+		}
+
+		if errors.As(err, &util.ErrPreconditionFailed{}) {
+			return err
+		}
+
 		if err != nil {
 			return fmt.Errorf(`could not delete the endpoint: %s`, err)
 		}
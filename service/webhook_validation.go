@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/model"
+)
+
+// ValidateWebhookCreateRequest validates that the webhook has an HTTPS URL and that it subscribes to at least one
+// known event type.
+func ValidateWebhookCreateRequest(req *model.WebhookCreateRequest) error {
+	err := validateWebhookURL(req.URL)
+	if err != nil {
+		return err
+	}
+
+	return validateWebhookEventTypes(req.EventTypes)
+}
+
+// ValidateWebhookEditRequest validates the fields of the webhook which are being updated.
+func ValidateWebhookEditRequest(req *model.WebhookEditRequest) error {
+	if req.URL != nil {
+		err := validateWebhookURL(*req.URL)
+		if err != nil {
+			return err
+		}
+	}
+
+	if req.EventTypes != nil {
+		return validateWebhookEventTypes(req.EventTypes)
+	}
+
+	return nil
+}
+
+// validateWebhookURL makes sure that the deliveries only ever leave over HTTPS.
+func validateWebhookURL(rawURL string) error {
+	uri, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("the provided URL is invalid")
+	}
+
+	if uri.Scheme != "https" || uri.Host == "" {
+		return fmt.Errorf("the URL must be an absolute HTTPS URL")
+	}
+
+	if uri.User != nil {
+		return fmt.Errorf("the URL must not contain credentials")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), webhookLookupTimeout)
+	defer cancel()
+
+	addresses, err := lookupWebhookHost(ctx, uri.Hostname())
+	if err != nil || len(addresses) == 0 {
+		return fmt.Errorf("the URL's host could not be resolved")
+	}
+
+	for _, address := range addresses {
+		if !isPublicWebhookIP(address.IP) {
+			return fmt.Errorf("the URL must not point to a private or special-purpose address")
+		}
+	}
+
+	return nil
+}
+
+// webhookLookupTimeout caps how long resolving the webhooks' hosts may take.
+const webhookLookupTimeout = 5 * time.Second
+
+// lookupWebhookHost resolves the hosts of the webhooks' URLs.
+var lookupWebhookHost = net.DefaultResolver.LookupIPAddr
+
+// webhookDeniedPrefixes are the address blocks the webhooks may not reach: the ones of the IANA IPv4 and IPv6
+// special-purpose address registries, along with the multicast and the reserved blocks. They keep the webhooks from
+// reaching the services in the internal network, the cloud metadata endpoints and the addresses which get translated
+// to them.
+var webhookDeniedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this network"
+	netip.MustParsePrefix("10.0.0.0/8"),      // private use
+	netip.MustParsePrefix("100.64.0.0/10"),   // shared address space (CGNAT)
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local
+	netip.MustParsePrefix("172.16.0.0/12"),   // private use
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation (TEST-NET-1)
+	netip.MustParsePrefix("192.31.196.0/24"), // AS112-v4
+	netip.MustParsePrefix("192.52.193.0/24"), // AMT
+	netip.MustParsePrefix("192.88.99.0/24"),  // deprecated 6to4 relay anycast
+	netip.MustParsePrefix("192.168.0.0/16"),  // private use
+	netip.MustParsePrefix("192.175.48.0/24"), // direct delegation AS112 service
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation (TEST-NET-2)
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation (TEST-NET-3)
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, including the limited broadcast address
+	netip.MustParsePrefix("::/128"),          // unspecified
+	netip.MustParsePrefix("::1/128"),         // loopback
+	netip.MustParsePrefix("::ffff:0:0/96"),   // IPv4-mapped
+	netip.MustParsePrefix("64:ff9b::/96"),    // IPv4-IPv6 translation (NAT64)
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local-use IPv4-IPv6 translation
+	netip.MustParsePrefix("100::/64"),        // discard-only
+	netip.MustParsePrefix("2001::/23"),       // IETF protocol assignments, including Teredo
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("3fff::/20"),       // documentation
+	netip.MustParsePrefix("5f00::/16"),       // segment routing (SRv6) SIDs
+	netip.MustParsePrefix("fc00::/7"),        // unique-local
+	netip.MustParsePrefix("fe80::/10"),       // link-local
+	netip.MustParsePrefix("fec0::/10"),       // deprecated site-local
+	netip.MustParsePrefix("ff00::/8"),        // multicast
+}
+
+// isPublicWebhookIP returns true when the deliveries may be sent to the given address. The IPv4-mapped IPv6 addresses
+// are checked as the IPv4 addresses they stand for.
+func isPublicWebhookIP(ip net.IP) bool {
+	address, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+
+	address = address.Unmap()
+
+	for _, prefix := range webhookDeniedPrefixes {
+		if prefix.Contains(address) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// webhookDialControl refuses to connect to the addresses the webhooks are not allowed to reach. It checks the address
+// the connection is actually made to, since the host could resolve to a different address than when the webhook's URL
+// was validated.
+func webhookDialControl(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicWebhookIP(ip) {
+		return fmt.Errorf("the webhook address %s is not allowed", host)
+	}
+
+	return nil
+}
+
+func validateWebhookEventTypes(eventTypes []string) error {
+	if len(eventTypes) == 0 {
+		return fmt.Errorf("at least one event type is required")
+	}
+
+	for _, eventType := range eventTypes {
+		if !slices.Contains(model.WebhookEventTypes, eventType) {
+			return fmt.Errorf(`invalid event type "%s"`, eventType)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,278 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/internal/events"
+	"github.com/RedHatInsights/sources-api-go/kafka"
+	logging "github.com/RedHatInsights/sources-api-go/logger"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/redhatinsights/platform-go-middlewares/v2/identity"
+)
+
+// The headers the webhook deliveries are sent with. The signature is the hex encoded HMAC-SHA256 of the timestamp, a
+// dot and the body, keyed with the webhook's secret, so that the receivers can verify both the sender and the
+// freshness of the delivery.
+const (
+	WebhookDeliveryHeader  = "X-Sources-Delivery"
+	WebhookEventTypeHeader = "X-Sources-Event-Type"
+	WebhookTimestampHeader = "X-Sources-Timestamp"
+	WebhookSignatureHeader = "X-Sources-Signature"
+)
+
+const (
+	// WebhookMaxAttempts is the number of times a delivery gets sent before giving up on it.
+	WebhookMaxAttempts = 8
+	// webhookRetryBaseDelay is the delay before the first retry, which doubles with every failed attempt.
+	webhookRetryBaseDelay = 30 * time.Second
+	// webhookRetryMaxDelay caps the delay between two attempts.
+	webhookRetryMaxDelay = time.Hour
+	// webhookErrorMaxLength caps how long the errors of the delivery log get.
+	webhookErrorMaxLength = 1024
+)
+
+// WebhookClient is the client the deliveries are sent with. The redirects are not followed, since they could lead the
+// deliveries away from the HTTPS URLs the webhooks were validated with, and the connections to the private and the
+// special-purpose addresses are refused. The deliveries do not go through the environment's proxy, so that the
+// addresses get checked for the receivers themselves.
+var WebhookClient = &http.Client{
+	Timeout: 10 * time.Second,
+	Transport: &http.Transport{
+		DialContext: (&net.Dialer{
+			Timeout: 5 * time.Second,
+			Control: webhookDialControl,
+		}).DialContext,
+		TLSHandshakeTimeout: 5 * time.Second,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+	},
+	CheckRedirect: func(_ *http.Request, _ []*http.Request) error {
+		return http.ErrUseLastResponse
+	},
+}
+
+// webhookBody is what the webhooks receive: the event type along with the same payload the event gets raised with.
+type webhookBody struct {
+	EventType string          `json:"event_type"`
+	CreatedAt string          `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// WebhookSender raises the events through the wrapped sender, and then queues their deliveries for the webhooks which
+// are subscribed to them.
+type WebhookSender struct {
+	events.Sender
+}
+
+func (ws *WebhookSender) RaiseEvent(eventType string, payload []byte, headers []kafka.Header) error {
+	err := ws.Sender.RaiseEvent(eventType, payload, headers)
+
+	// The webhooks are a best effort on top of the event stream, so they do not make raising the event fail.
+	queueErr := QueueWebhookDeliveries(eventType, payload, headers)
+	if queueErr != nil {
+		logging.Log.Warnf(`Unable to queue the webhook deliveries of the "%s" event: %s`, eventType, queueErr)
+	}
+
+	return err
+}
+
+// QueueWebhookDeliveries queues a delivery of the event for every enabled webhook of the event's tenant which is
+// subscribed to it. The tenant is picked from the same headers the event gets raised with.
+func QueueWebhookDeliveries(eventType string, payload []byte, headers []kafka.Header) error {
+	if !slices.Contains(model.WebhookEventTypes, eventType) {
+		return nil
+	}
+
+	var id identity.Identity
+	for _, header := range headers {
+		switch header.Key {
+		case h.OrgID:
+			id.OrgID = string(header.Value)
+		case h.AccountNumber:
+			id.AccountNumber = string(header.Value)
+		}
+	}
+
+	if id.OrgID == "" && id.AccountNumber == "" {
+		return nil
+	}
+
+	tenant, err := dao.GetTenantDao().TenantByIdentity(&id)
+	if errors.As(err, &util.ErrNotFound{}) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	webhookDao := dao.GetWebhookDao(&dao.RequestParams{TenantID: &tenant.Id})
+
+	webhooks, err := webhookDao.ListForEventType(eventType)
+	if err != nil {
+		return err
+	}
+
+	if len(webhooks) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+
+	body, err := json.Marshal(webhookBody{
+		EventType: eventType,
+		CreatedAt: util.DateTimeToRFC3339(now),
+		Data:      payload,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal the webhook body: %w", err)
+	}
+
+	deliveries := make([]model.WebhookDelivery, len(webhooks))
+	for i, webhook := range webhooks {
+		deliveries[i] = model.WebhookDelivery{
+			EventType:     eventType,
+			Payload:       body,
+			Status:        model.WebhookDeliveryPending,
+			NextAttemptAt: &now,
+			WebhookID:     webhook.ID,
+		}
+	}
+
+	return webhookDao.CreateDeliveries(deliveries)
+}
+
+// NewWebhookSecret generates the random secret a new webhook's deliveries get signed with.
+func NewWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+
+	_, err := rand.Read(secret)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate the webhook secret: %w", err)
+	}
+
+	return hex.EncodeToString(secret), nil
+}
+
+// SignWebhookPayload returns the signature of the delivery's body sent at the given timestamp.
+func SignWebhookPayload(secret, timestamp string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// WebhookRetryDelay returns how long to wait before sending the delivery again after the given number of attempts.
+func WebhookRetryDelay(attempts int) time.Duration {
+	if attempts < 1 {
+		return webhookRetryBaseDelay
+	}
+
+	delay := webhookRetryBaseDelay << (attempts - 1)
+	if delay <= 0 || delay > webhookRetryMaxDelay {
+		return webhookRetryMaxDelay
+	}
+
+	return delay
+}
+
+// DeliverWebhook sends the delivery to its webhook, which must be preloaded, and records the outcome in the delivery.
+// The failed deliveries are scheduled for a retry with an exponential backoff, until they run out of attempts.
+func DeliverWebhook(delivery *model.WebhookDelivery) {
+	now := time.Now()
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	statusCode, err := sendWebhookDelivery(delivery, now)
+	if statusCode != 0 {
+		delivery.ResponseStatus = &statusCode
+	} else {
+		delivery.ResponseStatus = nil
+	}
+
+	if err == nil {
+		delivery.Status = model.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.Error = ""
+
+		return
+	}
+
+	delivery.Error = err.Error()
+	if len(delivery.Error) > webhookErrorMaxLength {
+		delivery.Error = delivery.Error[:webhookErrorMaxLength]
+	}
+
+	if delivery.Attempts >= WebhookMaxAttempts || !delivery.Webhook.Enabled {
+		delivery.Status = model.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+
+		return
+	}
+
+	nextAttemptAt := now.Add(WebhookRetryDelay(delivery.Attempts))
+
+	delivery.Status = model.WebhookDeliveryPending
+	delivery.NextAttemptAt = &nextAttemptAt
+}
+
+// sendWebhookDelivery signs and sends the delivery, returning the status code of the response if there was one.
+func sendWebhookDelivery(delivery *model.WebhookDelivery, now time.Time) (int, error) {
+	if !delivery.Webhook.Enabled {
+		return 0, errors.New("the webhook is disabled")
+	}
+
+	secret, err := util.Decrypt(delivery.Webhook.Secret)
+	if err != nil {
+		return 0, fmt.Errorf("unable to decrypt the webhook secret: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(now.Unix(), 10)
+
+	ctx, cancel := context.WithTimeout(context.Background(), WebhookClient.Timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, delivery.Webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("unable to build the webhook request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(WebhookEventTypeHeader, delivery.EventType)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(secret, timestamp, delivery.Payload))
+
+	resp, err := WebhookClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("unable to send the webhook request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	// The response's body is left out of the delivery log, so that the webhooks cannot be used to read the responses
+	// of other services back.
+	if resp.StatusCode/100 != 2 {
+		return resp.StatusCode, fmt.Errorf("the webhook responded with status %d", resp.StatusCode)
+	}
+
+	return resp.StatusCode, nil
+}
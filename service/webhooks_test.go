@@ -0,0 +1,224 @@
+package service
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+)
+
+// webhookTestServer starts an HTTPS server which answers the deliveries with the given status code, and points the
+// webhook client at it for the duration of the test.
+func webhookTestServer(t *testing.T, statusCode int, handler func(r *http.Request, body []byte)) *httptest.Server {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		handler(r, body)
+
+		w.WriteHeader(statusCode)
+		_, _ = w.Write([]byte("response body"))
+	}))
+
+	client := server.Client()
+	client.CheckRedirect = WebhookClient.CheckRedirect
+	client.Timeout = WebhookClient.Timeout
+
+	originalClient := WebhookClient
+	WebhookClient = client
+
+	t.Cleanup(func() {
+		WebhookClient = originalClient
+		server.Close()
+	})
+
+	return server
+}
+
+// webhookTestDelivery returns a delivery for a webhook pointing at the given URL and signed with the given secret.
+func webhookTestDelivery(t *testing.T, url, secret string) *model.WebhookDelivery {
+	util.OverrideEncryptionKey(strings.Repeat("test", 8))
+
+	encryptedSecret, err := util.Encrypt(secret)
+	if err != nil {
+		t.Fatalf("unable to encrypt the secret: %s", err)
+	}
+
+	return &model.WebhookDelivery{
+		ID:        10,
+		EventType: "Source.update",
+		Payload:   []byte(`{"event_type":"Source.update","data":{"id":1}}`),
+		Status:    model.WebhookDeliveryPending,
+		Webhook: model.Webhook{
+			ID:      1,
+			URL:     url,
+			Secret:  encryptedSecret,
+			Enabled: true,
+		},
+	}
+}
+
+// TestSignWebhookPayload tests that the payloads get signed with the HMAC-SHA256 of the timestamp and the body.
+func TestSignWebhookPayload(t *testing.T) {
+	// echo -n '1700000000.{"a":1}' | openssl dgst -sha256 -hmac secret
+	want := "sha256=49f24e537407743fa4a0242bb63b94b9a47ee99cbbe071ccd8a22550ae411686"
+
+	got := SignWebhookPayload("secret", "1700000000", []byte(`{"a":1}`))
+	if got != want {
+		t.Fatalf(`want the signature "%s", got "%s"`, want, got)
+	}
+
+	if got == SignWebhookPayload("secret", "1700000001", []byte(`{"a":1}`)) {
+		t.Errorf("want the signature to depend on the timestamp")
+	}
+
+	if got == SignWebhookPayload("other", "1700000000", []byte(`{"a":1}`)) {
+		t.Errorf("want the signature to depend on the secret")
+	}
+}
+
+// TestWebhookRetryDelay tests that the delay doubles with every attempt, up to the cap.
+func TestWebhookRetryDelay(t *testing.T) {
+	testData := map[int]time.Duration{
+		0:   30 * time.Second,
+		1:   30 * time.Second,
+		2:   time.Minute,
+		3:   2 * time.Minute,
+		7:   32 * time.Minute,
+		8:   time.Hour,
+		100: time.Hour,
+	}
+
+	for attempts, want := range testData {
+		got := WebhookRetryDelay(attempts)
+		if got != want {
+			t.Errorf(`want a delay of %s after %d attempts, got %s`, want, attempts, got)
+		}
+	}
+}
+
+// TestDeliverWebhook tests that the delivery gets sent signed and that it is marked as succeeded.
+func TestDeliverWebhook(t *testing.T) {
+	var received *http.Request
+	var receivedBody []byte
+
+	server := webhookTestServer(t, http.StatusNoContent, func(r *http.Request, body []byte) {
+		received = r
+		receivedBody = body
+	})
+
+	delivery := webhookTestDelivery(t, server.URL, "secret")
+
+	DeliverWebhook(delivery)
+
+	if delivery.Status != model.WebhookDeliverySucceeded || delivery.Attempts != 1 || delivery.NextAttemptAt != nil || delivery.LastAttemptAt == nil {
+		t.Fatalf(`unexpected delivery "%+v"`, delivery)
+	}
+
+	if delivery.ResponseStatus == nil || *delivery.ResponseStatus != http.StatusNoContent {
+		t.Errorf(`want the response status %d, got "%v"`, http.StatusNoContent, delivery.ResponseStatus)
+	}
+
+	if received == nil {
+		t.Fatalf("the delivery was not received")
+	}
+
+	if string(receivedBody) != string(delivery.Payload) {
+		t.Errorf(`want the body "%s", got "%s"`, delivery.Payload, receivedBody)
+	}
+
+	if received.Header.Get(WebhookDeliveryHeader) != "10" || received.Header.Get(WebhookEventTypeHeader) != "Source.update" {
+		t.Errorf(`unexpected headers "%v"`, received.Header)
+	}
+
+	want := SignWebhookPayload("secret", received.Header.Get(WebhookTimestampHeader), receivedBody)
+	if received.Header.Get(WebhookSignatureHeader) != want {
+		t.Errorf(`want the signature "%s", got "%s"`, want, received.Header.Get(WebhookSignatureHeader))
+	}
+}
+
+// TestDeliverWebhookRetry tests that the failed deliveries get scheduled for a retry with a backoff.
+func TestDeliverWebhookRetry(t *testing.T) {
+	server := webhookTestServer(t, http.StatusServiceUnavailable, func(_ *http.Request, _ []byte) {})
+
+	delivery := webhookTestDelivery(t, server.URL, "secret")
+	delivery.Attempts = 2
+
+	DeliverWebhook(delivery)
+
+	if delivery.Status != model.WebhookDeliveryPending || delivery.Attempts != 3 {
+		t.Fatalf(`unexpected delivery "%+v"`, delivery)
+	}
+
+	if delivery.ResponseStatus == nil || *delivery.ResponseStatus != http.StatusServiceUnavailable {
+		t.Errorf(`want the response status %d, got "%v"`, http.StatusServiceUnavailable, delivery.ResponseStatus)
+	}
+
+	if strings.Contains(delivery.Error, "response body") {
+		t.Errorf(`want the response body left out of the error, got "%s"`, delivery.Error)
+	}
+
+	if delivery.NextAttemptAt == nil || delivery.NextAttemptAt.Sub(*delivery.LastAttemptAt) != WebhookRetryDelay(3) {
+		t.Errorf(`want the next attempt in %s, got "%v"`, WebhookRetryDelay(3), delivery.NextAttemptAt)
+	}
+}
+
+// TestDeliverWebhookMaxAttempts tests that the deliveries which run out of attempts are marked as failed.
+func TestDeliverWebhookMaxAttempts(t *testing.T) {
+	server := webhookTestServer(t, http.StatusInternalServerError, func(_ *http.Request, _ []byte) {})
+
+	delivery := webhookTestDelivery(t, server.URL, "secret")
+	delivery.Attempts = WebhookMaxAttempts - 1
+
+	DeliverWebhook(delivery)
+
+	if delivery.Status != model.WebhookDeliveryFailed || delivery.Attempts != WebhookMaxAttempts || delivery.NextAttemptAt != nil {
+		t.Errorf(`unexpected delivery "%+v"`, delivery)
+	}
+}
+
+// TestDeliverWebhookDisabled tests that the deliveries of the disabled webhooks are not sent.
+func TestDeliverWebhookDisabled(t *testing.T) {
+	called := false
+	server := webhookTestServer(t, http.StatusOK, func(_ *http.Request, _ []byte) { called = true })
+
+	delivery := webhookTestDelivery(t, server.URL, "secret")
+	delivery.Webhook.Enabled = false
+
+	DeliverWebhook(delivery)
+
+	if called {
+		t.Errorf("the delivery of a disabled webhook must not be sent")
+	}
+
+	if delivery.Status != model.WebhookDeliveryFailed || delivery.ResponseStatus != nil {
+		t.Errorf(`unexpected delivery "%+v"`, delivery)
+	}
+}
+
+// TestDeliverWebhookPrivateAddress tests that the deliveries do not connect to the loopback addresses, even when the
+// webhook's URL got past the validation.
+func TestDeliverWebhookPrivateAddress(t *testing.T) {
+	called := false
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	delivery := webhookTestDelivery(t, server.URL, "secret")
+
+	DeliverWebhook(delivery)
+
+	if called {
+		t.Errorf("the delivery must not reach the loopback address")
+	}
+
+	if delivery.Status != model.WebhookDeliveryPending || !strings.Contains(delivery.Error, "is not allowed") {
+		t.Errorf(`unexpected delivery "%+v"`, delivery)
+	}
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+	"github.com/sirupsen/logrus"
+)
+
+// sourceExportPageSize is the number of sources fetched at a time when exporting.
+const sourceExportPageSize = 100
+
+// sourceExportPreloads are the relations of a source that end up in its export.
+var sourceExportPreloads = []string{"SourceType", "Applications.ApplicationType", "Endpoints", "SourceRhcConnections.RhcConnection"}
+
+// SourceExport exports the given source along with its applications, endpoints, authentications and rhc connections
+// as a bulk create document, which can be imported back through the bulk create or the bulk imports.
+func SourceExport(c echo.Context) error {
+	sourcesDB, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	src, err := sourcesDB.GetByIdWithPreload(&id, sourceExportPreloads...)
+	if err != nil {
+		return err
+	}
+
+	exporter, err := newSourceExporter(c)
+	if err != nil {
+		return err
+	}
+
+	err = exporter.export([]m.Source{*src})
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, exporter.document)
+}
+
+// SourceExportList exports every source which matches the given filters in a single bulk create document, the same
+// way "SourceExport" exports a single source.
+func SourceExportList(c echo.Context) error {
+	sourcesDB, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	filters, err := getFilters(c)
+	if err != nil {
+		return err
+	}
+
+	// When listing sources via cert-auth we want to lock them down to only the
+	// satellite source type.
+	if c.Get("cert-auth") != nil {
+		satelliteId := strconv.Itoa(int(dao.Static.GetSourceTypeId("satellite")))
+		filters = append(filters, util.Filter{Name: "source_type_id", Value: []string{satelliteId}})
+	}
+
+	exporter, err := newSourceExporter(c)
+	if err != nil {
+		return err
+	}
+
+	for offset := 0; ; offset += sourceExportPageSize {
+		sources, count, err := sourcesDB.List(sourceExportPageSize, offset, filters)
+		if err != nil {
+			return err
+		}
+
+		ids := make([]int64, len(sources))
+		for i := range sources {
+			ids[i] = sources[i].ID
+		}
+
+		// the listed sources do not carry the relations, so the whole page is fetched again along with them.
+		preloaded, err := sourcesDB.ListByIdsWithPreload(ids, sourceExportPreloads...)
+		if err != nil {
+			return err
+		}
+
+		err = exporter.export(preloaded)
+		if err != nil {
+			return err
+		}
+
+		if len(sources) == 0 || int64(offset+sourceExportPageSize) >= count {
+			break
+		}
+	}
+
+	handlerLogEntry(c).WithFields(logrus.Fields{
+		"sources_count":         len(exporter.document.Sources),
+		"authentications_count": len(exporter.document.Authentications),
+		"secrets_included":      exporter.encryptionKey != nil,
+	}).Infof("sources exported")
+
+	return c.JSON(http.StatusOK, exporter.document)
+}
+
+// sourceExporter builds the bulk create document of the exported sources. The authentications' passwords are left
+// out, unless the caller provided an encryption key, in which case they are exported encrypted with a key derived
+// from it. The key derivation parameters go in the document's "encryption" header, so that the key can be derived
+// again when importing the document.
+type sourceExporter struct {
+	authDao       dao.AuthenticationDao
+	encryptionKey *util.PassphraseKey
+	document      m.BulkCreateRequest
+}
+
+func newSourceExporter(c echo.Context) (*sourceExporter, error) {
+	authDao, err := getAuthenticationDao(c)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter := &sourceExporter{
+		authDao: authDao,
+		document: m.BulkCreateRequest{
+			Sources:         make([]m.BulkCreateSource, 0),
+			Applications:    make([]m.BulkCreateApplication, 0),
+			Endpoints:       make([]m.BulkCreateEndpoint, 0),
+			Authentications: make([]m.BulkCreateAuthentication, 0),
+			RhcConnections:  make([]m.BulkCreateRhcConnection, 0),
+		},
+	}
+
+	passphrase := c.Request().Header.Get(h.EncryptionKey)
+	if passphrase == "" {
+		return exporter, nil
+	}
+
+	kdf, err := util.NewPassphraseKDF()
+	if err != nil {
+		return nil, err
+	}
+
+	exporter.encryptionKey, err = kdf.DeriveKey(passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	exporter.document.Encryption = kdf
+
+	return exporter, nil
+}
+
+// export adds the given sources, which must have their "sourceExportPreloads" relations loaded, and their dependants
+// to the document. The resources are linked up by the source's name instead of their IDs, since the IDs get generated
+// again when importing the document.
+func (e *sourceExporter) export(sources []m.Source) error {
+	if len(sources) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(sources))
+	for i := range sources {
+		ids[i] = sources[i].ID
+	}
+
+	// the listed authentications do not carry the secrets of every secret store, so they need to be fetched along
+	// with their passwords when these get exported.
+	var authentications []m.Authentication
+	var err error
+	if e.encryptionKey != nil {
+		authentications, err = e.authDao.ListSecretsForSources(ids)
+	} else {
+		authentications, err = e.authDao.ListForSources(ids)
+	}
+	if err != nil {
+		return err
+	}
+
+	authenticationsBySource := make(map[int64][]m.Authentication, len(sources))
+	for _, auth := range authentications {
+		authenticationsBySource[auth.SourceID] = append(authenticationsBySource[auth.SourceID], auth)
+	}
+
+	for i := range sources {
+		err = e.exportSource(&sources[i], authenticationsBySource[sources[i].ID])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportSource adds the given source, its relations and the given authentications of it to the document.
+func (e *sourceExporter) exportSource(src *m.Source, authentications []m.Authentication) error {
+	e.document.Sources = append(e.document.Sources, m.BulkCreateSource{
+		SourceCreateRequest: m.SourceCreateRequest{
+			Name:                util.StringRef(src.Name),
+			Version:             src.Version,
+			Imported:            src.Imported,
+			SourceRef:           src.SourceRef,
+			AppCreationWorkflow: src.AppCreationWorkflow,
+		},
+		SourceTypeName: src.SourceType.Name,
+	})
+
+	// the authentications name their resource by the application type's name or by the endpoint's host.
+	resourceNames := map[string]string{fmt.Sprintf("Source:%d", src.ID): src.Name}
+
+	for _, app := range src.Applications {
+		e.document.Applications = append(e.document.Applications, m.BulkCreateApplication{
+			ApplicationCreateRequest: m.ApplicationCreateRequest{Extra: app.Extra},
+			ApplicationTypeName:      app.ApplicationType.Name,
+			SourceName:               src.Name,
+		})
+
+		resourceNames[fmt.Sprintf("Application:%d", app.ID)] = app.ApplicationType.Name
+	}
+
+	for _, endpt := range src.Endpoints {
+		e.document.Endpoints = append(e.document.Endpoints, m.BulkCreateEndpoint{
+			EndpointCreateRequest: m.EndpointCreateRequest{
+				Default:              endpt.Default != nil && *endpt.Default,
+				ReceptorNode:         endpt.ReceptorNode,
+				Role:                 util.ValueOrBlank(endpt.Role),
+				Scheme:               endpt.Scheme,
+				Host:                 util.ValueOrBlank(endpt.Host),
+				Port:                 endpt.Port,
+				Path:                 util.ValueOrBlank(endpt.Path),
+				VerifySsl:            endpt.VerifySsl,
+				CertificateAuthority: endpt.CertificateAuthority,
+			},
+			SourceName: src.Name,
+		})
+
+		resourceNames[fmt.Sprintf("Endpoint:%d", endpt.ID)] = util.ValueOrBlank(endpt.Host)
+	}
+
+	for _, sourceRhcConnection := range src.SourceRhcConnections {
+		e.document.RhcConnections = append(e.document.RhcConnections, m.BulkCreateRhcConnection{
+			RhcConnectionCreateRequest: m.RhcConnectionCreateRequest{
+				RhcId: sourceRhcConnection.RhcConnection.RhcId,
+				Extra: sourceRhcConnection.RhcConnection.Extra,
+			},
+			SourceName: src.Name,
+		})
+	}
+
+	for i := range authentications {
+		resourceName, ok := resourceNames[fmt.Sprintf("%s:%d", authentications[i].ResourceType, authentications[i].ResourceID)]
+		if !ok {
+			// the authentication's resource is gone, so there is nothing to link it up to.
+			continue
+		}
+
+		err := e.exportAuthentication(&authentications[i], src.Name, resourceName)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportAuthentication adds the given authentication to the document, with its password encrypted with the caller's
+// encryption key if there is one.
+func (e *sourceExporter) exportAuthentication(auth *m.Authentication, sourceName string, resourceName string) error {
+	exported := m.BulkCreateAuthentication{
+		AuthenticationCreateRequest: m.AuthenticationCreateRequest{
+			Name:         auth.Name,
+			AuthType:     auth.AuthType,
+			Username:     auth.Username,
+			Extra:        auth.GetExtra(),
+			ResourceType: strings.ToLower(auth.ResourceType),
+		},
+		ResourceName: resourceName,
+		SourceName:   sourceName,
+	}
+
+	if e.encryptionKey != nil {
+		password, err := auth.GetPassword()
+		if err != nil {
+			return err
+		}
+
+		if password != nil {
+			encrypted, err := e.encryptionKey.Encrypt(*password)
+			if err != nil {
+				return err
+			}
+
+			exported.EncryptedPassword = &encrypted
+		}
+	}
+
+	e.document.Authentications = append(e.document.Authentications, exported)
+
+	return nil
+}
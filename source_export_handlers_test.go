@@ -0,0 +1,278 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/parser"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// sourceExportRequest exports the given source, with the given encryption key if it is not empty.
+func sourceExportRequest(t *testing.T, id string, encryptionKey string) *httptest.ResponseRecorder {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/"+id+"/export",
+		nil,
+		map[string]interface{}{
+			h.TenantID: int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues(id)
+
+	if encryptionKey != "" {
+		c.Request().Header.Set(h.EncryptionKey, encryptionKey)
+	}
+
+	err := ErrorHandlingContext(SourceExport)(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	return rec
+}
+
+// unmarshalSourceExport unmarshals the exported bulk create document.
+func unmarshalSourceExport(t *testing.T, rec *httptest.ResponseRecorder) m.BulkCreateRequest {
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var document m.BulkCreateRequest
+
+	err := json.Unmarshal(rec.Body.Bytes(), &document)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the export: %s", err)
+	}
+
+	return document
+}
+
+// TestSourceExport tests that the source gets exported with its dependants linked up by the source's name, and that
+// the passwords are left out.
+func TestSourceExport(t *testing.T) {
+	src := fixtures.TestSourceData[0]
+
+	document := unmarshalSourceExport(t, sourceExportRequest(t, fmt.Sprintf("%d", src.ID), ""))
+
+	if len(document.Sources) != 1 || *document.Sources[0].Name != src.Name || document.Sources[0].SourceTypeName != "amazon" {
+		t.Fatalf(`unexpected exported sources "%+v"`, document.Sources)
+	}
+
+	if document.Sources[0].Uid != nil {
+		t.Errorf(`want the source's uid left out, got "%s"`, *document.Sources[0].Uid)
+	}
+
+	if len(document.Applications) != 2 || len(document.Endpoints) != 2 || len(document.RhcConnections) != 2 {
+		t.Fatalf(`want 2 applications, 2 endpoints and 2 rhc connections, got "%+v"`, document)
+	}
+
+	for _, app := range document.Applications {
+		if app.SourceName != src.Name || app.ApplicationTypeName == "" {
+			t.Errorf(`unexpected exported application "%+v"`, app)
+		}
+	}
+
+	for _, endpt := range document.Endpoints {
+		if endpt.SourceName != src.Name || endpt.Host == "" {
+			t.Errorf(`unexpected exported endpoint "%+v"`, endpt)
+		}
+	}
+
+	for _, rhcConnection := range document.RhcConnections {
+		if rhcConnection.SourceName != src.Name || rhcConnection.RhcId == "" {
+			t.Errorf(`unexpected exported rhc connection "%+v"`, rhcConnection)
+		}
+	}
+
+	if len(document.Authentications) != 1 {
+		t.Fatalf(`want 1 authentication, got "%+v"`, document.Authentications)
+	}
+
+	auth := document.Authentications[0]
+	if auth.ResourceType != "application" || auth.ResourceName != "app type one" || auth.SourceName != src.Name {
+		t.Errorf(`unexpected exported authentication "%+v"`, auth)
+	}
+
+	if auth.Password != nil || auth.EncryptedPassword != nil {
+		t.Errorf(`want the authentication's password redacted, got "%+v"`, auth)
+	}
+
+	if document.Encryption != nil {
+		t.Errorf(`want no key derivation parameters without an encryption key, got "%+v"`, document.Encryption)
+	}
+}
+
+// TestSourceExportEncryptedPasswords tests that the passwords get exported encrypted with the caller's key, and that
+// they can only be decrypted back with it.
+func TestSourceExportEncryptedPasswords(t *testing.T) {
+	if parser.RunningIntegrationTests {
+		t.Skip("Skipping test, the authentications are mocked")
+	}
+
+	testutils.SkipIfNotSecretStoreDatabase(t)
+
+	util.OverrideEncryptionKey(strings.Repeat("test", 8))
+
+	password, err := util.Encrypt("secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	auth := fixtures.TestAuthenticationData[0]
+	auth.Password = &password
+
+	backup := getAuthenticationDao
+	getAuthenticationDao = func(c echo.Context) (dao.AuthenticationDao, error) {
+		return &mocks.MockAuthenticationDao{Authentications: []m.Authentication{auth}}, nil
+	}
+
+	t.Cleanup(func() { getAuthenticationDao = backup })
+
+	document := unmarshalSourceExport(t, sourceExportRequest(t, "1", "caller key"))
+
+	exported := document.Authentications[0]
+	if exported.Password != nil || exported.EncryptedPassword == nil {
+		t.Fatalf(`want the password encrypted, got "%+v"`, exported)
+	}
+
+	if document.Encryption == nil {
+		t.Fatalf("want the key derivation parameters in the document")
+	}
+
+	key, err := document.Encryption.DeriveKey("caller key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := key.Decrypt(*exported.EncryptedPassword)
+	if err != nil || decrypted != "secret" {
+		t.Errorf(`want the password to decrypt to "secret", got "%s" (%v)`, decrypted, err)
+	}
+}
+
+// TestSourceExportNotFound tests that exporting a source which does not exist returns a not found error.
+func TestSourceExportNotFound(t *testing.T) {
+	templates.NotFoundTest(t, sourceExportRequest(t, "12345", ""))
+}
+
+// TestSourceExportList tests that every listed source gets exported in the same document.
+func TestSourceExportList(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/export",
+		nil,
+		map[string]interface{}{
+			h.TenantID: int64(1),
+			"filters":  []util.Filter{},
+		},
+	)
+
+	err := ErrorHandlingContext(SourceExportList)(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	document := unmarshalSourceExport(t, rec)
+
+	if !parser.RunningIntegrationTests && len(document.Sources) != len(fixtures.TestSourceData) {
+		t.Errorf("want %d exported sources, got %d", len(fixtures.TestSourceData), len(document.Sources))
+	}
+
+	names := make(map[string]bool)
+	for _, src := range document.Sources {
+		names[*src.Name] = true
+	}
+
+	for _, app := range document.Applications {
+		if !names[app.SourceName] {
+			t.Errorf(`want the application to link up to an exported source, got "%+v"`, app)
+		}
+	}
+}
+
+// TestSourceExportReimport tests that an exported source can be imported back through the bulk create.
+func TestSourceExportReimport(t *testing.T) {
+	testutils.SkipIfNotRunningIntegrationTests(t)
+
+	document := unmarshalSourceExport(t, sourceExportRequest(t, "1", ""))
+
+	// the names must not clash with the exported source, which still exists.
+	nameSource := "reimported source"
+	document.Sources[0].Name = &nameSource
+
+	for i := range document.Applications {
+		document.Applications[i].SourceName = nameSource
+	}
+
+	for i := range document.Endpoints {
+		document.Endpoints[i].SourceName = nameSource
+	}
+
+	for i := range document.Authentications {
+		document.Authentications[i].SourceName = nameSource
+	}
+
+	for i := range document.RhcConnections {
+		document.RhcConnections[i].SourceName = nameSource
+	}
+
+	body, err := json.Marshal(document)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/bulk_create",
+		bytes.NewReader(body),
+		map[string]interface{}{
+			h.TenantID: int64(1),
+		},
+	)
+
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+	c.Set(h.ParsedIdentity, testutils.IdentityHeaderForUser("testUser"))
+
+	err = BulkCreate(&mocks.MockSuperKeyProducer{})(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	var response m.BulkCreateResponse
+
+	err = json.Unmarshal(rec.Body.Bytes(), &response)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(response.Applications) != len(document.Applications) || len(response.Endpoints) != len(document.Endpoints) ||
+		len(response.Authentications) != len(document.Authentications) || len(response.RhcConnections) != len(document.RhcConnections) {
+		t.Errorf(`want every exported resource re-imported, got "%+v"`, response)
+	}
+
+	err = cleanSourceForTenant(nameSource, &fixtures.TestTenantData[0].Id)
+	if err != nil {
+		t.Errorf(`unexpected error received when deleting the source: %s`, err)
+	}
+}
@@ -2,11 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 
+	"github.com/RedHatInsights/sources-api-go/config"
 	"github.com/RedHatInsights/sources-api-go/dao"
 	"github.com/RedHatInsights/sources-api-go/logger"
 	"github.com/RedHatInsights/sources-api-go/metrics"
@@ -46,6 +49,11 @@ func SourceList(c echo.Context) error {
 		return err
 	}
 
+	includes, err := parseIncludes(c, sourceIncludes)
+	if err != nil {
+		return err
+	}
+
 	var (
 		sources []m.Source
 		count   int64
@@ -66,11 +74,18 @@ func SourceList(c echo.Context) error {
 	c.Logger().Infof("tenant: %v", *sourcesDB.Tenant())
 
 	out := make([]interface{}, len(sources))
-	for i := 0; i < len(sources); i++ {
-		out[i] = sources[i].ToResponse()
+	if len(includes) == 0 {
+		for i := 0; i < len(sources); i++ {
+			out[i] = sources[i].ToResponse()
+		}
+	} else {
+		out, err = sourcesWithIncluded(c, sourcesDB, sources, includes)
+		if err != nil {
+			return err
+		}
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(sources)...))
 }
 
 func SourceGet(c echo.Context) error {
@@ -84,6 +99,11 @@ func SourceGet(c echo.Context) error {
 		return util.NewErrBadRequest(err)
 	}
 
+	includes, err := parseIncludes(c, sourceIncludes)
+	if err != nil {
+		return err
+	}
+
 	c.Logger().Infof("Getting Source Id %v", id)
 
 	s, err := sourcesDB.GetById(&id)
@@ -91,7 +111,61 @@ func SourceGet(c echo.Context) error {
 		return err
 	}
 
-	return c.JSON(http.StatusOK, s.ToResponse())
+	if len(includes) == 0 {
+		return sendResource(c, s, s.ToResponse())
+	}
+
+	// The entity tag only tracks the source itself, so it is not sent along with the related records.
+	out, err := sourcesWithIncluded(c, sourcesDB, []m.Source{*s}, includes)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, out[0])
+}
+
+// sourcesWithIncluded returns the sources' responses along with the requested related records. The related records
+// of all the sources are loaded at once, instead of one source at a time.
+func sourcesWithIncluded(c echo.Context, sourcesDB dao.SourceDao, sources []m.Source, includes []string) ([]interface{}, error) {
+	ids := make([]int64, len(sources))
+	for i := range sources {
+		ids[i] = sources[i].ID
+	}
+
+	preloaded, err := sourcesDB.ListByIdsWithPreload(ids, preloadsFor(includes, sourcePreloads)...)
+	if err != nil {
+		return nil, err
+	}
+
+	preloadedByID := make(map[int64]*m.Source, len(preloaded))
+	for i := range preloaded {
+		preloadedByID[preloaded[i].ID] = &preloaded[i]
+	}
+
+	var auths map[int64][]interface{}
+
+	if slices.Contains(includes, "authentications") {
+		auths, err = includedAuthentications(c, ids, "source")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	out := make([]interface{}, len(sources))
+
+	for i := range sources {
+		src, ok := preloadedByID[sources[i].ID]
+		if !ok {
+			return nil, util.NewErrNotFound("source")
+		}
+
+		out[i], err = withIncluded(c, sources[i].ToResponse(), sourceIncluded(src, auths[src.ID], includes), sourceIncludes)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return out, nil
 }
 
 func SourceCreate(c echo.Context) error {
@@ -156,6 +230,16 @@ func SourceEdit(c echo.Context) error {
 		return err
 	}
 
+	err = checkIfMatch(c, s)
+	if err != nil {
+		return err
+	}
+
+	// Keep the fetched version, so that the update does not go through if the source gets modified in the meantime.
+	updatedAt := s.UpdatedAt
+
+	setAuditPrevious(c, s)
+
 	// Store the previous status before updating the source.
 	previousStatus := s.AvailabilityStatus
 
@@ -163,7 +247,7 @@ func SourceEdit(c echo.Context) error {
 	if s.PausedAt != nil {
 		input := &m.SourcePausedEditRequest{}
 
-		err := c.Bind(input)
+		err := bindEditRequest(c, s.ToResponse(), input)
 		if err != nil {
 			return err
 		}
@@ -175,7 +259,7 @@ func SourceEdit(c echo.Context) error {
 	} else {
 		input := &m.SourceEditRequest{}
 
-		err := c.Bind(input)
+		err := bindEditRequest(c, s.ToResponse(), input)
 		if err != nil {
 			return err
 		}
@@ -188,7 +272,12 @@ func SourceEdit(c echo.Context) error {
 		s.UpdateFromRequest(input)
 	}
 
-	err = sourcesDB.Update(s)
+	if hasIfMatch(c) {
+		err = sourcesDB.UpdateIfUnmodified(s, updatedAt)
+	} else {
+		err = sourcesDB.Update(s)
+	}
+
 	if err != nil {
 		return err
 	}
@@ -200,6 +289,7 @@ func SourceEdit(c echo.Context) error {
 
 	setNotificationForAvailabilityStatus(c, previousStatus, s)
 	setEventStreamResource(c, s)
+	setETag(c, s)
 
 	return c.JSON(http.StatusOK, s.ToResponse())
 }
@@ -220,6 +310,11 @@ func SourceDelete(c echo.Context) (err error) {
 		return err
 	}
 
+	err = checkIfMatch(c, s)
+	if err != nil {
+		return err
+	}
+
 	if c.Get("cert-auth") != nil {
 		satelliteId := dao.Static.GetSourceTypeId("satellite")
 		if s.SourceTypeID != satelliteId {
@@ -228,10 +323,40 @@ func SourceDelete(c echo.Context) (err error) {
 		}
 	}
 
+	// Keep the source around for the retention window so that it can be restored. The "PurgeDeletedSourcesJob"
+	// deletes it for good, and raises the destroy events, once the window expires.
+	if config.Get().SourceRetention > 0 {
+		if hasIfMatch(c) {
+			_, err = sourcesDB.SoftDeleteIfUnmodified(id, s.UpdatedAt)
+		} else {
+			_, err = sourcesDB.SoftDelete(id)
+		}
+
+		if err != nil {
+			return err
+		}
+
+		handlerLogEntry(c).WithFields(logrus.Fields{
+			"tenant_id": *sourcesDB.Tenant(),
+			"source_id": id,
+		}).Infof("soft deleted source")
+
+		setAuditDestroyedResource(c, s)
+
+		return c.NoContent(http.StatusNoContent)
+	}
+
 	// Superkey sources are deleted asynchronously: enqueue a job that
-	// tells the superkey worker to clean up cloud resources first.
+	// tells the superkey worker to clean up cloud resources first. The
+	// "If-Match" header can only be checked against the fetched source
+	// for these.
 	if sourcesDB.IsSuperkey(id) {
-		return enqueueSuperKeyDelete(c, "source", id)
+		err = enqueueSuperKeyDelete(c, "source", id)
+		if err != nil {
+			return err
+		}
+
+		return c.NoContent(http.StatusAccepted)
 	}
 
 	// Non-superkey: cascade delete immediately.
@@ -240,7 +365,16 @@ func SourceDelete(c echo.Context) (err error) {
 		return err
 	}
 
-	err = service.DeleteCascade(sourcesDB.Tenant(), sourcesDB.User(), "Source", id, forwardableHeaders)
+	if hasIfMatch(c) {
+		err = service.DeleteCascadeIfUnmodified(sourcesDB.Tenant(), sourcesDB.User(), "Source", id, s.UpdatedAt, forwardableHeaders)
+	} else {
+		err = service.DeleteCascade(sourcesDB.Tenant(), sourcesDB.User(), "Source", id, forwardableHeaders)
+	}
+
+	if errors.As(err, &util.ErrPreconditionFailed{}) {
+		return err
+	}
+
 	if err != nil {
 		return util.NewErrBadRequest(err)
 	}
@@ -250,9 +384,39 @@ func SourceDelete(c echo.Context) (err error) {
 		"source_id": id,
 	}).Infof("deleted source")
 
+	setAuditDestroyedResource(c, s)
+
 	return c.NoContent(http.StatusNoContent)
 }
 
+// SourceRestore brings back a soft deleted source whose retention window has not expired yet.
+func SourceRestore(c echo.Context) error {
+	sourcesDB, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	s, err := sourcesDB.Restore(id)
+	if err != nil {
+		return err
+	}
+
+	handlerLogEntry(c).WithFields(logrus.Fields{
+		"tenant_id": *sourcesDB.Tenant(),
+		"source_id": id,
+	}).Infof("restored source")
+
+	setAuditRestoredResource(c, s)
+	setETag(c, s)
+
+	return c.JSON(http.StatusOK, s.ToResponse())
+}
+
 func SourceListAuthentications(c echo.Context) error {
 	authDao, err := getAuthenticationDao(c)
 	if err != nil {
@@ -315,7 +479,7 @@ func SourceTypeListSource(c echo.Context) error {
 		out[i] = sources[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(sources)...))
 }
 
 func ApplicationTypeListSource(c echo.Context) error {
@@ -356,7 +520,7 @@ func ApplicationTypeListSource(c echo.Context) error {
 		out[i] = sources[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(sources)...))
 }
 
 func SourceCheckAvailability(metricsService metrics.MetricsService) echo.HandlerFunc {
@@ -472,7 +636,7 @@ func SourcesRhcConnectionList(c echo.Context) error {
 		out[i] = rhcConnections[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(rhcConnections)...))
 }
 
 // SourcePause pauses a source and all its dependant applications, by setting the former's and the latter's "paused_at"
@@ -141,7 +141,7 @@ func TestSourceListAuthentications(t *testing.T) {
 		}
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 
 	conf.SecretStore = originalSecretStore
 }
@@ -363,7 +363,7 @@ func TestSourceTypeSourceSubcollectionList(t *testing.T) {
 		t.Error(err)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestSourceTypeSourceSubcollectionListTenantNotExists tests that empty list
@@ -572,7 +572,7 @@ func TestApplicationTypeListSourceSubcollectionList(t *testing.T) {
 		t.Error(err)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestApplicationTypeListSourceSubcollectionListTenantNotExists tests that empty list
@@ -791,7 +791,7 @@ func TestSourceList(t *testing.T) {
 		t.Error(err)
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 // TestSourceListTenantNotExists tests that empty list is returned for not existing tenant
@@ -900,7 +900,7 @@ func TestSourceListSatellite(t *testing.T) {
 		t.Error("ghosts infected the return")
 	}
 
-	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, 100, 0)
+	testutils.AssertLinks(t, c.Request().RequestURI, out.Links, out.Meta.Count, 100, 0)
 }
 
 func TestSourceListBadRequestInvalidFilter(t *testing.T) {
@@ -1092,6 +1092,158 @@ func TestSourceGetBadRequest(t *testing.T) {
 	templates.BadRequestTest(t, rec)
 }
 
+// TestSourceGetInclude tests that the requested related records are returned along with the source, and that the
+// authentications do not carry their secrets.
+func TestSourceGetInclude(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/1?include=applications,endpoints,authentications,rhc_connections,source_type",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	err := SourceGet(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want status code %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var out struct {
+		m.SourceResponse
+		Applications    []map[string]interface{} `json:"applications"`
+		Endpoints       []map[string]interface{} `json:"endpoints"`
+		Authentications []map[string]interface{} `json:"authentications"`
+		RhcConnections  []map[string]interface{} `json:"rhc_connections"`
+		SourceType      map[string]interface{}   `json:"source_type"`
+	}
+
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("failed unmarshaling output: %s", err)
+	}
+
+	if out.ID != "1" || *out.Name != "Source1" {
+		t.Errorf(`want the source with id "1", got "%s"`, rec.Body.String())
+	}
+
+	if len(out.Applications) == 0 || len(out.Endpoints) == 0 || len(out.Authentications) == 0 || len(out.RhcConnections) == 0 {
+		t.Errorf(`want the related records to be included, got "%s"`, rec.Body.String())
+	}
+
+	for _, app := range out.Applications {
+		if app["source_id"] != "1" {
+			t.Errorf(`want only the source's applications, got "%v"`, app)
+		}
+	}
+
+	if out.SourceType["id"] != strconv.FormatInt(fixtures.TestSourceData[0].SourceTypeID, 10) {
+		t.Errorf(`want the source's source type, got "%v"`, out.SourceType)
+	}
+
+	for _, auth := range out.Authentications {
+		if _, ok := auth["password"]; ok {
+			t.Errorf(`want the included authentications to be redacted, got "%v"`, auth)
+		}
+
+		if _, ok := auth["password_hash"]; ok {
+			t.Errorf(`want the included authentications to be redacted, got "%v"`, auth)
+		}
+	}
+
+	if rec.Header().Get(h.ETag) != "" {
+		t.Errorf("want no entity tag for the compound documents")
+	}
+}
+
+// TestSourceGetIncludeBadRequest tests that the unknown relations get rejected.
+func TestSourceGetIncludeBadRequest(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/1?include=applications,tenant",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	badRequestSourceGet := ErrorHandlingContext(SourceGet)
+
+	err := badRequestSourceGet(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
+// TestSourceListInclude tests that every listed source gets its related records included.
+func TestSourceListInclude(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources?include=applications,authentications",
+		nil,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"filters":  []util.Filter{},
+			"tenantID": int64(1),
+		},
+	)
+
+	err := SourceList(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("want status code %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var out struct {
+		Data []struct {
+			ID              string                   `json:"id"`
+			Applications    []map[string]interface{} `json:"applications"`
+			Authentications []map[string]interface{} `json:"authentications"`
+		} `json:"data"`
+	}
+
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("failed unmarshaling output: %s", err)
+	}
+
+	if len(out.Data) == 0 {
+		t.Fatalf(`want sources in the response, got "%s"`, rec.Body.String())
+	}
+
+	for _, src := range out.Data {
+		if src.Applications == nil {
+			t.Errorf(`want the applications of the source "%s" to be included`, src.ID)
+		}
+
+		if src.Authentications == nil {
+			t.Errorf(`want the authentications of the source "%s" to be included`, src.ID)
+		}
+
+		for _, app := range src.Applications {
+			if app["source_id"] != src.ID {
+				t.Errorf(`want only the applications of the source "%s", got "%v"`, src.ID, app)
+			}
+		}
+	}
+}
+
 // TestSourceCreateBadRequest tests that the handler responds with an 400 when an invalid JSON is received
 func TestSourceCreateBadRequest(t *testing.T) {
 	emptyName := ""
@@ -1339,6 +1491,79 @@ func TestSourceEditNotFound(t *testing.T) {
 	templates.NotFoundTest(t, rec)
 }
 
+// TestSourceGetNotModified tests that a "304 Not Modified" response is sent when the client already has the current
+// representation of the source.
+func TestSourceGetNotModified(t *testing.T) {
+	source := fixtures.TestSourceData[0]
+
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		"/api/sources/v3.1/sources/1",
+		nil,
+		map[string]interface{}{
+			"tenantID": source.TenantID,
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues(fmt.Sprintf("%d", source.ID))
+	c.Request().Header.Set(h.IfNoneMatch, source.ETag())
+
+	err := SourceGet(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusNotModified {
+		t.Errorf("Wrong return code, expected %v got %v", http.StatusNotModified, rec.Code)
+	}
+
+	if rec.Body.Len() != 0 {
+		t.Errorf("Expected an empty body, got %s", rec.Body.String())
+	}
+
+	if got := rec.Header().Get(h.ETag); got != source.ETag() {
+		t.Errorf(`Wrong entity tag, expected "%s" got "%s"`, source.ETag(), got)
+	}
+}
+
+// TestSourceEditPreconditionFailed tests that a "412 Precondition Failed" response is sent when the client tries to
+// update the source from a stale representation.
+func TestSourceEditPreconditionFailed(t *testing.T) {
+	source := fixtures.TestSourceData[0]
+
+	req := m.SourceEditRequest{
+		Name: util.StringRef("New source name"),
+	}
+
+	body, _ := json.Marshal(req)
+
+	c, rec := request.CreateTestContext(
+		http.MethodPatch,
+		"/api/sources/v3.1/sources/1",
+		bytes.NewReader(body),
+		map[string]interface{}{
+			"tenantID": source.TenantID,
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues(fmt.Sprintf("%d", source.ID))
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+	c.Request().Header.Set(h.IfMatch, util.NewETag("stale"))
+
+	preconditionFailedSourceEdit := ErrorHandlingContext(SourceEdit)
+
+	err := preconditionFailedSourceEdit(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("Wrong return code, expected %v got %v", http.StatusPreconditionFailed, rec.Code)
+	}
+}
+
 func TestSourceEditBadRequest(t *testing.T) {
 	newSourceName := "New source name"
 	req := m.SourceEditRequest{
@@ -1486,6 +1711,11 @@ func TestSourceDelete(t *testing.T) {
 	// SourceDelete() uses cascade delete - this test creates own data
 	// and checks that all related objects were deleted (app auths, apps,
 	// endpoints, rhc connections and source itself)
+	// without a retention window the source gets deleted right away.
+	originalSourceRetention := conf.SourceRetention
+	conf.SourceRetention = 0
+
+	defer func() { conf.SourceRetention = originalSourceRetention }()
 
 	// List for all created authentications
 	var auths []m.Authentication
@@ -1760,6 +1990,193 @@ func TestSourceDeleteBadRequest(t *testing.T) {
 	templates.BadRequestTest(t, rec)
 }
 
+// TestSourceDeleteSoftDeletes tests that with a retention window the source only gets soft deleted, and that the
+// deletion ends up in the audit trail.
+func TestSourceDeleteSoftDeletes(t *testing.T) {
+	originalSourceRetention := conf.SourceRetention
+	conf.SourceRetention = 3600
+
+	defer func() { conf.SourceRetention = originalSourceRetention }()
+
+	c, rec := request.CreateTestContext(
+		http.MethodDelete,
+		"/api/sources/v3.1/sources/1",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	// Use a mock so that the fixture source does not get soft deleted in the integration tests' database.
+	backupDao := getSourceDao
+	getSourceDao = func(c echo.Context) (dao.SourceDao, error) {
+		return &mocks.MockSourceDao{Sources: fixtures.TestSourceData}, nil
+	}
+
+	defer func() { getSourceDao = backupDao }()
+
+	err := SourceDelete(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusNoContent {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusNoContent, rec.Code)
+	}
+
+	if c.Get("audit_event_type") != "Source.destroy" {
+		t.Errorf(`want the "Source.destroy" audit event type, got "%v"`, c.Get("audit_event_type"))
+	}
+}
+
+func TestSourceRestore(t *testing.T) {
+	deletedAt := time.Now()
+	deletedSource := fixtures.TestSourceData[0]
+	deletedSource.DeletedAt = &deletedAt
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/sources/1/restore",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	backupDao := getSourceDao
+	getSourceDao = func(c echo.Context) (dao.SourceDao, error) {
+		return &mocks.MockSourceDao{DeletedSources: []m.Source{deletedSource}}, nil
+	}
+
+	defer func() { getSourceDao = backupDao }()
+
+	err := SourceRestore(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusOK {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusOK, rec.Code)
+	}
+
+	var out m.SourceResponse
+
+	err = json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if out.ID != "1" {
+		t.Errorf(`want the restored source "1", got "%s"`, out.ID)
+	}
+
+	if c.Get("audit_event_type") != "Source.restore" {
+		t.Errorf(`want the "Source.restore" audit event type, got "%v"`, c.Get("audit_event_type"))
+	}
+}
+
+// TestSourceRestoreNotFound tests that the sources which are not soft deleted cannot be restored.
+func TestSourceRestoreNotFound(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/sources/1/restore",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	backupDao := getSourceDao
+	getSourceDao = func(c echo.Context) (dao.SourceDao, error) {
+		return &mocks.MockSourceDao{Sources: fixtures.TestSourceData}, nil
+	}
+
+	defer func() { getSourceDao = backupDao }()
+
+	notFoundSourceRestore := ErrorHandlingContext(SourceRestore)
+
+	err := notFoundSourceRestore(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.NotFoundTest(t, rec)
+}
+
+// TestSourceRestoreNameTaken tests that a soft deleted source cannot be restored when a new source took its name.
+func TestSourceRestoreNameTaken(t *testing.T) {
+	deletedAt := time.Now()
+	deletedSource := fixtures.TestSourceData[0]
+	deletedSource.DeletedAt = &deletedAt
+
+	newSource := fixtures.TestSourceData[1]
+	newSource.Name = deletedSource.Name
+
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/sources/1/restore",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("1")
+
+	backupDao := getSourceDao
+	getSourceDao = func(c echo.Context) (dao.SourceDao, error) {
+		return &mocks.MockSourceDao{Sources: []m.Source{newSource}, DeletedSources: []m.Source{deletedSource}}, nil
+	}
+
+	defer func() { getSourceDao = backupDao }()
+
+	err := ErrorHandlingContext(SourceRestore)(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	if rec.Code != http.StatusConflict {
+		t.Errorf(`want status "%d", got "%d"`, http.StatusConflict, rec.Code)
+	}
+
+	if c.Get("audit_event_type") != nil {
+		t.Errorf(`want no audit event, got "%v"`, c.Get("audit_event_type"))
+	}
+}
+
+func TestSourceRestoreBadRequest(t *testing.T) {
+	c, rec := request.CreateTestContext(
+		http.MethodPost,
+		"/api/sources/v3.1/sources/xxx/restore",
+		nil,
+		map[string]interface{}{
+			"tenantID": int64(1),
+		},
+	)
+
+	c.SetParamNames("id")
+	c.SetParamValues("xxx")
+
+	badRequestSourceRestore := ErrorHandlingContext(SourceRestore)
+
+	err := badRequestSourceRestore(c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	templates.BadRequestTest(t, rec)
+}
+
 func TestAvailabilityStatusCheck(t *testing.T) {
 	c, rec := request.CreateTestContext(
 		http.MethodPost,
@@ -3000,6 +3417,12 @@ func TestSourceDeleteWithOwnership(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 	testutils.SkipIfNotSecretStoreDatabase(t)
 
+	// without a retention window the source gets deleted right away.
+	originalSourceRetention := conf.SourceRetention
+	conf.SourceRetention = 0
+
+	defer func() { conf.SourceRetention = originalSourceRetention }()
+
 	accountNumber := "112567"
 	userIDWithOwnRecords := "user_based_user"
 
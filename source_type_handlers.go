@@ -45,7 +45,7 @@ func SourceTypeList(c echo.Context) error {
 		out[i] = sourceTypes[i].ToResponse()
 	}
 
-	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset))
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(sourceTypes)...))
 }
 
 func SourceTypeGet(c echo.Context) error {
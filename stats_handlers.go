@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// SourceStats counts the sources which match the given filters, grouped by the "group_by" dimensions.
+func SourceStats(c echo.Context) error {
+	sourcesDB, err := getSourceDao(c)
+	if err != nil {
+		return err
+	}
+
+	filters, err := getFilters(c)
+	if err != nil {
+		return err
+	}
+
+	// When listing sources via cert-auth we want to lock them down to only the
+	// satellite source type.
+	if c.Get("cert-auth") != nil {
+		satelliteId := strconv.Itoa(int(dao.Static.GetSourceTypeId("satellite")))
+		filters = append(filters, util.Filter{Name: "source_type_id", Value: []string{satelliteId}})
+	}
+
+	groupBy := parseGroupBy(c)
+
+	groups, err := sourcesDB.Stats(groupBy, filters)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, m.NewStatsResponse(groupBy, groups))
+}
+
+// ApplicationStats counts the applications which match the given filters, grouped by the "group_by" dimensions.
+func ApplicationStats(c echo.Context) error {
+	applicationDB, err := getApplicationDao(c)
+	if err != nil {
+		return err
+	}
+
+	filters, err := getFilters(c)
+	if err != nil {
+		return err
+	}
+
+	groupBy := parseGroupBy(c)
+
+	groups, err := applicationDB.Stats(groupBy, filters)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, m.NewStatsResponse(groupBy, groups))
+}
+
+// parseGroupBy returns the requested dimensions, which can either come as repeated "group_by" parameters or as a
+// comma separated list.
+func parseGroupBy(c echo.Context) []string {
+	groupBy := make([]string, 0)
+
+	for _, value := range c.QueryParams()["group_by"] {
+		for _, dimension := range strings.Split(value, ",") {
+			if dimension = strings.TrimSpace(dimension); dimension != "" {
+				groupBy = append(groupBy, dimension)
+			}
+		}
+	}
+
+	return groupBy
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/fixtures"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/parser"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// statsRequest requests the stats of the given handler with the given query.
+func statsRequest(t *testing.T, handler echo.HandlerFunc, path string) *httptest.ResponseRecorder {
+	c, rec := request.CreateTestContext(
+		http.MethodGet,
+		path,
+		nil,
+		map[string]interface{}{
+			h.TenantID: int64(1),
+			"filters":  []util.Filter{},
+		},
+	)
+
+	err := ErrorHandlingContext(handler)(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	return rec
+}
+
+// unmarshalStats unmarshals the stats response.
+func unmarshalStats(t *testing.T, rec *httptest.ResponseRecorder) m.StatsResponse {
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var stats m.StatsResponse
+
+	err := json.Unmarshal(rec.Body.Bytes(), &stats)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the stats: %s", err)
+	}
+
+	return stats
+}
+
+// TestSourceStats tests that the sources get counted by their availability status, and that the group counts add
+// up to the total.
+func TestSourceStats(t *testing.T) {
+	stats := unmarshalStats(t, statsRequest(t, SourceStats, "/api/sources/v3.1/sources/stats?group_by=availability_status"))
+
+	if len(stats.GroupBy) != 1 || stats.GroupBy[0] != "availability_status" {
+		t.Errorf(`want the "availability_status" dimension echoed back, got "%v"`, stats.GroupBy)
+	}
+
+	var sum int64
+	for _, group := range stats.Groups {
+		if _, ok := group.Dimensions["availability_status"]; !ok {
+			t.Errorf(`want the "availability_status" dimension in the group, got "%+v"`, group)
+		}
+
+		sum += group.Count
+	}
+
+	if sum != stats.Total {
+		t.Errorf("want the groups to add up to %d, got %d", stats.Total, sum)
+	}
+
+	if !parser.RunningIntegrationTests && stats.Total != int64(len(fixtures.TestSourceData)) {
+		t.Errorf("want %d sources counted, got %d", len(fixtures.TestSourceData), stats.Total)
+	}
+}
+
+// TestApplicationStatsWithoutDimensions tests that the applications get counted in a single group when no dimensions
+// are requested.
+func TestApplicationStatsWithoutDimensions(t *testing.T) {
+	stats := unmarshalStats(t, statsRequest(t, ApplicationStats, "/api/sources/v3.1/applications/stats"))
+
+	if len(stats.Groups) != 1 || len(stats.Groups[0].Dimensions) != 0 {
+		t.Fatalf(`want a single group without dimensions, got "%+v"`, stats.Groups)
+	}
+
+	if !parser.RunningIntegrationTests && stats.Total != int64(len(fixtures.TestApplicationData)) {
+		t.Errorf("want %d applications counted, got %d", len(fixtures.TestApplicationData), stats.Total)
+	}
+}
+
+// TestStatsBadDimension tests that the unknown dimensions get rejected.
+func TestStatsBadDimension(t *testing.T) {
+	templates.BadRequestTest(t, statsRequest(t, SourceStats, "/api/sources/v3.1/sources/stats?group_by=availability_status,password"))
+}
+
+// TestParseGroupBy tests that the dimensions can be given both as repeated parameters and as comma separated lists.
+func TestParseGroupBy(t *testing.T) {
+	c, _ := request.CreateTestContext(http.MethodGet, "/api/sources/v3.1/sources/stats?group_by=availability_status,%20source_type&group_by=app_creation_workflow&group_by=", nil, map[string]interface{}{})
+
+	groupBy := parseGroupBy(c)
+
+	want := []string{"availability_status", "source_type", "app_creation_workflow"}
+	if len(groupBy) != len(want) {
+		t.Fatalf(`want "%v", got "%v"`, want, groupBy)
+	}
+
+	for i := range want {
+		if groupBy[i] != want[i] {
+			t.Errorf(`want "%v", got "%v"`, want, groupBy)
+		}
+	}
+}
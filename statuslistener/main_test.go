@@ -5,8 +5,10 @@ import (
 	"testing"
 
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/database"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/mocks"
 	"github.com/RedHatInsights/sources-api-go/internal/testutils/parser"
 	l "github.com/RedHatInsights/sources-api-go/logger"
+	"github.com/RedHatInsights/sources-api-go/service"
 )
 
 // runningIntegration is used to skip integration tests if we're just running unit tests.
@@ -17,6 +19,8 @@ func TestMain(t *testing.M) {
 
 	flags := parser.ParseFlags()
 
+	service.Broadcaster = &mocks.MockEventBroadcaster{}
+
 	if flags.CreateDb {
 		database.CreateTestDB()
 	} else if flags.Integration {
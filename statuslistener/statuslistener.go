@@ -49,9 +49,10 @@ func Run(shutdown chan struct{}) {
 	avs.subscribeToAvailabilityStatus(shutdown)
 }
 
+// NewEventStreamProducer returns a producer which raises the events through the service's producer, so that they
+// reach the tenants' webhooks and event streams too.
 func NewEventStreamProducer() *events.EventStreamProducer {
-	sender := &events.EventStreamSender{}
-	return &events.EventStreamProducer{Sender: sender}
+	return &events.EventStreamProducer{Sender: service.Producer()}
 }
 
 func (avs *AvailabilityStatusListener) subscribeToAvailabilityStatus(shutdown chan struct{}) {
@@ -199,6 +200,11 @@ func (avs *AvailabilityStatusListener) processEvent(statusMessage types.StatusMe
 				l.Log.Errorf("[tenant_id: %d][resource_type: %s][resource_id: %d][resource_uuid: %s] unable to emit notification: %v", resource.TenantID, resource.ResourceType, resource.ResourceID, resource.ResourceUID, err)
 			}
 		}
+
+		err = service.BroadcastAvailabilityTransition(tenant, statusMessage.ResourceType, statusMessage.ResourceID, previousStatus, statusMessage.Status)
+		if err != nil {
+			l.Log.Warnf("[tenant_id: %d][resource_type: %s][resource_id: %d][resource_uuid: %s] unable to broadcast the availability transition: %v", resource.TenantID, resource.ResourceType, resource.ResourceID, resource.ResourceUID, err)
+		}
 	}
 
 	updateAttributeKeys := make([]string, 0)
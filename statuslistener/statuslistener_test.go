@@ -424,6 +424,22 @@ type TestData struct {
 	EmailNotificationInfo                     *m.EmailNotificationInfo
 }
 
+// broadcastedStreamEvent returns whether an event of the given type was broadcast to the tenants' event streams.
+func broadcastedStreamEvent(broadcaster *mocks.MockEventBroadcaster, eventType string) bool {
+	for _, messages := range broadcaster.Published {
+		for _, message := range messages {
+			var event service.StreamEvent
+
+			err := json.Unmarshal(message, &event)
+			if err == nil && event.EventType == eventType {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
 func TestConsumeStatusMessage(t *testing.T) {
 	testutils.SkipIfNotRunningIntegrationTests(t)
 
@@ -470,6 +486,9 @@ func TestConsumeStatusMessage(t *testing.T) {
 	for _, testEntry := range testData {
 		service.NotificationProducer = &mocks.MockAvailabilityStatusNotificationProducer{EmailNotificationInfo: testEntry.EmailNotificationInfo}
 
+		broadcaster := &mocks.MockEventBroadcaster{}
+		service.Broadcaster = broadcaster
+
 		sender := MockEventStreamSender{TestSuite: t, StatusMessage: testEntry.StatusMessage}
 		esp := &events.EventStreamProducer{Sender: &sender}
 
@@ -508,6 +527,10 @@ func TestConsumeStatusMessage(t *testing.T) {
 				t.Errorf("Invalid email notification data(%s:%s)", testEntry.ResourceType, testEntry.ResourceID)
 				t.Errorf("Expected: %v Obtained: %v", testEntry.EmailNotificationInfo, notificationProducer.EmailNotificationInfo)
 			}
+
+			if !broadcastedStreamEvent(broadcaster, testEntry.ResourceType+".availability_status") {
+				t.Errorf("the availability transition was not broadcast (%s:%s)", testEntry.ResourceType, testEntry.ResourceID)
+			}
 		}
 	}
 
@@ -38,8 +38,13 @@ func setupSuperKeyTest(superKeyEnabled bool) func() {
 	origGlobalGetSourceDao := dao.GetSourceDao
 	origGlobalGetApplicationDao := dao.GetApplicationDao
 	origGlobalGetAuthDao := dao.GetAuthenticationDao
+	origGlobalGetAuditEventDao := dao.GetAuditEventDao
 	origEnqueue := jobs.Enqueue
 
+	// Without a retention window the sources get deleted right away
+	origSourceRetention := conf.SourceRetention
+	conf.SourceRetention = 0
+
 	// Build mock DAOs
 	mockSrcDao := &mocks.MockSourceDao{
 		Sources:         fixtures.TestSourceData,
@@ -63,6 +68,10 @@ func setupSuperKeyTest(superKeyEnabled bool) func() {
 	dao.GetApplicationDao = func(_ *dao.RequestParams) dao.ApplicationDao { return mockAppDao }
 	dao.GetAuthenticationDao = func(_ *dao.RequestParams) dao.AuthenticationDao { return mockAuthDao }
 
+	// The handlers which destroy several resources record the audit events themselves
+	mockAuditDao := &mocks.MockAuditEventDao{}
+	dao.GetAuditEventDao = func(_ *dao.RequestParams) dao.AuditEventDao { return mockAuditDao }
+
 	// Mock Enqueue
 	mockEnqueuedJobs = nil
 	jobs.Enqueue = mockEnqueue
@@ -73,7 +82,9 @@ func setupSuperKeyTest(superKeyEnabled bool) func() {
 		dao.GetSourceDao = origGlobalGetSourceDao
 		dao.GetApplicationDao = origGlobalGetApplicationDao
 		dao.GetAuthenticationDao = origGlobalGetAuthDao
+		dao.GetAuditEventDao = origGlobalGetAuditEventDao
 		jobs.Enqueue = origEnqueue
+		conf.SourceRetention = origSourceRetention
 	}
 }
 
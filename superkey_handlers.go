@@ -2,7 +2,6 @@ package main
 
 import (
 	"fmt"
-	"net/http"
 
 	"github.com/RedHatInsights/sources-api-go/jobs"
 	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
@@ -16,8 +15,8 @@ import (
 // worker to tear down the cloud resources first, then cascade-deletes the
 // DB records after a short delay.
 //
-// Returns 202 Accepted to indicate the delete has been accepted but will
-// complete asynchronously.
+// The callers are expected to respond with 202 Accepted, to indicate that the
+// delete has been accepted but will complete asynchronously.
 func enqueueSuperKeyDelete(c echo.Context, model string, id int64) error {
 	tenantId, ok := c.Get(h.TenantID).(int64)
 	if !ok {
@@ -42,5 +41,5 @@ func enqueueSuperKeyDelete(c echo.Context, model string, id int64) error {
 		Id:       id,
 	})
 
-	return c.NoContent(http.StatusAccepted)
+	return nil
 }
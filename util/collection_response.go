@@ -22,30 +22,46 @@ type Metadata struct {
 type Links struct {
 	First string `json:"first"`
 	Last  string `json:"last"`
+	Next  string `json:"next,omitempty"`
+	Prev  string `json:"prev,omitempty"`
 }
 
-func CollectionResponse(collection []interface{}, req *http.Request, count, limit, offset int) *Collection {
+// CollectionResponse builds the response for a page of a collection. The "bounds" are the first and the last items of
+// the page, and when given, they are used to build the "next" and "prev" links with keyset cursors.
+func CollectionResponse(collection []interface{}, req *http.Request, count, limit, offset int, bounds ...Paginable) *Collection {
 	var first, last string
 
 	q := req.URL.Query()
 
+	// the "first" and "last" links are always offset based, so that older
+	// clients can keep using them.
+	q.Del("cursor")
+
 	// set the "first" link with same limit+offset (what they requested)
 	q.Set("limit", strconv.Itoa(limit))
 	q.Set("offset", strconv.Itoa(offset))
 	params, _ := url.PathUnescape(q.Encode())
 	first = fmt.Sprintf("%v?%v", req.URL.Path, params)
 
-	// set the "last" link with limit+offset set for the next page
-	q.Set("offset", strconv.Itoa(offset+limit))
+	// set the "last" link with the offset of the actual last page
+	lastOffset := 0
+	if limit > 0 && count > 0 {
+		lastOffset = ((count - 1) / limit) * limit
+	}
+
+	q.Set("offset", strconv.Itoa(lastOffset))
 	params, _ = url.PathUnescape(q.Encode())
 	last = fmt.Sprintf("%v?%v", req.URL.Path, params)
 
-	// set offset based on limit size aka page size
 	links := Links{
 		First: first,
 		Last:  last,
 	}
 
+	if len(bounds) == 2 {
+		links.Next, links.Prev = cursorLinks(req, len(collection), limit, offset, bounds[0], bounds[1])
+	}
+
 	return &Collection{
 		Data: collection,
 		Meta: Metadata{
@@ -56,3 +72,65 @@ func CollectionResponse(collection []interface{}, req *http.Request, count, limi
 		Links: links,
 	}
 }
+
+// cursorLinks builds the "next" and "prev" links for the page delimited by the given items. A full page is assumed to
+// have more items after it, and the page that was fetched from an anchor is assumed to have more items on the
+// anchor's side. The lists sorted by the client do not get the links, since the cursors only hold for the lists
+// sorted by the keyset columns.
+func cursorLinks(req *http.Request, pageSize, limit, offset int, firstItem, lastItem Paginable) (string, string) {
+	if req.URL.Query().Has("sort_by") {
+		return "", ""
+	}
+
+	firstCursor := firstItem.Cursor()
+	lastCursor := lastItem.Cursor()
+
+	if firstCursor == nil || lastCursor == nil {
+		return "", ""
+	}
+
+	var requestCursor *Cursor
+	if raw := req.URL.Query().Get("cursor"); raw != "" {
+		requestCursor, _ = ParseCursor(raw)
+	}
+
+	var hasNext, hasPrev bool
+
+	switch {
+	case requestCursor == nil:
+		hasNext = pageSize == limit
+		hasPrev = offset > 0
+	case requestCursor.Previous:
+		hasNext = true
+		hasPrev = pageSize == limit
+	default:
+		hasNext = pageSize == limit
+		hasPrev = true
+	}
+
+	var next, prev string
+
+	if hasNext {
+		next = cursorLink(req, limit, lastCursor)
+	}
+
+	if hasPrev {
+		firstCursor.Previous = true
+		prev = cursorLink(req, limit, firstCursor)
+	}
+
+	return next, prev
+}
+
+// cursorLink builds a link to the current path with the given limit and cursor.
+func cursorLink(req *http.Request, limit int, cursor *Cursor) string {
+	q := req.URL.Query()
+
+	q.Del("offset")
+	q.Set("limit", strconv.Itoa(limit))
+	q.Set("cursor", cursor.Encode())
+
+	params, _ := url.PathUnescape(q.Encode())
+
+	return fmt.Sprintf("%v?%v", req.URL.Path, params)
+}
@@ -0,0 +1,106 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// CursorFilterOperation is the operation of the filter which carries the keyset cursor down to the DAOs, the same
+// way the "sort_by" operation carries the sorting.
+const CursorFilterOperation = "cursor"
+
+// KeysetFilterOperation is the operation of the filter which tells the DAOs that the list is not sorted by the client,
+// so that every page, and not only the ones fetched from a cursor, gets sorted by the keyset columns the cursors are
+// anchored on.
+const KeysetFilterOperation = "keyset"
+
+// Cursor is the decoded form of the opaque "cursor" query parameter used for keyset pagination. It points at the
+// "(created_at, id)" tuple of the item the page is anchored on. The tables that do not have a "created_at" column,
+// like the "authentications" one, are anchored on the "id" only.
+type Cursor struct {
+	CreatedAt *time.Time `json:"c,omitempty"`
+	ID        int64      `json:"i"`
+	// Previous signals that the page to be fetched is the one before the anchor item, instead of the one after it.
+	Previous bool `json:"p,omitempty"`
+}
+
+// Paginable is implemented by the models which can be paginated using keyset cursors.
+type Paginable interface {
+	// Cursor returns the cursor which points at the item, or nil if the item cannot be used as a keyset anchor.
+	Cursor() *Cursor
+}
+
+// ParseCursor decodes the given opaque cursor.
+func ParseCursor(raw string) (*Cursor, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var cursor Cursor
+
+	err = json.Unmarshal(decoded, &cursor)
+	if err != nil || cursor.ID < 1 {
+		return nil, errors.New("invalid cursor")
+	}
+
+	return &cursor, nil
+}
+
+// Encode returns the opaque representation of the cursor, safe to be used in URLs.
+func (c *Cursor) Encode() string {
+	// The struct only holds marshallable fields, so the error can be safely ignored.
+	encoded, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(encoded)
+}
+
+// IsPrevious returns true when the cursor points to the page before its anchor item. It is safe to call it on a nil
+// cursor.
+func (c *Cursor) IsPrevious() bool {
+	return c != nil && c.Previous
+}
+
+// NewCursor returns a cursor anchored on the given "(created_at, id)" tuple. A nil "createdAt" anchors the cursor on
+// the id only.
+func NewCursor(createdAt *time.Time, id int64) *Cursor {
+	if createdAt != nil {
+		// Postgres stores the timestamps with microsecond precision, so we truncate it to make sure that the
+		// comparisons against the stored values hold.
+		truncated := createdAt.Truncate(time.Microsecond)
+		createdAt = &truncated
+	}
+
+	return &Cursor{CreatedAt: createdAt, ID: id}
+}
+
+// IsKeysetSorted returns true when the filters carry a cursor, or when they tell that the list is sorted by the keyset
+// columns.
+func IsKeysetSorted(filters []Filter) bool {
+	for _, filter := range filters {
+		if filter.Operation == CursorFilterOperation || filter.Operation == KeysetFilterOperation {
+			return true
+		}
+	}
+
+	return false
+}
+
+// CursorFromFilters returns the cursor carried by the given filters, or nil if there is none.
+func CursorFromFilters(filters []Filter) (*Cursor, error) {
+	for _, filter := range filters {
+		if filter.Operation != CursorFilterOperation {
+			continue
+		}
+
+		if len(filter.Value) != 1 {
+			return nil, errors.New("invalid cursor")
+		}
+
+		return ParseCursor(filter.Value[0])
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,150 @@
+package util
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// testPaginable is a helper type to be able to build cursor links without depending on the models.
+type testPaginable struct {
+	cursor *Cursor
+}
+
+func (tp testPaginable) Cursor() *Cursor {
+	return tp.cursor
+}
+
+// TestCursorRoundTrip tests that an encoded cursor decodes back to the same values.
+func TestCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2024, 1, 2, 3, 4, 5, 6789, time.UTC)
+
+	cursor := NewCursor(&createdAt, 12)
+	cursor.Previous = true
+
+	got, err := ParseCursor(cursor.Encode())
+	if err != nil {
+		t.Fatalf("unexpected error when parsing the cursor: %s", err)
+	}
+
+	// The nanoseconds get truncated to match Postgres' precision.
+	want := time.Date(2024, 1, 2, 3, 4, 5, 6000, time.UTC)
+	if !got.CreatedAt.Equal(want) {
+		t.Errorf(`want created at "%s", got "%s"`, want, got.CreatedAt)
+	}
+
+	if got.ID != 12 {
+		t.Errorf(`want id "12", got "%d"`, got.ID)
+	}
+
+	if !got.IsPrevious() {
+		t.Error("want a previous cursor, got a next one")
+	}
+}
+
+// TestParseCursorInvalid tests that malformed cursors are rejected.
+func TestParseCursorInvalid(t *testing.T) {
+	for _, raw := range []string{"not base64!", "e30", "eyJpIjotMX0"} {
+		_, err := ParseCursor(raw)
+		if err == nil {
+			t.Errorf(`want error for cursor "%s", got none`, raw)
+		}
+	}
+}
+
+// TestCollectionResponseLastLink tests that the "last" link points to the actual last page.
+func TestCollectionResponseLastLink(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?limit=10&offset=0", nil)
+
+	collection := CollectionResponse(nil, req, 25, 10, 0)
+
+	want := "/api/sources/v3.1/sources?limit=10&offset=20"
+	if collection.Links.Last != want {
+		t.Errorf(`want last link "%s", got "%s"`, want, collection.Links.Last)
+	}
+}
+
+// TestCollectionResponseCursorLinks tests that the "next" and "prev" links get built out of the page bounds.
+func TestCollectionResponseCursorLinks(t *testing.T) {
+	first := testPaginable{cursor: NewCursor(nil, 11)}
+	last := testPaginable{cursor: NewCursor(nil, 12)}
+	page := []interface{}{"a", "b"}
+
+	// A full page requested with a cursor has pages on both sides.
+	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?limit=2&cursor="+NewCursor(nil, 10).Encode(), nil)
+
+	collection := CollectionResponse(page, req, 25, 2, 0, first, last)
+
+	next := cursorFromLink(t, collection.Links.Next)
+	if next.ID != 12 || next.Previous {
+		t.Errorf(`want next link anchored after "12", got "%+v"`, next)
+	}
+
+	prev := cursorFromLink(t, collection.Links.Prev)
+	if prev.ID != 11 || !prev.Previous {
+		t.Errorf(`want prev link anchored before "11", got "%+v"`, prev)
+	}
+
+	// A partial first page has no pages on either side.
+	req = httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?limit=10", nil)
+
+	collection = CollectionResponse(page, req, 2, 10, 0, first, last)
+	if collection.Links.Next != "" || collection.Links.Prev != "" {
+		t.Errorf(`want no cursor links, got next "%s" and prev "%s"`, collection.Links.Next, collection.Links.Prev)
+	}
+}
+
+// TestCollectionResponseCursorLinksSorted tests that the lists sorted by the client do not get cursor links, since the
+// cursors cannot be combined with a sorting.
+func TestCollectionResponseCursorLinksSorted(t *testing.T) {
+	first := testPaginable{cursor: NewCursor(nil, 11)}
+	last := testPaginable{cursor: NewCursor(nil, 12)}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/sources/v3.1/sources?limit=2&sort_by=name", nil)
+
+	collection := CollectionResponse([]interface{}{"a", "b"}, req, 25, 2, 0, first, last)
+	if collection.Links.Next != "" || collection.Links.Prev != "" {
+		t.Errorf(`want no cursor links, got next "%s" and prev "%s"`, collection.Links.Next, collection.Links.Prev)
+	}
+}
+
+// TestIsKeysetSorted tests that the lists are sorted by the keyset columns when the filters carry a cursor or the
+// keyset sorting.
+func TestIsKeysetSorted(t *testing.T) {
+	tests := []struct {
+		filters []Filter
+		want    bool
+	}{
+		{filters: nil, want: false},
+		{filters: []Filter{{Operation: "sort_by", Value: []string{"name"}}}, want: false},
+		{filters: []Filter{{Operation: KeysetFilterOperation}}, want: true},
+		{filters: []Filter{{Operation: CursorFilterOperation, Value: []string{NewCursor(nil, 1).Encode()}}}, want: true},
+	}
+
+	for _, tt := range tests {
+		if got := IsKeysetSorted(tt.filters); got != tt.want {
+			t.Errorf("want %t for %+v, got %t", tt.want, tt.filters, got)
+		}
+	}
+}
+
+// cursorFromLink extracts and parses the cursor from the given link.
+func cursorFromLink(t *testing.T, link string) *Cursor {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		t.Fatalf(`unable to parse link "%s": %s`, link, err)
+	}
+
+	if parsed.Query().Has("offset") {
+		t.Errorf(`want no offset in the cursor link, got "%s"`, link)
+	}
+
+	cursor, err := ParseCursor(parsed.Query().Get("cursor"))
+	if err != nil {
+		t.Fatalf(`unable to parse the cursor from link "%s": %s`, link, err)
+	}
+
+	return cursor
+}
@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
+	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"os"
 	"path"
@@ -12,6 +14,7 @@ import (
 	"strings"
 
 	"github.com/RedHatInsights/sources-api-go/config"
+	"golang.org/x/crypto/argon2"
 )
 
 var (
@@ -106,6 +109,117 @@ func Decrypt(str string) (string, error) {
 	return decode(string(rawPass))
 }
 
+// The default parameters of the Argon2id derivation of the passphrase keys, which are the second recommended option of
+// RFC 9106. The parameters come along with the imported documents, so the limits keep them from exhausting the
+// service.
+const (
+	passphraseKDFAlgorithm  = "argon2id"
+	passphraseKDFSaltSize   = 16
+	passphraseKDFTime       = 3
+	passphraseKDFMemory     = 64 * 1024
+	passphraseKDFThreads    = 4
+	passphraseKDFMaxTime    = 10
+	passphraseKDFMaxMemory  = 256 * 1024
+	passphraseKDFMaxThreads = 16
+)
+
+// PassphraseKDF holds the parameters a passphrase's key gets derived with. They travel along with the secrets
+// encrypted with the key, so that the holder of the passphrase can derive the same key again to decrypt them.
+type PassphraseKDF struct {
+	Algorithm string `json:"algorithm"`
+	Salt      string `json:"salt"`
+	Time      uint32 `json:"time"`
+	Memory    uint32 `json:"memory"`
+	Threads   uint8  `json:"threads"`
+}
+
+// NewPassphraseKDF returns the default key derivation parameters along with a random salt.
+func NewPassphraseKDF() (*PassphraseKDF, error) {
+	salt := make([]byte, passphraseKDFSaltSize)
+
+	_, err := rand.Read(salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PassphraseKDF{
+		Algorithm: passphraseKDFAlgorithm,
+		Salt:      base64.StdEncoding.EncodeToString(salt),
+		Time:      passphraseKDFTime,
+		Memory:    passphraseKDFMemory,
+		Threads:   passphraseKDFThreads,
+	}, nil
+}
+
+// DeriveKey derives the key of the given passphrase with the parameters.
+func (kdf *PassphraseKDF) DeriveKey(passphrase string) (*PassphraseKey, error) {
+	if passphrase == "" {
+		return nil, errors.New("no encryption key present")
+	}
+
+	if kdf.Algorithm != passphraseKDFAlgorithm {
+		return nil, fmt.Errorf(`unsupported key derivation algorithm "%s"`, kdf.Algorithm)
+	}
+
+	if kdf.Time < 1 || kdf.Time > passphraseKDFMaxTime || kdf.Memory < 1 || kdf.Memory > passphraseKDFMaxMemory || kdf.Threads < 1 || kdf.Threads > passphraseKDFMaxThreads {
+		return nil, errors.New("the key derivation parameters are out of bounds")
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(kdf.Salt)
+	if err != nil || len(salt) < passphraseKDFSaltSize {
+		return nil, errors.New("invalid key derivation salt")
+	}
+
+	block, err := aes.NewCipher(argon2.IDKey([]byte(passphrase), salt, kdf.Time, kdf.Memory, kdf.Threads, 32))
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PassphraseKey{gcm: gcm}, nil
+}
+
+// PassphraseKey encrypts the secrets that leave the service with a key derived from a caller provided passphrase,
+// instead of the service's encryption key, so that only the holder of the passphrase can read them back.
+type PassphraseKey struct {
+	gcm cipher.AEAD
+}
+
+// Encrypt encrypts str. The output is the base64 encoded random nonce followed by the AES-256-GCM sealed secret.
+func (k *PassphraseKey) Encrypt(str string) (string, error) {
+	nonce := make([]byte, k.gcm.NonceSize())
+
+	_, err := rand.Read(nonce)
+	if err != nil {
+		return "", err
+	}
+
+	return base64.StdEncoding.EncodeToString(k.gcm.Seal(nonce, nonce, []byte(str), nil)), nil
+}
+
+// Decrypt decrypts a secret encrypted by "Encrypt" with the same key.
+func (k *PassphraseKey) Decrypt(str string) (string, error) {
+	sealed, err := base64.StdEncoding.DecodeString(str)
+	if err != nil {
+		return "", err
+	}
+
+	if len(sealed) < k.gcm.NonceSize() {
+		return "", errors.New("the encrypted secret is too short")
+	}
+
+	plaintext, err := k.gcm.Open(nil, sealed[:k.gcm.NonceSize()], sealed[k.gcm.NonceSize():], nil)
+	if err != nil {
+		return "", err
+	}
+
+	return string(plaintext), nil
+}
+
 func decode(pw string) (string, error) {
 	// create the block from the key
 	block, err := aes.NewCipher([]byte(key))
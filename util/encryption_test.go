@@ -97,3 +97,105 @@ func TestSetDefaultEncryptionKey(t *testing.T) {
 		t.Errorf("Wrong encryption key! setDefaultEncryptionKey() did not work properly")
 	}
 }
+
+// TestPassphraseKey tests that the secrets encrypted with a passphrase's key can only be decrypted with the key
+// derived from the same passphrase and parameters.
+func TestPassphraseKey(t *testing.T) {
+	kdf, err := NewPassphraseKDF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := kdf.DeriveKey("passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := key.Encrypt("sources-api-tests")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := key.Encrypt("sources-api-tests")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if out == other {
+		t.Errorf("want different ciphertexts for the same secret, got %q twice", out)
+	}
+
+	// the key gets derived again from the parameters, like the imports do.
+	derived, err := kdf.DeriveKey("passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	decrypted, err := derived.Decrypt(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if decrypted != "sources-api-tests" {
+		t.Errorf("decryption failed, got %v expected %v", decrypted, "sources-api-tests")
+	}
+
+	wrongPassphrase, err := kdf.DeriveKey("another passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = wrongPassphrase.Decrypt(out)
+	if err == nil {
+		t.Errorf("want an error when decrypting with the wrong passphrase, got none")
+	}
+
+	otherKdf, err := NewPassphraseKDF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if otherKdf.Salt == kdf.Salt {
+		t.Errorf("want a different salt every time, got %q twice", kdf.Salt)
+	}
+
+	wrongSalt, err := otherKdf.DeriveKey("passphrase")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = wrongSalt.Decrypt(out)
+	if err == nil {
+		t.Errorf("want an error when decrypting with a key derived from another salt, got none")
+	}
+
+	_, err = kdf.DeriveKey("")
+	if err == nil {
+		t.Errorf("want an error when deriving a key without a passphrase, got none")
+	}
+}
+
+// TestPassphraseKDFBounds tests that the key derivation parameters which could exhaust the service, or which are too
+// weak, get rejected.
+func TestPassphraseKDFBounds(t *testing.T) {
+	valid, err := NewPassphraseKDF()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []PassphraseKDF{
+		{Algorithm: "pbkdf2", Salt: valid.Salt, Time: valid.Time, Memory: valid.Memory, Threads: valid.Threads},
+		{Algorithm: valid.Algorithm, Salt: "c2hvcnQ=", Time: valid.Time, Memory: valid.Memory, Threads: valid.Threads},
+		{Algorithm: valid.Algorithm, Salt: valid.Salt, Time: 0, Memory: valid.Memory, Threads: valid.Threads},
+		{Algorithm: valid.Algorithm, Salt: valid.Salt, Time: passphraseKDFMaxTime + 1, Memory: valid.Memory, Threads: valid.Threads},
+		{Algorithm: valid.Algorithm, Salt: valid.Salt, Time: valid.Time, Memory: passphraseKDFMaxMemory + 1, Threads: valid.Threads},
+		{Algorithm: valid.Algorithm, Salt: valid.Salt, Time: valid.Time, Memory: valid.Memory, Threads: 0},
+	}
+
+	for _, tc := range testCases {
+		_, err := tc.DeriveKey("passphrase")
+		if err == nil {
+			t.Errorf("want an error for the parameters %+v, got none", tc)
+		}
+	}
+}
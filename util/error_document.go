@@ -70,3 +70,39 @@ func NewErrBadRequest(t interface{}) error {
 
 	return ErrBadRequest{Message: errorMessage}
 }
+
+type ErrPreconditionFailed struct {
+	Message string
+}
+
+func (e ErrPreconditionFailed) Error() string {
+	return fmt.Sprintf("precondition failed: %s", e.Message)
+}
+
+func NewErrPreconditionFailed(message string) error {
+	return ErrPreconditionFailed{Message: message}
+}
+
+type ErrConflict struct {
+	Message string
+}
+
+func (e ErrConflict) Error() string {
+	return fmt.Sprintf("conflict: %s", e.Message)
+}
+
+func NewErrConflict(message string) error {
+	return ErrConflict{Message: message}
+}
+
+type ErrUnprocessableEntity struct {
+	Message string
+}
+
+func (e ErrUnprocessableEntity) Error() string {
+	return fmt.Sprintf("unprocessable entity: %s", e.Message)
+}
+
+func NewErrUnprocessableEntity(message string) error {
+	return ErrUnprocessableEntity{Message: message}
+}
@@ -0,0 +1,69 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// NewETag returns a strong entity tag computed out of the given parts, already quoted so that it can be sent as is in
+// the "ETag" header.
+func NewETag(parts ...string) string {
+	hash := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+
+	return `"` + hex.EncodeToString(hash[:16]) + `"`
+}
+
+// ETagSecretsDigest returns the part of an entity tag which tracks the given secrets without disclosing them. The
+// secrets are authenticated with the service's encryption key instead of being hashed as they are, so that the entity
+// tags cannot be used to guess them offline. Without the key, the secrets do not contribute to the tag.
+func ETagSecretsDigest(secrets ...string) string {
+	if !keyPresent {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, []byte(key))
+	mac.Write([]byte(strings.Join(secrets, "\x00")))
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewETagFromUpdatedAt returns the entity tag of a resource that tracks its modifications in an "updated_at" column.
+// The timestamp is truncated to microseconds, which is the precision the database stores it with.
+func NewETagFromUpdatedAt(resourceType string, id int64, updatedAt time.Time) string {
+	return NewETag(resourceType, strconv.FormatInt(id, 10), updatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano))
+}
+
+// ETagMatches returns true when the given "If-Match" or "If-None-Match" header value matches the entity tag. The
+// header may hold a comma separated list of tags, or the "*" wildcard which matches any tag. When "weak" is true, the
+// weak comparison function is used, which ignores the "W/" prefix of the tags as RFC 9110 dictates for the
+// "If-None-Match" header.
+func ETagMatches(header string, etag string, weak bool) bool {
+	if weak {
+		etag = strings.TrimPrefix(etag, "W/")
+	}
+
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+
+		if candidate == "*" {
+			return true
+		}
+
+		if weak {
+			candidate = strings.TrimPrefix(candidate, "W/")
+		} else if strings.HasPrefix(candidate, "W/") {
+			// Weak tags never match under the strong comparison.
+			continue
+		}
+
+		if candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
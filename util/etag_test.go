@@ -0,0 +1,93 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestNewETagFromUpdatedAt tests that the entity tags change along with the resource's modification date, and that
+// the timestamp's precision beyond microseconds is ignored.
+func TestNewETagFromUpdatedAt(t *testing.T) {
+	updatedAt := time.Date(2024, 1, 2, 3, 4, 5, 6789, time.UTC)
+
+	etag := NewETagFromUpdatedAt("Source", 1, updatedAt)
+
+	if etag[0] != '"' || etag[len(etag)-1] != '"' {
+		t.Errorf(`want a quoted entity tag, got "%s"`, etag)
+	}
+
+	if got := NewETagFromUpdatedAt("Source", 1, updatedAt.Truncate(time.Microsecond)); got != etag {
+		t.Errorf(`want "%s" for the truncated timestamp, got "%s"`, etag, got)
+	}
+
+	if got := NewETagFromUpdatedAt("Source", 1, updatedAt.In(time.FixedZone("CEST", 7200))); got != etag {
+		t.Errorf(`want "%s" for the same timestamp in a different time zone, got "%s"`, etag, got)
+	}
+
+	if got := NewETagFromUpdatedAt("Source", 1, updatedAt.Add(time.Second)); got == etag {
+		t.Error("want a different entity tag for a different modification date")
+	}
+
+	if got := NewETagFromUpdatedAt("Application", 1, updatedAt); got == etag {
+		t.Error("want a different entity tag for a different resource type")
+	}
+}
+
+// TestETagSecretsDigest tests that the secrets' digest tracks the secrets, and that it depends on the encryption key so
+// that it cannot be computed without it.
+func TestETagSecretsDigest(t *testing.T) {
+	backupKey, backupKeyPresent := key, keyPresent
+	defer func() { key, keyPresent = backupKey, backupKeyPresent }()
+
+	OverrideEncryptionKey(strings.Repeat("test", 8))
+
+	digest := ETagSecretsDigest("password", "")
+
+	if got := ETagSecretsDigest("password", ""); got != digest {
+		t.Errorf(`want "%s" for the same secrets, got "%s"`, digest, got)
+	}
+
+	if got := ETagSecretsDigest("other", ""); got == digest {
+		t.Error("want a different digest for a different secret")
+	}
+
+	unkeyed := sha256.Sum256([]byte("password\x00"))
+	if digest == hex.EncodeToString(unkeyed[:]) {
+		t.Error("want the digest to be keyed")
+	}
+
+	OverrideEncryptionKey(strings.Repeat("abcd", 8))
+
+	if got := ETagSecretsDigest("password", ""); got == digest {
+		t.Error("want a different digest for a different key")
+	}
+}
+
+// TestETagMatches tests the strong and weak comparison of the entity tags against the conditional headers.
+func TestETagMatches(t *testing.T) {
+	etag := NewETag("Source", "1")
+
+	testCases := []struct {
+		header string
+		weak   bool
+		want   bool
+	}{
+		{header: etag, weak: false, want: true},
+		{header: etag, weak: true, want: true},
+		{header: "*", weak: false, want: true},
+		{header: `"foo", ` + etag, weak: false, want: true},
+		{header: `"foo", "bar"`, weak: false, want: false},
+		{header: "W/" + etag, weak: false, want: false},
+		{header: "W/" + etag, weak: true, want: true},
+		{header: `"foo"`, weak: true, want: false},
+	}
+
+	for _, tc := range testCases {
+		if got := ETagMatches(tc.header, etag, tc.weak); got != tc.want {
+			t.Errorf(`header "%s" with weak comparison "%t": want "%t", got "%t"`, tc.header, tc.weak, tc.want, got)
+		}
+	}
+}
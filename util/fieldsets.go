@@ -0,0 +1,68 @@
+package util
+
+import (
+	"encoding/json"
+	"errors"
+)
+
+// FieldsFilterOperation is the operation of the filter which carries the requested sparse fieldset down to the DAOs,
+// so that they only select the columns that are going to be returned.
+const FieldsFilterOperation = "fields"
+
+// FieldsFromFilters returns the sparse fieldset carried by the given filters, or nil if there is none.
+func FieldsFromFilters(filters []Filter) []string {
+	for _, filter := range filters {
+		if filter.Operation == FieldsFilterOperation {
+			return filter.Value
+		}
+	}
+
+	return nil
+}
+
+// TrimJSONFields removes the members which are not in the given fieldset from the given JSON document. The document
+// can either be a single resource or a collection response, in which case every resource in its "data" member gets
+// trimmed. The "id" member is always kept, since the resources cannot be identified without it.
+func TrimJSONFields(document []byte, fields []string) ([]byte, error) {
+	value, err := decodeJSON(document)
+	if err != nil {
+		return nil, err
+	}
+
+	object, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the document is not a JSON object")
+	}
+
+	_, hasMeta := object["meta"]
+	data, isCollection := object["data"].([]interface{})
+
+	if !hasMeta || !isCollection {
+		return json.Marshal(TrimJSONObject(object, fields))
+	}
+
+	for i, item := range data {
+		if resource, ok := item.(map[string]interface{}); ok {
+			data[i] = TrimJSONObject(resource, fields)
+		}
+	}
+
+	return json.Marshal(object)
+}
+
+// TrimJSONObject removes the members which are not in the given fieldset from the given decoded JSON object. The "id"
+// member is always kept.
+func TrimJSONObject(object map[string]interface{}, fields []string) map[string]interface{} {
+	keep := map[string]bool{"id": true}
+	for _, field := range fields {
+		keep[field] = true
+	}
+
+	for member := range object {
+		if !keep[member] {
+			delete(object, member)
+		}
+	}
+
+	return object
+}
@@ -22,9 +22,27 @@ func IsValidColumnName(name string) bool {
 	return columnNameRegex.MatchString(name)
 }
 
+// The operations of the filters which group other filters, instead of filtering on a column themselves.
+const (
+	AndFilterOperation = "and"
+	OrFilterOperation  = "or"
+	NotFilterOperation = "not"
+)
+
+// MaxFilterGroupDepth is the number of filter groups a filter can be nested in.
+const MaxFilterGroupDepth = 5
+
 type Filter struct {
 	Subresource string
 	Name        string
 	Operation   string
 	Value       []string
+	// Filters are the grouped filters of the "and", "or" and "not" operations. The "not" operation negates the
+	// conjunction of its filters.
+	Filters []Filter
+}
+
+// IsGroup returns true when the filter groups other filters.
+func (f Filter) IsGroup() bool {
+	return f.Operation == AndFilterOperation || f.Operation == OrFilterOperation || f.Operation == NotFilterOperation
 }
@@ -0,0 +1,472 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	// MergePatchContentType is the media type of the JSON Merge Patch documents, as defined in RFC 7396.
+	MergePatchContentType = "application/merge-patch+json"
+	// JSONPatchContentType is the media type of the JSON Patch documents, as defined in RFC 6902.
+	JSONPatchContentType = "application/json-patch+json"
+)
+
+// ErrJSONPatchTestFailed is returned when a "test" operation of a JSON Patch document does not hold.
+var ErrJSONPatchTestFailed = errors.New("test operation failed")
+
+// jsonPatchOperation represents a single operation of a JSON Patch document.
+type jsonPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  *string         `json:"path"`
+	From  *string         `json:"from"`
+	Value json.RawMessage `json:"value"`
+}
+
+// ApplyMergePatch applies the given JSON Merge Patch document to the given JSON document, as described in RFC 7396.
+func ApplyMergePatch(document []byte, patch []byte) ([]byte, error) {
+	target, err := decodeJSON(document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	mergePatch, err := decodeJSON(patch)
+	if err != nil {
+		return nil, fmt.Errorf("invalid merge patch: %w", err)
+	}
+
+	return json.Marshal(applyMergePatch(target, mergePatch))
+}
+
+func applyMergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = applyMergePatch(targetObject[key], value)
+		}
+	}
+
+	return targetObject
+}
+
+// ApplyJSONPatch applies the given JSON Patch document to the given JSON document, as described in RFC 6902. The
+// operations are applied in order, and the whole patch fails if any of them fails. When a "test" operation does not
+// hold, the returned error wraps ErrJSONPatchTestFailed.
+func ApplyJSONPatch(document []byte, patch []byte) ([]byte, error) {
+	target, err := decodeJSON(document)
+	if err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+
+	var operations []jsonPatchOperation
+
+	err = json.Unmarshal(patch, &operations)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON patch: %w", err)
+	}
+
+	for i, operation := range operations {
+		target, err = applyJSONPatchOperation(target, operation)
+		if err != nil {
+			return nil, fmt.Errorf(`operation %d ("%s"): %w`, i, operation.Op, err)
+		}
+	}
+
+	return json.Marshal(target)
+}
+
+func applyJSONPatchOperation(target interface{}, operation jsonPatchOperation) (interface{}, error) {
+	if operation.Path == nil {
+		return nil, errors.New(`missing "path" member`)
+	}
+
+	path, err := parseJSONPointer(*operation.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	switch operation.Op {
+	case "add", "replace", "test":
+		// An explicit null value is kept as the "null" literal, so an empty value means that the member is missing.
+		if len(operation.Value) == 0 {
+			return nil, errors.New(`missing "value" member`)
+		}
+
+		value, err := decodeJSON(operation.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value: %w", err)
+		}
+
+		switch operation.Op {
+		case "add":
+			return addJSONValue(target, path, value)
+		case "replace":
+			// A replacement is functionally identical to a removal followed by an addition, but the target location
+			// must exist.
+			target, _, err = removeJSONValue(target, path)
+			if err != nil {
+				return nil, err
+			}
+
+			return addJSONValue(target, path, value)
+		default:
+			current, err := getJSONValue(target, path)
+			if err != nil {
+				return nil, err
+			}
+
+			if !jsonEqual(current, value) {
+				return nil, fmt.Errorf(`%w: the value at "%s" is different`, ErrJSONPatchTestFailed, *operation.Path)
+			}
+
+			return target, nil
+		}
+
+	case "remove":
+		target, _, err = removeJSONValue(target, path)
+
+		return target, err
+
+	case "move", "copy":
+		if operation.From == nil {
+			return nil, errors.New(`missing "from" member`)
+		}
+
+		from, err := parseJSONPointer(*operation.From)
+		if err != nil {
+			return nil, err
+		}
+
+		var value interface{}
+
+		if operation.Op == "move" {
+			if *operation.Path != *operation.From && strings.HasPrefix(*operation.Path, *operation.From+"/") {
+				return nil, errors.New("a location cannot be moved into one of its children")
+			}
+
+			target, value, err = removeJSONValue(target, from)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			value, err = getJSONValue(target, from)
+			if err != nil {
+				return nil, err
+			}
+
+			// Copy the value so that both locations do not share the same maps or slices.
+			raw, err := json.Marshal(value)
+			if err != nil {
+				return nil, err
+			}
+
+			value, err = decodeJSON(raw)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		return addJSONValue(target, path, value)
+
+	default:
+		return nil, fmt.Errorf(`unknown operation "%s"`, operation.Op)
+	}
+}
+
+// parseJSONPointer splits the given JSON Pointer into its unescaped reference tokens, as described in RFC 6901.
+func parseJSONPointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf(`invalid JSON pointer "%s"`, pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex parses the given reference token as an index of an array of the given length. The "-" token, which
+// points past the last element, is only allowed when adding values.
+func arrayIndex(token string, length int, adding bool) (int, error) {
+	if adding && token == "-" {
+		return length, nil
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf(`invalid array index "%s"`, token)
+	}
+
+	maxIndex := length - 1
+	if adding {
+		maxIndex = length
+	}
+
+	if index > maxIndex {
+		return 0, fmt.Errorf(`array index "%s" out of bounds`, token)
+	}
+
+	return index, nil
+}
+
+func getJSONValue(target interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch node := target.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf(`member "%s" not found`, token)
+			}
+
+			target = value
+		case []interface{}:
+			index, err := arrayIndex(token, len(node), false)
+			if err != nil {
+				return nil, err
+			}
+
+			target = node[index]
+		default:
+			return nil, fmt.Errorf(`cannot reference "%s" in a scalar value`, token)
+		}
+	}
+
+	return target, nil
+}
+
+func addJSONValue(target interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	token := path[0]
+
+	switch node := target.(type) {
+	case map[string]interface{}:
+		if len(path) == 1 {
+			node[token] = value
+
+			return node, nil
+		}
+
+		child, ok := node[token]
+		if !ok {
+			return nil, fmt.Errorf(`member "%s" not found`, token)
+		}
+
+		child, err := addJSONValue(child, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		node[token] = child
+
+		return node, nil
+	case []interface{}:
+		if len(path) == 1 {
+			index, err := arrayIndex(token, len(node), true)
+			if err != nil {
+				return nil, err
+			}
+
+			return append(node[:index], append([]interface{}{value}, node[index:]...)...), nil
+		}
+
+		index, err := arrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, err
+		}
+
+		child, err := addJSONValue(node[index], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+
+		node[index] = child
+
+		return node, nil
+	default:
+		return nil, fmt.Errorf(`cannot reference "%s" in a scalar value`, token)
+	}
+}
+
+func removeJSONValue(target interface{}, path []string) (interface{}, interface{}, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.New("the whole document cannot be removed")
+	}
+
+	token := path[0]
+
+	switch node := target.(type) {
+	case map[string]interface{}:
+		child, ok := node[token]
+		if !ok {
+			return nil, nil, fmt.Errorf(`member "%s" not found`, token)
+		}
+
+		if len(path) == 1 {
+			delete(node, token)
+
+			return node, child, nil
+		}
+
+		child, removed, err := removeJSONValue(child, path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		node[token] = child
+
+		return node, removed, nil
+	case []interface{}:
+		index, err := arrayIndex(token, len(node), false)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if len(path) == 1 {
+			removed := node[index]
+
+			return append(node[:index], node[index+1:]...), removed, nil
+		}
+
+		child, removed, err := removeJSONValue(node[index], path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		node[index] = child
+
+		return node, removed, nil
+	default:
+		return nil, nil, fmt.Errorf(`cannot reference "%s" in a scalar value`, token)
+	}
+}
+
+// jsonEqual compares two decoded JSON values. Numbers are compared by their value rather than by their
+// representation, so that "1" and "1.0" are considered equal.
+func jsonEqual(a interface{}, b interface{}) bool {
+	aNumber, aIsNumber := a.(json.Number)
+	bNumber, bIsNumber := b.(json.Number)
+
+	if aIsNumber && bIsNumber {
+		aFloat, aErr := aNumber.Float64()
+		bFloat, bErr := bNumber.Float64()
+
+		if aErr != nil || bErr != nil {
+			return aNumber == bNumber
+		}
+
+		return aFloat == bFloat
+	}
+
+	switch aValue := a.(type) {
+	case map[string]interface{}:
+		bValue, ok := b.(map[string]interface{})
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+
+		for key, value := range aValue {
+			other, ok := bValue[key]
+			if !ok || !jsonEqual(value, other) {
+				return false
+			}
+		}
+
+		return true
+	case []interface{}:
+		bValue, ok := b.([]interface{})
+		if !ok || len(aValue) != len(bValue) {
+			return false
+		}
+
+		for i := range aValue {
+			if !jsonEqual(aValue[i], bValue[i]) {
+				return false
+			}
+		}
+
+		return true
+	default:
+		return reflect.DeepEqual(a, b)
+	}
+}
+
+// decodeJSON decodes the given JSON document keeping the numbers as they are, so that big integers do not lose their
+// precision.
+func decodeJSON(raw []byte) (interface{}, error) {
+	var value interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	err := decoder.Decode(&value)
+	if err != nil {
+		return nil, err
+	}
+
+	return value, nil
+}
+
+// JSONDiff returns the top level members of the "modified" JSON object which differ from the ones of the "original"
+// object. The members that were removed from the modified object are returned with a nil value.
+func JSONDiff(original []byte, modified []byte) (map[string]interface{}, error) {
+	originalValue, err := decodeJSON(original)
+	if err != nil {
+		return nil, err
+	}
+
+	modifiedValue, err := decodeJSON(modified)
+	if err != nil {
+		return nil, err
+	}
+
+	originalObject, ok := originalValue.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the original document is not a JSON object")
+	}
+
+	modifiedObject, ok := modifiedValue.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("the patched document is not a JSON object")
+	}
+
+	diff := map[string]interface{}{}
+
+	for key, value := range modifiedObject {
+		if originalValue, ok := originalObject[key]; !ok || !jsonEqual(originalValue, value) {
+			diff[key] = value
+		}
+	}
+
+	for key := range originalObject {
+		if _, ok := modifiedObject[key]; !ok {
+			diff[key] = nil
+		}
+	}
+
+	return diff, nil
+}
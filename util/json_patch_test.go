@@ -0,0 +1,135 @@
+package util
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestApplyMergePatch tests the merge patches with the examples from RFC 7396's appendix A.
+func TestApplyMergePatch(t *testing.T) {
+	testCases := []struct {
+		document string
+		patch    string
+		want     string
+	}{
+		{document: `{"a":"b"}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{document: `{"a":"b"}`, patch: `{"b":"c"}`, want: `{"a":"b","b":"c"}`},
+		{document: `{"a":"b"}`, patch: `{"a":null}`, want: `{}`},
+		{document: `{"a":"b","b":"c"}`, patch: `{"a":null}`, want: `{"b":"c"}`},
+		{document: `{"a":["b"]}`, patch: `{"a":"c"}`, want: `{"a":"c"}`},
+		{document: `{"a":"c"}`, patch: `{"a":["b"]}`, want: `{"a":["b"]}`},
+		{document: `{"a":{"b":"c"}}`, patch: `{"a":{"b":"d","c":null}}`, want: `{"a":{"b":"d"}}`},
+		{document: `{"a":[{"b":"c"}]}`, patch: `{"a":[1]}`, want: `{"a":[1]}`},
+		{document: `["a","b"]`, patch: `["c","d"]`, want: `["c","d"]`},
+		{document: `{"a":"b"}`, patch: `["c"]`, want: `["c"]`},
+		{document: `{"e":null}`, patch: `{"a":1}`, want: `{"a":1,"e":null}`},
+		{document: `[1,2]`, patch: `{"a":"b","c":null}`, want: `{"a":"b"}`},
+		{document: `{}`, patch: `{"a":{"bb":{"ccc":null}}}`, want: `{"a":{"bb":{}}}`},
+		{document: `{"a":12345678901234567890}`, patch: `{"b":1}`, want: `{"a":12345678901234567890,"b":1}`},
+	}
+
+	for _, tc := range testCases {
+		got, err := ApplyMergePatch([]byte(tc.document), []byte(tc.patch))
+		if err != nil {
+			t.Errorf(`unexpected error when applying "%s" to "%s": %s`, tc.patch, tc.document, err)
+			continue
+		}
+
+		if string(got) != tc.want {
+			t.Errorf(`applying "%s" to "%s": want "%s", got "%s"`, tc.patch, tc.document, tc.want, string(got))
+		}
+	}
+}
+
+// TestApplyJSONPatch tests the JSON patches with the examples from RFC 6902's appendix A.
+func TestApplyJSONPatch(t *testing.T) {
+	testCases := []struct {
+		document string
+		patch    string
+		want     string
+	}{
+		{document: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/baz","value":"qux"}]`, want: `{"baz":"qux","foo":"bar"}`},
+		{document: `{"foo":["bar","baz"]}`, patch: `[{"op":"add","path":"/foo/1","value":"qux"}]`, want: `{"foo":["bar","qux","baz"]}`},
+		{document: `{"baz":"qux","foo":"bar"}`, patch: `[{"op":"remove","path":"/baz"}]`, want: `{"foo":"bar"}`},
+		{document: `{"foo":["bar","qux","baz"]}`, patch: `[{"op":"remove","path":"/foo/1"}]`, want: `{"foo":["bar","baz"]}`},
+		{document: `{"baz":"qux","foo":"bar"}`, patch: `[{"op":"replace","path":"/baz","value":"boo"}]`, want: `{"baz":"boo","foo":"bar"}`},
+		{document: `{"foo":{"bar":"baz","waldo":"fred"},"qux":{"corge":"grault"}}`, patch: `[{"op":"move","from":"/foo/waldo","path":"/qux/thud"}]`, want: `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{document: `{"foo":["all","grass","cows","eat"]}`, patch: `[{"op":"move","from":"/foo/1","path":"/foo/3"}]`, want: `{"foo":["all","cows","eat","grass"]}`},
+		{document: `{"baz":"qux","foo":["a",2,"c"]}`, patch: `[{"op":"test","path":"/baz","value":"qux"},{"op":"test","path":"/foo/1","value":2.0}]`, want: `{"baz":"qux","foo":["a",2,"c"]}`},
+		{document: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/child","value":{"grandchild":{}}}]`, want: `{"child":{"grandchild":{}},"foo":"bar"}`},
+		{document: `{"foo":["bar"]}`, patch: `[{"op":"add","path":"/foo/-","value":["abc","def"]}]`, want: `{"foo":["bar",["abc","def"]]}`},
+		{document: `{"foo":null}`, patch: `[{"op":"test","path":"/foo","value":null}]`, want: `{"foo":null}`},
+		{document: `{"/":9,"~1":10}`, patch: `[{"op":"test","path":"/~01","value":10}]`, want: `{"/":9,"~1":10}`},
+		{document: `{"foo":{"bar":1}}`, patch: `[{"op":"copy","from":"/foo","path":"/baz"},{"op":"add","path":"/baz/qux","value":2}]`, want: `{"baz":{"bar":1,"qux":2},"foo":{"bar":1}}`},
+	}
+
+	for _, tc := range testCases {
+		got, err := ApplyJSONPatch([]byte(tc.document), []byte(tc.patch))
+		if err != nil {
+			t.Errorf(`unexpected error when applying "%s" to "%s": %s`, tc.patch, tc.document, err)
+			continue
+		}
+
+		if string(got) != tc.want {
+			t.Errorf(`applying "%s" to "%s": want "%s", got "%s"`, tc.patch, tc.document, tc.want, string(got))
+		}
+	}
+}
+
+// TestApplyJSONPatchErrors tests that the invalid operations make the whole patch fail.
+func TestApplyJSONPatchErrors(t *testing.T) {
+	testCases := []struct {
+		document       string
+		patch          string
+		wantTestFailed bool
+	}{
+		{document: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/baz/bat","value":"qux"}]`},
+		{document: `{"foo":"bar"}`, patch: `[{"op":"remove","path":"/baz"}]`},
+		{document: `{"foo":"bar"}`, patch: `[{"op":"replace","path":"/baz","value":"qux"}]`},
+		{document: `{"foo":["bar"]}`, patch: `[{"op":"add","path":"/foo/2","value":"qux"}]`},
+		{document: `{"foo":["bar"]}`, patch: `[{"op":"add","path":"/foo/01","value":"qux"}]`},
+		{document: `{"foo":{"bar":1}}`, patch: `[{"op":"move","from":"/foo","path":"/foo/bar"}]`},
+		{document: `{"foo":"bar"}`, patch: `[{"op":"unknown","path":"/foo"}]`},
+		{document: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/baz"}]`},
+		{document: `{"foo":"bar"}`, patch: `[{"op":"add","path":"baz","value":1}]`},
+		{document: `{"foo":"bar"}`, patch: `{"op":"add","path":"/baz","value":1}`},
+		{document: `{"baz":"qux"}`, patch: `[{"op":"test","path":"/baz","value":"bar"}]`, wantTestFailed: true},
+		{document: `{"foo":"bar"}`, patch: `[{"op":"add","path":"/baz","value":1},{"op":"test","path":"/foo","value":1}]`, wantTestFailed: true},
+	}
+
+	for _, tc := range testCases {
+		_, err := ApplyJSONPatch([]byte(tc.document), []byte(tc.patch))
+		if err == nil {
+			t.Errorf(`want an error when applying "%s" to "%s", got none`, tc.patch, tc.document)
+			continue
+		}
+
+		if errors.Is(err, ErrJSONPatchTestFailed) != tc.wantTestFailed {
+			t.Errorf(`applying "%s" to "%s": unexpected error "%s"`, tc.patch, tc.document, err)
+		}
+	}
+}
+
+// TestJSONDiff tests that only the modified top level members are returned.
+func TestJSONDiff(t *testing.T) {
+	diff, err := JSONDiff([]byte(`{"a":1,"b":{"c":2},"d":"e"}`), []byte(`{"a":1.0,"b":{"c":3},"f":"g"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if len(diff) != 3 {
+		t.Errorf(`want 3 modified members, got %v`, diff)
+	}
+
+	if _, ok := diff["a"]; ok {
+		t.Errorf(`want "a" to be unmodified, got %v`, diff)
+	}
+
+	if value, ok := diff["d"]; !ok || value != nil {
+		t.Errorf(`want "d" to be removed, got %v`, diff)
+	}
+
+	if value, ok := diff["f"]; !ok || value != "g" {
+		t.Errorf(`want "f" to be added, got %v`, diff)
+	}
+}
@@ -0,0 +1,242 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/RedHatInsights/sources-api-go/dao"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/service"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+var getWebhookDao func(c echo.Context) (dao.WebhookDao, error)
+
+func getWebhookDaoWithTenant(c echo.Context) (dao.WebhookDao, error) {
+	requestParams, err := dao.NewRequestParamsFromContext(c)
+	if err != nil {
+		return nil, err
+	}
+
+	return dao.GetWebhookDao(requestParams), nil
+}
+
+// rejectWebhookCertAuth refuses the cert-auth callers, which are locked down to the satellite sources, since the
+// webhooks get the events of every source of the tenant, and their deliveries log them.
+func rejectWebhookCertAuth(c echo.Context) error {
+	if c.Get("cert-auth") != nil {
+		return util.NewErrBadRequest("Unauthorized.")
+	}
+
+	return nil
+}
+
+func WebhookList(c echo.Context) error {
+	webhookDao, err := getWebhookDao(c)
+	if err != nil {
+		return err
+	}
+
+	filters, err := getFilters(c)
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := getLimitAndOffset(c)
+	if err != nil {
+		return err
+	}
+
+	webhooks, count, err := webhookDao.List(limit, offset, filters)
+	if err != nil {
+		return err
+	}
+
+	out := make([]interface{}, len(webhooks))
+	for i := 0; i < len(webhooks); i++ {
+		out[i] = webhooks[i].ToResponse()
+	}
+
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(webhooks)...))
+}
+
+func WebhookGet(c echo.Context) error {
+	webhookDao, err := getWebhookDao(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	webhook, err := webhookDao.GetById(&id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, webhook.ToResponse())
+}
+
+// WebhookCreate subscribes a webhook to the tenant's events. The secret the deliveries get signed with is generated
+// here, and it is only ever returned in this response.
+func WebhookCreate(c echo.Context) error {
+	err := rejectWebhookCertAuth(c)
+	if err != nil {
+		return err
+	}
+
+	webhookDao, err := getWebhookDao(c)
+	if err != nil {
+		return err
+	}
+
+	input := &m.WebhookCreateRequest{}
+
+	err = c.Bind(input)
+	if err != nil {
+		return err
+	}
+
+	err = service.ValidateWebhookCreateRequest(input)
+	if err != nil {
+		return util.NewErrBadRequest(fmt.Sprintf("Validation failed: %v", err))
+	}
+
+	secret, err := service.NewWebhookSecret()
+	if err != nil {
+		return err
+	}
+
+	encryptedSecret, err := util.Encrypt(secret)
+	if err != nil {
+		return err
+	}
+
+	webhook := &m.Webhook{
+		URL:        input.URL,
+		EventTypes: input.EventTypes,
+		Secret:     encryptedSecret,
+		Enabled:    input.Enabled == nil || *input.Enabled,
+	}
+
+	err = webhookDao.Create(webhook)
+	if err != nil {
+		return err
+	}
+
+	out := webhook.ToResponse()
+	out.Secret = secret
+
+	return c.JSON(http.StatusCreated, out)
+}
+
+func WebhookEdit(c echo.Context) error {
+	err := rejectWebhookCertAuth(c)
+	if err != nil {
+		return err
+	}
+
+	webhookDao, err := getWebhookDao(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	input := &m.WebhookEditRequest{}
+
+	err = c.Bind(input)
+	if err != nil {
+		return err
+	}
+
+	err = service.ValidateWebhookEditRequest(input)
+	if err != nil {
+		return util.NewErrBadRequest(fmt.Sprintf("Validation failed: %v", err))
+	}
+
+	webhook, err := webhookDao.GetById(&id)
+	if err != nil {
+		return err
+	}
+
+	webhook.UpdateFromRequest(input)
+
+	err = webhookDao.Update(webhook)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(http.StatusOK, webhook.ToResponse())
+}
+
+func WebhookDelete(c echo.Context) error {
+	webhookDao, err := getWebhookDao(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	_, err = webhookDao.Delete(&id)
+	if err != nil {
+		return err
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+// WebhookDeliveryList lists the delivery log of the given webhook.
+func WebhookDeliveryList(c echo.Context) error {
+	err := rejectWebhookCertAuth(c)
+	if err != nil {
+		return err
+	}
+
+	webhookDao, err := getWebhookDao(c)
+	if err != nil {
+		return err
+	}
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		return util.NewErrBadRequest(err)
+	}
+
+	filters, err := getFilters(c)
+	if err != nil {
+		return err
+	}
+
+	limit, offset, err := getLimitAndOffset(c)
+	if err != nil {
+		return err
+	}
+
+	_, err = webhookDao.GetById(&id)
+	if err != nil {
+		return err
+	}
+
+	deliveries, count, err := webhookDao.ListDeliveries(id, limit, offset, filters)
+	if err != nil {
+		return err
+	}
+
+	out := make([]interface{}, len(deliveries))
+	for i := 0; i < len(deliveries); i++ {
+		out[i] = deliveries[i].ToResponse()
+	}
+
+	return c.JSON(http.StatusOK, util.CollectionResponse(out, c.Request(), int(count), limit, offset, pageBounds(deliveries)...))
+}
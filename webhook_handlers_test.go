@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/request"
+	"github.com/RedHatInsights/sources-api-go/internal/testutils/templates"
+	h "github.com/RedHatInsights/sources-api-go/middleware/headers"
+	m "github.com/RedHatInsights/sources-api-go/model"
+	"github.com/RedHatInsights/sources-api-go/util"
+	"github.com/labstack/echo/v4"
+)
+
+// webhookRequest sends a request with the given body to the webhook handler.
+func webhookRequest(t *testing.T, handler echo.HandlerFunc, method string, path string, id string, body interface{}) *httptest.ResponseRecorder {
+	var requestBody *bytes.Reader
+
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			t.Fatalf("unable to marshal the request body: %s", err)
+		}
+
+		requestBody = bytes.NewReader(encoded)
+	} else {
+		requestBody = bytes.NewReader(nil)
+	}
+
+	c, rec := request.CreateTestContext(
+		method,
+		path,
+		requestBody,
+		map[string]interface{}{
+			"limit":    100,
+			"offset":   0,
+			"filters":  []util.Filter{},
+			h.TenantID: int64(1),
+		},
+	)
+
+	c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+	if id != "" {
+		c.SetParamNames("id")
+		c.SetParamValues(id)
+	}
+
+	err := ErrorHandlingContext(handler)(c)
+	if err != nil {
+		t.Errorf(`unexpected error: %s`, err)
+	}
+
+	return rec
+}
+
+// TestWebhookList tests that the tenant's webhooks get listed without their secrets.
+func TestWebhookList(t *testing.T) {
+	rec := webhookRequest(t, WebhookList, http.MethodGet, "/api/sources/v3.1/webhooks", "", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var out struct {
+		Data []m.WebhookResponse `json:"data"`
+	}
+
+	err := json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the webhooks: %s", err)
+	}
+
+	if len(out.Data) < 2 {
+		t.Fatalf("want at least 2 webhooks, got %d", len(out.Data))
+	}
+
+	for _, webhook := range out.Data {
+		if webhook.Secret != "" {
+			t.Errorf(`the secret of the webhook "%s" must not be listed`, webhook.ID)
+		}
+	}
+}
+
+// TestWebhookGet tests that a single webhook gets returned.
+func TestWebhookGet(t *testing.T) {
+	rec := webhookRequest(t, WebhookGet, http.MethodGet, "/api/sources/v3.1/webhooks/1", "1", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var out m.WebhookResponse
+
+	err := json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the webhook: %s", err)
+	}
+
+	if out.ID != "1" || !out.Enabled || len(out.EventTypes) != 2 || out.Secret != "" {
+		t.Errorf(`unexpected webhook "%+v"`, out)
+	}
+}
+
+// TestWebhookGetNotFound tests that a not found error is returned for a missing webhook.
+func TestWebhookGetNotFound(t *testing.T) {
+	templates.NotFoundTest(t, webhookRequest(t, WebhookGet, http.MethodGet, "/api/sources/v3.1/webhooks/12345", "12345", nil))
+}
+
+// TestWebhookGetBadRequest tests that an invalid webhook id gets rejected.
+func TestWebhookGetBadRequest(t *testing.T) {
+	templates.BadRequestTest(t, webhookRequest(t, WebhookGet, http.MethodGet, "/api/sources/v3.1/webhooks/xxx", "xxx", nil))
+}
+
+// TestWebhookCreate tests that the webhook gets created and that its secret is returned only once.
+func TestWebhookCreate(t *testing.T) {
+	util.OverrideEncryptionKey(strings.Repeat("test", 8))
+
+	// a public IP address, so that the URL's validation does not need to resolve its host.
+	requestBody := m.WebhookCreateRequest{
+		URL:        "https://93.184.215.14/hooks",
+		EventTypes: []string{"Source.update", "Application.destroy"},
+	}
+
+	rec := webhookRequest(t, WebhookCreate, http.MethodPost, "/api/sources/v3.1/webhooks", "", requestBody)
+
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusCreated, rec.Code, rec.Body.String())
+	}
+
+	var out m.WebhookResponse
+
+	err := json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the webhook: %s", err)
+	}
+
+	if out.URL != requestBody.URL || len(out.EventTypes) != 2 || !out.Enabled {
+		t.Errorf(`unexpected webhook "%+v"`, out)
+	}
+
+	// 32 random bytes, hex encoded.
+	if len(out.Secret) != 64 {
+		t.Errorf(`want the generated secret in the response, got "%s"`, out.Secret)
+	}
+}
+
+// TestWebhookCreateBadRequest tests that the webhooks which do not use HTTPS or which subscribe to unknown event
+// types get rejected.
+func TestWebhookCreateBadRequest(t *testing.T) {
+	requestBodies := []m.WebhookCreateRequest{
+		{URL: "http://example.com/hooks", EventTypes: []string{"Source.update"}},
+		{URL: "https://example.com/hooks", EventTypes: []string{"Source.explode"}},
+		{URL: "https://example.com/hooks"},
+	}
+
+	for _, requestBody := range requestBodies {
+		templates.BadRequestTest(t, webhookRequest(t, WebhookCreate, http.MethodPost, "/api/sources/v3.1/webhooks", "", requestBody))
+	}
+}
+
+// TestWebhookEdit tests that the webhook gets updated.
+func TestWebhookEdit(t *testing.T) {
+	enabled := false
+	requestBody := m.WebhookEditRequest{
+		EventTypes: []string{"Source.destroy"},
+		Enabled:    &enabled,
+	}
+
+	rec := webhookRequest(t, WebhookEdit, http.MethodPatch, "/api/sources/v3.1/webhooks/1", "1", requestBody)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var out m.WebhookResponse
+
+	err := json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the webhook: %s", err)
+	}
+
+	if out.Enabled || len(out.EventTypes) != 1 || out.EventTypes[0] != "Source.destroy" {
+		t.Errorf(`unexpected webhook "%+v"`, out)
+	}
+
+	// Undo the changes for the rest of the tests.
+	enabled = true
+	requestBody = m.WebhookEditRequest{EventTypes: []string{"Source.create", "Source.update"}, Enabled: &enabled}
+
+	rec = webhookRequest(t, WebhookEdit, http.MethodPatch, "/api/sources/v3.1/webhooks/1", "1", requestBody)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+}
+
+// TestWebhookEditBadRequest tests that an invalid URL gets rejected.
+func TestWebhookEditBadRequest(t *testing.T) {
+	url := "ftp://example.com/hooks"
+
+	templates.BadRequestTest(t, webhookRequest(t, WebhookEdit, http.MethodPatch, "/api/sources/v3.1/webhooks/1", "1", m.WebhookEditRequest{URL: &url}))
+}
+
+// TestWebhookEditNotFound tests that a not found error is returned when editing a missing webhook.
+func TestWebhookEditNotFound(t *testing.T) {
+	enabled := false
+
+	templates.NotFoundTest(t, webhookRequest(t, WebhookEdit, http.MethodPatch, "/api/sources/v3.1/webhooks/12345", "12345", m.WebhookEditRequest{Enabled: &enabled}))
+}
+
+// TestWebhookDeleteNotFound tests that a not found error is returned when deleting a missing webhook.
+func TestWebhookDeleteNotFound(t *testing.T) {
+	templates.NotFoundTest(t, webhookRequest(t, WebhookDelete, http.MethodDelete, "/api/sources/v3.1/webhooks/12345", "12345", nil))
+}
+
+// TestWebhookDeliveryList tests that only the given webhook's deliveries get listed.
+func TestWebhookDeliveryList(t *testing.T) {
+	rec := webhookRequest(t, WebhookDeliveryList, http.MethodGet, "/api/sources/v3.1/webhooks/1/deliveries", "1", nil)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("want status code %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var out struct {
+		Data []m.WebhookDeliveryResponse `json:"data"`
+	}
+
+	err := json.Unmarshal(rec.Body.Bytes(), &out)
+	if err != nil {
+		t.Fatalf("unable to unmarshal the webhook deliveries: %s", err)
+	}
+
+	if len(out.Data) != 2 {
+		t.Fatalf("want 2 deliveries, got %d", len(out.Data))
+	}
+
+	for _, delivery := range out.Data {
+		if delivery.WebhookID != "1" || delivery.Status == "" {
+			t.Errorf(`unexpected delivery "%+v"`, delivery)
+		}
+	}
+}
+
+// TestWebhookDeliveryListNotFound tests that the deliveries of a missing webhook can not be listed.
+func TestWebhookDeliveryListNotFound(t *testing.T) {
+	templates.NotFoundTest(t, webhookRequest(t, WebhookDeliveryList, http.MethodGet, "/api/sources/v3.1/webhooks/12345/deliveries", "12345", nil))
+}
+
+// TestWebhookCertAuth tests that the cert-auth callers can neither subscribe a webhook to the tenant's events nor read
+// the delivered ones.
+func TestWebhookCertAuth(t *testing.T) {
+	testCases := []struct {
+		handler echo.HandlerFunc
+		method  string
+		id      string
+		body    string
+	}{
+		{handler: WebhookCreate, method: http.MethodPost, body: `{"url": "https://example.com/hooks", "event_types": ["Source.update"]}`},
+		{handler: WebhookEdit, method: http.MethodPatch, id: "1", body: `{"url": "https://example.com/hooks"}`},
+		{handler: WebhookDeliveryList, method: http.MethodGet, id: "1"},
+	}
+
+	for _, tc := range testCases {
+		c, rec := request.CreateTestContext(
+			tc.method,
+			"/api/sources/v3.1/webhooks",
+			strings.NewReader(tc.body),
+			map[string]interface{}{
+				"limit":     100,
+				"offset":    0,
+				"filters":   []util.Filter{},
+				h.TenantID:  int64(1),
+				"cert-auth": true,
+			},
+		)
+
+		c.Request().Header.Add("Content-Type", "application/json;charset=utf-8")
+
+		if tc.id != "" {
+			c.SetParamNames("id")
+			c.SetParamValues(tc.id)
+		}
+
+		err := ErrorHandlingContext(tc.handler)(c)
+		if err != nil {
+			t.Errorf(`unexpected error: %s`, err)
+		}
+
+		templates.BadRequestTest(t, rec)
+	}
+}